@@ -10,8 +10,12 @@
 //	prism review staged               # review staged changes
 //	prism review commit <sha>         # review a specific commit
 //	prism review range origin/main..HEAD  # review a revision range
+//	prism review branch               # review the current branch vs the default branch
 //	prism review snippet              # review code from stdin
 //	prism review codebase             # review all tracked files
+//	prism review dir <path>           # review a directory without git
+//	prism format --format sarif < report.json  # re-render a saved report
+//	prism badge --from report.json --out badge.svg  # findings badge
 //
 // See https://github.com/dshills/prism for full documentation.
 package main
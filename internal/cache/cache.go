@@ -18,15 +18,34 @@ type Entry struct {
 	TTL       int       `json:"ttl"`
 }
 
+// Mode selects whether a cache serves stored entries.
+type Mode int
+
+const (
+	// ReadWrite serves stored entries and stores new ones.
+	ReadWrite Mode = iota
+	// WriteOnly never serves stored entries but still stores new ones, so a
+	// fresh run refreshes the cache for later runs.
+	WriteOnly
+)
+
 // Cache provides file-based caching for LLM review responses.
 type Cache struct {
 	dir        string
 	ttlSeconds int
 	enabled    bool
+	mode       Mode
 }
 
-// New creates a new Cache. If dir is empty, uses the default cache directory.
+// New creates a new read-write Cache. If dir is empty, uses the default
+// cache directory.
 func New(enabled bool, dir string, ttlSeconds int) (*Cache, error) {
+	return NewWithMode(enabled, dir, ttlSeconds, ReadWrite)
+}
+
+// NewWithMode creates a new Cache in the given mode. If dir is empty, uses
+// the default cache directory.
+func NewWithMode(enabled bool, dir string, ttlSeconds int, mode Mode) (*Cache, error) {
 	if !enabled {
 		return &Cache{enabled: false}, nil
 	}
@@ -44,12 +63,14 @@ func New(enabled bool, dir string, ttlSeconds int) (*Cache, error) {
 		dir:        dir,
 		ttlSeconds: ttlSeconds,
 		enabled:    true,
+		mode:       mode,
 	}, nil
 }
 
-// Get retrieves a cached entry by key. Returns ("", false) on miss.
+// Get retrieves a cached entry by key. Returns ("", false) on miss, and
+// always in WriteOnly mode.
 func (c *Cache) Get(key string) (string, bool) {
-	if !c.enabled {
+	if !c.enabled || c.mode == WriteOnly {
 		return "", false
 	}
 	path := c.entryPath(key)
@@ -175,15 +196,36 @@ func HashKey(key string) string {
 	return fmt.Sprintf("%x", h)
 }
 
-// BuildCacheKey creates a cache key from the review inputs.
-func BuildCacheKey(provider, model, diff string) string {
-	return HashKey(fmt.Sprintf("%s:%s:%s", provider, model, diff))
+// BuildCacheKey creates a cache key from the review inputs. prompt is the
+// prompt text the diff is reviewed with, so a change to the instructions
+// sent with an unchanged diff misses the cache.
+func BuildCacheKey(provider, model, prompt, diff string) string {
+	return HashKey(fmt.Sprintf("%s:%s:%s:%s", provider, model, HashKey(prompt), diff))
 }
 
 func (c *Cache) entryPath(key string) string {
 	return filepath.Join(c.dir, HashKey(key)+".json")
 }
 
+// RepoDir returns the cache directory namespaced to the repository rooted at
+// repoRoot: a subdirectory of base (or the default cache directory when base
+// is empty) named after a hash of the root path.
+func RepoDir(base, repoRoot string) (string, error) {
+	if base == "" {
+		d, err := defaultCacheDir()
+		if err != nil {
+			return "", err
+		}
+		base = d
+	}
+	return filepath.Join(base, "repos", HashKey(filepath.Clean(repoRoot))[:16]), nil
+}
+
+// DefaultDir returns the platform-appropriate default cache directory.
+func DefaultDir() (string, error) {
+	return defaultCacheDir()
+}
+
 func defaultCacheDir() (string, error) {
 	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
 		return filepath.Join(xdg, "prism"), nil
@@ -181,9 +181,10 @@ func TestHashKey(t *testing.T) {
 }
 
 func TestBuildCacheKey(t *testing.T) {
-	k1 := BuildCacheKey("anthropic", "claude-3-5-sonnet", "diff content")
-	k2 := BuildCacheKey("anthropic", "claude-3-5-sonnet", "diff content")
-	k3 := BuildCacheKey("openai", "gpt-4o", "diff content")
+	k1 := BuildCacheKey("anthropic", "claude-3-5-sonnet", "prompt", "diff content")
+	k2 := BuildCacheKey("anthropic", "claude-3-5-sonnet", "prompt", "diff content")
+	k3 := BuildCacheKey("openai", "gpt-4o", "prompt", "diff content")
+	k4 := BuildCacheKey("anthropic", "claude-3-5-sonnet", "other prompt", "diff content")
 
 	if k1 != k2 {
 		t.Error("Same inputs should produce same cache key")
@@ -191,6 +192,9 @@ func TestBuildCacheKey(t *testing.T) {
 	if k1 == k3 {
 		t.Error("Different provider should produce different cache key")
 	}
+	if k1 == k4 {
+		t.Error("Different prompt should produce different cache key")
+	}
 }
 
 func TestCache_GetMissingFile(t *testing.T) {
@@ -328,3 +332,71 @@ func TestCache_OverwriteExisting(t *testing.T) {
 		t.Errorf("Got = %q, want %q", got, "updated")
 	}
 }
+
+func TestRepoDir(t *testing.T) {
+	base := t.TempDir()
+	a, err := RepoDir(base, "/src/repo-a")
+	if err != nil {
+		t.Fatalf("RepoDir error: %v", err)
+	}
+	b, _ := RepoDir(base, "/src/repo-b")
+	again, _ := RepoDir(base, "/src/repo-a/")
+	if a == b {
+		t.Error("different repos should get different dirs")
+	}
+	if a != again {
+		t.Errorf("same repo should get the same dir: %s vs %s", a, again)
+	}
+	if filepath.Dir(filepath.Dir(a)) != base {
+		t.Errorf("repo dir %s should be under %s", a, base)
+	}
+
+	// Clearing one repo's cache leaves the other's entries alone.
+	ca, _ := New(true, a, 0)
+	cb, _ := New(true, b, 0)
+	if err := ca.Put("k", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cb.Put("k", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ca.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ca.Get("k"); ok {
+		t.Error("repo a entry should be cleared")
+	}
+	if v, ok := cb.Get("k"); !ok || v != "b" {
+		t.Errorf("repo b entry should survive, got %q, %v", v, ok)
+	}
+	if st, _ := cb.GetStats(); st.Entries != 1 || st.Dir != b {
+		t.Errorf("repo b stats = %+v", st)
+	}
+}
+
+func TestCache_WriteOnly(t *testing.T) {
+	dir := t.TempDir()
+	rw, err := New(true, dir, 86400)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	if err := rw.Put("key", "old"); err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+
+	wo, err := NewWithMode(true, dir, 86400, WriteOnly)
+	if err != nil {
+		t.Fatalf("NewWithMode error: %v", err)
+	}
+	if _, ok := wo.Get("key"); ok {
+		t.Error("WriteOnly cache should never serve entries")
+	}
+	if err := wo.Put("key", "new"); err != nil {
+		t.Fatalf("Put error: %v", err)
+	}
+
+	got, ok := rw.Get("key")
+	if !ok || got != "new" {
+		t.Errorf("read-write Get = %q, %v; want the entry written in WriteOnly mode", got, ok)
+	}
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/dshills/prism/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagBadgeFrom   string
+	flagBadgeOut    string
+	flagBadgeFormat string
+)
+
+var badgeCmd = &cobra.Command{
+	Use:   "badge",
+	Short: "Render a findings-count badge from a saved JSON report",
+	Long: `Badge reads a JSON report produced by "prism review --format json" and
+renders a small badge showing the high/medium/low counts, colored by the
+highest severity. Use --format json to emit a shields.io endpoint document
+instead of an SVG.`,
+	Example: `  prism badge --from report.json --out badge.svg
+  prism badge --from report.json --format json --out badge.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var writer output.BadgeWriter
+		contentType := "image/svg+xml"
+		switch flagBadgeFormat {
+		case "", "svg":
+		case "json":
+			writer.JSON = true
+			contentType = "application/json"
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unsupported badge format %q (use svg or json)\n", flagBadgeFormat)
+			exitCode = ExitUsageError
+			return nil
+		}
+
+		in := cmd.InOrStdin()
+		if flagBadgeFrom != "" && flagBadgeFrom != "-" {
+			f, err := os.Open(flagBadgeFrom)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exitCode = ExitUsageError
+				return nil
+			}
+			defer f.Close()
+			in = f
+		}
+
+		report, err := readReport(in)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exitCode = ExitUsageError
+			return nil
+		}
+
+		var buf bytes.Buffer
+		if err := writer.Write(&buf, report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing badge: %v\n", err)
+			exitCode = ExitRuntimeError
+			return nil
+		}
+		if flagBadgeOut == "" {
+			_, err = buf.WriteTo(cmd.OutOrStdout())
+		} else {
+			err = writeToSink(cmd.Context(), flagBadgeOut, contentType, buf.Bytes())
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing badge: %v\n", err)
+			exitCode = ExitRuntimeError
+		}
+		return nil
+	},
+}
+
+func init() {
+	badgeCmd.Flags().StringVar(&flagBadgeFrom, "from", "", "JSON report to read (default: stdin)")
+	badgeCmd.Flags().StringVar(&flagBadgeOut, "out", "", "Output file path, http(s) URL to POST to, or s3://bucket/key to upload to (default: stdout)")
+	badgeCmd.Flags().StringVar(&flagBadgeFormat, "format", "svg", "Badge format (svg, json)")
+}
+
+// writeToSink delivers data to an output destination (file path, http(s)
+// URL, or s3 URL) via output.OpenSink.
+func writeToSink(ctx context.Context, dest, contentType string, data []byte) error {
+	sink, err := output.OpenSink(ctx, dest, contentType)
+	if err != nil {
+		return err
+	}
+	if _, err := sink.Write(data); err != nil {
+		sink.Close()
+		return err
+	}
+	return sink.Close()
+}
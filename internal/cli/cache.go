@@ -7,6 +7,7 @@ import (
 
 	"github.com/dshills/prism/internal/cache"
 	"github.com/dshills/prism/internal/config"
+	"github.com/dshills/prism/internal/review"
 	"github.com/spf13/cobra"
 )
 
@@ -19,11 +20,11 @@ var cacheClearCmd = &cobra.Command{
 	Use:   "clear",
 	Short: "Clear all cached review results",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.Load(nil)
+		cfg, err := config.Load(cacheOverrides())
 		if err != nil {
 			return err
 		}
-		c, err := cache.New(true, cfg.Cache.Dir, cfg.Cache.TTLSeconds)
+		c, err := cache.New(true, review.CacheDir(cfg.Cache), cfg.Cache.TTLSeconds)
 		if err != nil {
 			return fmt.Errorf("opening cache: %w", err)
 		}
@@ -39,11 +40,11 @@ var cacheShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show cache statistics",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.Load(nil)
+		cfg, err := config.Load(cacheOverrides())
 		if err != nil {
 			return err
 		}
-		c, err := cache.New(cfg.Cache.Enabled, cfg.Cache.Dir, cfg.Cache.TTLSeconds)
+		c, err := cache.New(cfg.Cache.Enabled, review.CacheDir(cfg.Cache), cfg.Cache.TTLSeconds)
 		if err != nil {
 			return fmt.Errorf("opening cache: %w", err)
 		}
@@ -64,7 +65,16 @@ var cacheShowCmd = &cobra.Command{
 	},
 }
 
+// cacheOverrides returns the config overrides from the cache command flags.
+func cacheOverrides() map[string]string {
+	if flagCachePerRepo {
+		return map[string]string{"cachePerRepo": "true"}
+	}
+	return nil
+}
+
 func init() {
+	cacheCmd.PersistentFlags().BoolVar(&flagCachePerRepo, "per-repo", false, "Use the current repository's cache subdirectory (cache.perRepo)")
 	cacheCmd.AddCommand(cacheClearCmd)
 	cacheCmd.AddCommand(cacheShowCmd)
 }
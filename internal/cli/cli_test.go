@@ -1,12 +1,26 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/dshills/prism/internal/config"
+	"github.com/dshills/prism/internal/gitctx"
+	"github.com/dshills/prism/internal/history"
+	"github.com/dshills/prism/internal/providers"
+	"github.com/dshills/prism/internal/review"
+	"github.com/spf13/cobra"
 )
 
 // resetFlags resets all package-level flag variables to their zero values.
@@ -23,15 +37,75 @@ func resetFlags() {
 	flagFailOn = ""
 	flagMaxFindings = 0
 	flagRules = ""
+	flagRulesPack = ""
 	flagNoRedact = false
+	flagMergeIdentical = false
+	flagTags = nil
+	flagMetaFile = ""
+	flagEscalate = ""
+	flagEscalateConf = 0
+	flagSARIFSuppressions = ""
+	flagWithHunks = false
+	flagRequireTests = false
+	flagPostHook = ""
+	flagNewCodeOnly = false
+	flagWithNote = false
+	flagDropNoop = false
+	flagNoTiming = false
+	flagWithOwners = false
+	flagMaxCost = 0
+	flagMinDiffBytes = 0
+	flagMarkdownTOC = false
+	flagTextTable = false
+	flagGroupBy = ""
+	flagFields = ""
+	flagPathsIgnoreCase = false
+	flagGuide = ""
+	flagPromptTemplate = ""
+	flagQuiet = false
+	flagConcurrency = 0
+	flagRetryBudget = 0
+	flagBaseline = ""
+	baselineReport = nil
+	runMetadata = nil
+	sarifSuppressions = nil
+	dismissals = nil
+	flagAudit = false
+	flagRefreshCache = false
+	flagCachePerRepo = false
+	auditLog = nil
+	flagSinceDays = 0
+	flagFilesFrom = ""
+	flagMaxFilesPerChunk = 0
+	flagExplainExit = false
+	flagEnvFile = ""
+	flagInitForce = false
+	flagIndex = false
+	flagPatch = false
+	flagInteractive = false
+	flagNotify = ""
+	flagAmend = false
 	flagParent = ""
 	flagMergeBase = false
+	flagPerCommit = false
+	flagBranchBase = ""
 	flagSnippetPath = ""
 	flagSnippetLang = ""
 	flagSnippetBase = ""
+	flagSnippetFiles = nil
+	flagCombinedModes = nil
 	flagGHOwner = ""
 	flagGHRepo = ""
 	flagGHDryRun = false
+	flagGLProject = ""
+	flagGLDryRun = false
+	flagStream = false
+	flagTimeout = 0
+	flagBadgeFrom = ""
+	flagBadgeOut = ""
+	flagBadgeFormat = "svg"
+	flagHistoryLimit = 20
+	flagHistoryAll = false
 }
 
 // --- splitComma tests ---
@@ -116,6 +190,19 @@ func TestBuildOverrides_AllFlags(t *testing.T) {
 	}
 }
 
+func TestBuildOverrides_CachePerRepo(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	flagCachePerRepo = true
+
+	if m := buildOverrides(); m["cachePerRepo"] != "true" {
+		t.Errorf("cachePerRepo = %q, want %q", m["cachePerRepo"], "true")
+	}
+	if m := cacheOverrides(); m["cachePerRepo"] != "true" {
+		t.Errorf("cache command cachePerRepo = %q, want %q", m["cachePerRepo"], "true")
+	}
+}
+
 func TestBuildOverrides_PartialFlags(t *testing.T) {
 	resetFlags()
 	flagProvider = "gemini"
@@ -181,6 +268,20 @@ func TestBuildDiffOpts_FromConfig(t *testing.T) {
 	}
 }
 
+func TestBuildDiffOpts_NoRedactDropsRedactPaths(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	cfg := config.Default()
+
+	if opts := buildDiffOpts(cfg); len(opts.RedactPaths) == 0 {
+		t.Error("RedactPaths should come from privacy.redactPaths by default")
+	}
+	flagNoRedact = true
+	if opts := buildDiffOpts(cfg); len(opts.RedactPaths) != 0 {
+		t.Errorf("RedactPaths = %v, want none under --no-redact", opts.RedactPaths)
+	}
+}
+
 func TestBuildDiffOpts_PathsFlagOverridesInclude(t *testing.T) {
 	resetFlags()
 	flagPaths = "src/**/*.go,lib/**/*.go"
@@ -246,6 +347,68 @@ func TestBuildDiffOpts_NoFlagOverrides(t *testing.T) {
 	}
 }
 
+func TestWholeFileDiffOpts_SinceDays(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+
+	opts, err := wholeFileDiffOpts(config.Config{})
+	if err != nil || !opts.ModifiedSince.IsZero() {
+		t.Fatalf("without --since-days ModifiedSince should be zero, got %v, %v", opts.ModifiedSince, err)
+	}
+
+	flagSinceDays = 7
+	opts, err = wholeFileDiffOpts(config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Now().AddDate(0, 0, -7)
+	if d := opts.ModifiedSince.Sub(want); d > time.Minute || d < -time.Minute {
+		t.Errorf("ModifiedSince = %v, want about %v", opts.ModifiedSince, want)
+	}
+
+	flagSinceDays = -1
+	if _, err := wholeFileDiffOpts(config.Config{}); err == nil {
+		t.Error("expected error for negative --since-days")
+	}
+}
+
+func TestExplainExit(t *testing.T) {
+	tests := []struct {
+		code   int
+		detail string
+		want   string
+	}{
+		{ExitSuccess, "", "exit 0: success"},
+		{ExitFindings, "", "exit 1: findings met the --fail-on threshold"},
+		{ExitFindings, `findings met the "high" --fail-on threshold`, `exit 1: findings met the "high" --fail-on threshold`},
+		{ExitUsageError, "", "exit 2: usage error"},
+		{ExitAuthError, "", "exit 3: authentication error"},
+		{ExitRuntimeError, "", "exit 4: runtime error"},
+	}
+	for _, tt := range tests {
+		if got := explainExit(tt.code, tt.detail); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("explainExit(%d, %q) = %q, want prefix %q", tt.code, tt.detail, got, tt.want)
+		}
+	}
+}
+
+func TestApplyFailOn(t *testing.T) {
+	origCode, origDetail := exitCode, exitDetail
+	t.Cleanup(func() { exitCode, exitDetail = origCode, origDetail })
+	report := &review.Report{Findings: []review.Finding{{Severity: review.SeverityMedium}}}
+
+	exitCode, exitDetail = ExitSuccess, ""
+	applyFailOn(report, config.Config{FailOn: "high"})
+	if exitCode != ExitSuccess {
+		t.Errorf("medium finding should not fail at high threshold, exit = %d", exitCode)
+	}
+
+	applyFailOn(report, config.Config{FailOn: "medium"})
+	if exitCode != ExitFindings || !strings.Contains(exitDetail, `"medium"`) {
+		t.Errorf("exit = %d, detail = %q; want findings exit naming the threshold", exitCode, exitDetail)
+	}
+}
+
 // --- version command tests ---
 
 func TestVersionCmd_Execute(t *testing.T) {
@@ -493,6 +656,378 @@ func TestGithubCmd_MissingArg(t *testing.T) {
 	}
 }
 
+func TestGithubCmd_LargeDiffFilteredAndChunked(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	savedExitCode := exitCode
+	t.Cleanup(func() { exitCode = savedExitCode })
+	exitCode = ExitSuccess
+
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	if err := os.MkdirAll(filepath.Join(tmpDir, "prism"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfgJSON := `{"provider":"ollama","model":"llama3","cache":{"enabled":false}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "prism", "config.json"), []byte(cfgJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 60 files of ~5KB each: ~300KB, well over the chunk threshold
+	var diff strings.Builder
+	var names []string
+	for i := 0; i < 60; i++ {
+		name := fmt.Sprintf("pkg/file%02d.go", i)
+		if i%6 == 0 {
+			name = fmt.Sprintf("vendor/dep%02d.go", i)
+		}
+		names = append(names, name)
+		fmt.Fprintf(&diff, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n@@ -0,0 +1,100 @@\n", name, name, name, name)
+		for j := 0; j < 100; j++ {
+			fmt.Fprintf(&diff, "+var v%d_%d = \"%s\"\n", i, j, strings.Repeat("x", 20))
+		}
+	}
+	gh := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/o/r/pulls/7":
+			io.WriteString(w, diff.String())
+		case "/repos/o/r/pulls/7/files":
+			var files []map[string]string
+			for _, n := range names {
+				files = append(files, map[string]string{"filename": n})
+			}
+			json.NewEncoder(w).Encode(files)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer gh.Close()
+	t.Setenv("GITHUB_API_URL", gh.URL)
+	t.Setenv("GITHUB_TOKEN", "test-token")
+
+	var mu sync.Mutex
+	var requests, inFlight, peak int
+	var sawVendor bool
+	llm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests++
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		if strings.Contains(string(body), "vendor/") {
+			sawVendor = true
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		io.WriteString(w, `{"choices":[{"message":{"content":"[]"}}]}`)
+	}))
+	defer llm.Close()
+	t.Setenv("OLLAMA_HOST", llm.URL)
+
+	flagGHOwner, flagGHRepo, flagGHDryRun = "o", "r", true
+	flagExclude = "vendor/**"
+	flagMaxDiffBytes = 250000
+	flagConcurrency = 1
+	flagOut = filepath.Join(tmpDir, "report.json")
+
+	githubCmd.SetContext(context.Background())
+	if err := githubCmd.RunE(githubCmd, []string{"7"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exitCode != ExitSuccess {
+		t.Fatalf("exitCode = %d, want %d", exitCode, ExitSuccess)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if requests < 2 {
+		t.Errorf("requests = %d, want the large PR diff split into several chunks", requests)
+	}
+	if peak > 1 {
+		t.Errorf("peak concurrent requests = %d, want at most 1 (--concurrency)", peak)
+	}
+	if sawVendor {
+		t.Error("excluded vendor/ files should not be sent to the model")
+	}
+}
+
+func TestReviewFilesCmd_FromList(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	savedExitCode := exitCode
+	t.Cleanup(func() { exitCode = savedExitCode })
+	exitCode = ExitSuccess
+
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	if err := os.MkdirAll(filepath.Join(tmpDir, "prism"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfgJSON := `{"provider":"ollama","model":"llama3","cache":{"enabled":false}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "prism", "config.json"), []byte(cfgJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	work := t.TempDir()
+	os.MkdirAll(filepath.Join(work, "vendor"), 0o755)
+	os.WriteFile(filepath.Join(work, "main.go"), []byte("package main\n"), 0o644)
+	os.WriteFile(filepath.Join(work, "vendor", "dep.go"), []byte("package dep\n"), 0o644)
+	list := filepath.Join(tmpDir, "changed.txt")
+	os.WriteFile(list, []byte("main.go\nvendor/dep.go\ngone.go\n\n"), 0o644)
+	origDir, _ := os.Getwd()
+	os.Chdir(work)
+	defer os.Chdir(origDir)
+
+	var prompt string
+	llm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		prompt = string(body)
+		io.WriteString(w, `{"choices":[{"message":{"content":"[]"}}]}`)
+	}))
+	defer llm.Close()
+	t.Setenv("OLLAMA_HOST", llm.URL)
+
+	flagFilesFrom = list
+	flagExclude = "vendor/**"
+	flagOut = filepath.Join(tmpDir, "report.json")
+	flagFormat = "json"
+
+	reviewFilesCmd.SetContext(context.Background())
+	if err := reviewFilesCmd.RunE(reviewFilesCmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exitCode != ExitSuccess {
+		t.Fatalf("exitCode = %d, want %d", exitCode, ExitSuccess)
+	}
+	if !strings.Contains(prompt, "main.go") {
+		t.Error("listed file main.go should be sent to the model")
+	}
+	if strings.Contains(prompt, "vendor/dep.go") {
+		t.Error("excluded vendor/dep.go should not be sent to the model")
+	}
+	out, _ := os.ReadFile(flagOut)
+	if !strings.Contains(string(out), `"warnings"`) || !strings.Contains(string(out), "gone.go") {
+		t.Errorf("report should warn about the missing gone.go, got:\n%s", out)
+	}
+}
+
+func TestReviewFilesCmd_RecordsHistory(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	savedExitCode := exitCode
+	t.Cleanup(func() { exitCode = savedExitCode })
+	exitCode = ExitSuccess
+
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	if err := os.MkdirAll(filepath.Join(tmpDir, "prism"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	historyFile := filepath.Join(tmpDir, "history.jsonl")
+	cfgJSON := fmt.Sprintf(`{"provider":"ollama","model":"llama3","cache":{"enabled":false},"history":{"enabled":true,"file":%q}}`, historyFile)
+	if err := os.WriteFile(filepath.Join(tmpDir, "prism", "config.json"), []byte(cfgJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	work := t.TempDir()
+	os.WriteFile(filepath.Join(work, "main.go"), []byte("package main\n"), 0o644)
+	list := filepath.Join(tmpDir, "changed.txt")
+	os.WriteFile(list, []byte("main.go\n"), 0o644)
+	origDir, _ := os.Getwd()
+	os.Chdir(work)
+	defer os.Chdir(origDir)
+
+	findings := `[{\"severity\":\"high\",\"category\":\"bug\",\"title\":\"Bad\",\"message\":\"m\",\"confidence\":0.9,\"path\":\"main.go\",\"startLine\":1,\"endLine\":1}]`
+	llm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"choices":[{"message":{"content":"`+findings+`"}}]}`)
+	}))
+	defer llm.Close()
+	t.Setenv("OLLAMA_HOST", llm.URL)
+
+	flagFilesFrom = list
+	flagOut = filepath.Join(tmpDir, "report.json")
+
+	reviewFilesCmd.SetContext(context.Background())
+	for i := 0; i < 2; i++ {
+		if err := reviewFilesCmd.RunE(reviewFilesCmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	records, err := history.Load(historyFile)
+	if err != nil {
+		t.Fatalf("loading history: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("history has %d runs, want 2", len(records))
+	}
+	if records[1].Counts.High != 1 || len(records[1].Findings) != 1 || records[1].Findings[0].Path != "main.go" {
+		t.Errorf("record = %+v", records[1])
+	}
+	if err := historyDiffCmd.RunE(historyDiffCmd, []string{"latest~1", "latest"}); err != nil {
+		t.Errorf("history diff: %v", err)
+	}
+	if err := historyDiffCmd.RunE(historyDiffCmd, []string{"latest~2", "latest"}); err == nil {
+		t.Error("history diff of a missing run should fail")
+	}
+}
+
+func TestReviewFilesCmd_RequiresFrom(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	savedExitCode := exitCode
+	t.Cleanup(func() { exitCode = savedExitCode })
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if err := reviewFilesCmd.RunE(reviewFilesCmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exitCode != ExitUsageError {
+		t.Errorf("exitCode = %d, want %d", exitCode, ExitUsageError)
+	}
+}
+
+func TestGitlabCmd_DryRun(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	savedExitCode := exitCode
+	t.Cleanup(func() { exitCode = savedExitCode })
+	exitCode = ExitSuccess
+
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	if err := os.MkdirAll(filepath.Join(tmpDir, "prism"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfgJSON := `{"provider":"ollama","model":"llama3","cache":{"enabled":false}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "prism", "config.json"), []byte(cfgJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var posted bool
+	gl := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			posted = true
+		}
+		io.WriteString(w, `{"diff_refs":{"base_sha":"b","head_sha":"h","start_sha":"s"},"changes":[`+
+			`{"old_path":"app.go","new_path":"app.go","diff":"@@ -1 +1,2 @@\n package app\n+var x = 1\n"},`+
+			`{"old_path":"vendor/dep.go","new_path":"vendor/dep.go","diff":"@@ -1 +1,2 @@\n package dep\n+var y = 2\n"}]}`)
+	}))
+	defer gl.Close()
+	t.Setenv("GITLAB_API_URL", gl.URL)
+	t.Setenv("GITLAB_TOKEN", "test-token")
+
+	var prompt string
+	llm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		prompt = string(body)
+		io.WriteString(w, `{"choices":[{"message":{"content":"[]"}}]}`)
+	}))
+	defer llm.Close()
+	t.Setenv("OLLAMA_HOST", llm.URL)
+
+	flagGLProject, flagGLDryRun = "group/app", true
+	flagExclude = "vendor/**"
+	flagOut = filepath.Join(tmpDir, "report.json")
+
+	gitlabCmd.SetContext(context.Background())
+	if err := gitlabCmd.RunE(gitlabCmd, []string{"!3"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exitCode != ExitSuccess {
+		t.Fatalf("exitCode = %d, want %d", exitCode, ExitSuccess)
+	}
+	if !strings.Contains(prompt, "app.go") || strings.Contains(prompt, "vendor/dep.go") {
+		t.Errorf("prompt should hold app.go but not the excluded vendor file:\n%s", prompt)
+	}
+	if posted {
+		t.Error("--dry-run should not post to GitLab")
+	}
+}
+
+// --- --patch tests ---
+
+func TestSelectPatch(t *testing.T) {
+	diff := gitctx.DiffResult{
+		Diff: "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1,2 @@\n package a\n+var x = 1\n" +
+			"@@ -9 +10,2 @@\n }\n+var y = 2\n" +
+			"diff --git a/b.go b/b.go\n--- a/b.go\n+++ b/b.go\n@@ -1 +1,2 @@\n package b\n+var z = 3\n",
+		Files: []string{"a.go", "b.go"},
+		Mode:  "unstaged",
+	}
+
+	var out strings.Builder
+	got := selectPatch(strings.NewReader("n\nmaybe\ny\nn\n"), &out, diff)
+
+	if strings.Contains(got.Diff, "var x") || !strings.Contains(got.Diff, "var y") || strings.Contains(got.Diff, "b.go") {
+		t.Errorf("expected only the second a.go hunk, got:\n%s", got.Diff)
+	}
+	if len(got.Files) != 1 || got.Files[0] != "a.go" {
+		t.Errorf("Files = %v, want [a.go]", got.Files)
+	}
+	if !strings.Contains(out.String(), "a.go (hunk 2/3)") || !strings.Contains(out.String(), "Please answer") {
+		t.Errorf("unexpected prompt output:\n%s", out.String())
+	}
+}
+
+func TestSelectPatch_AllAndQuit(t *testing.T) {
+	diff := gitctx.DiffResult{
+		Diff: "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1,2 @@\n package a\n+var x = 1\n" +
+			"diff --git a/b.go b/b.go\n--- a/b.go\n+++ b/b.go\n@@ -1 +1,2 @@\n package b\n+var z = 3\n",
+	}
+	if got := selectPatch(strings.NewReader("a\n"), io.Discard, diff); len(got.Files) != 2 {
+		t.Errorf("a: Files = %v, want both files", got.Files)
+	}
+	if got := selectPatch(strings.NewReader("q\n"), io.Discard, diff); got.Diff != "" {
+		t.Errorf("q: Diff = %q, want empty", got.Diff)
+	}
+	if got := selectPatch(strings.NewReader(""), io.Discard, diff); got.Diff != "" {
+		t.Errorf("EOF: Diff = %q, want empty", got.Diff)
+	}
+}
+
+func TestTriageFindings(t *testing.T) {
+	var findings []review.Finding
+	for _, key := range []string{"k1", "k2", "k3", "k4", "k5"} {
+		findings = append(findings, review.Finding{
+			Severity:  review.SeverityMedium,
+			Category:  review.CategoryBug,
+			Title:     "Finding " + key,
+			StableKey: key,
+			Locations: []review.Location{{Path: "a.go", Lines: review.LineRange{Start: 1, End: 1}}},
+		})
+	}
+	var out strings.Builder
+	kept, res := triageFindings(strings.NewReader("d\nd\nx\nf\nq\n"), &out, findings, nil)
+	var keys []string
+	for _, f := range kept {
+		keys = append(keys, f.StableKey)
+	}
+	if strings.Join(keys, ",") != "k3,k4,k5" {
+		t.Errorf("kept = %v, want k3,k4,k5", keys)
+	}
+	if len(res.dismissed) != 2 || res.dismissed[0].StableKey != "k1" || res.dismissed[1].StableKey != "k2" {
+		t.Errorf("dismissed = %v, want k1 and k2", res.dismissed)
+	}
+	if res.fixLater != 1 || len(kept[0].Tags) != 1 || kept[0].Tags[0] != fixLaterTag {
+		t.Errorf("k3 should be tagged %s: %+v", fixLaterTag, kept[0])
+	}
+	if res.accepted != 2 {
+		t.Errorf("accepted = %d, want 2 (q accepts the rest)", res.accepted)
+	}
+	if !strings.Contains(out.String(), "Please answer a, d, f, or q.") {
+		t.Error("an invalid answer should be re-prompted")
+	}
+	if strings.Contains(out.String(), "Finding k5") {
+		t.Error("findings after q should not be shown")
+	}
+}
+
 // --- review command structure tests ---
 
 func TestReviewCmd_HasSubcommands(t *testing.T) {
@@ -501,7 +1036,10 @@ func TestReviewCmd_HasSubcommands(t *testing.T) {
 		"staged":   false,
 		"commit":   false,
 		"range":    false,
+		"branch":   false,
 		"snippet":  false,
+		"dir":      false,
+		"files":    false,
 	}
 
 	for _, sub := range reviewCmd.Commands() {
@@ -568,3 +1106,870 @@ func TestVersionConstant(t *testing.T) {
 		t.Error("version constant is empty")
 	}
 }
+
+func TestBuildOverrides_RulesPack(t *testing.T) {
+	resetFlags()
+	defer resetFlags()
+
+	flagRulesPack = "go-security"
+	m := buildOverrides()
+	if m["rulesFile"] != "pack:go-security" {
+		t.Errorf("rulesFile = %q, want %q", m["rulesFile"], "pack:go-security")
+	}
+
+	flagRules = "rules.json"
+	m = buildOverrides()
+	if m["rulesFile"] != "rules.json" {
+		t.Errorf("rulesFile = %q, want --rules to take precedence", m["rulesFile"])
+	}
+}
+
+// --- format command tests ---
+
+func TestReadReport_Valid(t *testing.T) {
+	input := `{"tool":"prism","version":"0.5.0","runId":"abc","findings":[{"id":"1","severity":"high","category":"bug","title":"T","message":"M","confidence":0.9,"locations":[{"path":"a.go","lines":{"start":1,"end":2}}]}]}`
+	report, err := readReport(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("readReport error: %v", err)
+	}
+	if len(report.Findings) != 1 || report.Findings[0].Title != "T" {
+		t.Errorf("unexpected findings: %+v", report.Findings)
+	}
+}
+
+func TestReadReport_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"empty":            "",
+		"malformed":        "{not json",
+		"not a report":     `{"foo":"bar"}`,
+		"invalid severity": `{"tool":"prism","findings":[{"severity":"urgent"}]}`,
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := readReport(strings.NewReader(input)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestFormatCmd_Markdown(t *testing.T) {
+	resetFlags()
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	savedExitCode := exitCode
+	t.Cleanup(func() { exitCode = savedExitCode })
+	exitCode = ExitSuccess
+
+	outPath := filepath.Join(tmpDir, "report.md")
+	formatCmd.SetIn(strings.NewReader(`{"tool":"prism","version":"0.5.0","runId":"abc","findings":[]}`))
+	formatCmd.SetArgs([]string{"--format", "markdown", "--out", outPath})
+	if err := formatCmd.Execute(); err != nil {
+		t.Fatalf("format returned error: %v", err)
+	}
+	if exitCode != ExitSuccess {
+		t.Fatalf("exitCode = %d, want %d", exitCode, ExitSuccess)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "#") {
+		t.Errorf("expected markdown output, got: %s", data)
+	}
+}
+
+func TestFormatCmd_BadInput(t *testing.T) {
+	resetFlags()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	savedExitCode := exitCode
+	t.Cleanup(func() { exitCode = savedExitCode })
+	exitCode = ExitSuccess
+
+	formatCmd.SetIn(strings.NewReader("not json"))
+	formatCmd.SetArgs([]string{"--format", "json"})
+	if err := formatCmd.Execute(); err != nil {
+		t.Fatalf("format returned error: %v", err)
+	}
+	if exitCode != ExitUsageError {
+		t.Errorf("exitCode = %d, want %d (ExitUsageError)", exitCode, ExitUsageError)
+	}
+}
+
+// --- metadata tests ---
+
+func TestBuildMetadata(t *testing.T) {
+	metaPath := filepath.Join(t.TempDir(), "meta.json")
+	if err := os.WriteFile(metaPath, []byte(`{"env":"staging","team":"core"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := buildMetadata(metaPath, []string{"env=prod", "ticket=PRJ-42", "note=a=b"})
+	if err != nil {
+		t.Fatalf("buildMetadata error: %v", err)
+	}
+	want := map[string]string{"env": "prod", "team": "core", "ticket": "PRJ-42", "note": "a=b"}
+	if len(meta) != len(want) {
+		t.Fatalf("got %v, want %v", meta, want)
+	}
+	for k, v := range want {
+		if meta[k] != v {
+			t.Errorf("meta[%q] = %q, want %q", k, meta[k], v)
+		}
+	}
+}
+
+func TestBuildMetadata_Empty(t *testing.T) {
+	meta, err := buildMetadata("", nil)
+	if err != nil {
+		t.Fatalf("buildMetadata error: %v", err)
+	}
+	if meta != nil {
+		t.Errorf("expected nil metadata, got %v", meta)
+	}
+}
+
+func TestBuildMetadata_Invalid(t *testing.T) {
+	if _, err := buildMetadata("", []string{"novalue"}); err == nil {
+		t.Error("expected error for tag without '='")
+	}
+	if _, err := buildMetadata("", []string{"=value"}); err == nil {
+		t.Error("expected error for tag without key")
+	}
+	badPath := filepath.Join(t.TempDir(), "meta.json")
+	if err := os.WriteFile(badPath, []byte(`{"n":1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := buildMetadata(badPath, nil); err == nil {
+		t.Error("expected error for non-string meta values")
+	}
+}
+
+func TestLoadRunInputs_Baseline(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+
+	flagBaseline = filepath.Join(t.TempDir(), "missing.json")
+	if err := loadRunInputs(); err == nil {
+		t.Error("a missing --baseline should fail before the review")
+	}
+
+	flagBaseline = filepath.Join(t.TempDir(), "baseline.json")
+	os.WriteFile(flagBaseline, []byte(`{"tool":"prism","findings":[{"id":"f1","severity":"high","title":"Bad"}]}`), 0o644)
+	if err := loadRunInputs(); err != nil {
+		t.Fatalf("loadRunInputs error: %v", err)
+	}
+	if baselineReport == nil || len(baselineReport.Findings) != 1 {
+		t.Errorf("baselineReport = %+v, want the loaded report", baselineReport)
+	}
+}
+
+func TestLoadRunInputs_Metadata(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+
+	flagTags = []string{"novalue"}
+	if err := loadRunInputs(); err == nil {
+		t.Error("a malformed --tag should fail before the review")
+	}
+	flagTags = []string{"env=prod"}
+	flagMetaFile = filepath.Join(t.TempDir(), "missing.json")
+	if err := loadRunInputs(); err == nil {
+		t.Error("a missing --meta-file should fail before the review")
+	}
+	flagMetaFile = ""
+	if err := loadRunInputs(); err != nil {
+		t.Fatalf("loadRunInputs error: %v", err)
+	}
+	if runMetadata["env"] != "prod" {
+		t.Errorf("runMetadata = %v, want env=prod", runMetadata)
+	}
+}
+
+func TestLoadRunInputs_SARIFSuppressions(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+
+	flagSARIFSuppressions = filepath.Join(t.TempDir(), "missing.json")
+	if err := loadRunInputs(); err == nil {
+		t.Error("a missing --sarif-suppressions file should fail before the review")
+	}
+
+	flagSARIFSuppressions = filepath.Join(t.TempDir(), "sups.json")
+	os.WriteFile(flagSARIFSuppressions, []byte(`[{"ruleId":"prism/security"}]`), 0o644)
+	if err := loadRunInputs(); err != nil {
+		t.Fatalf("loadRunInputs error: %v", err)
+	}
+	if opts := writerOptions(config.Default()); len(opts.SARIFSuppressions) != 1 {
+		t.Errorf("SARIFSuppressions = %v, want the loaded entry", opts.SARIFSuppressions)
+	}
+}
+
+func TestTriageScreen(t *testing.T) {
+	var findings []review.Finding
+	for _, key := range []string{"k1", "k2", "k3", "k4"} {
+		findings = append(findings, review.Finding{
+			Severity:  review.SeverityMedium,
+			Category:  review.CategoryBug,
+			Title:     "Finding " + key,
+			StableKey: key,
+			Locations: []review.Location{{Path: "a.go", Lines: review.LineRange{Start: 1, End: 1}}},
+		})
+	}
+
+	// Dismiss k1, tag k2, page back and accept k2 instead, skip k3, ignore
+	// an unknown key, and finish on k4.
+	var out strings.Builder
+	kept, res := triageScreen(strings.NewReader("df\x1b[Dan?q"), &out, findings, nil)
+	var keys []string
+	for _, f := range kept {
+		keys = append(keys, f.StableKey)
+	}
+	if strings.Join(keys, ",") != "k2,k3,k4" {
+		t.Errorf("kept = %v, want k2,k3,k4", keys)
+	}
+	if len(res.dismissed) != 1 || res.dismissed[0].StableKey != "k1" {
+		t.Errorf("dismissed = %v, want k1", res.dismissed)
+	}
+	if res.fixLater != 0 || res.accepted != 3 || len(kept[0].Tags) != 0 {
+		t.Errorf("fixLater = %d, accepted = %d; the later decision on k2 should win and undecided findings be accepted", res.fixLater, res.accepted)
+	}
+	screen := out.String()
+	if !strings.Contains(screen, "Finding 2/4  [fix later]") {
+		t.Error("paging back should show the earlier decision")
+	}
+	if !strings.HasPrefix(screen, enterAltScreen) || !strings.HasSuffix(screen, leaveAltScreen) {
+		t.Error("triage should run on the alternate screen and restore it")
+	}
+
+	// Moving past the last finding finishes; end of input accepts the rest
+	kept, res = triageScreen(strings.NewReader("ff"), io.Discard, findings[:3], nil)
+	if len(kept) != 3 || res.fixLater != 2 || res.accepted != 1 || kept[1].Tags[0] != fixLaterTag {
+		t.Errorf("kept = %v, res = %+v", kept, res)
+	}
+}
+
+func TestLoadRunInputs_Dismissals(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+
+	work := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", work).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	sub := filepath.Join(work, "pkg")
+	os.Mkdir(sub, 0o755)
+	t.Chdir(sub)
+
+	if err := loadRunInputs(); err != nil || dismissals != nil {
+		t.Fatalf("without a dismissals file: dismissals = %v, err = %v", dismissals, err)
+	}
+
+	// The file is read from the repository root, not the working directory
+	os.WriteFile(filepath.Join(work, dismissalsFile), []byte(`[{"fingerprint":"k1"}]`), 0o644)
+	if err := loadRunInputs(); err != nil {
+		t.Fatalf("loadRunInputs error: %v", err)
+	}
+	report := &review.Report{Findings: []review.Finding{
+		{Severity: review.SeverityHigh, Title: "one", StableKey: "k1"},
+		{Severity: review.SeverityLow, Title: "two", StableKey: "k2"},
+	}}
+	if n := applyDismissals(report); n != 1 {
+		t.Errorf("applyDismissals = %d, want 1", n)
+	}
+	if len(report.Findings) != 1 || report.Findings[0].StableKey != "k2" || report.Summary.Counts.High != 0 {
+		t.Errorf("report = %+v, want only k2 left and the summary recomputed", report)
+	}
+
+	os.WriteFile(filepath.Join(work, dismissalsFile), []byte(`{`), 0o644)
+	if err := loadRunInputs(); err == nil {
+		t.Error("an invalid dismissals file should fail before the review")
+	}
+}
+
+func TestApplyRequireTests(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	cfg := config.Default()
+	files := []string{"internal/foo/foo.go"}
+
+	report := &review.Report{}
+	if applyRequireTests(report, files, cfg) {
+		t.Fatal("policy should not apply without --require-tests")
+	}
+
+	flagRequireTests = true
+	if !applyRequireTests(report, files, cfg) {
+		t.Fatal("expected policy violation for code-only change")
+	}
+	if len(report.Findings) != 1 || report.Findings[0].ID != review.RequireTestsID {
+		t.Fatalf("expected require-tests finding, got %+v", report.Findings)
+	}
+	if report.Summary.Counts.Medium != 1 {
+		t.Errorf("summary not recomputed: %+v", report.Summary)
+	}
+
+	report = &review.Report{}
+	if applyRequireTests(report, append(files, "internal/foo/foo_test.go"), cfg) {
+		t.Error("policy should pass when a test file changed")
+	}
+}
+
+func TestApplyPostHook(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	cfg := config.Default()
+	report := review.BuildReport(gitctx.DiffResult{Mode: "staged"}, []review.Finding{
+		{ID: "a", Severity: review.SeverityLow, Category: review.CategoryStyle, Title: "old title"},
+	}, 0, 0)
+
+	same, err := applyPostHook(context.Background(), report, cfg)
+	if err != nil || same != report {
+		t.Fatalf("without --post-hook the report should pass through, got %v, %v", same, err)
+	}
+
+	flagPostHook = `sed -e 's/old title/new title/' -e 's/"severity":"low"/"severity":"high"/'`
+	got, err := applyPostHook(context.Background(), report, cfg)
+	if err != nil {
+		t.Fatalf("applyPostHook error: %v", err)
+	}
+	if got.Findings[0].Title != "new title" {
+		t.Errorf("title = %q, want hook output", got.Findings[0].Title)
+	}
+	if got.Summary.Counts.High != 1 || got.Summary.Verdict != review.VerdictBlock {
+		t.Errorf("summary not recomputed from hook output: %+v", got.Summary)
+	}
+
+	for _, hook := range []string{"echo not-json", "exit 3"} {
+		flagPostHook = hook
+		if _, err := applyPostHook(context.Background(), report, cfg); err == nil {
+			t.Errorf("expected error for hook %q", hook)
+		}
+	}
+}
+
+// --- init command tests ---
+
+func TestDetectProviders_KeysFirst(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("GEMINI_API_KEY", "test-key")
+	t.Setenv("MISTRAL_API_KEY", "")
+	t.Setenv("OPENROUTER_API_KEY", "")
+
+	choices := detectProviders()
+	if len(choices) != 6 {
+		t.Fatalf("got %d choices, want 6", len(choices))
+	}
+	if choices[0].name != "gemini" || !choices[0].detected {
+		t.Errorf("first choice = %+v, want detected gemini", choices[0])
+	}
+	for _, c := range choices {
+		if c.name == "anthropic" && c.detected {
+			t.Error("anthropic should not be detected without a key")
+		}
+	}
+}
+
+func TestRunSetup_WritesConfig(t *testing.T) {
+	resetFlags()
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "test-key")
+	t.Setenv("GEMINI_API_KEY", "")
+
+	// Provider 1 is openai (the only detected key); pick its second model
+	// after one invalid entry.
+	var out strings.Builder
+	if err := runSetup(strings.NewReader("1\n9\n2\n"), &out); err != nil {
+		t.Fatalf("runSetup error: %v", err)
+	}
+
+	cfg, err := config.LoadFile()
+	if err != nil {
+		t.Fatalf("LoadFile error: %v", err)
+	}
+	if cfg.Provider != "openai" {
+		t.Errorf("Provider = %q, want %q", cfg.Provider, "openai")
+	}
+	if cfg.Model != knownModels[1].Models[1] {
+		t.Errorf("Model = %q, want %q", cfg.Model, knownModels[1].Models[1])
+	}
+	if cfg.Format == "" {
+		t.Error("other settings should keep their defaults")
+	}
+	if !strings.Contains(out.String(), "between 1 and") {
+		t.Error("expected a re-prompt for the invalid choice")
+	}
+}
+
+func TestRunSetup_DefaultsOnEmptyInput(t *testing.T) {
+	resetFlags()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+
+	if err := runSetup(strings.NewReader(""), io.Discard); err != nil {
+		t.Fatalf("runSetup error: %v", err)
+	}
+	cfg, err := config.LoadFile()
+	if err != nil {
+		t.Fatalf("LoadFile error: %v", err)
+	}
+	if cfg.Provider != "anthropic" || cfg.Model != knownModels[0].Models[0] {
+		t.Errorf("got %s/%s, want first provider and model", cfg.Provider, cfg.Model)
+	}
+}
+
+// --- --timeout tests ---
+
+func TestRunReview_Timeout(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	savedExitCode := exitCode
+	t.Cleanup(func() { exitCode = savedExitCode })
+	exitCode = ExitSuccess
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	cfg := config.Default()
+	cfg.Provider = "ollama"
+	cfg.Model = "llama3"
+	cfg.Cache.Enabled = false
+	flagOut = filepath.Join(t.TempDir(), "report.json")
+
+	flagTimeout = 50 * time.Millisecond
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	applyTimeout(cmd)
+	t.Cleanup(cancelTimeout)
+
+	diff := gitctx.DiffResult{
+		Diff:  "diff --git a/x.go b/x.go\n--- a/x.go\n+++ b/x.go\n@@ -0,0 +1 @@\n+package x\n",
+		Files: []string{"x.go"},
+		Mode:  "snippet",
+	}
+	runReview(cmd.Context(), diff, cfg)
+
+	if exitCode != ExitRuntimeError {
+		t.Errorf("exitCode = %d, want %d (ExitRuntimeError)", exitCode, ExitRuntimeError)
+	}
+	if _, err := os.Stat(flagOut); err == nil {
+		t.Error("no report should be written after a timeout")
+	}
+}
+
+// --- badge tests ---
+
+func TestBadgeCmd_SVGFromFile(t *testing.T) {
+	resetFlags()
+	savedExitCode := exitCode
+	t.Cleanup(func() { exitCode = savedExitCode })
+	exitCode = ExitSuccess
+
+	tmpDir := t.TempDir()
+	in := filepath.Join(tmpDir, "report.json")
+	report := `{"tool":"prism","summary":{"counts":{"low":1,"medium":0,"high":2},"highestSeverity":"high"},"findings":[]}`
+	if err := os.WriteFile(in, []byte(report), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	outPath := filepath.Join(tmpDir, "badge.svg")
+
+	badgeCmd.SetArgs([]string{"--from", in, "--out", outPath})
+	if err := badgeCmd.Execute(); err != nil {
+		t.Fatalf("badge returned error: %v", err)
+	}
+	if exitCode != ExitSuccess {
+		t.Fatalf("exitCode = %d, want %d", exitCode, ExitSuccess)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "<svg") || !strings.Contains(string(data), "2 high | 0 medium | 1 low") {
+		t.Errorf("unexpected badge:\n%s", data)
+	}
+}
+
+func TestBadgeCmd_BadFormat(t *testing.T) {
+	resetFlags()
+	savedExitCode := exitCode
+	t.Cleanup(func() { exitCode = savedExitCode })
+	exitCode = ExitSuccess
+
+	badgeCmd.SetIn(strings.NewReader(`{"tool":"prism","findings":[]}`))
+	badgeCmd.SetArgs([]string{"--format", "png"})
+	if err := badgeCmd.Execute(); err != nil {
+		t.Fatalf("badge returned error: %v", err)
+	}
+	if exitCode != ExitUsageError {
+		t.Errorf("exitCode = %d, want %d (ExitUsageError)", exitCode, ExitUsageError)
+	}
+}
+
+func TestSnippetFiles(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.go")
+	b := filepath.Join(dir, "pkg", "b.py")
+	if err := os.MkdirAll(filepath.Dir(b), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(a, []byte("package a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("print('b')"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := snippetFiles([]string{a, b}, "", gitctx.DiffOptions{})
+	if err != nil {
+		t.Fatalf("snippetFiles error: %v", err)
+	}
+	if diff.Mode != "snippet" {
+		t.Errorf("Mode = %q, want snippet", diff.Mode)
+	}
+	wantFiles := []string{filepath.ToSlash(a), filepath.ToSlash(b)}
+	if strings.Join(diff.Files, ",") != strings.Join(wantFiles, ",") {
+		t.Errorf("Files = %v, want %v", diff.Files, wantFiles)
+	}
+	for _, f := range wantFiles {
+		if !strings.Contains(diff.Diff, "+++ b/"+f) {
+			t.Errorf("diff missing section for %s:\n%s", f, diff.Diff)
+		}
+	}
+	if !strings.Contains(diff.Diff, "+print('b')\n") {
+		t.Errorf("file without trailing newline should still end its section:\n%s", diff.Diff)
+	}
+
+	if _, err := snippetFiles([]string{filepath.Join(dir, "missing.go")}, "", gitctx.DiffOptions{}); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	// Register restores for every variable the file may set.
+	for _, k := range []string{"ANTHROPIC_API_KEY", "PRISM_TEST_QUOTED", "PRISM_TEST_EXPORTED", "PRISM_TEST_PRESET"} {
+		t.Setenv(k, "")
+		os.Unsetenv(k)
+	}
+	t.Setenv("PRISM_TEST_PRESET", "from-env")
+
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "# keys\n\nANTHROPIC_API_KEY=sk-test-123 # comment\n" +
+		"PRISM_TEST_QUOTED=\"a # b\"\nexport PRISM_TEST_EXPORTED='x'\nPRISM_TEST_PRESET=from-file\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := loadEnvFile(path); err != nil {
+		t.Fatalf("loadEnvFile error: %v", err)
+	}
+	for k, want := range map[string]string{
+		"ANTHROPIC_API_KEY":   "sk-test-123",
+		"PRISM_TEST_QUOTED":   "a # b",
+		"PRISM_TEST_EXPORTED": "x",
+		"PRISM_TEST_PRESET":   "from-env",
+	} {
+		if got := os.Getenv(k); got != want {
+			t.Errorf("%s = %q, want %q", k, got, want)
+		}
+	}
+	if _, err := providers.New("anthropic", "claude-sonnet-4-6"); err != nil {
+		t.Errorf("provider should pick up the key from the env file: %v", err)
+	}
+}
+
+func TestLoadEnvFile_BadLineHidesValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("OK=1\nnot a valid line sk-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("OK", "")
+	os.Unsetenv("OK")
+
+	err := loadEnvFile(path)
+	if err == nil {
+		t.Fatal("expected error for malformed line")
+	}
+	if !strings.Contains(err.Error(), ":2:") || strings.Contains(err.Error(), "sk-secret") {
+		t.Errorf("error should name the line without its contents: %v", err)
+	}
+}
+
+// --- review combined tests ---
+
+func TestParseCombinedSpec(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    combinedSpec
+		wantErr bool
+	}{
+		{"staged", combinedSpec{Mode: "staged"}, false},
+		{"codebase", combinedSpec{Mode: "codebase"}, false},
+		{"commit:abc123", combinedSpec{Mode: "commit", Arg: "abc123"}, false},
+		{"range:main..HEAD", combinedSpec{Mode: "range", Arg: "main..HEAD"}, false},
+		{"dir:./src", combinedSpec{Mode: "dir", Arg: "./src"}, false},
+		{"staged:x", combinedSpec{}, true},
+		{"commit", combinedSpec{}, true},
+		{"branch", combinedSpec{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseCombinedSpec(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseCombinedSpec(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseCombinedSpec(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestRunCombinedReview_MergesSources(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	savedExitCode := exitCode
+	t.Cleanup(func() { exitCode = savedExitCode })
+	exitCode = ExitSuccess
+
+	finding := `[{"severity":"medium","category":"bug","title":"Shared issue","message":"m","path":"x.go","startLine":1,"endLine":1}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"content": finding}}},
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	var specs []combinedSpec
+	for _, name := range []string{"a", "b"} {
+		dir := filepath.Join(t.TempDir(), name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "x.go"), []byte("package x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		specs = append(specs, combinedSpec{Mode: "dir", Arg: dir})
+	}
+
+	cfg := config.Default()
+	cfg.Provider = "ollama"
+	cfg.Model = "llama3"
+	cfg.Cache.Enabled = false
+	cfg.Format = "json"
+	cfg.FailOn = "none"
+	flagOut = filepath.Join(t.TempDir(), "report.json")
+
+	runCombinedReview(context.Background(), specs, cfg)
+	if exitCode != ExitSuccess {
+		t.Fatalf("exitCode = %d, want %d", exitCode, ExitSuccess)
+	}
+
+	data, err := os.ReadFile(flagOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report review.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Inputs.Mode != "combined" {
+		t.Errorf("Inputs.Mode = %q, want combined", report.Inputs.Mode)
+	}
+	if len(report.Findings) != 1 {
+		t.Errorf("got %d findings, want 1 after dedup", len(report.Findings))
+	}
+	if len(report.Inputs.Sources) != 2 {
+		t.Fatalf("got %d sources, want 2", len(report.Inputs.Sources))
+	}
+	for _, s := range report.Inputs.Sources {
+		if s.Mode != "dir" || s.Files != 1 || s.Findings != 1 {
+			t.Errorf("source = %+v, want dir with 1 file and 1 finding", s)
+		}
+	}
+}
+
+func TestConsoleSummary(t *testing.T) {
+	report := &review.Report{
+		Summary: review.Summary{
+			Counts:  review.SeverityCounts{High: 1, Low: 2},
+			Verdict: review.VerdictBlock,
+		},
+		Stats: review.DiffStats{FilesChanged: 3},
+	}
+	got := consoleSummary(report, "report.json")
+	want := "prism: 1 high, 0 medium, 2 low in 3 files (BLOCK); report written to report.json"
+	if got != want {
+		t.Errorf("consoleSummary = %q, want %q", got, want)
+	}
+
+	report.Summary.Verdict = ""
+	if got := consoleSummary(report, "out.md"); strings.Contains(got, "(") {
+		t.Errorf("consoleSummary without a verdict = %q, want no verdict", got)
+	}
+}
+
+func TestReviewRangeCmd_PerCommit(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	savedExitCode := exitCode
+	t.Cleanup(func() { exitCode = savedExitCode })
+	exitCode = ExitSuccess
+
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	if err := os.MkdirAll(filepath.Join(tmpDir, "prism"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfgJSON := `{"provider":"ollama","model":"llama3","cache":{"enabled":false}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "prism", "config.json"), []byte(cfgJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	work := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = work
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	for _, name := range []string{"base.go", "a.go", "b.go", "c.go"} {
+		os.WriteFile(filepath.Join(work, name), []byte("package main\n\nvar "+strings.TrimSuffix(name, ".go")+" = 1\n"), 0o644)
+		git("add", name)
+		git("commit", "-q", "-m", "add "+name)
+	}
+	origDir, _ := os.Getwd()
+	os.Chdir(work)
+	defer os.Chdir(origDir)
+
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	llm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		path := "a.go"
+		for _, p := range []string{"b.go", "c.go"} {
+			if strings.Contains(string(body), "+++ b/"+p) {
+				path = p
+			}
+		}
+		content := fmt.Sprintf(`[{"severity":"medium","category":"bug","title":"Issue in %s","message":"m","path":"%s","startLine":3,"endLine":3}]`, path, path)
+		json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{{"message": map[string]string{"content": content}}}})
+	}))
+	defer llm.Close()
+	t.Setenv("OLLAMA_HOST", llm.URL)
+
+	flagPerCommit = true
+	flagConcurrency = 2
+	flagFormat = "json"
+	flagOut = filepath.Join(tmpDir, "report.json")
+
+	reviewRangeCmd.SetContext(context.Background())
+	if err := reviewRangeCmd.RunE(reviewRangeCmd, []string{"HEAD~3..HEAD"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exitCode != ExitSuccess {
+		t.Fatalf("exitCode = %d, want %d", exitCode, ExitSuccess)
+	}
+
+	data, err := os.ReadFile(flagOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report review.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	commits := make(map[string]string)
+	for _, f := range report.Findings {
+		loc := f.Locations[0]
+		if loc.Commit == "" {
+			t.Errorf("finding %q has no commit", f.Title)
+		}
+		commits[loc.Path] = loc.Commit
+	}
+	if len(commits) != 3 || commits["a.go"] == commits["b.go"] || commits["b.go"] == commits["c.go"] {
+		t.Errorf("want one finding per commit with distinct SHAs, got %v", commits)
+	}
+	if flagGroupBy != "" {
+		t.Errorf("flagGroupBy = %q, the per-commit default must not leak into later reviews", flagGroupBy)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if peak < 2 {
+		t.Errorf("peak concurrent requests = %d, want commits reviewed in parallel", peak)
+	}
+	if peak > 2 {
+		t.Errorf("peak concurrent requests = %d, want at most 2 (--concurrency)", peak)
+	}
+}
+
+func TestReviewRange_PerCommitAuthError(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	savedExitCode := exitCode
+	t.Cleanup(func() { exitCode = savedExitCode })
+	exitCode = ExitSuccess
+
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	os.MkdirAll(filepath.Join(tmpDir, "prism"), 0o755)
+	os.WriteFile(filepath.Join(tmpDir, "prism", "config.json"), []byte(`{"provider":"ollama","model":"llama3","cache":{"enabled":false}}`), 0o644)
+
+	work := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = work
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	for _, name := range []string{"base.go", "a.go", "b.go"} {
+		os.WriteFile(filepath.Join(work, name), []byte("package main\n"), 0o644)
+		git("add", name)
+		git("commit", "-q", "-m", "add "+name)
+	}
+	t.Chdir(work)
+
+	llm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid API key", http.StatusUnauthorized)
+	}))
+	defer llm.Close()
+	t.Setenv("OLLAMA_HOST", llm.URL)
+
+	flagPerCommit = true
+	flagFormat = "json"
+	flagOut = filepath.Join(tmpDir, "report.json")
+
+	reviewRangeCmd.SetContext(context.Background())
+	if err := reviewRangeCmd.RunE(reviewRangeCmd, []string{"HEAD~2..HEAD"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exitCode != ExitAuthError {
+		t.Errorf("exitCode = %d, want %d for a rejected key", exitCode, ExitAuthError)
+	}
+	if _, err := os.Stat(flagOut); err == nil {
+		t.Error("no report should be written when authentication fails")
+	}
+}
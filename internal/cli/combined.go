@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dshills/prism/internal/config"
+	"github.com/dshills/prism/internal/gitctx"
+	"github.com/dshills/prism/internal/providers"
+	"github.com/dshills/prism/internal/review"
+	"github.com/spf13/cobra"
+)
+
+// flagCombinedModes holds the --mode specs for review combined.
+var flagCombinedModes []string
+
+// combinedSpec is one parsed --mode spec: a diff mode and its argument.
+type combinedSpec struct {
+	Mode string
+	Arg  string
+}
+
+// parseCombinedSpec parses a --mode spec: unstaged, staged, codebase,
+// commit:<sha>, range:<revRange>, or dir:<path>.
+func parseCombinedSpec(spec string) (combinedSpec, error) {
+	mode, arg, hasArg := strings.Cut(strings.TrimSpace(spec), ":")
+	switch mode {
+	case "unstaged", "staged", "codebase":
+		if hasArg {
+			return combinedSpec{}, fmt.Errorf("mode %q takes no argument", mode)
+		}
+	case "commit", "range", "dir":
+		if arg == "" {
+			return combinedSpec{}, fmt.Errorf("mode %q requires an argument (%s:<value>)", mode, mode)
+		}
+	default:
+		return combinedSpec{}, fmt.Errorf("unknown mode %q (use unstaged, staged, codebase, commit:<sha>, range:<revRange>, or dir:<path>)", spec)
+	}
+	return combinedSpec{Mode: mode, Arg: arg}, nil
+}
+
+// wholeFile reports whether the spec reviews whole files rather than a diff.
+func (s combinedSpec) wholeFile() bool {
+	return s.Mode == "codebase" || s.Mode == "dir"
+}
+
+func (s combinedSpec) String() string {
+	if s.Arg == "" {
+		return s.Mode
+	}
+	return s.Mode + ":" + s.Arg
+}
+
+// collectCombinedDiff builds the diff for one spec.
+func collectCombinedDiff(s combinedSpec, cfg config.Config) (gitctx.DiffResult, error) {
+	switch s.Mode {
+	case "unstaged":
+		return gitctx.Unstaged(buildDiffOpts(cfg))
+	case "staged":
+		return gitctx.Staged(buildDiffOpts(cfg))
+	case "commit":
+		return gitctx.Commit(s.Arg, "", buildDiffOpts(cfg))
+	case "range":
+		return gitctx.Range(s.Arg, true, buildDiffOpts(cfg))
+	}
+	opts, err := wholeFileDiffOpts(cfg)
+	if err != nil {
+		return gitctx.DiffResult{}, err
+	}
+	if s.Mode == "dir" {
+		return gitctx.Dir(s.Arg, opts)
+	}
+	return gitctx.Codebase(opts)
+}
+
+var reviewCombinedCmd = &cobra.Command{
+	Use:   "combined",
+	Short: "Review several diff modes and merge the findings into one report",
+	Long: `Review each --mode in turn and merge the findings, deduplicated by ID,
+into a single report with mode "combined". The report's inputs list each
+source with its file and finding counts.
+
+Modes: unstaged, staged, codebase, commit:<sha>, range:<revRange>, dir:<path>.
+
+Example:
+  prism review combined --mode staged --mode unstaged`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		if len(flagCombinedModes) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: at least one --mode is required")
+			exitCode = ExitUsageError
+			return nil
+		}
+		if flagCompare != "" {
+			fmt.Fprintln(os.Stderr, "Error: --compare is not supported with review combined")
+			exitCode = ExitUsageError
+			return nil
+		}
+		var specs []combinedSpec
+		for _, m := range flagCombinedModes {
+			s, err := parseCombinedSpec(m)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: --mode: %v\n", err)
+				exitCode = ExitUsageError
+				return nil
+			}
+			specs = append(specs, s)
+		}
+		runCombinedReview(cmd.Context(), specs, cfg)
+		return nil
+	},
+}
+
+func runCombinedReview(ctx context.Context, specs []combinedSpec, cfg config.Config) {
+	if flagNoRedact {
+		cfg.Privacy.RedactSecrets = false
+		fmt.Fprintln(os.Stderr, "WARNING: secret and path redaction are disabled")
+	}
+
+	startTime := time.Now()
+
+	var allFindings []review.Finding
+	var sources []review.SourceInfo
+	var totalLLMMs int64
+	var reviewedDiffs strings.Builder
+	var changedFiles []string
+
+	for i, s := range specs {
+		fmt.Fprintf(os.Stderr, "Reviewing %d/%d: %s\n", i+1, len(specs), s)
+
+		diff, err := collectCombinedDiff(s, cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", s, err)
+			exitCode = ExitRuntimeError
+			return
+		}
+		source := review.SourceInfo{Mode: diff.Mode, Range: diff.Range, Files: len(diff.Files)}
+		if strings.TrimSpace(diff.Diff) == "" {
+			fmt.Fprintf(os.Stderr, "  Skipping (empty diff)\n")
+			sources = append(sources, source)
+			continue
+		}
+		noteMetadataOnly(diff)
+
+		var report *review.Report
+		if s.wholeFile() {
+			report, err = review.RunCodebase(ctx, diff, review.CodebaseConfig{
+				Config:             cfg,
+				MaxFindingsPerFile: flagMaxFindingsPerFile,
+				MaxFilesPerChunk:   flagMaxFilesPerChunk,
+				OnFinding:          streamFindings(cfg),
+			})
+		} else {
+			report, err = review.RunWithOptions(ctx, diff, cfg, review.Options{OnFinding: streamFindings(cfg)})
+		}
+		if err != nil {
+			if providers.IsAuthError(err) {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exitCode = ExitAuthError
+				return
+			}
+			if providers.IsModelError(err) {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exitCode = ExitUsageError
+				return
+			}
+			if timedOut(ctx) {
+				fmt.Fprintf(os.Stderr, "Error: review timed out after %s (--timeout)\n", flagTimeout)
+				exitCode = ExitRuntimeError
+				return
+			}
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", s, err)
+			exitCode = ExitRuntimeError
+			return
+		}
+
+		if !s.wholeFile() {
+			applyNewCodeOnly(report, diff.Diff)
+		}
+		applyDropNoop(report, diff.Diff)
+		if flagWithHunks {
+			review.AttachHunks(report.Findings, review.RedactDiff(diff.Diff, cfg))
+		}
+
+		source.Findings = len(report.Findings)
+		sources = append(sources, source)
+		allFindings = append(allFindings, report.Findings...)
+		totalLLMMs += report.Timing.LLMMs
+		reviewedDiffs.WriteString(diff.Diff)
+		if !s.wholeFile() {
+			changedFiles = append(changedFiles, diff.Files...)
+		}
+	}
+
+	// The same change can appear in several modes (e.g. staged and a
+	// commit range); deduplicate by ID before applying the limit.
+	allFindings = review.DeduplicateFindings(allFindings)
+	allFindings = review.LimitFindings(allFindings, cfg.MaxFindings)
+
+	meta, _ := gitctx.GetRepoMeta()
+	synthDiff := gitctx.DiffResult{
+		Diff: reviewedDiffs.String(),
+		Mode: "combined",
+		Repo: meta,
+	}
+
+	report := review.BuildReport(synthDiff, allFindings, totalLLMMs, time.Since(startTime).Milliseconds())
+	report.Inputs.Sources = sources
+
+	applyEscalation(ctx, report, reviewedDiffs.String(), cfg)
+	missingTests := applyRequireTests(report, changedFiles, cfg)
+	applyOwners(report, changedFiles)
+	applyReviewNote(ctx, report, reviewedDiffs.String(), cfg)
+	if report = publishReport(ctx, report, cfg, flagGroupBy); report == nil {
+		return
+	}
+	applyExitPolicy(report, cfg, missingTests)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/dshills/prism/internal/config"
 	"github.com/spf13/cobra"
@@ -70,6 +71,9 @@ var configShowCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		if cfg.RepoFile != "" {
+			fmt.Fprintf(os.Stderr, "Including %s from repo config %s\n", strings.Join(cfg.RepoKeys, ", "), cfg.RepoFile)
+		}
 
 		data, err := json.MarshalIndent(cfg, "", "  ")
 		if err != nil {
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// flagEnvFile names a dotenv file loaded before config and provider
+// resolution.
+var flagEnvFile string
+
+// loadEnvFile sets KEY=VALUE pairs from a dotenv file in the process
+// environment. Variables that are already set keep their values. Blank
+// lines, # comments, and an "export " prefix are allowed; values may be
+// wrapped in single or double quotes. Errors name the file and line but
+// never include values, which are usually secrets.
+func loadEnvFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening env file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return fmt.Errorf("%s:%d: expected KEY=VALUE", path, lineNo)
+		}
+		if _, set := os.LookupEnv(key); set {
+			continue
+		}
+		if err := os.Setenv(key, envValue(strings.TrimSpace(value))); err != nil {
+			return fmt.Errorf("%s:%d: setting %s: %w", path, lineNo, key, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading env file: %w", err)
+	}
+	return nil
+}
+
+// envValue unquotes a dotenv value. Unquoted values end at a " #" comment.
+func envValue(v string) string {
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		return v[1 : len(v)-1]
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = strings.TrimSpace(v[:i])
+	}
+	return v
+}
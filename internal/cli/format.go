@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dshills/prism/internal/review"
+	"github.com/spf13/cobra"
+)
+
+var formatCmd = &cobra.Command{
+	Use:   "format",
+	Short: "Re-render a saved JSON report from stdin in another format",
+	Long: `Format reads a JSON report produced by "prism review --format json" from
+stdin and writes it using the chosen output format. No provider is called,
+so a previous run can be turned into markdown or SARIF at no cost.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		report, err := readReport(cmd.InOrStdin())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exitCode = ExitUsageError
+			return nil
+		}
+
+		if err := writeReport(cmd.Context(), report, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			exitCode = ExitRuntimeError
+		}
+		return nil
+	},
+}
+
+// readReport decodes and sanity-checks a prism JSON report.
+func readReport(r io.Reader) (*review.Report, error) {
+	var report review.Report
+	dec := json.NewDecoder(r)
+	if err := dec.Decode(&report); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("no report on stdin")
+		}
+		return nil, fmt.Errorf("invalid report JSON: %w", err)
+	}
+	if report.Tool != "prism" {
+		return nil, fmt.Errorf("input is not a prism report (tool = %q)", report.Tool)
+	}
+	for i, f := range report.Findings {
+		if review.SeverityRank(f.Severity) == 0 {
+			return nil, fmt.Errorf("finding %d: invalid severity %q", i, f.Severity)
+		}
+	}
+	if report.Findings == nil {
+		report.Findings = []review.Finding{}
+	}
+	return &report, nil
+}
+
+func init() {
+	formatCmd.Flags().StringVar(&flagFormat, "format", "", "Output format (text, json, markdown, sarif, summary, html, junit)")
+	formatCmd.Flags().StringVar(&flagOut, "out", "", "Output file path, http(s) URL to POST to, or s3://bucket/key to upload to (default: stdout)")
+	formatCmd.Flags().StringVar(&flagSARIFSuppressions, "sarif-suppressions", "", "JSON file of suppressions (by ruleId or fingerprint) to mark in SARIF output")
+	formatCmd.Flags().BoolVar(&flagNoTiming, "no-timing", false, "Omit the timing footer from text and markdown output")
+	formatCmd.Flags().BoolVar(&flagMarkdownTOC, "md-toc", false, "Add a table of contents linking to each finding to markdown output")
+	formatCmd.Flags().BoolVar(&flagTextTable, "text-table", false, "Print text output as an aligned table of severity, location, category, and title")
+	formatCmd.Flags().StringVar(&flagGroupBy, "group-by", "", "Section text and markdown output by severity (default), category, file, or commit")
+	formatCmd.Flags().StringVar(&flagFields, "fields", "", "Comma-separated finding fields to keep in JSON output (e.g. id,severity,locations.path,title)")
+	formatCmd.Flags().BoolVar(&flagQuiet, "quiet", false, "Do not print the one-line summary to stderr when --out sends the report elsewhere")
+}
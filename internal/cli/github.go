@@ -1,15 +1,12 @@
 package cli
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"strconv"
 
-	"github.com/dshills/prism/internal/config"
 	"github.com/dshills/prism/internal/github"
 	"github.com/dshills/prism/internal/gitctx"
-	"github.com/dshills/prism/internal/output"
 	"github.com/dshills/prism/internal/providers"
 	"github.com/dshills/prism/internal/review"
 	"github.com/spf13/cobra"
@@ -34,7 +31,7 @@ var githubCmd = &cobra.Command{
 			return nil
 		}
 
-		cfg, err := config.Load(buildOverrides())
+		cfg, err := loadConfig()
 		if err != nil {
 			return err
 		}
@@ -64,7 +61,7 @@ var githubCmd = &cobra.Command{
 			return nil
 		}
 
-		ctx := context.Background()
+		ctx := cmd.Context()
 
 		// Fetch PR diff
 		fmt.Fprintf(os.Stderr, "Fetching PR #%d from %s/%s...\n", prNumber, owner, repo)
@@ -87,31 +84,49 @@ var githubCmd = &cobra.Command{
 			files = nil
 		}
 
-		// Build DiffResult for the review engine
-		diffResult := gitctx.DiffResult{
-			Diff:  diff,
-			Files: files,
-			Mode:  "github-pr",
-			Range: fmt.Sprintf("#%d", prNumber),
+		// Build DiffResult for the review engine, filtered and truncated
+		// the same way as a local diff
+		diffOpts := buildDiffOpts(cfg)
+		diffResult := gitctx.FromPatch(diff, "github-pr", fmt.Sprintf("#%d", prNumber), diffOpts)
+		if files != nil {
+			diffResult.Files = gitctx.FilterFiles(files, diffOpts)
 		}
+		files = diffResult.Files
+		noteMetadataOnly(diffResult)
 
 		// Run review
-		report, err := review.Run(ctx, diffResult, cfg)
+		report, err := review.RunWithOptions(ctx, diffResult, cfg, review.Options{OnFinding: streamFindings(cfg)})
 		if err != nil {
 			if providers.IsAuthError(err) {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				exitCode = ExitAuthError
 				return nil
 			}
+			if providers.IsModelError(err) {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exitCode = ExitUsageError
+				return nil
+			}
+			if timedOut(ctx) {
+				fmt.Fprintf(os.Stderr, "Error: review timed out after %s (--timeout)\n", flagTimeout)
+				exitCode = ExitRuntimeError
+				return nil
+			}
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			exitCode = ExitRuntimeError
 			return nil
 		}
 
-		// Write local output
-		if err := output.WriteReport(report, cfg.Format, flagOut); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
-			exitCode = ExitRuntimeError
+		applyNewCodeOnly(report, diffResult.Diff)
+		applyDropNoop(report, diffResult.Diff)
+		applyEscalation(ctx, report, diffResult.Diff, cfg)
+		if flagWithHunks {
+			review.AttachHunks(report.Findings, review.RedactDiff(diffResult.Diff, cfg))
+		}
+		missingTests := applyRequireTests(report, files, cfg)
+		applyOwners(report, files)
+		applyReviewNote(ctx, report, diffResult.Diff, cfg)
+		if report = publishReport(ctx, report, cfg, flagGroupBy); report == nil {
 			return nil
 		}
 
@@ -124,7 +139,9 @@ var githubCmd = &cobra.Command{
 				diffFileSet[f] = true
 			}
 
-			ghReview := github.BuildGitHubReview(report.Findings, diffFileSet)
+			ghReview := github.BuildGitHubReviewWithOptions(report.Findings, diffFileSet, github.ReviewOptions{
+				Owners: report.Owners,
+			})
 			fmt.Fprintf(os.Stderr, "Posting review (%d inline comments)...\n", len(ghReview.Comments))
 
 			if err := ghClient.PostReview(ctx, owner, repo, prNumber, ghReview); err != nil {
@@ -136,16 +153,7 @@ var githubCmd = &cobra.Command{
 			fmt.Fprintf(os.Stderr, "Review posted to PR #%d.\n", prNumber)
 		}
 
-		// Check fail-on threshold
-		if cfg.FailOn != "none" && cfg.FailOn != "" {
-			for _, f := range report.Findings {
-				if review.MeetsThreshold(f.Severity, cfg.FailOn) {
-					exitCode = ExitFindings
-					return nil
-				}
-			}
-		}
-
+		applyExitPolicy(report, cfg, missingTests)
 		return nil
 	},
 }
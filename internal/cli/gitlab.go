@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dshills/prism/internal/gitctx"
+	"github.com/dshills/prism/internal/gitlab"
+	"github.com/dshills/prism/internal/providers"
+	"github.com/dshills/prism/internal/review"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagGLProject string
+	flagGLDryRun  bool
+)
+
+var gitlabCmd = &cobra.Command{
+	Use:   "gitlab <mr-iid>",
+	Short: "Review a GitLab merge request",
+	Long:  "Fetch a merge request diff from GitLab, run review, and optionally post findings as MR discussion comments.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		iid, err := strconv.Atoi(strings.TrimPrefix(args[0], "!"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid MR number %q\n", args[0])
+			exitCode = ExitUsageError
+			return nil
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		// Detect the project if not provided
+		project := flagGLProject
+		if project == "" {
+			project, err = gitlab.DetectProject()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\nUse the --project flag to specify it manually.\n", err)
+				exitCode = ExitRuntimeError
+				return nil
+			}
+		}
+
+		// Create GitLab client
+		glClient, err := gitlab.NewClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exitCode = ExitAuthError
+			return nil
+		}
+
+		ctx := cmd.Context()
+
+		// Fetch MR diff
+		fmt.Fprintf(os.Stderr, "Fetching MR !%d from %s...\n", iid, project)
+		mr, err := glClient.GetMergeRequest(ctx, project, iid)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exitCode = ExitRuntimeError
+			return nil
+		}
+
+		if mr.Diff == "" {
+			fmt.Fprintln(os.Stdout, "MR has no diff — nothing to review.")
+			return nil
+		}
+
+		// Build DiffResult for the review engine, filtered and truncated
+		// the same way as a local diff
+		diffOpts := buildDiffOpts(cfg)
+		diffResult := gitctx.FromPatch(mr.Diff, "gitlab-mr", fmt.Sprintf("!%d", iid), diffOpts)
+		diffResult.Files = gitctx.FilterFiles(mr.Files, diffOpts)
+		files := diffResult.Files
+		noteMetadataOnly(diffResult)
+
+		// Run review
+		report, err := review.RunWithOptions(ctx, diffResult, cfg, review.Options{OnFinding: streamFindings(cfg)})
+		if err != nil {
+			if providers.IsAuthError(err) {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exitCode = ExitAuthError
+				return nil
+			}
+			if providers.IsModelError(err) {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exitCode = ExitUsageError
+				return nil
+			}
+			if timedOut(ctx) {
+				fmt.Fprintf(os.Stderr, "Error: review timed out after %s (--timeout)\n", flagTimeout)
+				exitCode = ExitRuntimeError
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exitCode = ExitRuntimeError
+			return nil
+		}
+
+		applyNewCodeOnly(report, diffResult.Diff)
+		applyDropNoop(report, diffResult.Diff)
+		applyEscalation(ctx, report, diffResult.Diff, cfg)
+		if flagWithHunks {
+			review.AttachHunks(report.Findings, review.RedactDiff(diffResult.Diff, cfg))
+		}
+		missingTests := applyRequireTests(report, files, cfg)
+		applyOwners(report, files)
+		applyReviewNote(ctx, report, diffResult.Diff, cfg)
+		if report = publishReport(ctx, report, cfg, flagGroupBy); report == nil {
+			return nil
+		}
+
+		// Post review to GitLab (unless dry-run)
+		if flagGLDryRun {
+			fmt.Fprintf(os.Stderr, "Dry run: %d findings found, not posting to GitLab.\n", len(report.Findings))
+		} else {
+			diffFileSet := make(map[string]bool, len(files))
+			for _, f := range files {
+				diffFileSet[f] = true
+			}
+
+			glReview := gitlab.BuildGitLabReviewWithOptions(report.Findings, diffFileSet, gitlab.ReviewOptions{
+				Owners: report.Owners,
+			})
+			fmt.Fprintf(os.Stderr, "Posting review (%d inline comments)...\n", len(glReview.Comments))
+
+			if err := glClient.PostReview(ctx, project, iid, mr, glReview); err != nil {
+				fmt.Fprintf(os.Stderr, "Error posting review: %v\n", err)
+				exitCode = ExitRuntimeError
+				return nil
+			}
+
+			fmt.Fprintf(os.Stderr, "Review posted to MR !%d.\n", iid)
+		}
+
+		applyExitPolicy(report, cfg, missingTests)
+		return nil
+	},
+}
+
+func init() {
+	addReviewFlags(gitlabCmd)
+	gitlabCmd.Flags().StringVar(&flagGLProject, "project", "", "GitLab project path (group/project) or numeric ID (auto-detected if omitted)")
+	gitlabCmd.Flags().BoolVar(&flagGLDryRun, "dry-run", false, "Run review but don't post to GitLab")
+}
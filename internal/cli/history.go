@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/dshills/prism/internal/config"
+	"github.com/dshills/prism/internal/gitctx"
+	"github.com/dshills/prism/internal/history"
+	"github.com/dshills/prism/internal/review"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagHistoryLimit int
+	flagHistoryAll   bool
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recorded review runs and how findings trend",
+	Long: `Show review runs recorded in the history file.
+
+Recording is off by default; set "history": {"enabled": true} in the config
+file to append every review report to the history.`,
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "List recorded runs with finding counts and their trend",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		records, err := loadHistory()
+		if err != nil {
+			return err
+		}
+		if !flagHistoryAll {
+			records = currentRepoRuns(records)
+		}
+		if len(records) == 0 {
+			fmt.Fprintln(os.Stdout, "No runs recorded.")
+			return nil
+		}
+		writeHistoryTable(records)
+		return nil
+	},
+}
+
+var historyDiffCmd = &cobra.Command{
+	Use:   "diff <run1> <run2>",
+	Short: "Compare the findings of two recorded runs",
+	Long: `Compare the findings of two recorded runs.
+
+Runs are named by run ID or a unique prefix of one, or as latest or
+latest~N (N runs before the latest). Findings are matched by their stable
+key, so a finding that moved lines still counts as the same finding.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		records, err := loadHistory()
+		if err != nil {
+			return err
+		}
+		from, err := history.Find(records, args[0])
+		if err != nil {
+			return err
+		}
+		to, err := history.Find(records, args[1])
+		if err != nil {
+			return err
+		}
+		writeHistoryDiff(from, to)
+		return nil
+	},
+}
+
+// historyPath returns the configured history file, or the default one.
+func historyPath(cfg config.Config) (string, error) {
+	if cfg.History.File != "" {
+		return cfg.History.File, nil
+	}
+	return history.DefaultPath()
+}
+
+func loadHistory() ([]history.Record, error) {
+	cfg, err := config.Load(nil)
+	if err != nil {
+		return nil, err
+	}
+	path, err := historyPath(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return history.Load(path)
+}
+
+// recordHistory appends report to the history file when history is
+// enabled. A failure is reported as a warning and does not fail the run.
+func recordHistory(report *review.Report, cfg config.Config) {
+	if !cfg.History.Enabled {
+		return
+	}
+	path, err := historyPath(cfg)
+	if err == nil {
+		err = history.Append(path, history.FromReport(report))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: recording history: %v\n", err)
+	}
+}
+
+// currentRepoRuns keeps the runs recorded for the repository containing
+// the working directory. Outside a repository all runs are kept.
+func currentRepoRuns(records []history.Record) []history.Record {
+	meta, err := gitctx.GetRepoMeta()
+	if err != nil || meta.Root == "" {
+		return records
+	}
+	var kept []history.Record
+	for _, rec := range records {
+		if rec.Repo == meta.Root {
+			kept = append(kept, rec)
+		}
+	}
+	return kept
+}
+
+// writeHistoryTable prints the last --limit runs, oldest first, with the
+// change in total findings from the run before each.
+func writeHistoryTable(records []history.Record) {
+	start := 0
+	if flagHistoryLimit > 0 && len(records) > flagHistoryLimit {
+		start = len(records) - flagHistoryLimit
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RUN\tTIME\tCOMMIT\tMODE\tCRITICAL\tHIGH\tMEDIUM\tLOW\tINFO\tTOTAL\tTREND")
+	for i := start; i < len(records); i++ {
+		rec := records[i]
+		trend := "-"
+		if i > 0 {
+			trend = formatDelta(rec.Total() - records[i-1].Total())
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%s\n",
+			shortRunID(rec.RunID), rec.Time.Local().Format("2006-01-02 15:04"), gitctx.ShortCommit(rec.Commit), rec.Mode,
+			rec.Counts.Critical, rec.Counts.High, rec.Counts.Medium, rec.Counts.Low, rec.Counts.Info, rec.Total(), trend)
+	}
+	tw.Flush()
+}
+
+// writeHistoryDiff prints the count changes between two runs and the
+// findings each one introduced, fixed, or kept.
+func writeHistoryDiff(from, to history.Record) {
+	fmt.Fprintf(os.Stdout, "%s -> %s\n\n", shortRunID(from.RunID), shortRunID(to.RunID))
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SEVERITY\tBEFORE\tAFTER\tCHANGE")
+	for _, s := range review.Severities {
+		before, after := from.Counts.Count(s), to.Counts.Count(s)
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", s, before, after, formatDelta(after-before))
+	}
+	fmt.Fprintf(tw, "total\t%d\t%d\t%s\n", from.Total(), to.Total(), formatDelta(to.Total()-from.Total()))
+	tw.Flush()
+
+	c := history.Compare(from, to)
+	writeHistoryEntries("New", c.New)
+	writeHistoryEntries("Fixed", c.Fixed)
+	fmt.Fprintf(os.Stdout, "\nPersisting: %d\n", len(c.Persisting))
+}
+
+func writeHistoryEntries(label string, entries []history.Entry) {
+	fmt.Fprintf(os.Stdout, "\n%s: %d\n", label, len(entries))
+	for _, e := range entries {
+		loc := e.Path
+		if e.Line > 0 {
+			loc = fmt.Sprintf("%s:%d", e.Path, e.Line)
+		}
+		fmt.Fprintf(os.Stdout, "  [%s] %s  %s\n", e.Severity, loc, e.Title)
+	}
+}
+
+// formatDelta renders a count change with its sign, e.g. "+2" or "-1".
+func formatDelta(n int) string {
+	if n > 0 {
+		return fmt.Sprintf("+%d", n)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func shortRunID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+func init() {
+	historyShowCmd.Flags().IntVar(&flagHistoryLimit, "limit", 20, "Show at most this many of the latest runs (0 for all)")
+	historyShowCmd.Flags().BoolVar(&flagHistoryAll, "all", false, "Show runs from every repository, not just the current one")
+	historyCmd.AddCommand(historyShowCmd)
+	historyCmd.AddCommand(historyDiffCmd)
+}
@@ -130,7 +130,7 @@ func getHookPath() (string, error) {
 func generateHookScript(failOn, format string, maxFindings int) string {
 	var b strings.Builder
 	b.WriteString(hookMarkerStart + "\n")
-	b.WriteString(fmt.Sprintf("prism review staged --fail-on %s --format %s --max-findings %d\n", failOn, format, maxFindings))
+	b.WriteString(fmt.Sprintf("prism review staged --index --fail-on %s --format %s --max-findings %d\n", failOn, format, maxFindings))
 	b.WriteString("PRISM_EXIT=$?\n")
 	b.WriteString("if [ $PRISM_EXIT -eq 1 ]; then\n")
 	b.WriteString("  echo \"prism: findings above threshold, commit blocked\"\n")
@@ -180,7 +180,7 @@ func removePrismSection(existing string) string {
 func init() {
 	hookCmd.AddCommand(hookInstallCmd)
 	hookCmd.AddCommand(hookUninstallCmd)
-	hookInstallCmd.Flags().StringVar(&hookFailOn, "fail-on", "high", "Fail on severity threshold (none, low, medium, high)")
-	hookInstallCmd.Flags().StringVar(&hookFormat, "format", "text", "Output format (text, json, markdown, sarif)")
+	hookInstallCmd.Flags().StringVar(&hookFailOn, "fail-on", "high", "Fail on severity threshold (none, info, low, medium, high, critical)")
+	hookInstallCmd.Flags().StringVar(&hookFormat, "format", "text", "Output format (text, json, markdown, sarif, summary, html, junit)")
 	hookInstallCmd.Flags().IntVar(&hookMaxFindings, "max-findings", 10, "Maximum number of findings")
 }
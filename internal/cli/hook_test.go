@@ -14,7 +14,7 @@ func TestGenerateHookScript(t *testing.T) {
 	if !strings.Contains(script, hookMarkerEnd) {
 		t.Error("Script missing end marker")
 	}
-	if !strings.Contains(script, "prism review staged --fail-on high --format text --max-findings 10") {
+	if !strings.Contains(script, "prism review staged --index --fail-on high --format text --max-findings 10") {
 		t.Error("Script missing prism command with correct flags")
 	}
 	if !strings.Contains(script, "PRISM_EXIT=$?") {
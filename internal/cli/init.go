@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dshills/prism/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var flagInitForce bool
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Interactively choose a provider and model and write the config file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.ConfigPath()
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); err == nil && !flagInitForce {
+			fmt.Fprintf(os.Stderr, "Config file already exists at %s (use --force to overwrite)\n", path)
+			return nil
+		}
+		return runSetup(cmd.InOrStdin(), cmd.OutOrStdout())
+	},
+}
+
+// providerChoice is a provider offered by the setup wizard.
+type providerChoice struct {
+	name     string
+	envVar   string // API key variable; empty for local providers
+	detected bool   // true if envVar is set (always true for local providers)
+}
+
+// detectProviders lists the supported providers, with those whose API key
+// is set in the environment first.
+func detectProviders() []providerChoice {
+	all := []providerChoice{
+		{name: "anthropic", envVar: "ANTHROPIC_API_KEY"},
+		{name: "openai", envVar: "OPENAI_API_KEY"},
+		{name: "gemini", envVar: "GEMINI_API_KEY"},
+		{name: "mistral", envVar: "MISTRAL_API_KEY"},
+		{name: "openrouter", envVar: "OPENROUTER_API_KEY"},
+		{name: "ollama"},
+	}
+	var ready, missing []providerChoice
+	for _, p := range all {
+		p.detected = p.envVar == "" || os.Getenv(p.envVar) != ""
+		if p.detected && p.envVar != "" {
+			ready = append(ready, p)
+		} else {
+			missing = append(missing, p)
+		}
+	}
+	return append(ready, missing...)
+}
+
+// runSetup walks the user through picking a provider and model, then saves
+// the choice to the config file, keeping any other settings already there.
+func runSetup(in io.Reader, out io.Writer) error {
+	r := bufio.NewReader(in)
+	choices := detectProviders()
+
+	fmt.Fprintln(out, "Select a provider:")
+	for i, p := range choices {
+		status := ""
+		switch {
+		case p.envVar == "":
+			status = "(local, no API key needed)"
+		case p.detected:
+			status = fmt.Sprintf("(%s found)", p.envVar)
+		default:
+			status = fmt.Sprintf("(set %s)", p.envVar)
+		}
+		fmt.Fprintf(out, "  %d) %s %s\n", i+1, p.name, status)
+	}
+	idx, err := promptIndex(r, out, len(choices))
+	if err != nil {
+		return err
+	}
+	chosen := choices[idx]
+	provider := chosen.name
+
+	var models []string
+	for _, info := range knownModels {
+		if info.Provider == provider {
+			models = info.Models
+		}
+	}
+	fmt.Fprintf(out, "\nSelect a %s model:\n", provider)
+	for i, m := range models {
+		fmt.Fprintf(out, "  %d) %s\n", i+1, m)
+	}
+	idx, err = promptIndex(r, out, len(models))
+	if err != nil {
+		return err
+	}
+	model := models[idx]
+
+	cfg, err := config.LoadFile()
+	if err != nil || cfg.Provider == "" {
+		cfg = config.Default()
+	}
+	cfg.Provider = provider
+	cfg.Model = model
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+
+	path, _ := config.ConfigPath()
+	fmt.Fprintf(out, "\nSaved provider %s, model %s to %s\n", provider, model, path)
+	if !chosen.detected {
+		fmt.Fprintf(out, "Remember to set %s before running a review.\n", chosen.envVar)
+	}
+	return nil
+}
+
+// promptIndex reads a 1-based choice from r, defaulting to the first option
+// on empty input. Returns the 0-based index.
+func promptIndex(r *bufio.Reader, out io.Writer, n int) (int, error) {
+	for {
+		fmt.Fprintf(out, "Choice [1]: ")
+		line, err := r.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if err != nil && err != io.EOF {
+				return 0, err
+			}
+			return 0, nil
+		}
+		if k, convErr := strconv.Atoi(line); convErr == nil && k >= 1 && k <= n {
+			return k - 1, nil
+		}
+		fmt.Fprintf(out, "Please enter a number between 1 and %d.\n", n)
+		if err != nil {
+			return 0, fmt.Errorf("no valid choice entered")
+		}
+	}
+}
+
+// maybeFirstRunSetup offers the setup wizard when prism is run interactively
+// with no config file and no provider chosen via env or flags. CI and
+// non-terminal runs skip it and use the defaults.
+func maybeFirstRunSetup() {
+	if flagProvider != "" || os.Getenv("PRISM_PROVIDER") != "" || os.Getenv("CI") != "" {
+		return
+	}
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stderr) {
+		return
+	}
+	path, err := config.ConfigPath()
+	if err != nil {
+		return
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "No prism config found. Let's pick a provider (run `prism init` to change it later).")
+	if err := runSetup(os.Stdin, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "Setup skipped: %v\n", err)
+	}
+	fmt.Fprintln(os.Stderr)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func init() {
+	initCmd.Flags().BoolVar(&flagInitForce, "force", false, "Overwrite an existing config file")
+}
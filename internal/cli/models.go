@@ -6,7 +6,6 @@ import (
 	"os"
 	"time"
 
-	"github.com/dshills/prism/internal/config"
 	"github.com/dshills/prism/internal/providers"
 	"github.com/spf13/cobra"
 )
@@ -50,6 +49,25 @@ var knownModels = []modelInfo{
 			"gemini-2.5-pro",
 		},
 	},
+	{
+		Provider: "mistral",
+		Models: []string{
+			"mistral-large-latest",
+			"codestral-latest",
+			"mistral-medium-latest",
+			"mistral-small-latest",
+		},
+	},
+	{
+		Provider: "openrouter",
+		Models: []string{
+			"anthropic/claude-sonnet-4.6",
+			"openai/gpt-5.2",
+			"google/gemini-2.5-pro",
+			"qwen/qwen3-coder",
+			"deepseek/deepseek-chat",
+		},
+	},
 	{
 		Provider: "ollama",
 		Models: []string{
@@ -81,7 +99,7 @@ var modelsDoctorCmd = &cobra.Command{
 	Use:   "doctor",
 	Short: "Validate provider credentials",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.Load(buildOverrides())
+		cfg, err := loadConfig()
 		if err != nil {
 			return err
 		}
@@ -100,7 +118,7 @@ var modelsDoctorCmd = &cobra.Command{
 			return nil
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
 		defer cancel()
 
 		_, err = p.Review(ctx, providers.ReviewRequest{
@@ -112,6 +130,8 @@ var modelsDoctorCmd = &cobra.Command{
 			fmt.Fprintf(os.Stderr, "FAIL: %v\n", err)
 			if providers.IsAuthError(err) {
 				exitCode = ExitAuthError
+			} else if providers.IsModelError(err) {
+				exitCode = ExitUsageError
 			} else {
 				exitCode = ExitRuntimeError
 			}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/dshills/prism/internal/config"
+	"github.com/dshills/prism/internal/notify"
+	"github.com/dshills/prism/internal/review"
+)
+
+// flagNotify lists the chat targets (comma-separated) that receive a
+// summary of the report.
+var flagNotify string
+
+// notifyReport posts a summary of report to each --notify target. A failure
+// is reported as a warning and does not fail the run.
+func notifyReport(ctx context.Context, report *review.Report, cfg config.Config) {
+	for _, target := range splitComma(flagNotify) {
+		var err error
+		switch target {
+		case notify.TargetSlack:
+			var slack *notify.Slack
+			slack, err = notify.NewSlack(cfg.Notify.SlackWebhookURL)
+			if err == nil {
+				err = slack.Send(ctx, report, notify.CIRunURL())
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: --notify %s: %v\n", target, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dshills/prism/internal/gitctx"
+	"github.com/dshills/prism/internal/review"
+)
+
+// selectPatch shows each hunk of diff on out and asks whether to review it,
+// like `git add -p`. It returns diff narrowed to the chosen hunks, with
+// Files listing only the files that still have a hunk. Answers are y
+// (review), n (skip), a (review this and all remaining hunks), and q (skip
+// this and all remaining hunks); end of input skips the rest.
+func selectPatch(in io.Reader, out io.Writer, diff gitctx.DiffResult) gitctx.DiffResult {
+	r := bufio.NewReader(in)
+	var total int
+	review.SelectHunks(diff.Diff, func(review.PatchHunk) bool {
+		total++
+		return false
+	})
+
+	n := 0
+	rest := "" // "a" or "q" once the user has decided for the remaining hunks
+	var files []string
+	seen := make(map[string]bool)
+	diff.Diff = review.SelectHunks(diff.Diff, func(h review.PatchHunk) bool {
+		n++
+		if rest == "" {
+			rest = promptHunk(r, out, h, n, total)
+		}
+		keep := rest == "a" || rest == "y"
+		if rest == "y" || rest == "n" {
+			rest = ""
+		}
+		if keep && !seen[h.Path] {
+			seen[h.Path] = true
+			files = append(files, h.Path)
+		}
+		return keep
+	})
+	diff.Files = files
+	return diff
+}
+
+// promptHunk prints h and reads an answer until a valid one is given.
+// End of input answers q.
+func promptHunk(r *bufio.Reader, out io.Writer, h review.PatchHunk, n, total int) string {
+	fmt.Fprintf(out, "\n%s (hunk %d/%d)\n%s\n", h.Path, n, total, h.Text)
+	for {
+		fmt.Fprint(out, "Review this hunk [y,n,a,q,?]? ")
+		line, err := r.ReadString('\n')
+		switch answer := strings.ToLower(strings.TrimSpace(line)); answer {
+		case "y", "n", "a", "q":
+			return answer
+		case "?":
+			fmt.Fprintln(out, "y - review this hunk\nn - skip this hunk\na - review this and all remaining hunks\nq - skip this and all remaining hunks")
+		default:
+			if err != nil {
+				return "q"
+			}
+			if answer != "" {
+				fmt.Fprintln(out, "Please answer y, n, a, or q.")
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/dshills/prism/internal/config"
+	"github.com/dshills/prism/internal/review"
+)
+
+// applyPostHook pipes the JSON report to the --post-hook command and returns
+// the report it prints on stdout, which replaces the original for output
+// and gating. The hook's stderr is passed through. The summary and verdict
+// are recomputed from the returned findings so gating stays consistent.
+// Without --post-hook the report is returned unchanged.
+func applyPostHook(ctx context.Context, report *review.Report, cfg config.Config) (*review.Report, error) {
+	if flagPostHook == "" {
+		return report, nil
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("encoding report for post-hook: %w", err)
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", flagPostHook)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", flagPostHook)
+	}
+	var stdout bytes.Buffer
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("post-hook %q: %w", flagPostHook, err)
+	}
+
+	out, err := readReport(&stdout)
+	if err != nil {
+		return nil, fmt.Errorf("post-hook output: %w", err)
+	}
+	out.Summary = review.ComputeSummary(out.Findings)
+	out.Summary.Verdict = review.ComputeVerdict(out.Findings, cfg.FailOn)
+	return out, nil
+}
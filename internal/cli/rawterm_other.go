@@ -0,0 +1,14 @@
+//go:build !(linux || darwin || freebsd)
+
+package cli
+
+import (
+	"errors"
+	"os"
+)
+
+// makeRaw is not supported here, so --interactive falls back to the
+// line-based prompt.
+func makeRaw(*os.File) (func(), error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
@@ -0,0 +1,37 @@
+//go:build linux || darwin || freebsd
+
+package cli
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// makeRaw puts the terminal on f into a raw input mode for full-screen
+// triage: keys arrive one at a time without echo, and Ctrl-C is read as a
+// key rather than raising SIGINT. Output processing is left on. The
+// returned func restores the previous mode.
+func makeRaw(f *os.File) (func(), error) {
+	fd := f.Fd()
+	var old syscall.Termios
+	if err := termiosIoctl(fd, ioctlGetTermios, &old); err != nil {
+		return nil, err
+	}
+	raw := old
+	raw.Lflag &^= syscall.ICANON | syscall.ECHO | syscall.ISIG | syscall.IEXTEN
+	raw.Iflag &^= syscall.IXON | syscall.ICRNL
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	if err := termiosIoctl(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+	return func() { _ = termiosIoctl(fd, ioctlSetTermios, &old) }, nil
+}
+
+func termiosIoctl(fd, req uintptr, t *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(unsafe.Pointer(t))); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -2,10 +2,15 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dshills/prism/internal/config"
@@ -18,35 +23,100 @@ import (
 
 // Shared review flags
 var (
-	flagPaths        string
-	flagExclude      string
-	flagContextLines int
-	flagMaxDiffBytes int
-	flagProvider     string
-	flagModel        string
-	flagCompare      string
-	flagFormat       string
-	flagOut          string
-	flagFailOn       string
-	flagMaxFindings  int
-	flagRules        string
-	flagNoRedact     bool
+	flagPaths             string
+	flagExclude           string
+	flagContextLines      int
+	flagMaxDiffBytes      int
+	flagMinDiffBytes      int
+	flagProvider          string
+	flagModel             string
+	flagCompare           string
+	flagFormat            string
+	flagOut               string
+	flagFailOn            string
+	flagMaxFindings       int
+	flagRules             string
+	flagRulesPack         string
+	flagNoRedact          bool
+	flagMergeIdentical    bool
+	flagTags              []string
+	flagMetaFile          string
+	flagEscalate          string
+	flagEscalateConf      float64
+	flagSARIFSuppressions string
+	flagWithHunks         bool
+	flagRequireTests      bool
+	flagPostHook          string
+	flagNewCodeOnly       bool
+	flagWithNote          bool
+	flagDropNoop          bool
+	flagNoTiming          bool
+	flagWithOwners        bool
+	flagMaxCost           float64
+	flagMarkdownTOC       bool
+	flagTextTable         bool
+	flagGroupBy           string
+	flagFields            string
+	flagPathsIgnoreCase   bool
+	flagGuide             string
+	flagPromptTemplate    string
+	flagQuiet             bool
+	flagConcurrency       int
+	flagRetryBudget       int
+	flagBaseline          string
+	flagAudit             bool
+	flagRefreshCache      bool
+	flagCachePerRepo      bool
+	flagStream            bool
 )
 
 func addReviewFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&flagPaths, "paths", "", "Include file path globs (comma-separated)")
-	cmd.Flags().StringVar(&flagExclude, "exclude", "", "Exclude file path globs (comma-separated)")
+	cmd.Flags().StringVar(&flagExclude, "exclude", "", "Exclude file path globs (comma-separated; a leading ! re-includes, last match wins)")
+	cmd.Flags().BoolVar(&flagPathsIgnoreCase, "paths-ignore-case", false, "Match --paths and --exclude globs ignoring case")
 	cmd.Flags().IntVar(&flagContextLines, "context-lines", 0, "Number of context lines in diff")
 	cmd.Flags().IntVar(&flagMaxDiffBytes, "max-diff-bytes", 0, "Maximum diff size in bytes")
-	cmd.Flags().StringVar(&flagProvider, "provider", "", "LLM provider (anthropic, openai, gemini)")
+	cmd.Flags().IntVar(&flagMinDiffBytes, "min-diff-bytes", 0, "Skip the review, reporting no findings, when the diff is smaller than this many bytes (0 = always review)")
+	cmd.Flags().Float64Var(&flagMaxCost, "max-cost", 0, "Abort before sending if the estimated prompt cost in USD exceeds this budget (0 = no limit)")
+	cmd.Flags().StringVar(&flagProvider, "provider", "", "LLM provider (anthropic, openai, gemini, mistral, openrouter, ollama, lmstudio, auto)")
 	cmd.Flags().StringVar(&flagModel, "model", "", "Model name")
 	cmd.Flags().StringVar(&flagCompare, "compare", "", "Compare mode: comma-separated provider:model pairs")
-	cmd.Flags().StringVar(&flagFormat, "format", "", "Output format (text, json, markdown, sarif)")
-	cmd.Flags().StringVar(&flagOut, "out", "", "Output file path (default: stdout)")
-	cmd.Flags().StringVar(&flagFailOn, "fail-on", "", "Fail on severity threshold (none, low, medium, high)")
+	cmd.Flags().IntVar(&flagConcurrency, "concurrency", 0, "Maximum parallel LLM calls across chunks and compare models (default 4)")
+	cmd.Flags().IntVar(&flagRetryBudget, "retry-budget", 0, "Maximum retries shared by all chunks of a chunked review (0 = no cap)")
+	cmd.Flags().StringVar(&flagFormat, "format", "", "Output format (text, json, markdown, sarif, summary, html, junit)")
+	cmd.Flags().StringVar(&flagOut, "out", "", "Output file path, http(s) URL to POST to, or s3://bucket/key to upload to (default: stdout)")
+	cmd.Flags().StringVar(&flagSARIFSuppressions, "sarif-suppressions", "", "JSON file of suppressions (by ruleId or fingerprint) to mark in SARIF output")
+	cmd.Flags().BoolVar(&flagNoTiming, "no-timing", false, "Omit the timing footer from text and markdown output")
+	cmd.Flags().BoolVar(&flagMarkdownTOC, "md-toc", false, "Add a table of contents linking to each finding to markdown output")
+	cmd.Flags().BoolVar(&flagTextTable, "text-table", false, "Print text output as an aligned table of severity, location, category, and title")
+	cmd.Flags().StringVar(&flagGroupBy, "group-by", "", "Section text and markdown output by severity (default), category, file, or commit")
+	cmd.Flags().StringVar(&flagFields, "fields", "", "Comma-separated finding fields to keep in JSON output (e.g. id,severity,locations.path,title)")
+	cmd.Flags().BoolVar(&flagQuiet, "quiet", false, "Do not print the one-line summary to stderr when --out sends the report elsewhere")
+	cmd.Flags().StringVar(&flagFailOn, "fail-on", "", "Fail on severity threshold (none, info, low, medium, high, critical)")
 	cmd.Flags().IntVar(&flagMaxFindings, "max-findings", 0, "Maximum number of findings")
-	cmd.Flags().StringVar(&flagRules, "rules", "", "Rules file path")
-	cmd.Flags().BoolVar(&flagNoRedact, "no-redact", false, "Disable secret redaction (use with caution)")
+	cmd.Flags().StringVar(&flagRules, "rules", "", "Rules source: file path, http(s) URL, git:<ref>:<path>, or pack:<name> (comma-separated sources are merged in order)")
+	cmd.Flags().StringVar(&flagRulesPack, "rules-pack", "", "Built-in rules pack name (ignored if --rules is set)")
+	cmd.Flags().StringVar(&flagGuide, "guide", "", "Markdown or text style guide the model enforces as authoritative standards")
+	cmd.Flags().StringVar(&flagPromptTemplate, "prompt-template", "", "Go text/template file defining \"system\" and/or \"user\" templates that replace the review prompts")
+	cmd.Flags().BoolVar(&flagNoRedact, "no-redact", false, "Disable secret and path redaction (use with caution)")
+	cmd.Flags().BoolVar(&flagAudit, "audit", false, "Record SHA-256 hashes of each prompt sent and response received in the report")
+	cmd.Flags().BoolVar(&flagRefreshCache, "refresh-cache", false, "Skip cached results but still store fresh ones for later runs")
+	cmd.Flags().BoolVar(&flagCachePerRepo, "cache-per-repo", false, "Keep this repository's cache entries in their own subdirectory (cache.perRepo)")
+	cmd.Flags().StringVar(&flagEscalate, "escalate", "", "Second-opinion model (provider:model) that must confirm high-severity findings")
+	cmd.Flags().Float64Var(&flagEscalateConf, "escalate-below-confidence", 0, "Also escalate findings with confidence below this value (requires --escalate)")
+	cmd.Flags().StringArrayVar(&flagTags, "tag", nil, "Attach key=value metadata to the report (repeatable)")
+	cmd.Flags().StringVar(&flagMetaFile, "meta-file", "", "JSON file of string key/value metadata to attach to the report")
+	cmd.Flags().StringVar(&flagBaseline, "baseline", "", "JSON report of an earlier run; report only new findings and findings whose severity increased")
+	cmd.Flags().BoolVar(&flagMergeIdentical, "merge-identical", false, "Merge findings with the same title, category, and suggestion into one finding with multiple locations")
+	cmd.Flags().BoolVar(&flagWithHunks, "with-hunks", false, "Attach the diff hunk each finding refers to (not used by codebase/dir reviews)")
+	cmd.Flags().StringVar(&flagPostHook, "post-hook", "", "Shell command that receives the JSON report on stdin and prints the report to use on stdout")
+	cmd.Flags().BoolVar(&flagRequireTests, "require-tests", false, "Fail when non-test files change without any test file changes (not used by codebase/dir reviews)")
+	cmd.Flags().BoolVar(&flagWithNote, "with-note", false, "On a review with no findings, ask the model for a short note on what it checked (one extra LLM call)")
+	cmd.Flags().BoolVar(&flagDropNoop, "drop-noop-suggestions", false, "Drop findings whose suggestion is identical to the code already at their location")
+	cmd.Flags().BoolVar(&flagWithOwners, "with-owners", false, "List the CODEOWNERS of changed files in the report (and the GitHub review body)")
+	cmd.Flags().BoolVar(&flagNewCodeOnly, "new-code-only", false, "Drop findings not anchored to lines added by the diff (not used by codebase/dir reviews)")
+	cmd.Flags().BoolVar(&flagStream, "stream", false, "Print findings to stderr as the provider streams them in, before the full report")
+	cmd.Flags().StringVar(&flagNotify, "notify", "", "Post a summary of the report to chat after the review (slack; webhook from PRISM_SLACK_WEBHOOK_URL or notify.slackWebhookUrl)")
 }
 
 func buildOverrides() map[string]string {
@@ -72,21 +142,66 @@ func buildOverrides() map[string]string {
 	if flagMaxDiffBytes > 0 {
 		m["maxDiffBytes"] = fmt.Sprintf("%d", flagMaxDiffBytes)
 	}
+	if flagMinDiffBytes > 0 {
+		m["minDiffBytes"] = fmt.Sprintf("%d", flagMinDiffBytes)
+	}
 	if flagRules != "" {
 		m["rulesFile"] = flagRules
+	} else if flagRulesPack != "" {
+		m["rulesFile"] = "pack:" + flagRulesPack
+	}
+	if flagGuide != "" {
+		m["guideFile"] = flagGuide
+	}
+	if flagPromptTemplate != "" {
+		m["promptTemplateFile"] = flagPromptTemplate
+	}
+	if flagConcurrency > 0 {
+		m["concurrency"] = fmt.Sprintf("%d", flagConcurrency)
+	}
+	if flagRetryBudget > 0 {
+		m["retryBudget"] = fmt.Sprintf("%d", flagRetryBudget)
 	}
 	if flagCompare != "" {
 		m["compare"] = flagCompare
 	}
+	if flagMaxCost > 0 {
+		m["maxCost"] = strconv.FormatFloat(flagMaxCost, 'f', -1, 64)
+	}
+	if flagRefreshCache {
+		m["refreshCache"] = "true"
+	}
+	if flagCachePerRepo {
+		m["cachePerRepo"] = "true"
+	}
 	return m
 }
 
+// loadConfig loads the effective config with the flag overrides. Values
+// from a repository config file come with the code under review, so they
+// are listed on stderr unless --quiet is set.
+func loadConfig() (config.Config, error) {
+	cfg, err := config.Load(buildOverrides())
+	if err != nil {
+		return config.Config{}, err
+	}
+	if cfg.RepoFile != "" && !flagQuiet {
+		fmt.Fprintf(os.Stderr, "Warning: applying %s from repo config %s\n", strings.Join(cfg.RepoKeys, ", "), cfg.RepoFile)
+	}
+	return cfg, nil
+}
+
 func buildDiffOpts(cfg config.Config) gitctx.DiffOptions {
 	opts := gitctx.DiffOptions{
-		ContextLines: cfg.ContextLines,
-		MaxDiffBytes: cfg.MaxDiffBytes,
-		Include:      cfg.Include,
-		Exclude:      cfg.Exclude,
+		ContextLines:    cfg.ContextLines,
+		MaxDiffBytes:    cfg.MaxDiffBytes,
+		Include:         cfg.Include,
+		Exclude:         cfg.Exclude,
+		CaseInsensitive: flagPathsIgnoreCase,
+	}
+	// --no-redact turns off path redaction along with secret redaction.
+	if !flagNoRedact {
+		opts.RedactPaths = cfg.Privacy.RedactPaths
 	}
 	if flagPaths != "" {
 		opts.Include = splitComma(flagPaths)
@@ -97,6 +212,370 @@ func buildDiffOpts(cfg config.Config) gitctx.DiffOptions {
 	return opts
 }
 
+// severityIcons returns the configured per-severity icon overrides, or nil
+// to use the defaults.
+func severityIcons(cfg config.Config) map[review.Severity]string {
+	if len(cfg.Output.Icons) == 0 {
+		return nil
+	}
+	icons := make(map[review.Severity]string, len(cfg.Output.Icons))
+	for sev, icon := range cfg.Output.Icons {
+		icons[review.Severity(sev)] = icon
+	}
+	return icons
+}
+
+// streamFindings returns a callback that prints each finding to stderr as
+// it arrives with --stream, or nil without it. The callback is safe to call
+// from the concurrent chunk reviews.
+func streamFindings(cfg config.Config) func(review.Finding) {
+	if !flagStream {
+		return nil
+	}
+	tw := &output.TextWriter{Icons: severityIcons(cfg)}
+	var mu sync.Mutex
+	return func(f review.Finding) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = tw.WriteFinding(os.Stderr, f)
+	}
+}
+
+// writerOptions builds output rendering options from the effective config
+// and output flags.
+func writerOptions(cfg config.Config) output.WriterOptions {
+	opts := output.WriterOptions{NoTiming: flagNoTiming, MarkdownTOC: flagMarkdownTOC, TextTable: flagTextTable, GroupBy: flagGroupBy}
+	if flagFields != "" {
+		opts.JSONFields = splitComma(flagFields)
+	}
+	opts.Icons = severityIcons(cfg)
+	opts.SARIFSuppressions = sarifSuppressions
+	return opts
+}
+
+// writeReport renders the report in the configured format to --out or stdout.
+// When the report goes to --out, a one-line summary is printed to stderr so
+// the terminal still shows the outcome, unless --quiet is set.
+func writeReport(ctx context.Context, report *review.Report, cfg config.Config) error {
+	return writeReportGroupedBy(ctx, report, cfg, flagGroupBy)
+}
+
+// writeReportGroupedBy is writeReport with the text and markdown sections
+// chosen by groupBy instead of --group-by.
+func writeReportGroupedBy(ctx context.Context, report *review.Report, cfg config.Config, groupBy string) error {
+	opts := writerOptions(cfg)
+	opts.GroupBy = groupBy
+	if err := output.WriteReportWithOptions(ctx, report, cfg.Format, flagOut, opts); err != nil {
+		return err
+	}
+	if flagOut != "" && !flagQuiet {
+		fmt.Fprintln(os.Stderr, consoleSummary(report, flagOut))
+	}
+	return nil
+}
+
+// consoleSummary returns the stderr line for a report written to dest: the
+// counts from review.SummaryLine, the verdict, and the destination.
+func consoleSummary(report *review.Report, dest string) string {
+	line := review.SummaryLine(report)
+	if report.Summary.Verdict != "" {
+		line += " (" + strings.ToUpper(report.Summary.Verdict) + ")"
+	}
+	return line + "; report written to " + dest
+}
+
+// noteMetadataOnly tells the user which rename/mode-only changes were left
+// out of the review.
+func noteMetadataOnly(diff gitctx.DiffResult) {
+	if len(diff.MetadataOnly) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Note: skipping %d metadata-only change(s) with no content to review: %s\n",
+		len(diff.MetadataOnly), strings.Join(diff.MetadataOnly, ", "))
+}
+
+// applyNewCodeOnly drops findings that do not touch lines added by diff
+// when --new-code-only is set, so context lines pulled into a range review
+// do not produce findings on pre-existing code.
+func applyNewCodeOnly(report *review.Report, diff string) {
+	if !flagNewCodeOnly {
+		return
+	}
+	report.Findings = review.FilterNewCode(report.Findings, diff)
+	report.Summary = review.ComputeSummary(report.Findings)
+}
+
+// applyDropNoop drops findings whose suggestion matches the existing code
+// when --drop-noop-suggestions is set.
+func applyDropNoop(report *review.Report, diff string) {
+	if !flagDropNoop {
+		return
+	}
+	report.Findings = review.DropNoopSuggestions(report.Findings, diff)
+	report.Summary = review.ComputeSummary(report.Findings)
+}
+
+// applyEscalation asks the --escalate model to confirm high-severity (and
+// optionally low-confidence) findings, dropping the ones it denies. If the
+// escalation fails the report is left unchanged.
+func applyEscalation(ctx context.Context, report *review.Report, diff string, cfg config.Config) {
+	if flagEscalate == "" {
+		return
+	}
+	res, err := review.Escalate(ctx, report.Findings, diff, flagEscalate, cfg, review.EscalateOptions{
+		BelowConfidence: flagEscalateConf,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: escalation failed, keeping original findings: %v\n", err)
+		return
+	}
+	if res.Escalated == 0 {
+		return
+	}
+	report.Findings = res.Findings
+	report.Summary = review.ComputeSummary(report.Findings)
+	report.Timing.LLMMs += res.LLMMs
+	fmt.Fprintf(os.Stderr, "Escalation (%s): %d reviewed, %d confirmed, %d denied\n",
+		flagEscalate, res.Escalated, res.Confirmed, res.Denied)
+}
+
+// applyRequireTests adds the --require-tests policy finding when files
+// contains production changes but no test changes. It reports whether the
+// policy was violated so callers can fail the run regardless of --fail-on.
+func applyRequireTests(report *review.Report, files []string, cfg config.Config) bool {
+	if !flagRequireTests {
+		return false
+	}
+	f := review.RequireTests(files, cfg.TestPatterns)
+	if f == nil {
+		return false
+	}
+	report.Findings = append(report.Findings, *f)
+	report.Summary = review.ComputeSummary(report.Findings)
+	return true
+}
+
+// applyOwners records the CODEOWNERS of files in the report when
+// --with-owners is set. A missing CODEOWNERS file only prints a note.
+func applyOwners(report *review.Report, files []string) {
+	if !flagWithOwners {
+		return
+	}
+	root := report.Repo.Root
+	if root == "" {
+		meta, _ := gitctx.GetRepoMeta()
+		root = meta.Root
+	}
+	if root == "" {
+		root = "."
+	}
+	co, err := review.LoadCodeOwners(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: reading CODEOWNERS: %v\n", err)
+		return
+	}
+	if co == nil {
+		fmt.Fprintln(os.Stderr, "Note: no CODEOWNERS file found; --with-owners has nothing to add")
+		return
+	}
+	if owners := co.MapOwners(files); len(owners) > 0 {
+		report.Owners = owners
+	}
+}
+
+// applyReviewNote asks the model for a short note on what it checked when
+// --with-note is set and the report has no findings. A failed request only
+// prints a warning.
+func applyReviewNote(ctx context.Context, report *review.Report, diff string, cfg config.Config) {
+	if !flagWithNote || len(report.Findings) > 0 {
+		return
+	}
+	note, llmMs, err := review.WriteReviewNote(ctx, diff, cfg)
+	report.Timing.LLMMs += llmMs
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: could not get review note: %v\n", err)
+		return
+	}
+	report.ReviewNote = note
+}
+
+// applyFailOn sets the findings exit code when any finding meets the
+// configured --fail-on threshold.
+func applyFailOn(report *review.Report, cfg config.Config) {
+	if cfg.FailOn == "none" || cfg.FailOn == "" {
+		return
+	}
+	for _, f := range report.Findings {
+		if review.MeetsThreshold(f.Severity, cfg.FailOn) {
+			exitCode = ExitFindings
+			exitDetail = fmt.Sprintf("findings met the %q --fail-on threshold", cfg.FailOn)
+			return
+		}
+	}
+}
+
+// finalizeReport drops findings dismissed in earlier triage, prints the
+// report's warnings and notes on stderr (unless --quiet), applies
+// output-only report transformations requested by flags, and sets the
+// summary verdict against the configured fail-on threshold.
+func finalizeReport(report *review.Report, cfg config.Config) {
+	dismissed := applyDismissals(report)
+	if !flagQuiet {
+		for _, w := range report.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+		if report.Skipped != "" {
+			fmt.Fprintf(os.Stderr, "Note: %s; skipping review\n", report.Skipped)
+		}
+		if dismissed > 0 {
+			fmt.Fprintf(os.Stderr, "Note: %d finding(s) dismissed in earlier triage are hidden (%s)\n", dismissed, dismissalsFile)
+		}
+	}
+	if flagMergeIdentical {
+		report.Findings = review.MergeIdenticalFindings(report.Findings)
+		report.Summary = review.ComputeSummary(report.Findings)
+	}
+	if baselineReport != nil {
+		report.Findings = review.ApplyBaseline(report.Findings, baselineReport.Findings)
+		report.Summary = review.ComputeSummary(report.Findings)
+	}
+	report.Summary.Verdict = review.ComputeVerdict(report.Findings, cfg.FailOn)
+	if auditLog != nil {
+		report.Audit = auditLog.Entries()
+	}
+	if usageTracker != nil {
+		if u := usageTracker.Usage(); u.Calls > 0 {
+			report.Timing.Usage = &u
+		}
+	}
+	report.Metadata = runMetadata
+}
+
+// publishReport runs the steps every review command shares once its report
+// is built: finalizeReport, the --post-hook, interactive triage, writing the
+// output with sections chosen by groupBy, history, and notifications. It
+// returns the report as the post hook left it, or nil with exitCode set
+// when a step fails.
+func publishReport(ctx context.Context, report *review.Report, cfg config.Config, groupBy string) *review.Report {
+	finalizeReport(report, cfg)
+	report, err := applyPostHook(ctx, report, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exitCode = ExitRuntimeError
+		return nil
+	}
+	if err := applyInteractive(report, cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exitCode = ExitRuntimeError
+		return nil
+	}
+	if err := writeReportGroupedBy(ctx, report, cfg, groupBy); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		exitCode = ExitRuntimeError
+		return nil
+	}
+	recordHistory(report, cfg)
+	notifyReport(ctx, report, cfg)
+	return report
+}
+
+// applyExitPolicy sets the exit code for a published report: findings when
+// --require-tests found source changes without test changes, otherwise by
+// --fail-on.
+func applyExitPolicy(report *review.Report, cfg config.Config, missingTests bool) {
+	if missingTests {
+		exitCode = ExitFindings
+		exitDetail = "non-test files changed without any test changes (--require-tests)"
+		return
+	}
+	applyFailOn(report, cfg)
+}
+
+// baselineReport is the --baseline report, loaded by loadRunInputs.
+var baselineReport *review.Report
+
+// runMetadata is the report metadata from --meta-file and --tag, built by
+// loadRunInputs.
+var runMetadata map[string]string
+
+// sarifSuppressions are the --sarif-suppressions entries, loaded by
+// loadRunInputs.
+var sarifSuppressions []output.SARIFSuppression
+
+// loadRunInputs reads the files named by flags that are only applied to the
+// report after the review, so a bad path or file fails the run before any
+// provider call.
+func loadRunInputs() error {
+	baselineReport = nil
+	if flagBaseline != "" {
+		baseline, err := loadBaseline(flagBaseline)
+		if err != nil {
+			return err
+		}
+		baselineReport = baseline
+	}
+	meta, err := buildMetadata(flagMetaFile, flagTags)
+	if err != nil {
+		return err
+	}
+	runMetadata = meta
+	sarifSuppressions = nil
+	if flagSARIFSuppressions != "" {
+		sups, err := output.LoadSARIFSuppressions(flagSARIFSuppressions)
+		if err != nil {
+			return err
+		}
+		sarifSuppressions = sups
+	}
+	sups, err := loadDismissals()
+	if err != nil {
+		return err
+	}
+	dismissals = sups
+	return nil
+}
+
+// loadBaseline reads the --baseline JSON report.
+func loadBaseline(path string) (*review.Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline: %w", err)
+	}
+	defer f.Close()
+	report, err := readReport(f)
+	if err != nil {
+		return nil, fmt.Errorf("baseline %s: %w", path, err)
+	}
+	return report, nil
+}
+
+// buildMetadata merges run metadata from a JSON meta file and key=value
+// tags. Tags win over meta file entries with the same key.
+func buildMetadata(metaFile string, tags []string) (map[string]string, error) {
+	meta := make(map[string]string)
+	if metaFile != "" {
+		data, err := os.ReadFile(metaFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading meta file: %w", err)
+		}
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return nil, fmt.Errorf("parsing meta file %s: must be a JSON object of strings: %w", metaFile, err)
+		}
+	}
+	for _, tag := range tags {
+		key, value, ok := strings.Cut(tag, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --tag %q: expected key=value", tag)
+		}
+		meta[key] = value
+	}
+	if len(meta) == 0 {
+		return nil, nil
+	}
+	return meta, nil
+}
+
 func splitComma(s string) []string {
 	parts := strings.Split(s, ",")
 	var result []string
@@ -109,11 +588,12 @@ func splitComma(s string) []string {
 	return result
 }
 
-func runReview(diff gitctx.DiffResult, cfg config.Config) {
+func runReview(ctx context.Context, diff gitctx.DiffResult, cfg config.Config) {
 	if flagNoRedact {
 		cfg.Privacy.RedactSecrets = false
-		fmt.Fprintln(os.Stderr, "WARNING: secret redaction is disabled")
+		fmt.Fprintln(os.Stderr, "WARNING: secret and path redaction are disabled")
 	}
+	noteMetadataOnly(diff)
 
 	// Determine compare models from flag or config
 	var compareModels []string
@@ -123,15 +603,13 @@ func runReview(diff gitctx.DiffResult, cfg config.Config) {
 		compareModels = cfg.Compare
 	}
 
-	ctx := context.Background()
-
 	var report *review.Report
 	var err error
 
 	if len(compareModels) >= 2 {
 		report, err = runCompareMode(ctx, diff, cfg, compareModels, nil)
 	} else {
-		report, err = review.Run(ctx, diff, cfg)
+		report, err = review.RunWithOptions(ctx, diff, cfg, review.Options{OnFinding: streamFindings(cfg)})
 	}
 
 	if err != nil {
@@ -140,32 +618,40 @@ func runReview(diff gitctx.DiffResult, cfg config.Config) {
 			exitCode = ExitAuthError
 			return
 		}
+		if providers.IsModelError(err) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exitCode = ExitUsageError
+			return
+		}
+		if timedOut(ctx) {
+			fmt.Fprintf(os.Stderr, "Error: review timed out after %s (--timeout)\n", flagTimeout)
+			exitCode = ExitRuntimeError
+			return
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		exitCode = ExitRuntimeError
 		return
 	}
 
-	if err := output.WriteReport(report, cfg.Format, flagOut); err != nil {
-		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
-		exitCode = ExitRuntimeError
-		return
+	applyNewCodeOnly(report, diff.Diff)
+	applyDropNoop(report, diff.Diff)
+	applyEscalation(ctx, report, diff.Diff, cfg)
+	if flagWithHunks {
+		review.AttachHunks(report.Findings, review.RedactDiff(diff.Diff, cfg))
 	}
-
-	// Check fail-on threshold
-	if cfg.FailOn != "none" && cfg.FailOn != "" {
-		for _, f := range report.Findings {
-			if review.MeetsThreshold(f.Severity, cfg.FailOn) {
-				exitCode = ExitFindings
-				return
-			}
-		}
+	missingTests := applyRequireTests(report, diff.Files, cfg)
+	applyOwners(report, diff.Files)
+	applyReviewNote(ctx, report, diff.Diff, cfg)
+	if report = publishReport(ctx, report, cfg, flagGroupBy); report == nil {
+		return
 	}
+	applyExitPolicy(report, cfg, missingTests)
 }
 
 func runCompareMode(ctx context.Context, diff gitctx.DiffResult, cfg config.Config, models []string, builder review.PromptBuilder) (*review.Report, error) {
 	startTime := time.Now()
 
-	rules, err := review.LoadRules(cfg.RulesFile)
+	rules, warnings, err := review.LoadRules(cfg.RulesFile)
 	if err != nil {
 		return nil, fmt.Errorf("loading rules: %w", err)
 	}
@@ -177,12 +663,10 @@ func runCompareMode(ctx context.Context, diff gitctx.DiffResult, cfg config.Conf
 		return nil, err
 	}
 
-	findings := cr.All
-	if cfg.MaxFindings > 0 && len(findings) > cfg.MaxFindings {
-		findings = findings[:cfg.MaxFindings]
-	}
+	findings := review.LimitFindings(cr.All, cfg.MaxFindings)
 
 	report := review.BuildReport(diff, findings, cr.LLMMs, time.Since(startTime).Milliseconds())
+	report.Warnings = append(warnings, cr.Warnings...)
 
 	// Print compare summary to stderr
 	fmt.Fprintf(os.Stderr, "Compare mode: %d models, %d consensus findings, %d total\n",
@@ -196,10 +680,10 @@ func runCompareMode(ctx context.Context, diff gitctx.DiffResult, cfg config.Conf
 	return report, nil
 }
 
-func runPerCommitReview(revRange string, cfg config.Config) {
+func runPerCommitReview(ctx context.Context, revRange string, cfg config.Config) {
 	if flagNoRedact {
 		cfg.Privacy.RedactSecrets = false
-		fmt.Fprintln(os.Stderr, "WARNING: secret redaction is disabled")
+		fmt.Fprintln(os.Stderr, "WARNING: secret and path redaction are disabled")
 	}
 
 	commits, err := gitctx.ListCommits(revRange, flagMergeBase)
@@ -213,39 +697,91 @@ func runPerCommitReview(revRange string, cfg config.Config) {
 		return
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	startTime := time.Now()
 
+	// Review commits in parallel, bounded like chunk reviews, then merge the
+	// results in range order so the report does not depend on scheduling
+	type commitResult struct {
+		diff   gitctx.DiffResult
+		report *review.Report
+		err    error
+	}
+	results := make([]commitResult, len(commits))
+	onFinding := streamFindings(cfg)
+	sem := make(chan struct{}, review.ConcurrencyLimit(cfg))
+	var wg sync.WaitGroup
+	for i, c := range commits {
+		wg.Add(1)
+		go func(i int, c gitctx.CommitInfo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				results[i].err = ctx.Err()
+				return
+			}
+			fmt.Fprintf(os.Stderr, "Reviewing commit %d/%d: %s %s\n", i+1, len(commits), gitctx.ShortCommit(c.SHA), c.Subject)
+
+			diff, err := gitctx.Commit(c.SHA, "", buildDiffOpts(cfg))
+			if err != nil {
+				results[i].err = fmt.Errorf("error getting diff: %w", err)
+				return
+			}
+			results[i].diff = diff
+			if strings.TrimSpace(diff.Diff) == "" {
+				return
+			}
+			results[i].report, results[i].err = review.RunWithOptions(ctx, diff, cfg, review.Options{OnFinding: onFinding})
+			if providers.IsAuthError(results[i].err) || providers.IsModelError(results[i].err) {
+				cancel() // every other commit would fail the same way
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	// A bad key or model fails the whole run, whichever commit hit it
+	for _, r := range results {
+		if providers.IsAuthError(r.err) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", r.err)
+			exitCode = ExitAuthError
+			return
+		}
+		if providers.IsModelError(r.err) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", r.err)
+			exitCode = ExitUsageError
+			return
+		}
+	}
+	if timedOut(ctx) {
+		fmt.Fprintf(os.Stderr, "Error: review timed out after %s (--timeout)\n", flagTimeout)
+		exitCode = ExitRuntimeError
+		return
+	}
+
 	var allFindings []review.Finding
 	var totalLLMMs int64
+	var reviewedDiffs strings.Builder
+	var changedFiles []string
+	var warnings []string
 
 	for i, c := range commits {
-		shortSHA := c.SHA
-		if len(shortSHA) > 7 {
-			shortSHA = shortSHA[:7]
-		}
-		fmt.Fprintf(os.Stderr, "Reviewing commit %d/%d: %s %s\n", i+1, len(commits), shortSHA, c.Subject)
-
-		diff, err := gitctx.Commit(c.SHA, "", buildDiffOpts(cfg))
+		shortSHA := gitctx.ShortCommit(c.SHA)
+		diff, report, err := results[i].diff, results[i].report, results[i].err
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "  Skipping (error getting diff): %v\n", err)
+			fmt.Fprintf(os.Stderr, "Skipping commit %s (%v)\n", shortSHA, err)
 			continue
 		}
-		if strings.TrimSpace(diff.Diff) == "" {
-			fmt.Fprintf(os.Stderr, "  Skipping (empty diff)\n")
+		if report == nil {
+			fmt.Fprintf(os.Stderr, "Skipping commit %s (empty diff)\n", shortSHA)
 			continue
 		}
 
-		report, err := review.Run(ctx, diff, cfg)
-		if err != nil {
-			if providers.IsAuthError(err) {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				exitCode = ExitAuthError
-				return
-			}
-			fmt.Fprintf(os.Stderr, "  Error reviewing commit %s: %v\n", shortSHA, err)
-			continue
+		applyNewCodeOnly(report, diff.Diff)
+		applyDropNoop(report, diff.Diff)
+		if flagWithHunks {
+			review.AttachHunks(report.Findings, review.RedactDiff(diff.Diff, cfg))
 		}
 
 		// Stamp commit SHA on each finding's locations
@@ -256,85 +792,131 @@ func runPerCommitReview(revRange string, cfg config.Config) {
 		}
 
 		allFindings = append(allFindings, report.Findings...)
+		for _, w := range report.Warnings {
+			if !slices.Contains(warnings, w) {
+				warnings = append(warnings, w)
+			}
+		}
 		totalLLMMs += report.Timing.LLMMs
+		reviewedDiffs.WriteString(diff.Diff)
+		changedFiles = append(changedFiles, diff.Files...)
 	}
 
-	// Deduplicate and sort
+	// Deduplicate, sort, and apply the max findings limit
 	allFindings = review.DeduplicateFindings(allFindings)
-	review.SortFindings(allFindings)
-
-	// Apply max findings limit
-	if cfg.MaxFindings > 0 && len(allFindings) > cfg.MaxFindings {
-		allFindings = allFindings[:cfg.MaxFindings]
-	}
+	allFindings = review.LimitFindings(allFindings, cfg.MaxFindings)
 
 	// Build a synthetic DiffResult for the aggregate report
 	meta, _ := gitctx.GetRepoMeta()
 	synthDiff := gitctx.DiffResult{
+		Diff:  reviewedDiffs.String(),
 		Mode:  "range",
 		Range: revRange,
 		Repo:  meta,
 	}
 
 	report := review.BuildReport(synthDiff, allFindings, totalLLMMs, time.Since(startTime).Milliseconds())
-
-	if err := output.WriteReport(report, cfg.Format, flagOut); err != nil {
-		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
-		exitCode = ExitRuntimeError
-		return
+	report.Warnings = warnings
+
+	applyEscalation(ctx, report, reviewedDiffs.String(), cfg)
+	missingTests := applyRequireTests(report, changedFiles, cfg)
+	applyOwners(report, changedFiles)
+	applyReviewNote(ctx, report, reviewedDiffs.String(), cfg)
+	// Section the aggregated report by commit unless --group-by says otherwise
+	groupBy := flagGroupBy
+	if groupBy == "" {
+		groupBy = output.GroupByCommit
 	}
-
-	// Check fail-on threshold
-	if cfg.FailOn != "none" && cfg.FailOn != "" {
-		for _, f := range report.Findings {
-			if review.MeetsThreshold(f.Severity, cfg.FailOn) {
-				exitCode = ExitFindings
-				return
-			}
-		}
+	if report = publishReport(ctx, report, cfg, groupBy); report == nil {
+		return
 	}
+	applyExitPolicy(report, cfg, missingTests)
 }
 
 var reviewCmd = &cobra.Command{
 	Use:   "review",
 	Short: "Review code changes",
 	Long:  "Review code changes using an LLM provider. Use subcommands to specify what to review.",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		maybeFirstRunSetup()
+	},
 }
 
+var flagPatch bool
+
 var reviewUnstagedCmd = &cobra.Command{
 	Use:   "unstaged",
 	Short: "Review unstaged changes (working tree vs index)",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.Load(buildOverrides())
+		cfg, err := loadConfig()
 		if err != nil {
 			return err
 		}
+		if flagPatch && (!isTerminal(os.Stdin) || !isTerminal(os.Stderr)) {
+			fmt.Fprintln(os.Stderr, "Error: --patch needs an interactive terminal; use --paths or --exclude to narrow a non-interactive review")
+			exitCode = ExitUsageError
+			return nil
+		}
+		if flagInteractive && (!isTerminal(os.Stdin) || !isTerminal(os.Stderr)) {
+			fmt.Fprintln(os.Stderr, "Error: --interactive needs an interactive terminal")
+			exitCode = ExitUsageError
+			return nil
+		}
 		diff, err := gitctx.Unstaged(buildDiffOpts(cfg))
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			exitCode = ExitRuntimeError
 			return nil
 		}
-		runReview(diff, cfg)
+		if flagPatch {
+			diff = selectPatch(os.Stdin, os.Stderr, diff)
+			if strings.TrimSpace(diff.Diff) == "" {
+				fmt.Fprintln(os.Stderr, "No hunks selected — nothing to review.")
+				return nil
+			}
+		}
+		runReview(cmd.Context(), diff, cfg)
 		return nil
 	},
 }
 
+var (
+	flagIndex bool
+	flagAmend bool
+)
+
 var reviewStagedCmd = &cobra.Command{
 	Use:   "staged",
 	Short: "Review staged changes (index vs HEAD)",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.Load(buildOverrides())
+		cfg, err := loadConfig()
 		if err != nil {
 			return err
 		}
-		diff, err := gitctx.Staged(buildDiffOpts(cfg))
+		if flagAmend && flagIndex {
+			fmt.Fprintln(os.Stderr, "Error: --amend and --index cannot be combined")
+			exitCode = ExitUsageError
+			return nil
+		}
+		var diff gitctx.DiffResult
+		switch {
+		case flagAmend:
+			diff, err = gitctx.Amend(buildDiffOpts(cfg))
+		case flagIndex:
+			diff, err = gitctx.Index(buildDiffOpts(cfg))
+		default:
+			diff, err = gitctx.Staged(buildDiffOpts(cfg))
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			exitCode = ExitRuntimeError
 			return nil
 		}
-		runReview(diff, cfg)
+		if len(diff.PartiallyStaged) > 0 {
+			fmt.Fprintf(os.Stderr, "Note: %d partially staged file(s); line numbers refer to the staged version: %s\n",
+				len(diff.PartiallyStaged), strings.Join(diff.PartiallyStaged, ", "))
+		}
+		runReview(cmd.Context(), diff, cfg)
 		return nil
 	},
 }
@@ -348,7 +930,7 @@ var reviewCommitCmd = &cobra.Command{
 	Short: "Review a specific commit",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.Load(buildOverrides())
+		cfg, err := loadConfig()
 		if err != nil {
 			return err
 		}
@@ -358,7 +940,7 @@ var reviewCommitCmd = &cobra.Command{
 			exitCode = ExitRuntimeError
 			return nil
 		}
-		runReview(diff, cfg)
+		runReview(cmd.Context(), diff, cfg)
 		return nil
 	},
 }
@@ -373,7 +955,7 @@ var reviewRangeCmd = &cobra.Command{
 	Short: "Review a revision range (e.g., origin/main..HEAD)",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.Load(buildOverrides())
+		cfg, err := loadConfig()
 		if err != nil {
 			return err
 		}
@@ -385,7 +967,7 @@ var reviewRangeCmd = &cobra.Command{
 		}
 
 		if flagPerCommit {
-			runPerCommitReview(args[0], cfg)
+			runPerCommitReview(cmd.Context(), args[0], cfg)
 			return nil
 		}
 
@@ -395,7 +977,45 @@ var reviewRangeCmd = &cobra.Command{
 			exitCode = ExitRuntimeError
 			return nil
 		}
-		runReview(diff, cfg)
+		runReview(cmd.Context(), diff, cfg)
+		return nil
+	},
+}
+
+var flagBranchBase string
+
+var reviewBranchCmd = &cobra.Command{
+	Use:   "branch",
+	Short: "Review the current branch against the default branch (merge-base...HEAD)",
+	Long: `Review every change on the current branch since it diverged from the
+default branch. The base is detected from origin/HEAD, falling back to
+main or master; use --base to choose it explicitly.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		base := flagBranchBase
+		if base == "" {
+			base, err = gitctx.DefaultBranch()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v; use --base to choose one\n", err)
+				exitCode = ExitUsageError
+				return nil
+			}
+		}
+		revRange := base + "..HEAD"
+		fmt.Fprintf(os.Stderr, "Reviewing %s (merge base)\n", revRange)
+
+		diff, err := gitctx.Range(revRange, true, buildDiffOpts(cfg))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exitCode = ExitRuntimeError
+			return nil
+		}
+		runReview(cmd.Context(), diff, cfg)
 		return nil
 	},
 }
@@ -404,18 +1024,38 @@ var (
 	flagSnippetPath        string
 	flagSnippetLang        string
 	flagSnippetBase        string
+	flagSnippetFiles       []string
 	flagMaxFindingsPerFile int
+	flagMaxFilesPerChunk   int
+	flagSinceDays          int
+	flagFilesFrom          string
 )
 
 var reviewSnippetCmd = &cobra.Command{
 	Use:   "snippet",
-	Short: "Review code from stdin",
+	Short: "Review code from stdin or --file",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.Load(buildOverrides())
+		cfg, err := loadConfig()
 		if err != nil {
 			return err
 		}
 
+		if len(flagSnippetFiles) > 0 {
+			if flagSnippetPath != "" || flagSnippetBase != "" {
+				fmt.Fprintln(os.Stderr, "Error: --file cannot be combined with --path or --base")
+				exitCode = ExitUsageError
+				return nil
+			}
+			diff, err := snippetFiles(flagSnippetFiles, flagSnippetLang, buildDiffOpts(cfg))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exitCode = ExitRuntimeError
+				return nil
+			}
+			runReview(cmd.Context(), diff, cfg)
+			return nil
+		}
+
 		content, err := io.ReadAll(os.Stdin)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
@@ -439,40 +1079,164 @@ var reviewSnippetCmd = &cobra.Command{
 			path = "stdin"
 		}
 
-		diff, err := gitctx.Snippet(string(content), path, flagSnippetLang, base)
+		diff, err := gitctx.SnippetWithOptions(string(content), path, flagSnippetLang, base, buildDiffOpts(cfg))
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			exitCode = ExitRuntimeError
 			return nil
 		}
-		runReview(diff, cfg)
+		runReview(cmd.Context(), diff, cfg)
 		return nil
 	},
 }
 
+// snippetFiles builds one snippet diff covering every --file, each under its
+// own path, so a single review reports findings against the right files.
+func snippetFiles(paths []string, lang string, opts gitctx.DiffOptions) (gitctx.DiffResult, error) {
+	combined := gitctx.DiffResult{Mode: "snippet"}
+	var b strings.Builder
+	for _, p := range paths {
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return gitctx.DiffResult{}, fmt.Errorf("reading %s: %w", p, err)
+		}
+		diff, err := gitctx.SnippetWithOptions(string(content), filepath.ToSlash(filepath.Clean(p)), lang, "", opts)
+		if err != nil {
+			return gitctx.DiffResult{}, err
+		}
+		b.WriteString(diff.Diff)
+		if !strings.HasSuffix(diff.Diff, "\n") {
+			b.WriteString("\n")
+		}
+		combined.Files = append(combined.Files, diff.Files...)
+	}
+	combined.Diff = b.String()
+	return combined, nil
+}
+
+// wholeFileDiffOpts builds diff options for the codebase and dir reviews,
+// adding the --since-days cutoff.
+func wholeFileDiffOpts(cfg config.Config) (gitctx.DiffOptions, error) {
+	opts := buildDiffOpts(cfg)
+	if flagSinceDays < 0 {
+		return opts, fmt.Errorf("--since-days must not be negative, got %d", flagSinceDays)
+	}
+	if flagSinceDays > 0 {
+		opts.ModifiedSince = time.Now().AddDate(0, 0, -flagSinceDays)
+	}
+	return opts, nil
+}
+
 var reviewCodebaseCmd = &cobra.Command{
 	Use:   "codebase",
 	Short: "Review all tracked files in the repository",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.Load(buildOverrides())
+		cfg, err := loadConfig()
 		if err != nil {
 			return err
 		}
-		diff, err := gitctx.Codebase(buildDiffOpts(cfg))
+		opts, err := wholeFileDiffOpts(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exitCode = ExitUsageError
+			return nil
+		}
+		diff, err := gitctx.Codebase(opts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			exitCode = ExitRuntimeError
 			return nil
 		}
-		runCodebaseReview(diff, cfg)
+		runCodebaseReview(cmd.Context(), diff, cfg)
 		return nil
 	},
 }
 
-func runCodebaseReview(diff gitctx.DiffResult, cfg config.Config) {
+var reviewDirCmd = &cobra.Command{
+	Use:   "dir <path>",
+	Short: "Review all files in a directory (no git repository required)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		opts, err := wholeFileDiffOpts(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exitCode = ExitUsageError
+			return nil
+		}
+		diff, err := gitctx.Dir(args[0], opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exitCode = ExitRuntimeError
+			return nil
+		}
+		runCodebaseReview(cmd.Context(), diff, cfg)
+		return nil
+	},
+}
+
+var reviewFilesCmd = &cobra.Command{
+	Use:   "files --from <file>",
+	Short: "Review the current content of files listed one per line in a file",
+	Long: `Review the current content of the files listed in --from, one path per
+line, as whole files like review codebase. Use it when CI has already computed
+the changed files and the git range is unavailable. Pass "-" to read the list
+from stdin. Paths are relative to the repository root (or the current
+directory outside a repository); --paths and --exclude apply, and listed files
+that no longer exist are skipped with a warning.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		if flagFilesFrom == "" {
+			fmt.Fprintln(os.Stderr, "Error: --from is required")
+			exitCode = ExitUsageError
+			return nil
+		}
+		paths, err := readPathList(flagFilesFrom)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exitCode = ExitRuntimeError
+			return nil
+		}
+		diff := gitctx.FromFiles(paths, buildDiffOpts(cfg))
+		diff.Range = flagFilesFrom
+		runCodebaseReview(cmd.Context(), diff, cfg)
+		return nil
+	},
+}
+
+// readPathList reads newline-separated paths from name, or from stdin when
+// name is "-". Blank lines are ignored.
+func readPathList(name string) ([]string, error) {
+	var data []byte
+	var err error
+	if name == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading file list: %w", err)
+	}
+	var paths []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths, nil
+}
+
+func runCodebaseReview(ctx context.Context, diff gitctx.DiffResult, cfg config.Config) {
 	if flagNoRedact {
 		cfg.Privacy.RedactSecrets = false
-		fmt.Fprintln(os.Stderr, "WARNING: secret redaction is disabled")
+		fmt.Fprintln(os.Stderr, "WARNING: secret and path redaction are disabled")
 	}
 
 	var compareModels []string
@@ -482,21 +1246,21 @@ func runCodebaseReview(diff gitctx.DiffResult, cfg config.Config) {
 		compareModels = cfg.Compare
 	}
 
-	ctx := context.Background()
-
 	var report *review.Report
 	var err error
 
 	if len(compareModels) >= 2 {
 		maxPerFile := flagMaxFindingsPerFile
 		codebaseBuilder := func(chunkDiff string, files []string, c config.Config, r *review.Rules) (string, string) {
-			return review.CodebaseSystemPrompt(), review.BuildCodebaseUserPrompt(chunkDiff, files, c.MaxFindings, maxPerFile, c.FailOn, r)
+			return review.CodebaseSystemPromptWithCategories(c.ExtraCategories), review.BuildCodebaseUserPrompt(chunkDiff, files, c.MaxFindings, maxPerFile, c.FailOn, r)
 		}
 		report, err = runCompareMode(ctx, diff, cfg, compareModels, codebaseBuilder)
 	} else {
 		cbCfg := review.CodebaseConfig{
 			Config:             cfg,
 			MaxFindingsPerFile: flagMaxFindingsPerFile,
+			MaxFilesPerChunk:   flagMaxFilesPerChunk,
+			OnFinding:          streamFindings(cfg),
 		}
 		report, err = review.RunCodebase(ctx, diff, cbCfg)
 	}
@@ -507,25 +1271,33 @@ func runCodebaseReview(diff gitctx.DiffResult, cfg config.Config) {
 			exitCode = ExitAuthError
 			return
 		}
+		if providers.IsModelError(err) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exitCode = ExitUsageError
+			return
+		}
+		if timedOut(ctx) {
+			fmt.Fprintf(os.Stderr, "Error: review timed out after %s (--timeout)\n", flagTimeout)
+			exitCode = ExitRuntimeError
+			return
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		exitCode = ExitRuntimeError
 		return
 	}
 
-	if err := output.WriteReport(report, cfg.Format, flagOut); err != nil {
-		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
-		exitCode = ExitRuntimeError
-		return
+	if len(diff.Missing) > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d listed file(s) do not exist and were skipped: %s",
+			len(diff.Missing), strings.Join(diff.Missing, ", ")))
 	}
-
-	if cfg.FailOn != "none" && cfg.FailOn != "" {
-		for _, f := range report.Findings {
-			if review.MeetsThreshold(f.Severity, cfg.FailOn) {
-				exitCode = ExitFindings
-				return
-			}
-		}
+	applyDropNoop(report, diff.Diff)
+	applyEscalation(ctx, report, diff.Diff, cfg)
+	applyOwners(report, diff.Files)
+	applyReviewNote(ctx, report, diff.Diff, cfg)
+	if report = publishReport(ctx, report, cfg, flagGroupBy); report == nil {
+		return
 	}
+	applyExitPolicy(report, cfg, false)
 }
 
 func init() {
@@ -534,8 +1306,12 @@ func init() {
 	reviewCmd.AddCommand(reviewStagedCmd)
 	reviewCmd.AddCommand(reviewCommitCmd)
 	reviewCmd.AddCommand(reviewRangeCmd)
+	reviewCmd.AddCommand(reviewBranchCmd)
 	reviewCmd.AddCommand(reviewSnippetCmd)
 	reviewCmd.AddCommand(reviewCodebaseCmd)
+	reviewCmd.AddCommand(reviewDirCmd)
+	reviewCmd.AddCommand(reviewFilesCmd)
+	reviewCmd.AddCommand(reviewCombinedCmd)
 
 	// Add shared flags to all review subcommands
 	for _, cmd := range []*cobra.Command{
@@ -543,24 +1319,51 @@ func init() {
 		reviewStagedCmd,
 		reviewCommitCmd,
 		reviewRangeCmd,
+		reviewBranchCmd,
 		reviewSnippetCmd,
 		reviewCodebaseCmd,
+		reviewDirCmd,
+		reviewFilesCmd,
+		reviewCombinedCmd,
 	} {
 		addReviewFlags(cmd)
 	}
 
 	// Codebase-specific flags
 	reviewCodebaseCmd.Flags().IntVar(&flagMaxFindingsPerFile, "max-findings-per-file", 10, "Maximum findings per file")
+	reviewDirCmd.Flags().IntVar(&flagMaxFindingsPerFile, "max-findings-per-file", 10, "Maximum findings per file")
+	reviewCodebaseCmd.Flags().IntVar(&flagMaxFilesPerChunk, "max-files-per-chunk", 0, "Maximum files per review request, in addition to the byte limit (0 = no limit)")
+	reviewDirCmd.Flags().IntVar(&flagMaxFilesPerChunk, "max-files-per-chunk", 0, "Maximum files per review request, in addition to the byte limit (0 = no limit)")
+	reviewCodebaseCmd.Flags().IntVar(&flagSinceDays, "since-days", 0, "Only review files changed by a commit in the last N days (0 = all)")
+	reviewDirCmd.Flags().IntVar(&flagSinceDays, "since-days", 0, "Only review files modified in the last N days (0 = all)")
+	reviewFilesCmd.Flags().IntVar(&flagMaxFindingsPerFile, "max-findings-per-file", 10, "Maximum findings per file")
+	reviewFilesCmd.Flags().IntVar(&flagMaxFilesPerChunk, "max-files-per-chunk", 0, "Maximum files per review request, in addition to the byte limit (0 = no limit)")
+	reviewFilesCmd.Flags().StringVar(&flagFilesFrom, "from", "", "File listing the paths to review, one per line (- for stdin)")
+
+	// Staged-specific flags
+	reviewUnstagedCmd.Flags().BoolVar(&flagPatch, "patch", false, "Choose interactively which hunks to review, like git add -p")
+	reviewUnstagedCmd.Flags().BoolVar(&flagInteractive, "interactive", false, "Triage findings one at a time (accept, dismiss, fix later); dismissals are written to .prism-suppressions.json")
+	reviewStagedCmd.Flags().BoolVar(&flagIndex, "index", false, "Review exactly what will be committed (staged blobs, no diff drivers)")
+	reviewStagedCmd.Flags().BoolVar(&flagAmend, "amend", false, "Review staged changes as the amended commit will contain them (index vs HEAD~1)")
 
 	// Commit-specific flags
 	reviewCommitCmd.Flags().StringVar(&flagParent, "parent", "", "Override parent SHA (for merge commits)")
 
 	// Range-specific flags
 	reviewRangeCmd.Flags().BoolVar(&flagMergeBase, "merge-base", true, "Use merge base for branch comparisons")
+
+	// Branch-specific flags
+	reviewBranchCmd.Flags().StringVar(&flagBranchBase, "base", "", "Base branch (default: detected from origin/HEAD, then main or master)")
 	reviewRangeCmd.Flags().BoolVar(&flagPerCommit, "per-commit", false, "Review each commit separately and aggregate findings")
 
 	// Snippet-specific flags
 	reviewSnippetCmd.Flags().StringVar(&flagSnippetPath, "path", "", "File path (for language detection and messages)")
 	reviewSnippetCmd.Flags().StringVar(&flagSnippetLang, "lang", "", "Language hint")
 	reviewSnippetCmd.Flags().StringVar(&flagSnippetBase, "base", "", "Base file to diff against")
+	reviewSnippetCmd.Flags().StringArrayVar(&flagSnippetFiles, "file", nil, "Review this file instead of stdin (repeatable; findings use each file's path)")
+
+	// Combined-specific flags
+	reviewCombinedCmd.Flags().StringArrayVar(&flagCombinedModes, "mode", nil, "Diff mode to include (repeatable): unstaged, staged, codebase, commit:<sha>, range:<revRange>, dir:<path>")
+	reviewCombinedCmd.Flags().IntVar(&flagMaxFindingsPerFile, "max-findings-per-file", 10, "Maximum findings per file (codebase and dir modes)")
+	reviewCombinedCmd.Flags().IntVar(&flagMaxFilesPerChunk, "max-files-per-chunk", 0, "Maximum files per review request in codebase and dir modes (0 = no limit)")
 }
@@ -1,9 +1,15 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/dshills/prism/internal/notify"
+	"github.com/dshills/prism/internal/output"
+	"github.com/dshills/prism/internal/providers"
 	"github.com/spf13/cobra"
 )
 
@@ -21,7 +27,89 @@ const (
 var rootCmd = &cobra.Command{
 	Use:   "prism",
 	Short: "Local AI code review CLI",
-	Long:  "Prism reviews code changes using LLM providers and emits findings with deterministic exit codes.",
+	Long: `Prism reviews code changes using LLM providers and emits findings with deterministic exit codes.
+
+Exit codes: 0 success, 1 findings at or above --fail-on, 2 usage error,
+3 authentication error, 4 runtime error. Use --explain-exit to print the
+reason for the code on stderr.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if flagEnvFile != "" {
+			if err := loadEnvFile(flagEnvFile); err != nil {
+				return err
+			}
+		}
+		if flagFields != "" {
+			if err := output.ValidateFields(splitComma(flagFields)); err != nil {
+				return err
+			}
+		}
+		if err := output.ValidateGroupBy(flagGroupBy); err != nil {
+			return err
+		}
+		if err := notify.ValidateTargets(splitComma(flagNotify)); err != nil {
+			return err
+		}
+		if err := loadRunInputs(); err != nil {
+			return err
+		}
+		applyTimeout(cmd)
+		applyAudit(cmd)
+		applyUsage(cmd)
+		if flagOffline {
+			providers.SetOffline(true)
+		}
+		return nil
+	},
+}
+
+// flagOffline restricts the run to local providers (see providers.Offline).
+var flagOffline bool
+
+// flagTimeout bounds the wall-clock time of the whole command.
+var flagTimeout time.Duration
+
+// cancelTimeout releases the --timeout context once the command returns.
+var cancelTimeout context.CancelFunc = func() {}
+
+// applyTimeout replaces the command context with one that expires after
+// --timeout, if set.
+func applyTimeout(cmd *cobra.Command) {
+	if flagTimeout <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), flagTimeout)
+	cancelTimeout = cancel
+	cmd.SetContext(ctx)
+}
+
+// auditLog records provider calls for the report when --audit is set.
+var auditLog *providers.AuditLog
+
+// applyAudit replaces the command context with one that records provider
+// calls in auditLog, if --audit is set.
+func applyAudit(cmd *cobra.Command) {
+	if !flagAudit {
+		return
+	}
+	auditLog = providers.NewAuditLog()
+	cmd.SetContext(providers.WithAuditLog(cmd.Context(), auditLog))
+}
+
+// usageTracker totals the tokens and cost of every provider call the
+// command makes, including escalation and review-note calls outside the
+// review itself.
+var usageTracker *providers.UsageTracker
+
+// applyUsage replaces the command context with one that records provider
+// calls in usageTracker.
+func applyUsage(cmd *cobra.Command) {
+	usageTracker = providers.NewUsageTracker(0)
+	cmd.SetContext(providers.WithUsageTracker(cmd.Context(), usageTracker))
+}
+
+// timedOut reports whether ctx was cancelled by the --timeout deadline.
+func timedOut(ctx context.Context) bool {
+	return flagTimeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded)
 }
 
 // Run executes the root command and returns an exit code.
@@ -30,21 +118,71 @@ func Run() int {
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(modelsCmd)
 	rootCmd.AddCommand(cacheCmd)
+	rootCmd.AddCommand(historyCmd)
 	rootCmd.AddCommand(hookCmd)
 	rootCmd.AddCommand(githubCmd)
+	rootCmd.AddCommand(gitlabCmd)
+	rootCmd.AddCommand(formatCmd)
+	rootCmd.AddCommand(badgeCmd)
+	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(versionCmd)
 
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	cancelTimeout()
+	code := exitCode
+	if err != nil {
 		// Cobra already prints the error
-		return ExitUsageError
+		code = ExitUsageError
 	}
-
-	return exitCode
+	if flagExplainExit {
+		fmt.Fprintln(os.Stderr, explainExit(code, exitDetail))
+	}
+	return code
 }
 
 // exitCode is set by command handlers to control the process exit code.
 var exitCode = ExitSuccess
 
+// exitDetail optionally replaces the generic --explain-exit message for
+// exitCode with a more specific reason.
+var exitDetail string
+
+// flagExplainExit prints the meaning of the exit code on stderr.
+var flagExplainExit bool
+
+// explainExit returns a one-line explanation of an exit code, preferring
+// detail when set.
+func explainExit(code int, detail string) string {
+	meaning := detail
+	if meaning == "" {
+		switch code {
+		case ExitSuccess:
+			meaning = "success; no findings met the --fail-on threshold"
+		case ExitFindings:
+			meaning = "findings met the --fail-on threshold"
+		case ExitUsageError:
+			meaning = "usage error: invalid arguments, flags, configuration, or an unknown model"
+		case ExitAuthError:
+			meaning = "authentication error: check the provider API key"
+		case ExitRuntimeError:
+			meaning = "runtime error: git, provider, network, timeout, or output failure"
+		default:
+			meaning = "unknown exit code"
+		}
+	}
+	return fmt.Sprintf("exit %d: %s", code, meaning)
+}
+
+func init() {
+	// Run the root hook (--env-file, --timeout) as well as subcommand hooks such as the
+	// review first-run setup.
+	cobra.EnableTraverseRunHooks = true
+	rootCmd.PersistentFlags().DurationVar(&flagTimeout, "timeout", 0, "Abort the command after this duration (e.g. 5m); 0 means no limit")
+	rootCmd.PersistentFlags().BoolVar(&flagExplainExit, "explain-exit", false, "Print a one-line explanation of the exit code to stderr (0 ok, 1 findings, 2 usage, 3 auth, 4 runtime)")
+	rootCmd.PersistentFlags().StringVar(&flagEnvFile, "env-file", "", "Load KEY=VALUE pairs (e.g. API keys) from a dotenv file; variables already set are not overridden")
+	rootCmd.PersistentFlags().BoolVar(&flagOffline, "offline", false, "Only allow local providers (ollama, lmstudio); refuse cloud providers before any network call")
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print prism version",
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dshills/prism/internal/config"
+	"github.com/dshills/prism/internal/gitctx"
+	"github.com/dshills/prism/internal/output"
+	"github.com/dshills/prism/internal/review"
+)
+
+// dismissalsFile is where --interactive records dismissed findings, at the
+// root of the repository. Every review drops the findings it lists.
+const dismissalsFile = ".prism-suppressions.json"
+
+// dismissals are the entries of the dismissals file, loaded by
+// loadRunInputs.
+var dismissals []output.SARIFSuppression
+
+// dismissalsPath returns the dismissals file of the current repository, or
+// of the working directory outside a repository.
+func dismissalsPath() string {
+	root := "."
+	if meta, err := gitctx.GetRepoMeta(); err == nil && meta.Root != "" {
+		root = meta.Root
+	}
+	return filepath.Join(root, dismissalsFile)
+}
+
+// loadDismissals reads the dismissals file. A missing file means no
+// dismissals.
+func loadDismissals() ([]output.SARIFSuppression, error) {
+	sups, err := output.LoadSARIFSuppressions(dismissalsPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return sups, err
+}
+
+// applyDismissals drops the findings listed in the dismissals file and
+// returns how many it dropped.
+func applyDismissals(report *review.Report) int {
+	if len(dismissals) == 0 {
+		return 0
+	}
+	kept := make([]review.Finding, 0, len(report.Findings))
+	for _, f := range report.Findings {
+		if !output.Suppressed(dismissals, f) {
+			kept = append(kept, f)
+		}
+	}
+	dropped := len(report.Findings) - len(kept)
+	if dropped > 0 {
+		report.Findings = kept
+		report.Summary = review.ComputeSummary(report.Findings)
+	}
+	return dropped
+}
+
+// fixLaterTag marks findings deferred during interactive triage.
+const fixLaterTag = "fix-later"
+
+// flagInteractive enables triage: a full-screen view on the terminal (see
+// triageScreen), or a line-based prompt on stderr where the terminal cannot
+// be put in raw mode.
+var flagInteractive bool
+
+// triageResult counts the decisions made during interactive triage.
+type triageResult struct {
+	accepted, fixLater int
+	dismissed          []review.Finding
+}
+
+// triageFindings is the line-based triage prompt. It shows each finding on
+// out and asks what to do with it.
+// Answers are a (accept), d (dismiss), f (fix later: tag the finding), and
+// q (accept this and all remaining findings); end of input answers q.
+// It returns the findings to keep.
+func triageFindings(in io.Reader, out io.Writer, findings []review.Finding, icons map[review.Severity]string) ([]review.Finding, triageResult) {
+	r := bufio.NewReader(in)
+	tw := &output.TextWriter{Icons: icons}
+	var res triageResult
+	kept := make([]review.Finding, 0, len(findings))
+	rest := false // true once the user has accepted the remaining findings
+	for i, f := range findings {
+		answer := "a"
+		if !rest {
+			fmt.Fprintf(out, "\nFinding %d/%d\n", i+1, len(findings))
+			_ = tw.WriteFinding(out, f)
+			fmt.Fprintf(out, "%s\n", f.Message)
+			if f.Suggestion != "" {
+				fmt.Fprintf(out, "Suggestion: %s\n", f.Suggestion)
+			}
+			answer = promptTriage(r, out)
+		}
+		switch answer {
+		case "d":
+			res.dismissed = append(res.dismissed, f)
+			continue
+		case "f":
+			f.Tags = append(f.Tags, fixLaterTag)
+			res.fixLater++
+		case "q":
+			rest = true
+			res.accepted++
+		default:
+			res.accepted++
+		}
+		kept = append(kept, f)
+	}
+	return kept, res
+}
+
+// promptTriage reads an answer until a valid one is given. End of input
+// answers q.
+func promptTriage(r *bufio.Reader, out io.Writer) string {
+	for {
+		fmt.Fprint(out, "Keep this finding [a,d,f,q,?]? ")
+		line, err := r.ReadString('\n')
+		switch answer := strings.ToLower(strings.TrimSpace(line)); answer {
+		case "a", "d", "f", "q":
+			return answer
+		case "?":
+			fmt.Fprintln(out, "a - accept the finding\nd - dismiss it and record it in the dismissals file\nf - keep it, tagged fix-later\nq - accept this and all remaining findings")
+		default:
+			if err != nil {
+				return "q"
+			}
+			if answer != "" {
+				fmt.Fprintln(out, "Please answer a, d, f, or q.")
+			}
+		}
+	}
+}
+
+// applyInteractive runs interactive triage on the report's findings,
+// drops dismissed ones, and appends them to the dismissals file so later
+// reviews drop them too.
+func applyInteractive(report *review.Report, cfg config.Config) error {
+	if !flagInteractive || len(report.Findings) == 0 {
+		return nil
+	}
+	var findings []review.Finding
+	var res triageResult
+	if restore, err := makeRaw(os.Stdin); err == nil {
+		findings, res = triageScreen(os.Stdin, os.Stderr, report.Findings, severityIcons(cfg))
+		restore()
+	} else {
+		findings, res = triageFindings(os.Stdin, os.Stderr, report.Findings, severityIcons(cfg))
+	}
+	report.Findings = findings
+	report.Summary = review.ComputeSummary(report.Findings)
+	report.Summary.Verdict = review.ComputeVerdict(report.Findings, cfg.FailOn)
+
+	sups := dismissals
+	for _, f := range res.dismissed {
+		key := f.StableKey
+		if key == "" {
+			key = f.ID
+		}
+		sups = append(sups, output.SARIFSuppression{
+			Fingerprint:   key,
+			Justification: "dismissed in interactive review: " + f.Title,
+		})
+	}
+	path := dismissalsPath()
+	if len(res.dismissed) > 0 {
+		data, err := json.MarshalIndent(sups, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding suppressions: %w", err)
+		}
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			return fmt.Errorf("writing dismissals file: %w", err)
+		}
+		dismissals = sups
+	}
+	fmt.Fprintf(os.Stderr, "\nTriage: %d accepted, %d dismissed, %d fix later", res.accepted, len(res.dismissed), res.fixLater)
+	if len(res.dismissed) > 0 {
+		fmt.Fprintf(os.Stderr, "; dismissals recorded in %s", path)
+	}
+	fmt.Fprintln(os.Stderr)
+	return nil
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/dshills/prism/internal/output"
+	"github.com/dshills/prism/internal/review"
+)
+
+// Terminal control sequences for the full-screen triage view.
+const (
+	enterAltScreen = "\x1b[?1049h\x1b[?25l"
+	leaveAltScreen = "\x1b[?25h\x1b[?1049l"
+	clearScreen    = "\x1b[H\x1b[2J"
+)
+
+// triageDecision is the choice made for one finding on the triage screen.
+type triageDecision int
+
+const (
+	undecided triageDecision = iota
+	decidedAccept
+	decidedDismiss
+	decidedFixLater
+)
+
+func (d triageDecision) String() string {
+	switch d {
+	case decidedAccept:
+		return "accepted"
+	case decidedDismiss:
+		return "dismissed"
+	case decidedFixLater:
+		return "fix later"
+	}
+	return "undecided"
+}
+
+// triageScreen runs full-screen triage, reading single keys from in, which
+// the caller has put in raw mode. It shows one finding per screen: a, d,
+// and f accept, dismiss, or tag the finding fix-later and move to the next
+// one; n, space, or the right arrow and p or the left arrow page without
+// deciding, so an earlier decision can be changed. Moving past the last
+// finding, q, Ctrl-C, or end of input finish triage, and findings left
+// undecided are accepted. It returns the findings to keep.
+func triageScreen(in io.Reader, out io.Writer, findings []review.Finding, icons map[review.Severity]string) ([]review.Finding, triageResult) {
+	r := bufio.NewReader(in)
+	tw := &output.TextWriter{Icons: icons}
+	decisions := make([]triageDecision, len(findings))
+
+	fmt.Fprint(out, enterAltScreen)
+	for i := 0; i < len(findings); {
+		drawTriageScreen(out, tw, findings, decisions, i)
+		key, err := readTriageKey(r)
+		if err != nil {
+			break
+		}
+		switch key {
+		case "a":
+			decisions[i] = decidedAccept
+			i++
+		case "d":
+			decisions[i] = decidedDismiss
+			i++
+		case "f":
+			decisions[i] = decidedFixLater
+			i++
+		case "n", " ", "right":
+			i++
+		case "p", "left":
+			if i > 0 {
+				i--
+			}
+		case "q", "ctrl-c":
+			i = len(findings)
+		}
+	}
+	fmt.Fprint(out, leaveAltScreen)
+
+	var res triageResult
+	kept := make([]review.Finding, 0, len(findings))
+	for i, f := range findings {
+		switch decisions[i] {
+		case decidedDismiss:
+			res.dismissed = append(res.dismissed, f)
+			continue
+		case decidedFixLater:
+			f.Tags = append(f.Tags, fixLaterTag)
+			res.fixLater++
+		default:
+			res.accepted++
+		}
+		kept = append(kept, f)
+	}
+	return kept, res
+}
+
+// drawTriageScreen redraws the screen for finding i: a header with its
+// position and decision, the finding, and a footer with the running counts
+// and the keys.
+func drawTriageScreen(out io.Writer, tw *output.TextWriter, findings []review.Finding, decisions []triageDecision, i int) {
+	f := findings[i]
+	fmt.Fprint(out, clearScreen)
+	fmt.Fprintf(out, "Finding %d/%d  [%s]\n\n", i+1, len(findings), decisions[i])
+	_ = tw.WriteFinding(out, f)
+	fmt.Fprintf(out, "%s\n", f.Message)
+	if f.Suggestion != "" {
+		fmt.Fprintf(out, "\nSuggestion: %s\n", f.Suggestion)
+	}
+	counts := make(map[triageDecision]int)
+	for _, d := range decisions {
+		counts[d]++
+	}
+	fmt.Fprintf(out, "\n%d accepted, %d dismissed, %d fix later, %d undecided\n",
+		counts[decidedAccept], counts[decidedDismiss], counts[decidedFixLater], counts[undecided])
+	fmt.Fprint(out, "a accept  d dismiss  f fix later  ←/p previous  →/n next  q finish (accepts the rest)\n")
+}
+
+// readTriageKey reads one key press, naming the arrow keys "left" and
+// "right" and Ctrl-C "ctrl-c". Other escape sequences read as "".
+func readTriageKey(r *bufio.Reader) (string, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	switch b {
+	case 0x03:
+		return "ctrl-c", nil
+	case 0x1b:
+		if next, err := r.ReadByte(); err != nil || next != '[' {
+			return "", nil
+		}
+		switch code, _ := r.ReadByte(); code {
+		case 'C':
+			return "right", nil
+		case 'D':
+			return "left", nil
+		}
+		return "", nil
+	}
+	if b >= 'A' && b <= 'Z' {
+		b += 'a' - 'A'
+	}
+	return string(b), nil
+}
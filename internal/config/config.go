@@ -12,19 +12,38 @@ import (
 
 // Config represents the prism configuration.
 type Config struct {
-	Provider     string        `json:"provider"`
-	Model        string        `json:"model"`
-	Compare      []string      `json:"compare,omitempty"`
-	Format       string        `json:"format"`
-	FailOn       string        `json:"failOn"`
-	MaxFindings  int           `json:"maxFindings"`
-	ContextLines int           `json:"contextLines"`
-	Include      []string      `json:"include"`
-	Exclude      []string      `json:"exclude"`
-	MaxDiffBytes int           `json:"maxDiffBytes"`
-	RulesFile    string        `json:"rulesFile,omitempty"`
-	Cache        CacheConfig   `json:"cache"`
-	Privacy      PrivacyConfig `json:"privacy"`
+	Provider     string   `json:"provider"`
+	Model        string   `json:"model"`
+	Compare      []string `json:"compare,omitempty"`
+	Format       string   `json:"format"`
+	FailOn       string   `json:"failOn"`
+	MaxFindings  int      `json:"maxFindings"`
+	ContextLines int      `json:"contextLines"`
+	Include      []string `json:"include"`
+	Exclude      []string `json:"exclude"`
+	MaxDiffBytes int      `json:"maxDiffBytes"`
+	MinDiffBytes int      `json:"minDiffBytes,omitempty"`
+	RulesFile    string   `json:"rulesFile,omitempty"`
+	GuideFile    string   `json:"guideFile,omitempty"`
+	// PromptTemplateFile is a Go text/template file that overrides the
+	// review prompts (see review.LoadPromptTemplate).
+	PromptTemplateFile string            `json:"promptTemplateFile,omitempty"`
+	TestPatterns       []string          `json:"testPatterns,omitempty"`
+	SeverityFloors     map[string]string `json:"severityFloors,omitempty"`
+	MaxCost            float64           `json:"maxCost,omitempty"`
+	Concurrency        int               `json:"concurrency,omitempty"`
+	RetryBudget        int               `json:"retryBudget,omitempty"`
+	ExtraCategories    []string          `json:"extraCategories,omitempty"`
+	Cache              CacheConfig       `json:"cache"`
+	Privacy            PrivacyConfig     `json:"privacy"`
+	Output             OutputConfig      `json:"output"`
+	History            HistoryConfig     `json:"history"`
+	Notify             NotifyConfig      `json:"notify"`
+	// RepoFile and RepoKeys record the repository config file Load applied
+	// and the keys it set, so the CLI can say so. They are never read from
+	// or written to a config file.
+	RepoFile string   `json:"-"`
+	RepoKeys []string `json:"-"`
 }
 
 // CacheConfig controls caching behavior.
@@ -32,6 +51,13 @@ type CacheConfig struct {
 	Enabled    bool   `json:"enabled"`
 	Dir        string `json:"dir,omitempty"`
 	TTLSeconds int    `json:"ttlSeconds"`
+	// PerRepo keeps each repository's entries in its own subdirectory of
+	// Dir so cache clear and cache show only affect the current repo.
+	PerRepo bool `json:"perRepo,omitempty"`
+	// Refresh skips cache reads but still writes results, so a run sees
+	// fresh responses while keeping the cache warm. It is set per run by
+	// --refresh-cache and never read from the config file.
+	Refresh bool `json:"-"`
 }
 
 // PrivacyConfig controls privacy/redaction behavior.
@@ -40,6 +66,30 @@ type PrivacyConfig struct {
 	RedactPaths   []string `json:"redactPaths,omitempty"`
 }
 
+// OutputConfig controls report rendering.
+type OutputConfig struct {
+	// Icons maps severity ("critical", "high", "medium", "low", "info") to
+	// the icon shown by the text and markdown writers. An empty value hides
+	// the icon.
+	Icons map[string]string `json:"icons,omitempty"`
+}
+
+// HistoryConfig controls the run history used by prism history.
+type HistoryConfig struct {
+	// Enabled appends a record of every review report to File.
+	Enabled bool `json:"enabled"`
+	// File is the JSON-lines history file. Empty means the default data
+	// directory (see history.DefaultPath).
+	File string `json:"file,omitempty"`
+}
+
+// NotifyConfig holds the destinations used by --notify.
+type NotifyConfig struct {
+	// SlackWebhookURL is the Slack incoming webhook for --notify slack.
+	// PRISM_SLACK_WEBHOOK_URL overrides it.
+	SlackWebhookURL string `json:"slackWebhookUrl,omitempty"`
+}
+
 // Default returns a Config with all defaults applied.
 func Default() Config {
 	return Config{
@@ -52,6 +102,16 @@ func Default() Config {
 		Include:      []string{"**/*"},
 		Exclude:      []string{"vendor/**", "**/*.gen.go", "**/dist/**"},
 		MaxDiffBytes: 500000,
+		TestPatterns: []string{
+			"**/*_test.go",
+			"**/test_*.py",
+			"**/*_test.py",
+			"**/*.test.js",
+			"**/*.spec.js",
+			"**/*.test.ts",
+			"**/*.spec.ts",
+			"**/*Test.java",
+		},
 		Cache: CacheConfig{
 			Enabled:    true,
 			TTLSeconds: 86400,
@@ -130,7 +190,8 @@ func Save(cfg Config) error {
 	return os.WriteFile(path, data, 0o644)
 }
 
-// Load builds the effective config by merging: defaults <- file <- env <- overrides.
+// Load builds the effective config by merging: defaults <- file <- repo file <- env <- overrides.
+// The repo file is found from the working directory (see RepoConfigPath).
 // The overrides map comes from CLI flags (only non-zero values should be set).
 func Load(overrides map[string]string) (Config, error) {
 	cfg := Default()
@@ -140,6 +201,18 @@ func Load(overrides map[string]string) (Config, error) {
 		return Config{}, err
 	}
 	mergeFile(&cfg, fileCfg)
+
+	repoPath, err := RepoConfigPath(".")
+	if err != nil {
+		return Config{}, err
+	}
+	if repoPath != "" {
+		rf, err := loadRepoFile(repoPath)
+		if err != nil {
+			return Config{}, err
+		}
+		mergeRepo(&cfg, rf)
+	}
 	if err := mergeEnv(&cfg); err != nil {
 		return Config{}, err
 	}
@@ -179,15 +252,45 @@ func mergeFile(dst *Config, src Config) {
 	if src.MaxDiffBytes > 0 {
 		dst.MaxDiffBytes = src.MaxDiffBytes
 	}
+	if src.MinDiffBytes > 0 {
+		dst.MinDiffBytes = src.MinDiffBytes
+	}
 	if src.RulesFile != "" {
 		dst.RulesFile = src.RulesFile
 	}
+	if src.GuideFile != "" {
+		dst.GuideFile = src.GuideFile
+	}
+	if src.PromptTemplateFile != "" {
+		dst.PromptTemplateFile = src.PromptTemplateFile
+	}
+	if len(src.TestPatterns) > 0 {
+		dst.TestPatterns = src.TestPatterns
+	}
+	if len(src.SeverityFloors) > 0 {
+		dst.SeverityFloors = src.SeverityFloors
+	}
+	if src.MaxCost > 0 {
+		dst.MaxCost = src.MaxCost
+	}
+	if src.Concurrency > 0 {
+		dst.Concurrency = src.Concurrency
+	}
+	if src.RetryBudget > 0 {
+		dst.RetryBudget = src.RetryBudget
+	}
+	if len(src.ExtraCategories) > 0 {
+		dst.ExtraCategories = src.ExtraCategories
+	}
 	if src.Cache.Dir != "" {
 		dst.Cache.Dir = src.Cache.Dir
 	}
 	if src.Cache.TTLSeconds > 0 {
 		dst.Cache.TTLSeconds = src.Cache.TTLSeconds
 	}
+	if src.Cache.PerRepo {
+		dst.Cache.PerRepo = true
+	}
 	// Bool fields: JSON zero value for bool is false, so we can't distinguish
 	// "unset" from "explicitly false" without custom unmarshaling. Use a heuristic:
 	// if the file had any non-zero field, it was loaded and we trust its booleans.
@@ -200,6 +303,18 @@ func mergeFile(dst *Config, src Config) {
 	if len(src.Privacy.RedactPaths) > 0 {
 		dst.Privacy.RedactPaths = src.Privacy.RedactPaths
 	}
+	if len(src.Output.Icons) > 0 {
+		dst.Output.Icons = src.Output.Icons
+	}
+	if src.History.Enabled {
+		dst.History.Enabled = true
+	}
+	if src.History.File != "" {
+		dst.History.File = src.History.File
+	}
+	if src.Notify.SlackWebhookURL != "" {
+		dst.Notify.SlackWebhookURL = src.Notify.SlackWebhookURL
+	}
 }
 
 func mergeEnv(cfg *Config) error {
@@ -215,6 +330,9 @@ func mergeEnv(cfg *Config) error {
 	if v := os.Getenv("PRISM_FORMAT"); v != "" {
 		cfg.Format = v
 	}
+	if v := os.Getenv("PRISM_SLACK_WEBHOOK_URL"); v != "" {
+		cfg.Notify.SlackWebhookURL = v
+	}
 	if v := os.Getenv("PRISM_MAX_FINDINGS"); v != "" {
 		n, err := strconv.Atoi(v)
 		if err != nil {
@@ -229,6 +347,13 @@ func mergeEnv(cfg *Config) error {
 		}
 		cfg.ContextLines = n
 	}
+	if v := os.Getenv("PRISM_CACHE_PER_REPO"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("PRISM_CACHE_PER_REPO must be a boolean, got %q", v)
+		}
+		cfg.Cache.PerRepo = b
+	}
 	return nil
 }
 
@@ -263,12 +388,48 @@ func mergeOverrides(cfg *Config, overrides map[string]string) {
 			cfg.MaxDiffBytes = n
 		}
 	}
+	if v, ok := overrides["minDiffBytes"]; ok && v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.MinDiffBytes = n
+		}
+	}
 	if v, ok := overrides["rulesFile"]; ok && v != "" {
 		cfg.RulesFile = v
 	}
+	if v, ok := overrides["guideFile"]; ok && v != "" {
+		cfg.GuideFile = v
+	}
+	if v, ok := overrides["promptTemplateFile"]; ok && v != "" {
+		cfg.PromptTemplateFile = v
+	}
 	if v, ok := overrides["compare"]; ok && v != "" {
 		cfg.Compare = strings.Split(v, ",")
 	}
+	if v, ok := overrides["maxCost"]; ok && v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			cfg.MaxCost = f
+		}
+	}
+	if v, ok := overrides["concurrency"]; ok && v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.Concurrency = n
+		}
+	}
+	if v, ok := overrides["retryBudget"]; ok && v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.RetryBudget = n
+		}
+	}
+	if v, ok := overrides["refreshCache"]; ok && v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.Cache.Refresh = b
+		}
+	}
+	if v, ok := overrides["cachePerRepo"]; ok && v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.Cache.PerRepo = b
+		}
+	}
 }
 
 // SetField sets a single config field by key name. Returns error if key is unknown.
@@ -300,8 +461,42 @@ func SetField(cfg *Config, key, value string) error {
 			return fmt.Errorf("maxDiffBytes must be an integer: %w", err)
 		}
 		cfg.MaxDiffBytes = n
+	case "minDiffBytes":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("minDiffBytes must be an integer: %w", err)
+		}
+		cfg.MinDiffBytes = n
 	case "rulesFile":
 		cfg.RulesFile = value
+	case "guideFile":
+		cfg.GuideFile = value
+	case "promptTemplateFile":
+		cfg.PromptTemplateFile = value
+	case "maxCost":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("maxCost must be a number: %w", err)
+		}
+		cfg.MaxCost = f
+	case "concurrency":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("concurrency must be an integer: %w", err)
+		}
+		cfg.Concurrency = n
+	case "retryBudget":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("retryBudget must be an integer: %w", err)
+		}
+		cfg.RetryBudget = n
+	case "cache.perRepo":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("cache.perRepo must be a boolean: %w", err)
+		}
+		cfg.Cache.PerRepo = b
 	default:
 		return fmt.Errorf("unknown config key: %s", key)
 	}
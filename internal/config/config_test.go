@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -130,6 +132,10 @@ func TestSetField(t *testing.T) {
 		{"contextLines", "10"},
 		{"maxDiffBytes", "1000000"},
 		{"rulesFile", "rules.json"},
+		{"maxCost", "0.5"},
+		{"minDiffBytes", "200"},
+		{"retryBudget", "5"},
+		{"cache.perRepo", "true"},
 	}
 
 	for _, tt := range tests {
@@ -144,6 +150,18 @@ func TestSetField(t *testing.T) {
 	if cfg.MaxFindings != 100 {
 		t.Errorf("MaxFindings = %d, want 100", cfg.MaxFindings)
 	}
+	if cfg.MaxCost != 0.5 {
+		t.Errorf("MaxCost = %v, want 0.5", cfg.MaxCost)
+	}
+	if cfg.MinDiffBytes != 200 {
+		t.Errorf("MinDiffBytes = %d, want 200", cfg.MinDiffBytes)
+	}
+	if cfg.RetryBudget != 5 {
+		t.Errorf("RetryBudget = %d, want 5", cfg.RetryBudget)
+	}
+	if !cfg.Cache.PerRepo {
+		t.Error("Cache.PerRepo should be set")
+	}
 }
 
 func TestSetField_UnknownKey(t *testing.T) {
@@ -238,6 +256,7 @@ func TestMergeFile_AllFields(t *testing.T) {
 		Cache: CacheConfig{
 			Dir:        "/tmp/cache",
 			TTLSeconds: 3600,
+			PerRepo:    true,
 		},
 		Privacy: PrivacyConfig{
 			RedactPaths: []string{"**/.secret"},
@@ -275,6 +294,9 @@ func TestMergeFile_AllFields(t *testing.T) {
 	if dst.Cache.TTLSeconds != 3600 {
 		t.Errorf("Cache.TTLSeconds = %d, want 3600", dst.Cache.TTLSeconds)
 	}
+	if !dst.Cache.PerRepo {
+		t.Error("Cache.PerRepo should be true")
+	}
 }
 
 func TestMergeEnv_InvalidMaxFindings(t *testing.T) {
@@ -334,6 +356,7 @@ func TestMergeOverrides_AllNumericFields(t *testing.T) {
 		"contextLines": "10",
 		"maxDiffBytes": "2000000",
 		"rulesFile":    "my-rules.json",
+		"retryBudget":  "8",
 	})
 	if cfg.ContextLines != 10 {
 		t.Errorf("ContextLines = %d, want 10", cfg.ContextLines)
@@ -344,6 +367,41 @@ func TestMergeOverrides_AllNumericFields(t *testing.T) {
 	if cfg.RulesFile != "my-rules.json" {
 		t.Errorf("RulesFile = %q, want %q", cfg.RulesFile, "my-rules.json")
 	}
+	if cfg.RetryBudget != 8 {
+		t.Errorf("RetryBudget = %d, want 8", cfg.RetryBudget)
+	}
+}
+
+func TestMergeOverrides_CachePerRepo(t *testing.T) {
+	cfg := Default()
+	mergeOverrides(&cfg, map[string]string{"cachePerRepo": "true"})
+	if !cfg.Cache.PerRepo {
+		t.Error("cachePerRepo override should set Cache.PerRepo")
+	}
+}
+
+func TestMergeEnv_CachePerRepo(t *testing.T) {
+	t.Setenv("PRISM_CACHE_PER_REPO", "1")
+	cfg := Default()
+	if err := mergeEnv(&cfg); err != nil {
+		t.Fatalf("mergeEnv error: %v", err)
+	}
+	if !cfg.Cache.PerRepo {
+		t.Error("PRISM_CACHE_PER_REPO=1 should set Cache.PerRepo")
+	}
+
+	t.Setenv("PRISM_CACHE_PER_REPO", "sometimes")
+	if err := mergeEnv(&cfg); err == nil {
+		t.Error("Expected error for invalid PRISM_CACHE_PER_REPO")
+	}
+}
+
+func TestMergeOverrides_RefreshCache(t *testing.T) {
+	cfg := Default()
+	mergeOverrides(&cfg, map[string]string{"refreshCache": "true"})
+	if !cfg.Cache.Refresh || !cfg.Cache.Enabled {
+		t.Errorf("Cache = %+v, want Refresh with caching still enabled", cfg.Cache)
+	}
 }
 
 func TestConfigDir_XDG(t *testing.T) {
@@ -472,3 +530,233 @@ func TestLoad_Integration(t *testing.T) {
 		t.Errorf("MaxFindings = %d, want 50 (default)", cfg.MaxFindings)
 	}
 }
+
+func TestMergeFile_OutputIcons(t *testing.T) {
+	dst := Default()
+	src := Config{Output: OutputConfig{Icons: map[string]string{"high": "!!!", "low": ""}}}
+	mergeFile(&dst, src)
+
+	if dst.Output.Icons["high"] != "!!!" {
+		t.Errorf("Output.Icons[high] = %q, want %q", dst.Output.Icons["high"], "!!!")
+	}
+	if icon, ok := dst.Output.Icons["low"]; !ok || icon != "" {
+		t.Error("Output.Icons[low] should be present and empty")
+	}
+}
+
+func TestRepoConfigPath(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "pkg", "api")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Not a git repository: no repo config even if the file exists
+	os.WriteFile(filepath.Join(root, RepoFileName), []byte(`{}`), 0o644)
+	if path, err := RepoConfigPath(sub); err != nil || path != "" {
+		t.Errorf("outside a repo: RepoConfigPath = %q, %v; want none", path, err)
+	}
+
+	os.Mkdir(filepath.Join(root, ".git"), 0o755)
+	path, err := RepoConfigPath(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(root, RepoFileName) {
+		t.Errorf("RepoConfigPath = %q, want the file at the repo root", path)
+	}
+
+	// The nearest file wins
+	os.WriteFile(filepath.Join(root, "pkg", RepoFileName), []byte(`{}`), 0o644)
+	if path, _ := RepoConfigPath(sub); path != filepath.Join(root, "pkg", RepoFileName) {
+		t.Errorf("RepoConfigPath = %q, want the nearest file", path)
+	}
+
+	// A YAML file is found too, but .prism.json wins within a directory
+	os.WriteFile(filepath.Join(sub, ".prism.yml"), []byte("failOn: low\n"), 0o644)
+	if path, _ := RepoConfigPath(sub); path != filepath.Join(sub, ".prism.yml") {
+		t.Errorf("RepoConfigPath = %q, want the .prism.yml", path)
+	}
+	os.WriteFile(filepath.Join(sub, RepoFileName), []byte(`{}`), 0o644)
+	if path, _ := RepoConfigPath(sub); path != filepath.Join(sub, RepoFileName) {
+		t.Errorf("RepoConfigPath = %q, want .prism.json preferred", path)
+	}
+}
+
+func TestLoad_RepoConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("PRISM_MAX_FINDINGS", "7")
+
+	root := t.TempDir()
+	os.Mkdir(filepath.Join(root, ".git"), 0o755)
+	os.WriteFile(filepath.Join(root, RepoFileName), []byte(`{
+		"maxFindings": 30,
+		"failOn": "medium",
+		"contextLines": 9,
+		"guideFile": "docs/STYLE.md",
+		"privacy": {"redactPaths": ["secrets/**"]}
+	}`), 0o644)
+	sub := filepath.Join(root, "cmd")
+	os.Mkdir(sub, 0o755)
+	t.Chdir(sub)
+
+	cfg, err := Load(map[string]string{"failOn": "high"})
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if cfg.ContextLines != 9 {
+		t.Errorf("ContextLines = %d, want the repo value", cfg.ContextLines)
+	}
+	if cfg.MaxFindings != 7 {
+		t.Errorf("MaxFindings = %d, env should override the repo file", cfg.MaxFindings)
+	}
+	if cfg.FailOn != "high" {
+		t.Errorf("FailOn = %q, flags should override the repo file", cfg.FailOn)
+	}
+	if cfg.GuideFile != filepath.Join(root, "docs", "STYLE.md") {
+		t.Errorf("GuideFile = %q, want it resolved against the repo file", cfg.GuideFile)
+	}
+	// Booleans the repo file leaves out keep their defaults
+	if !cfg.Cache.Enabled || !cfg.Privacy.RedactSecrets {
+		t.Errorf("Cache.Enabled = %v, RedactSecrets = %v; want the defaults", cfg.Cache.Enabled, cfg.Privacy.RedactSecrets)
+	}
+	if cfg.RepoFile != filepath.Join(root, RepoFileName) {
+		t.Errorf("RepoFile = %q", cfg.RepoFile)
+	}
+	want := []string{"contextLines", "failOn", "guideFile", "maxFindings", "privacy.redactPaths"}
+	if strings.Join(cfg.RepoKeys, ",") != strings.Join(want, ",") {
+		t.Errorf("RepoKeys = %v, want %v", cfg.RepoKeys, want)
+	}
+}
+
+func TestLoadRepoFile_Rejects(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"provider", `{"provider": "openai"}`, `"provider" cannot be set`},
+		{"prompt template", `{"promptTemplateFile": "p.tmpl"}`, `"promptTemplateFile" cannot be set`},
+		{"cache dir", `{"cache": {"dir": "/tmp/x"}}`, `"cache" cannot be set`},
+		{"history file", `{"history": {"file": "h.jsonl"}}`, `"history" cannot be set`},
+		{"slack webhook", `{"notify": {"slackWebhookUrl": "https://hooks.example"}}`, `"notify" cannot be set`},
+		{"redaction off", `{"privacy": {"redactSecrets": false}}`, "cannot turn off"},
+		{"unknown privacy key", `{"privacy": {"other": true}}`, `"privacy.other" cannot be set`},
+		{"absolute guide", `{"guideFile": "/etc/passwd"}`, "relative path inside"},
+		{"escaping guide", `{"guideFile": "../../notes.md"}`, "relative path inside"},
+		{"escaping rules", `{"rulesFile": "docs/../../rules.json"}`, "relative path inside"},
+		{"remote rules in list", `{"rulesFile": "pack:go,https://attacker/rules.json"}`, "cannot be fetched"},
+		{"absolute rules in list", `{"rulesFile": "a.json, /etc/other.json"}`, "relative path inside"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), RepoFileName)
+			os.WriteFile(path, []byte(tt.body), 0o644)
+			_, err := loadRepoFile(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadRepoFile_RulesSources(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, RepoFileName)
+	os.WriteFile(path, []byte(`{"rulesFile": "pack:go, rules/a.json,git:main:b.json"}`), 0o644)
+	rf, err := loadRepoFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "pack:go," + filepath.Join(dir, "rules", "a.json") + ",git:main:b.json"
+	if rf.cfg.RulesFile != want {
+		t.Errorf("RulesFile = %q, want %q", rf.cfg.RulesFile, want)
+	}
+}
+
+func TestLoadRepoFile_YAML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".prism.yml")
+	os.WriteFile(path, []byte(`# shared review settings
+failOn: medium
+maxFindings: 25
+guideFile: docs/STYLE.md   # relative to this file
+exclude:
+  - "vendor/**"
+  - '**/*.pb.go'
+testPatterns: ["*_test.go", "test_*.py"]
+severityFloors:
+  security: high
+privacy:
+  redactSecrets: true
+  redactPaths: ["secrets/**"]
+`), 0o644)
+	rf, err := loadRepoFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rf.cfg.FailOn != "medium" || rf.cfg.MaxFindings != 25 {
+		t.Errorf("FailOn = %q, MaxFindings = %d", rf.cfg.FailOn, rf.cfg.MaxFindings)
+	}
+	if rf.cfg.GuideFile != filepath.Join(dir, "docs", "STYLE.md") {
+		t.Errorf("GuideFile = %q", rf.cfg.GuideFile)
+	}
+	if strings.Join(rf.cfg.Exclude, ",") != "vendor/**,**/*.pb.go" {
+		t.Errorf("Exclude = %v", rf.cfg.Exclude)
+	}
+	if strings.Join(rf.cfg.TestPatterns, ",") != "*_test.go,test_*.py" {
+		t.Errorf("TestPatterns = %v", rf.cfg.TestPatterns)
+	}
+	if rf.cfg.SeverityFloors["security"] != "high" {
+		t.Errorf("SeverityFloors = %v", rf.cfg.SeverityFloors)
+	}
+	if !rf.cfg.Privacy.RedactSecrets || strings.Join(rf.cfg.Privacy.RedactPaths, ",") != "secrets/**" {
+		t.Errorf("Privacy = %+v", rf.cfg.Privacy)
+	}
+	want := []string{"exclude", "failOn", "guideFile", "maxFindings", "privacy.redactPaths", "privacy.redactSecrets", "severityFloors", "testPatterns"}
+	if strings.Join(rf.keys, ",") != strings.Join(want, ",") {
+		t.Errorf("keys = %v, want %v", rf.keys, want)
+	}
+}
+
+func TestLoadRepoFile_YAMLRejects(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"disallowed key", "provider: openai\n", `"provider" cannot be set`},
+		{"nested disallowed key", "privacy:\n  redactSecrets: false\n", "cannot turn off"},
+		{"remote rules", "rulesFile: https://attacker/rules.json\n", "cannot be fetched"},
+		{"anchor", "failOn: &level high\n", "unsupported YAML syntax"},
+		{"block scalar", "guideFile: |\n  docs.md\n", "unsupported YAML syntax"},
+		{"bad indentation", "failOn: high\n  maxFindings: 3\n", "unexpected indentation"},
+		{"top-level sequence", "- a\n", "must be a mapping"},
+		{"second document", "failOn: high\n---\nfailOn: low\n", "multiple documents"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".prism.yaml")
+			os.WriteFile(path, []byte(tt.body), 0o644)
+			_, err := loadRepoFile(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeRepo_OnlyAddsRedaction(t *testing.T) {
+	dst := Default()
+	dst.Privacy.RedactSecrets = false
+	dst.Privacy.RedactPaths = []string{"*.pem"}
+	mergeRepo(&dst, repoFile{
+		cfg:  Config{MaxFindings: 5, Privacy: PrivacyConfig{RedactSecrets: true, RedactPaths: []string{"secrets/**"}}},
+		keys: []string{"maxFindings", "privacy.redactPaths", "privacy.redactSecrets"},
+	})
+	if !dst.Privacy.RedactSecrets {
+		t.Error("RedactSecrets should be turned on by the repo file")
+	}
+	if strings.Join(dst.Privacy.RedactPaths, ",") != "*.pem,secrets/**" {
+		t.Errorf("RedactPaths = %v, want the repo patterns appended", dst.Privacy.RedactPaths)
+	}
+	if !dst.Cache.Enabled {
+		t.Error("Cache.Enabled should keep its value")
+	}
+}
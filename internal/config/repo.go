@@ -0,0 +1,212 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// RepoFileName is the name of the repository config file, committed so a
+// team shares its review settings.
+const RepoFileName = ".prism.json"
+
+// repoFileNames are the names a repository config file may have, in order
+// of preference when a directory holds more than one.
+var repoFileNames = []string{RepoFileName, ".prism.yml", ".prism.yaml"}
+
+// RepoConfigPath returns the repository config file that applies to dir:
+// the nearest .prism.json, .prism.yml, or .prism.yaml in dir or one of its
+// parents, stopping at the root of the git repository. It returns "" if
+// there is none or dir is not in a git repository.
+func RepoConfigPath(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if repoRoot(dir) == "" {
+		return "", nil
+	}
+	for {
+		for _, name := range repoFileNames {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+		if isRepoRoot(dir) {
+			return "", nil
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// repoRoot returns the nearest ancestor of dir (or dir itself) holding a
+// .git entry, or "" if there is none.
+func repoRoot(dir string) string {
+	for {
+		if isRepoRoot(dir) {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// isRepoRoot reports whether dir holds a .git directory, or a .git file as
+// in worktrees and submodules.
+func isRepoRoot(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// repoKeys are the top-level keys a repository config file may set. A repo
+// file comes with the code under review, so it is limited to settings that
+// tune the review; anything that picks where code is sent, where files are
+// written, or that could weaken redaction stays in the user's own config.
+var repoKeys = map[string]bool{
+	"failOn":          true,
+	"maxFindings":     true,
+	"contextLines":    true,
+	"include":         true,
+	"exclude":         true,
+	"maxDiffBytes":    true,
+	"minDiffBytes":    true,
+	"rulesFile":       true,
+	"guideFile":       true,
+	"testPatterns":    true,
+	"severityFloors":  true,
+	"extraCategories": true,
+	"privacy":         true,
+}
+
+// repoPrivacyKeys are the privacy keys a repository config file may set.
+// They can only add redaction: redactSecrets must be true and redactPaths
+// extends the global patterns.
+var repoPrivacyKeys = map[string]bool{
+	"redactSecrets": true,
+	"redactPaths":   true,
+}
+
+// repoFile is a parsed repository config file.
+type repoFile struct {
+	path string
+	cfg  Config
+	// keys lists the settings the file sets, in the form "privacy.redactPaths",
+	// sorted.
+	keys []string
+}
+
+// loadRepoFile reads the repository config file at path, converting it
+// from YAML first if its name ends in .yml or .yaml. It returns an error
+// for a key outside repoKeys, for redactSecrets set to false, and for a
+// rules or guide file path that is remote, absolute, or leaves the file's
+// directory. Relative rules and guide file paths are resolved against the
+// file's directory.
+func loadRepoFile(path string) (repoFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return repoFile{}, fmt.Errorf("reading repo config file: %w", err)
+	}
+	if ext := filepath.Ext(path); ext == ".yml" || ext == ".yaml" {
+		if data, err = yamlToJSON(data); err != nil {
+			return repoFile{}, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return repoFile{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	rf := repoFile{path: path}
+	for key, value := range raw {
+		if !repoKeys[key] {
+			return repoFile{}, fmt.Errorf("%s: %q cannot be set in a repo config file; set it in your own config instead", path, key)
+		}
+		if key != "privacy" {
+			rf.keys = append(rf.keys, key)
+			continue
+		}
+		var privacy map[string]json.RawMessage
+		if err := json.Unmarshal(value, &privacy); err != nil {
+			return repoFile{}, fmt.Errorf("parsing %s: privacy: %w", path, err)
+		}
+		for pkey := range privacy {
+			if !repoPrivacyKeys[pkey] {
+				return repoFile{}, fmt.Errorf("%s: \"privacy.%s\" cannot be set in a repo config file; set it in your own config instead", path, pkey)
+			}
+			rf.keys = append(rf.keys, "privacy."+pkey)
+		}
+	}
+	sort.Strings(rf.keys)
+
+	if err := json.Unmarshal(data, &rf.cfg); err != nil {
+		return repoFile{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if slices.Contains(rf.keys, "privacy.redactSecrets") && !rf.cfg.Privacy.RedactSecrets {
+		return repoFile{}, fmt.Errorf("%s: a repo config file cannot turn off privacy.redactSecrets", path)
+	}
+
+	dir := filepath.Dir(path)
+	if rf.cfg.RulesFile != "" {
+		rulesFile, err := repoRulesFile(dir, rf.cfg.RulesFile)
+		if err != nil {
+			return repoFile{}, fmt.Errorf("%s: %w", path, err)
+		}
+		rf.cfg.RulesFile = rulesFile
+	}
+	if rf.cfg.GuideFile != "" {
+		if !filepath.IsLocal(rf.cfg.GuideFile) {
+			return repoFile{}, fmt.Errorf("%s: guideFile %q must be a relative path inside the repository", path, rf.cfg.GuideFile)
+		}
+		rf.cfg.GuideFile = filepath.Join(dir, rf.cfg.GuideFile)
+	}
+	return rf, nil
+}
+
+// repoRulesFile checks each comma-separated source of a repo file's
+// rulesFile, the same split review.LoadRules makes, and resolves local files
+// against dir. pack: and git: sources are kept as they are; a URL or a path
+// that is absolute or leaves dir is an error.
+func repoRulesFile(dir, rulesFile string) (string, error) {
+	var sources []string
+	for _, source := range strings.Split(rulesFile, ",") {
+		source = strings.TrimSpace(source)
+		switch {
+		case source == "":
+			continue
+		case strings.HasPrefix(source, "pack:"), strings.HasPrefix(source, "git:"):
+		case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+			return "", fmt.Errorf("rulesFile source %q cannot be fetched from a repo config file; set it in your own config instead", source)
+		case !filepath.IsLocal(source):
+			return "", fmt.Errorf("rulesFile source %q must be a relative path inside the repository", source)
+		default:
+			source = filepath.Join(dir, source)
+		}
+		sources = append(sources, source)
+	}
+	return strings.Join(sources, ","), nil
+}
+
+// mergeRepo layers a repository config file over dst. loadRepoFile has
+// already limited it to repoKeys, so mergeFile only changes those; the
+// redaction settings are merged here so the file can add to them but never
+// remove any.
+func mergeRepo(dst *Config, rf repoFile) {
+	cacheCfg, privacy := dst.Cache, dst.Privacy
+	mergeFile(dst, rf.cfg)
+	dst.Cache, dst.Privacy = cacheCfg, privacy
+	if slices.Contains(rf.keys, "privacy.redactSecrets") {
+		dst.Privacy.RedactSecrets = true
+	}
+	if len(rf.cfg.Privacy.RedactPaths) > 0 {
+		dst.Privacy.RedactPaths = append(slices.Clone(dst.Privacy.RedactPaths), rf.cfg.Privacy.RedactPaths...)
+	}
+	dst.RepoFile = rf.path
+	dst.RepoKeys = rf.keys
+}
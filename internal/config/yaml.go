@@ -0,0 +1,310 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// yamlToJSON converts a repository config file written in YAML to JSON, so
+// it goes through the same checks as a .prism.json. prism keeps to the
+// standard library, so this handles only the subset a config file needs:
+// nested block mappings, block sequences of scalars, flow sequences and
+// mappings of scalars ([a, b] and {k: v}), quoted and plain scalars, and
+// comments. Anchors, aliases, tags, block scalars, and multiple documents
+// are rejected.
+func yamlToJSON(data []byte) ([]byte, error) {
+	lines, err := yamlLines(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return []byte("{}"), nil
+	}
+	if lines[0].indent != 0 || isYAMLSeqItem(lines[0].text) {
+		return nil, fmt.Errorf("line %d: the top level must be a mapping", lines[0].num)
+	}
+	p := &yamlParser{lines: lines}
+	v, err := p.mapping(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", lines[p.pos].num)
+	}
+	return json.Marshal(v)
+}
+
+// yamlLine is a non-blank line with its comment removed.
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+// yamlLines splits src into lines, dropping blank lines, comments, and a
+// leading document marker.
+func yamlLines(src string) ([]yamlLine, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(src, "\n") {
+		num := i + 1
+		text := strings.TrimRight(stripYAMLComment(strings.TrimRight(raw, "\r")), " \t")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed in indentation", num)
+		}
+		if trimmed == "---" && len(lines) == 0 {
+			continue
+		}
+		if trimmed == "---" || trimmed == "..." {
+			return nil, fmt.Errorf("line %d: multiple documents are not supported", num)
+		}
+		lines = append(lines, yamlLine{num: num, indent: len(text) - len(trimmed), text: trimmed})
+	}
+	return lines, nil
+}
+
+// stripYAMLComment removes a # comment that starts a line or follows
+// whitespace, outside of quotes.
+func stripYAMLComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+func isYAMLSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// block parses the mapping or sequence whose lines are indented by indent.
+func (p *yamlParser) block(indent int) (any, error) {
+	if isYAMLSeqItem(p.lines[p.pos].text) {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *yamlParser) mapping(indent int) (map[string]any, error) {
+	m := map[string]any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		line := p.lines[p.pos]
+		if isYAMLSeqItem(line.text) {
+			return nil, fmt.Errorf("line %d: expected a key, found a sequence item", line.num)
+		}
+		key, rest, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", line.num)
+		}
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", line.num, key)
+		}
+		p.pos++
+		var value any
+		var err error
+		switch {
+		case rest != "":
+			value, err = yamlInline(rest)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line.num, err)
+			}
+		case p.pos < len(p.lines) && p.lines[p.pos].indent > indent:
+			value, err = p.block(p.lines[p.pos].indent)
+		case p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isYAMLSeqItem(p.lines[p.pos].text):
+			// A sequence may sit at its key's indentation.
+			value, err = p.sequence(indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m[key] = value
+		if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].num)
+		}
+	}
+	return m, nil
+}
+
+func (p *yamlParser) sequence(indent int) ([]any, error) {
+	items := []any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isYAMLSeqItem(p.lines[p.pos].text) {
+		line := p.lines[p.pos]
+		item := strings.TrimSpace(strings.TrimPrefix(line.text, "-"))
+		p.pos++
+		if item == "" || strings.HasPrefix(item, "- ") {
+			return nil, fmt.Errorf("line %d: nested sequences are not supported", line.num)
+		}
+		if _, _, ok := splitYAMLKey(item); ok && !strings.HasPrefix(item, "{") {
+			return nil, fmt.Errorf("line %d: mappings in a sequence are not supported", line.num)
+		}
+		v, err := yamlInline(item)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.num, err)
+		}
+		items = append(items, v)
+	}
+	return items, nil
+}
+
+// splitYAMLKey splits "key: value" at the first colon outside quotes that
+// is followed by a space or ends the text.
+func splitYAMLKey(text string) (key, rest string, ok bool) {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ':' && (i+1 == len(text) || text[i+1] == ' '):
+			key = strings.TrimSpace(text[:i])
+			if key == "" {
+				return "", "", false
+			}
+			k, err := yamlScalar(key)
+			if err != nil {
+				return "", "", false
+			}
+			s, isString := k.(string)
+			if !isString {
+				s = key
+			}
+			return s, strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// yamlInline parses a value written on one line: a flow sequence, a flow
+// mapping, or a scalar.
+func yamlInline(s string) (any, error) {
+	switch {
+	case strings.HasPrefix(s, "["):
+		if !strings.HasSuffix(s, "]") {
+			return nil, fmt.Errorf("unterminated flow sequence %q", s)
+		}
+		items := []any{}
+		for _, part := range splitYAMLFlow(s[1 : len(s)-1]) {
+			v, err := yamlScalar(part)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	case strings.HasPrefix(s, "{"):
+		if !strings.HasSuffix(s, "}") {
+			return nil, fmt.Errorf("unterminated flow mapping %q", s)
+		}
+		m := map[string]any{}
+		for _, part := range splitYAMLFlow(s[1 : len(s)-1]) {
+			key, rest, ok := splitYAMLKey(part)
+			if !ok {
+				return nil, fmt.Errorf("expected \"key: value\" in %q", s)
+			}
+			v, err := yamlScalar(rest)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = v
+		}
+		return m, nil
+	}
+	return yamlScalar(s)
+}
+
+// splitYAMLFlow splits the inside of a flow collection on commas outside
+// quotes, dropping empty entries.
+func splitYAMLFlow(s string) []string {
+	var parts []string
+	var quote byte
+	start := 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) {
+			c := s[i]
+			if quote != 0 {
+				if c == '\\' && quote == '"' {
+					i++
+				} else if c == quote {
+					quote = 0
+				}
+				continue
+			}
+			if c == '"' || c == '\'' {
+				quote = c
+				continue
+			}
+			if c != ',' {
+				continue
+			}
+		}
+		if part := strings.TrimSpace(s[start:i]); part != "" {
+			parts = append(parts, part)
+		}
+		start = i + 1
+	}
+	return parts
+}
+
+// yamlScalar parses a quoted or plain scalar. Plain scalars become
+// booleans, null, or numbers where YAML would read them that way.
+func yamlScalar(s string) (any, error) {
+	switch {
+	case s == "", s == "~", s == "null", s == "Null", s == "NULL":
+		return nil, nil
+	case strings.HasPrefix(s, `"`):
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid double-quoted string %s", s)
+		}
+		return v, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return nil, fmt.Errorf("invalid single-quoted string %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case strings.ContainsAny(s[:1], "&*!|>%@`[]{}"):
+		return nil, fmt.Errorf("unsupported YAML syntax %q; quote the value if it is a string", s)
+	}
+	switch s {
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return f, nil
+	}
+	return s, nil
+}
@@ -1,20 +1,45 @@
 package gitctx
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/dshills/prism/internal/redact"
 )
 
 // DiffOptions controls how diffs are gathered.
+//
+// Include and Exclude are glob patterns (see MatchesAny) applied the same
+// way in every mode by applyFilters: a path is kept when Include is empty or
+// it matches an Include pattern (see includePatterns), and it is not
+// excluded. Exclude wins when a
+// path matches both. An Exclude pattern starting with "!" re-includes paths
+// excluded by an earlier pattern; as in .gitignore, the last matching
+// Exclude pattern decides.
 type DiffOptions struct {
 	ContextLines int
 	MaxDiffBytes int
 	Include      []string
 	Exclude      []string
+	// RedactPaths lists globs whose file contents are replaced with a
+	// placeholder when whole files are read (Codebase, Dir, Snippet).
+	RedactPaths []string
+	// ModifiedSince, when non-zero, limits Codebase and Dir to files changed
+	// at or after this time: by commit date for Codebase (any commit touching
+	// the file since then) and by modification time for Dir. It composes
+	// with Include and Exclude.
+	ModifiedSince time.Time
+	// CaseInsensitive matches Include and Exclude patterns ignoring case.
+	CaseInsensitive bool
 }
 
 // DiffResult holds the collected diff and metadata.
@@ -24,6 +49,15 @@ type DiffResult struct {
 	Mode  string
 	Range string
 	Repo  RepoMeta
+	// PartiallyStaged lists staged files that also have unstaged changes.
+	// Only populated by Index.
+	PartiallyStaged []string
+	// MetadataOnly describes sections dropped from Diff because they carry
+	// no content to review, such as pure renames and mode changes.
+	MetadataOnly []string
+	// Missing lists requested files that do not exist. Only populated by
+	// FromFiles.
+	Missing []string
 }
 
 // RepoMeta contains git repository metadata.
@@ -31,27 +65,96 @@ type RepoMeta struct {
 	Root   string
 	Head   string
 	Branch string
+	// Detached is true when HEAD is a commit rather than a branch, as in
+	// most CI checkouts. Branch is empty then.
+	Detached bool
 }
 
-// GetRepoMeta collects repository metadata from git.
+// GetRepoMeta collects repository metadata from git for the current
+// directory.
 func GetRepoMeta() (RepoMeta, error) {
-	root, err := gitOutput("rev-parse", "--show-toplevel")
+	return repoMetaIn(".")
+}
+
+// repoMetaIn collects repository metadata for the repository holding dir.
+func repoMetaIn(dir string) (RepoMeta, error) {
+	root, err := gitOutput("-C", dir, "rev-parse", "--show-toplevel")
 	if err != nil {
 		return RepoMeta{}, fmt.Errorf("not a git repository: %w", err)
 	}
-	head, err := gitOutput("rev-parse", "HEAD")
+	head, err := gitOutput("-C", dir, "rev-parse", "HEAD")
 	if err != nil {
 		head = "" // new repo with no commits
 	}
-	branch, err := gitOutput("rev-parse", "--abbrev-ref", "HEAD")
+	branch, err := gitOutput("-C", dir, "rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
 		branch = ""
 	}
-	return RepoMeta{
+	meta := RepoMeta{
 		Root:   strings.TrimSpace(root),
 		Head:   strings.TrimSpace(head),
 		Branch: strings.TrimSpace(branch),
-	}, nil
+	}
+	// On a detached HEAD git names the branch "HEAD"
+	if meta.Branch == "HEAD" {
+		meta.Branch = ""
+		meta.Detached = meta.Head != ""
+	}
+	return meta, nil
+}
+
+// ShortCommit abbreviates a commit SHA to seven characters.
+func ShortCommit(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// shallowHint tells the user how to get the history a shallow clone lacks.
+const shallowHint = "this is a shallow clone, so the commits it needs may be missing; " +
+	"fetch more history with `git fetch --deepen=<n>` or `git fetch --unshallow`"
+
+// IsShallow reports whether the current repository is a shallow clone, as
+// made by CI checkouts with a fetch depth.
+func IsShallow() bool {
+	out, err := gitOutput("rev-parse", "--is-shallow-repository")
+	return err == nil && strings.TrimSpace(out) == "true"
+}
+
+// warnShallow warns on stderr when the repository is shallow and any of
+// revs is not in it, before a diff that needs them fails or treats a
+// grafted commit as a root commit. It reports whether the repository is
+// shallow.
+func warnShallow(revs ...string) bool {
+	if !IsShallow() {
+		return false
+	}
+	for _, rev := range revs {
+		if _, err := gitOutput("rev-parse", "--verify", "--quiet", rev+"^{commit}"); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s is not available; %s\n", rev, shallowHint)
+			break
+		}
+	}
+	return true
+}
+
+// rangeEnds returns the revisions named by a range such as "a..b" or
+// "a...b". An omitted side means HEAD.
+func rangeEnds(revRange string) []string {
+	sep := ".."
+	if strings.Contains(revRange, "...") {
+		sep = "..."
+	}
+	parts := strings.SplitN(revRange, sep, 2)
+	var revs []string
+	for _, p := range parts {
+		if p == "" {
+			p = "HEAD"
+		}
+		revs = append(revs, p)
+	}
+	return revs
 }
 
 // Unstaged returns the diff of working tree vs index.
@@ -74,17 +177,79 @@ func Staged(opts DiffOptions) (DiffResult, error) {
 	return buildResult(diff, "staged", "", opts)
 }
 
+// emptyTree is the hash of git's empty tree, the base for diffs that have no
+// parent commit.
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// Amend returns the diff of index vs HEAD's parent: what HEAD will contain
+// after "git commit --amend". Line numbers match the amended commit. When
+// HEAD is the root commit the index is diffed against the empty tree.
+func Amend(opts DiffOptions) (DiffResult, error) {
+	args := buildDiffArgs(opts)
+	base, rangeStr := "HEAD~1", "HEAD~1"
+	if _, err := gitOutput("rev-parse", "--verify", "--quiet", "HEAD~1^{commit}"); err != nil {
+		if _, err := gitOutput("rev-parse", "--verify", "--quiet", "HEAD^{commit}"); err != nil {
+			return DiffResult{}, fmt.Errorf("--amend needs a commit to amend, but HEAD does not exist")
+		}
+		base, rangeStr = emptyTree, ""
+	}
+	diff, err := gitOutput(append([]string{"diff", "--cached", base}, args...)...)
+	if err != nil {
+		return DiffResult{}, fmt.Errorf("git diff --cached %s: %w", base, err)
+	}
+	return buildResult(diff, "amend", rangeStr, opts)
+}
+
+// Index returns the diff of exactly what the next commit will contain
+// (index vs HEAD). Unlike Staged, it bypasses external diff drivers and
+// textconv filters so hunk line numbers always match the staged blobs, and
+// it reports files that are only partially staged so callers can warn that
+// findings refer to the staged version rather than the working tree.
+func Index(opts DiffOptions) (DiffResult, error) {
+	args := buildDiffArgs(opts)
+	diff, err := gitOutput(append([]string{"diff", "--cached", "--no-ext-diff", "--no-textconv"}, args...)...)
+	if err != nil {
+		return DiffResult{}, fmt.Errorf("git diff --cached: %w", err)
+	}
+	result, err := buildResult(diff, "index", "", opts)
+	if err != nil {
+		return DiffResult{}, err
+	}
+
+	unstaged, err := gitOutput("diff", "--name-only")
+	if err != nil {
+		return DiffResult{}, fmt.Errorf("git diff --name-only: %w", err)
+	}
+	dirty := make(map[string]bool)
+	for _, line := range strings.Split(unstaged, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			dirty[line] = true
+		}
+	}
+	for _, f := range result.Files {
+		if dirty[f] {
+			result.PartiallyStaged = append(result.PartiallyStaged, f)
+		}
+	}
+	return result, nil
+}
+
 // Commit returns the diff for a specific commit vs its parent.
 func Commit(sha string, parent string, opts DiffOptions) (DiffResult, error) {
 	args := buildDiffArgs(opts)
 	if parent != "" {
+		shallow := warnShallow(parent, sha)
 		cmdArgs := append([]string{"diff", parent, sha}, args...)
 		diff, err := gitOutput(cmdArgs...)
 		if err != nil {
+			if shallow {
+				return DiffResult{}, fmt.Errorf("git diff %s %s: %w (%s)", parent, sha, err, shallowHint)
+			}
 			return DiffResult{}, fmt.Errorf("git diff %s %s: %w", parent, sha, err)
 		}
 		return buildResult(diff, "commit", sha, opts)
 	}
+	shallow := warnShallow(sha, sha+"~1")
 	cmdArgs := append([]string{"diff", sha + "~1", sha}, args...)
 	diff, err := gitOutput(cmdArgs...)
 	if err != nil {
@@ -92,6 +257,9 @@ func Commit(sha string, parent string, opts DiffOptions) (DiffResult, error) {
 		showArgs := append([]string{"show", "--format=", sha, "--"}, args[1:]...) // skip -U flag reuse
 		diff, err = gitOutput(showArgs...)
 		if err != nil {
+			if shallow {
+				return DiffResult{}, fmt.Errorf("git show %s: %w (%s)", sha, err, shallowHint)
+			}
 			return DiffResult{}, fmt.Errorf("git show %s: %w", sha, err)
 		}
 	}
@@ -105,16 +273,48 @@ func Range(revRange string, mergeBase bool, opts DiffOptions) (DiffResult, error
 	if mergeBase && strings.Contains(revRange, "..") && !strings.Contains(revRange, "...") {
 		diffRange = strings.Replace(revRange, "..", "...", 1)
 	}
+	shallow := warnShallow(rangeEnds(diffRange)...)
 	cmdArgs := append([]string{"diff", diffRange}, args...)
 	diff, err := gitOutput(cmdArgs...)
 	if err != nil {
+		if shallow {
+			return DiffResult{}, fmt.Errorf("git diff %s: %w (%s)", revRange, err, shallowHint)
+		}
 		return DiffResult{}, fmt.Errorf("git diff %s: %w", revRange, err)
 	}
 	return buildResult(diff, "range", revRange, opts)
 }
 
+// DefaultBranch returns the ref of the repository's default branch, for
+// use as the base of a feature-branch review. It reads origin/HEAD and falls
+// back to the first existing of origin/main, origin/master, main, and master.
+func DefaultBranch() (string, error) {
+	if out, err := gitOutput("symbolic-ref", "--quiet", "refs/remotes/origin/HEAD"); err == nil {
+		if ref := strings.TrimSpace(out); ref != "" {
+			return strings.TrimPrefix(ref, "refs/remotes/"), nil
+		}
+	}
+	for _, candidate := range []string{"origin/main", "origin/master", "main", "master"} {
+		if _, err := gitOutput("rev-parse", "--verify", "--quiet", candidate+"^{commit}"); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("could not detect the default branch (no origin/HEAD, main, or master)")
+}
+
 // Snippet wraps raw content as a "diff" for review. If base is provided, computes a real diff.
 func Snippet(content, path, lang, base string) (DiffResult, error) {
+	return SnippetWithOptions(content, path, lang, base, DiffOptions{})
+}
+
+// SnippetWithOptions is like Snippet but replaces the content (and base) with
+// a placeholder when path matches opts.RedactPaths.
+func SnippetWithOptions(content, path, lang, base string, opts DiffOptions) (DiffResult, error) {
+	content = string(redactFile(path, []byte(content), opts.RedactPaths))
+	if base != "" {
+		base = string(redactFile(path, []byte(base), opts.RedactPaths))
+	}
+
 	var diff string
 	if base != "" {
 		tmpDir, err := os.MkdirTemp("", "prism-snippet-*")
@@ -149,17 +349,7 @@ func Snippet(content, path, lang, base string) (DiffResult, error) {
 			return DiffResult{}, fmt.Errorf("git diff --no-index: %w", err)
 		}
 	} else {
-		lines := strings.Split(content, "\n")
-		var b strings.Builder
-		fmt.Fprintf(&b, "diff --git a/%s b/%s\n", path, path)
-		fmt.Fprintf(&b, "new file mode 100644\n")
-		fmt.Fprintf(&b, "--- /dev/null\n")
-		fmt.Fprintf(&b, "+++ b/%s\n", path)
-		fmt.Fprintf(&b, "@@ -0,0 +1,%d @@\n", len(lines))
-		for _, line := range lines {
-			fmt.Fprintf(&b, "+%s\n", line)
-		}
-		diff = b.String()
+		diff = syntheticSection(path, content)
 	}
 
 	return DiffResult{
@@ -169,19 +359,15 @@ func Snippet(content, path, lang, base string) (DiffResult, error) {
 	}, nil
 }
 
+// buildDiffArgs returns the git diff options for opts. Include patterns are
+// not passed to git as pathspecs, whose glob rules differ from MatchesAny;
+// buildResult filters the diff with applyFilters instead.
 func buildDiffArgs(opts DiffOptions) []string {
 	var args []string
 	if opts.ContextLines > 0 {
 		args = append(args, fmt.Sprintf("-U%d", opts.ContextLines))
 	}
 	args = append(args, "--")
-	if len(opts.Include) > 0 {
-		for _, p := range opts.Include {
-			if p != "**/*" {
-				args = append(args, p)
-			}
-		}
-	}
 	return args
 }
 
@@ -190,26 +376,105 @@ func buildResult(diff, mode, rangeStr string, opts DiffOptions) (DiffResult, err
 	if err != nil {
 		meta = RepoMeta{}
 	}
+	result := FromPatch(diff, mode, rangeStr, opts)
+	result.Repo = meta
+	return result, nil
+}
 
+// FromPatch builds a DiffResult from a diff obtained outside the local
+// repository, such as a pull request diff, with the same handling as local
+// diffs: metadata-only sections are dropped, the include/exclude filters
+// are applied, and the diff is truncated at MaxDiffBytes. Repo is left
+// empty.
+func FromPatch(diff, mode, rangeStr string, opts DiffOptions) DiffResult {
+	diff, metaOnly := DropMetadataOnly(diff)
 	files := extractFiles(diff)
 
-	// Filter excludes before truncating so excluded files don't consume the byte budget
-	if len(opts.Exclude) > 0 {
-		diff = filterExcluded(diff, opts.Exclude)
-		files = filterFileList(files, opts.Exclude)
-	}
+	// Filter before truncating so filtered-out files don't consume the byte budget
+	diff = filterDiff(diff, opts)
+	files = applyFilters(files, opts)
 
 	if opts.MaxDiffBytes > 0 && len(diff) > opts.MaxDiffBytes {
 		diff = diff[:opts.MaxDiffBytes] + "\n... (diff truncated at max-diff-bytes limit)\n"
 	}
 
 	return DiffResult{
-		Diff:  diff,
-		Files: files,
-		Mode:  mode,
-		Range: rangeStr,
-		Repo:  meta,
-	}, nil
+		Diff:         diff,
+		Files:        files,
+		Mode:         mode,
+		Range:        rangeStr,
+		MetadataOnly: metaOnly,
+	}
+}
+
+// DropMetadataOnly removes diff sections that have no hunks, such as pure
+// renames, mode changes, and new empty files, so the model is not asked to
+// review content that does not exist. Binary file sections are kept. It
+// returns the remaining diff and a short description of each dropped section.
+func DropMetadataOnly(diff string) (string, []string) {
+	sections := splitDiffSections(diff)
+	var kept []string
+	var dropped []string
+	for _, section := range sections {
+		if desc, ok := describeMetadataOnly(section); ok {
+			dropped = append(dropped, desc)
+			continue
+		}
+		kept = append(kept, section)
+	}
+	if len(dropped) == 0 {
+		return diff, nil
+	}
+	return strings.Join(kept, ""), dropped
+}
+
+// describeMetadataOnly reports whether section is a content-less file header
+// and, if so, summarizes the change (e.g. "old.go -> new.go (renamed)").
+func describeMetadataOnly(section string) (string, bool) {
+	if !strings.HasPrefix(section, "diff --git") {
+		return "", false
+	}
+	var path, renameFrom, renameTo, oldMode, newMode, created string
+	for _, line := range strings.Split(section, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"),
+			strings.HasPrefix(line, "Binary files"),
+			strings.HasPrefix(line, "GIT binary patch"):
+			return "", false
+		case strings.HasPrefix(line, "diff --git"):
+			if i := strings.LastIndex(line, " b/"); i >= 0 {
+				path = line[i+len(" b/"):]
+			}
+		case strings.HasPrefix(line, "rename from "):
+			renameFrom = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			renameTo = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "old mode "):
+			oldMode = strings.TrimPrefix(line, "old mode ")
+		case strings.HasPrefix(line, "new mode "):
+			newMode = strings.TrimPrefix(line, "new mode ")
+		case strings.HasPrefix(line, "new file mode "):
+			created = "new empty file"
+		case strings.HasPrefix(line, "deleted file mode "):
+			created = "deleted empty file"
+		}
+	}
+
+	var notes []string
+	if renameFrom != "" && renameTo != "" {
+		path = renameFrom + " -> " + renameTo
+		notes = append(notes, "renamed")
+	}
+	if oldMode != "" && newMode != "" {
+		notes = append(notes, "mode "+oldMode+" -> "+newMode)
+	}
+	if created != "" {
+		notes = append(notes, created)
+	}
+	if len(notes) == 0 {
+		return path, true
+	}
+	return path + " (" + strings.Join(notes, ", ") + ")", true
 }
 
 func extractFiles(diff string) []string {
@@ -227,12 +492,17 @@ func extractFiles(diff string) []string {
 	return files
 }
 
-func filterExcluded(diff string, excludes []string) string {
+// filterDiff drops the diff sections whose path fails applyFilters.
+// Sections without a path header are kept.
+func filterDiff(diff string, opts DiffOptions) string {
+	if len(opts.Include) == 0 && len(opts.Exclude) == 0 {
+		return diff
+	}
 	sections := splitDiffSections(diff)
 	var kept []string
 	for _, section := range sections {
 		path := extractPathFromSection(section)
-		if path == "" || !MatchesAny(path, excludes) {
+		if path == "" || keepPath(path, opts) {
 			kept = append(kept, section)
 		}
 	}
@@ -257,19 +527,99 @@ func splitDiffSections(diff string) []string {
 	return sections
 }
 
+// extractPathFromSection returns the new path of a diff section, or the old
+// path for a deleted file.
 func extractPathFromSection(section string) string {
+	var oldPath string
 	for _, line := range strings.Split(section, "\n") {
 		if strings.HasPrefix(line, "+++ b/") {
 			return strings.TrimPrefix(line, "+++ b/")
 		}
+		if strings.HasPrefix(line, "--- a/") {
+			oldPath = strings.TrimPrefix(line, "--- a/")
+		}
+	}
+	return oldPath
+}
+
+// keepPath reports whether path passes the include/exclude filters in opts.
+// Exclude wins over include.
+func keepPath(path string, opts DiffOptions) bool {
+	if opts.CaseInsensitive {
+		path = strings.ToLower(path)
+	}
+	if len(opts.Include) > 0 && !MatchesAny(path, includePatterns(foldPatterns(opts.Include, opts.CaseInsensitive))) {
+		return false
+	}
+	return !excluded(path, foldPatterns(opts.Exclude, opts.CaseInsensitive))
+}
+
+// includePatterns expands Include patterns the way git pathspecs read
+// them, which is how --paths worked before filtering moved out of git: a
+// pattern without a slash, such as "*.go", matches a base name at any
+// depth, and a pattern without glob characters, such as "src" or
+// "cmd/prism", also matches everything under that directory.
+func includePatterns(patterns []string) []string {
+	out := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		p = strings.TrimSuffix(p, "/")
+		switch {
+		case !strings.ContainsAny(p, "*?["):
+			out = append(out, p, p+"/**")
+		case !strings.Contains(p, "/"):
+			out = append(out, "**/"+p)
+		default:
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// excluded reports whether path is excluded by patterns. Patterns are
+// applied in order and the last match wins: a plain pattern excludes the
+// path and a "!" pattern includes it again.
+func excluded(path string, patterns []string) bool {
+	out := false
+	for _, p := range patterns {
+		if neg, ok := strings.CutPrefix(p, "!"); ok {
+			if out && MatchesAny(path, []string{neg}) {
+				out = false
+			}
+			continue
+		}
+		if !out && MatchesAny(path, []string{p}) {
+			out = true
+		}
+	}
+	return out
+}
+
+// foldPatterns lowercases patterns for case-insensitive matching.
+func foldPatterns(patterns []string, fold bool) []string {
+	if !fold || len(patterns) == 0 {
+		return patterns
 	}
-	return ""
+	folded := make([]string, len(patterns))
+	for i, p := range patterns {
+		folded[i] = strings.ToLower(p)
+	}
+	return folded
+}
+
+// FilterFiles returns the files that pass the include/exclude filters in
+// opts, for file lists obtained outside gitctx such as a pull request's.
+func FilterFiles(files []string, opts DiffOptions) []string {
+	return applyFilters(files, opts)
 }
 
-func filterFileList(files []string, excludes []string) []string {
+// applyFilters returns the files that pass the include/exclude filters in
+// opts, preserving order. Every review mode filters through it so a path is
+// treated the same whether it comes from a diff, git ls-files, or a
+// directory walk.
+func applyFilters(files []string, opts DiffOptions) []string {
 	var result []string
 	for _, f := range files {
-		if !MatchesAny(f, excludes) {
+		if keepPath(f, opts) {
 			result = append(result, f)
 		}
 	}
@@ -277,12 +627,18 @@ func filterFileList(files []string, excludes []string) []string {
 }
 
 // MatchesAny returns true if the path matches any of the given glob patterns.
+// Patterns use filepath.Match syntax per path segment, plus "**" segments
+// that match any number of directories; a leading "**/" also matches the
+// file's base name.
 func MatchesAny(path string, patterns []string) bool {
 	for _, pattern := range patterns {
 		matched, err := filepath.Match(pattern, path)
 		if err == nil && matched {
 			return true
 		}
+		if strings.Contains(pattern, "**") && MatchSegments(strings.Split(pattern, "/"), strings.Split(path, "/")) {
+			return true
+		}
 		clean := strings.TrimPrefix(pattern, "**/")
 		if clean != pattern {
 			matched, err = filepath.Match(clean, filepath.Base(path))
@@ -298,6 +654,29 @@ func MatchesAny(path string, patterns []string) bool {
 	return false
 }
 
+// MatchSegments matches path segments against pattern segments, where a
+// "**" segment matches zero or more path segments.
+func MatchSegments(pattern, path []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(path); i++ {
+				if MatchSegments(pattern[1:], path[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(path) == 0 {
+			return false
+		}
+		if ok, err := filepath.Match(pattern[0], path[0]); err != nil || !ok {
+			return false
+		}
+		pattern, path = pattern[1:], path[1:]
+	}
+	return len(path) == 0
+}
+
 // maxFileBytes is the per-file size limit for codebase review.
 const maxFileBytes = 1 << 20 // 1MB
 
@@ -310,35 +689,77 @@ func WalkFiles(opts DiffOptions) ([]string, error) {
 		return nil, fmt.Errorf("git ls-files: %w", err)
 	}
 
-	var files []string
+	var tracked []string
 	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
+		if line = strings.TrimSpace(line); line != "" {
+			tracked = append(tracked, line)
 		}
-		// Apply include filter
-		if len(opts.Include) > 0 {
-			if !MatchesAny(line, opts.Include) {
-				continue
-			}
+	}
+	candidates := applyFilters(tracked, opts)
+	if !opts.ModifiedSince.IsZero() {
+		recent, err := committedSince(opts.ModifiedSince)
+		if err != nil {
+			return nil, err
 		}
-		// Apply exclude filter
-		if len(opts.Exclude) > 0 {
-			if MatchesAny(line, opts.Exclude) {
-				continue
+		// ls-files paths are relative to the working directory and git log
+		// paths to the repository root; prefix bridges the two.
+		prefix, err := gitOutput("rev-parse", "--show-prefix")
+		if err != nil {
+			return nil, fmt.Errorf("git rev-parse --show-prefix: %w", err)
+		}
+		prefix = strings.TrimSpace(prefix)
+		var kept []string
+		for _, path := range candidates {
+			if recent[prefix+path] {
+				kept = append(kept, path)
 			}
 		}
-		// Skip binary files
-		if isBinary(line) {
-			continue
+		candidates = kept
+	}
+
+	// Skip binary files. Each check spawns git, so run them in parallel.
+	binary := make([]bool, len(candidates))
+	sem := make(chan struct{}, readConcurrency)
+	var wg sync.WaitGroup
+	for i, path := range candidates {
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			binary[i] = isBinary(path)
+		}(i, path)
+	}
+	wg.Wait()
+
+	var files []string
+	for i, path := range candidates {
+		if !binary[i] {
+			files = append(files, path)
 		}
-		files = append(files, line)
 	}
 
 	sort.Strings(files)
 	return files, nil
 }
 
+// committedSince returns the set of paths touched by any commit reachable
+// from HEAD with a commit date at or after since. Paths are relative to the
+// repository root.
+func committedSince(since time.Time) (map[string]bool, error) {
+	out, err := gitOutput("log", fmt.Sprintf("--since=@%d", since.Unix()), "--name-only", "--format=")
+	if err != nil {
+		return nil, fmt.Errorf("git log --since: %w", err)
+	}
+	recent := make(map[string]bool)
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			recent[line] = true
+		}
+	}
+	return recent, nil
+}
+
 // isBinary detects whether a file is binary using git diff --numstat.
 // Binary files show "-\t-\t" for added/removed lines.
 func isBinary(path string) bool {
@@ -359,52 +780,246 @@ func Codebase(opts DiffOptions) (DiffResult, error) {
 		return DiffResult{}, err
 	}
 
-	var combined strings.Builder
-	var includedFiles []string
-	totalBytes := 0
-
-	for _, path := range files {
+	sections := readSections(files, func(path string) ([]byte, bool) {
 		data, err := os.ReadFile(path)
-		if err != nil {
-			continue // skip unreadable files
-		}
-		if len(data) > maxFileBytes {
-			continue // skip oversized files
+		if err != nil || len(data) > maxFileBytes {
+			return nil, false // skip unreadable or oversized files
 		}
+		return redactFile(path, data, opts.RedactPaths), true
+	})
+	diff, includedFiles := assembleSections(sections, opts.MaxDiffBytes)
 
-		content := string(data)
-		lines := strings.Split(content, "\n")
+	return DiffResult{
+		Diff:  diff,
+		Files: includedFiles,
+		Mode:  "codebase",
+		Repo:  meta,
+	}, nil
+}
 
-		var section strings.Builder
-		fmt.Fprintf(&section, "diff --git a/%s b/%s\n", path, path)
-		fmt.Fprintf(&section, "new file mode 100644\n")
-		fmt.Fprintf(&section, "--- /dev/null\n")
-		fmt.Fprintf(&section, "+++ b/%s\n", path)
-		fmt.Fprintf(&section, "@@ -0,0 +1,%d @@\n", len(lines))
-		for _, line := range lines {
-			fmt.Fprintf(&section, "+%s\n", line)
-		}
+// Dir reads all non-binary files under root from the filesystem and
+// assembles them as synthetic unified diffs, like Codebase but without
+// requiring a git repository. Paths are reported relative to root.
+// Returns a DiffResult with Mode="dir".
+func Dir(root string, opts DiffOptions) (DiffResult, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return DiffResult{}, fmt.Errorf("reading directory: %w", err)
+	}
+	if !info.IsDir() {
+		return DiffResult{}, fmt.Errorf("%s is not a directory", root)
+	}
 
-		sectionStr := section.String()
+	var files []string
+	err = filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // skip unreadable entries
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if !opts.ModifiedSince.IsZero() {
+			info, err := d.Info()
+			if err != nil || info.ModTime().Before(opts.ModifiedSince) {
+				return nil
+			}
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return DiffResult{}, fmt.Errorf("walking %s: %w", root, err)
+	}
+	files = applyFilters(files, opts)
+	sort.Strings(files)
 
-		// Respect MaxDiffBytes as total budget
-		if opts.MaxDiffBytes > 0 && totalBytes+len(sectionStr) > opts.MaxDiffBytes {
-			break
+	sections := readSections(files, func(path string) ([]byte, bool) {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(path)))
+		if err != nil || len(data) > maxFileBytes || looksBinary(data) {
+			return nil, false
 		}
+		return redactFile(path, data, opts.RedactPaths), true
+	})
+	diff, includedFiles := assembleSections(sections, opts.MaxDiffBytes)
 
-		combined.WriteString(sectionStr)
-		includedFiles = append(includedFiles, path)
-		totalBytes += len(sectionStr)
+	// Repository metadata is optional here; outside a repo it stays empty.
+	meta, err := repoMetaIn(root)
+	if err != nil {
+		meta = RepoMeta{}
 	}
 
 	return DiffResult{
-		Diff:  combined.String(),
+		Diff:  diff,
 		Files: includedFiles,
-		Mode:  "codebase",
+		Mode:  "dir",
+		Range: root,
 		Repo:  meta,
 	}, nil
 }
 
+// FromFiles reads the listed files from the working tree and assembles them
+// as synthetic unified diffs, like Codebase but scoped to the given paths.
+// Paths are relative to the repository root, as git diff --name-only prints
+// them, or to the current directory outside a repository. Include and
+// exclude filters apply, and binary or oversized files are skipped. Files
+// that do not exist (for example deleted ones) are skipped and listed in
+// Missing. Returns a DiffResult with Mode="files".
+func FromFiles(paths []string, opts DiffOptions) DiffResult {
+	// Repository metadata is optional here; outside a repo it stays empty.
+	meta, err := GetRepoMeta()
+	if err != nil {
+		meta = RepoMeta{}
+	}
+	base := meta.Root
+	if base == "" {
+		base = "."
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		p = filepath.ToSlash(filepath.Clean(p))
+		if !seen[p] {
+			seen[p] = true
+			files = append(files, p)
+		}
+	}
+	files = applyFilters(files, opts)
+	sort.Strings(files)
+
+	var present, missing []string
+	for _, path := range files {
+		if _, err := os.Stat(filepath.Join(base, filepath.FromSlash(path))); errors.Is(err, fs.ErrNotExist) {
+			missing = append(missing, path)
+			continue
+		}
+		present = append(present, path)
+	}
+
+	sections := readSections(present, func(path string) ([]byte, bool) {
+		data, err := os.ReadFile(filepath.Join(base, filepath.FromSlash(path)))
+		if err != nil || len(data) > maxFileBytes || looksBinary(data) {
+			return nil, false
+		}
+		return redactFile(path, data, opts.RedactPaths), true
+	})
+	diff, includedFiles := assembleSections(sections, opts.MaxDiffBytes)
+
+	return DiffResult{
+		Diff:    diff,
+		Files:   includedFiles,
+		Mode:    "files",
+		Repo:    meta,
+		Missing: missing,
+	}
+}
+
+// redactFile replaces data with the path-policy placeholder when path
+// matches any of the redaction globs, so the content never reaches a prompt.
+func redactFile(path string, data []byte, patterns []string) []byte {
+	if len(patterns) == 0 || !redact.ShouldRedactPath(path, patterns) {
+		return data
+	}
+	return []byte(redact.Content(string(data), path, patterns))
+}
+
+// readConcurrency bounds the number of files read in parallel by Codebase and Dir.
+const readConcurrency = 8
+
+// fileSection is a synthetic diff section built from one file.
+type fileSection struct {
+	path    string
+	section string
+	ok      bool
+}
+
+// readSections loads each path with load and renders it as a synthetic diff
+// section, using a bounded pool of workers. Results are returned in the
+// same order as paths so output stays deterministic. Paths for which load
+// reports false are marked as skipped.
+func readSections(paths []string, load func(path string) ([]byte, bool)) []fileSection {
+	sections := make([]fileSection, len(paths))
+	sem := make(chan struct{}, readConcurrency)
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			data, ok := load(path)
+			if !ok {
+				sections[i] = fileSection{path: path}
+				return
+			}
+			sections[i] = fileSection{path: path, section: syntheticSection(path, string(data)), ok: true}
+		}(i, path)
+	}
+	wg.Wait()
+	return sections
+}
+
+// assembleSections concatenates sections in order, stopping before the one
+// that would push the total past maxBytes (0 means no limit). Returns the
+// combined diff and the paths included.
+func assembleSections(sections []fileSection, maxBytes int) (string, []string) {
+	var combined strings.Builder
+	var included []string
+	total := 0
+	for _, sec := range sections {
+		if !sec.ok {
+			continue
+		}
+		if maxBytes > 0 && total+len(sec.section) > maxBytes {
+			break
+		}
+		combined.WriteString(sec.section)
+		included = append(included, sec.path)
+		total += len(sec.section)
+	}
+	return combined.String(), included
+}
+
+// looksBinary reports whether data appears to be binary, using the same
+// heuristic as git: a NUL byte within the first 8000 bytes.
+func looksBinary(data []byte) bool {
+	n := len(data)
+	if n > 8000 {
+		n = 8000
+	}
+	return bytes.IndexByte(data[:n], 0) >= 0
+}
+
+// syntheticSection renders file content as a new-file unified diff section.
+func syntheticSection(path, content string) string {
+	lines := strings.Split(content, "\n")
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n", path, path)
+	fmt.Fprintf(&b, "new file mode 100644\n")
+	fmt.Fprintf(&b, "--- /dev/null\n")
+	fmt.Fprintf(&b, "+++ b/%s\n", path)
+	fmt.Fprintf(&b, "@@ -0,0 +1,%d @@\n", len(lines))
+	for _, line := range lines {
+		fmt.Fprintf(&b, "+%s\n", line)
+	}
+	return b.String()
+}
+
 // CommitInfo holds a commit SHA and its subject line.
 type CommitInfo struct {
 	SHA     string
@@ -421,8 +1036,12 @@ func ListCommits(revRange string, mergeBase bool) ([]CommitInfo, error) {
 
 	// Use --format to get SHA and subject in a single git call.
 	// Output format: "commit <sha>\n<subject>\n" per commit.
+	shallow := warnShallow(rangeEnds(listRange)...)
 	out, err := gitOutput("rev-list", "--reverse", "--format=%s", listRange)
 	if err != nil {
+		if shallow {
+			return nil, fmt.Errorf("git rev-list %s: %w (%s)", revRange, err, shallowHint)
+		}
 		return nil, fmt.Errorf("git rev-list %s: %w", revRange, err)
 	}
 
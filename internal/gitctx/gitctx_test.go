@@ -1,11 +1,15 @@
 package gitctx
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestExtractFiles(t *testing.T) {
@@ -42,7 +46,7 @@ func TestExtractFiles_Dedup(t *testing.T) {
 	}
 }
 
-func TestFilterExcluded(t *testing.T) {
+func TestFilterDiff(t *testing.T) {
 	diff := `diff --git a/main.go b/main.go
 --- a/main.go
 +++ b/main.go
@@ -54,7 +58,7 @@ diff --git a/vendor/lib.go b/vendor/lib.go
 @@ -1,3 +1,4 @@
 +package lib
 `
-	result := filterExcluded(diff, []string{"vendor/**"})
+	result := filterDiff(diff, DiffOptions{Exclude: []string{"vendor/**"}})
 	if strings.Contains(result, "vendor/lib.go") {
 		t.Error("vendor/lib.go should be excluded")
 	}
@@ -75,6 +79,11 @@ func TestMatchesAny(t *testing.T) {
 		{"pkg/foo.gen.go", []string{"**/*.gen.go"}, true},
 		{"dist/bundle.js", []string{"**/dist/**"}, true},
 		{"main.go", []string{"*.go"}, true},
+		{"vendor/a/b/lib.go", []string{"vendor/**"}, true},
+		{"src/a/b/c.go", []string{"src/**/*.go"}, true},
+		{"src/c.go", []string{"src/**/*.go"}, true},
+		{"lib/c.go", []string{"src/**/*.go"}, false},
+		{"a/node_modules/x/y.js", []string{"**/node_modules/**"}, true},
 	}
 	for _, tt := range tests {
 		got := MatchesAny(tt.path, tt.patterns)
@@ -147,9 +156,9 @@ func TestBuildDiffArgs(t *testing.T) {
 	if !found {
 		t.Error("args should contain -- separator")
 	}
-	// Should contain the include pattern
-	if args[len(args)-1] != "*.go" {
-		t.Errorf("last arg = %q, want %q", args[len(args)-1], "*.go")
+	// Include patterns are applied by applyFilters, not as git pathspecs
+	if args[len(args)-1] != "--" {
+		t.Errorf("last arg = %q, want %q", args[len(args)-1], "--")
 	}
 }
 
@@ -197,11 +206,11 @@ func TestExtractPathFromSection_NoPath(t *testing.T) {
 	}
 }
 
-func TestFilterFileList(t *testing.T) {
+func TestApplyFilters(t *testing.T) {
 	files := []string{"main.go", "vendor/lib.go", "pkg/util.go", "dist/bundle.js"}
-	result := filterFileList(files, []string{"vendor/**", "**/dist/**"})
+	result := applyFilters(files, DiffOptions{Exclude: []string{"vendor/**", "**/dist/**"}})
 	if len(result) != 2 {
-		t.Fatalf("filterFileList got %d files, want 2", len(result))
+		t.Fatalf("applyFilters got %d files, want 2", len(result))
 	}
 	if result[0] != "main.go" {
 		t.Errorf("result[0] = %q, want %q", result[0], "main.go")
@@ -211,10 +220,102 @@ func TestFilterFileList(t *testing.T) {
 	}
 }
 
-func TestFilterFileList_Empty(t *testing.T) {
-	result := filterFileList(nil, []string{"vendor/**"})
+func TestApplyFilters_Empty(t *testing.T) {
+	result := applyFilters(nil, DiffOptions{Exclude: []string{"vendor/**"}})
 	if len(result) != 0 {
-		t.Errorf("filterFileList nil input got %d, want 0", len(result))
+		t.Errorf("applyFilters nil input got %d, want 0", len(result))
+	}
+}
+
+// overlapOpts includes all Go files but excludes one that matches both.
+var overlapOpts = DiffOptions{
+	Include: []string{"**/*.go"},
+	Exclude: []string{"internal/gen/**"},
+}
+
+func TestApplyFilters_ExcludeWinsOverInclude(t *testing.T) {
+	files := []string{"main.go", "internal/gen/api.go", "README.md"}
+	got := applyFilters(files, overlapOpts)
+	if len(got) != 1 || got[0] != "main.go" {
+		t.Errorf("applyFilters = %v, want [main.go]", got)
+	}
+}
+
+func TestApplyFilters_NegatedExclude(t *testing.T) {
+	files := []string{"vendor/lib.go", "vendor/keep/patched.go", "main.go"}
+	got := applyFilters(files, DiffOptions{Exclude: []string{"vendor/**", "!vendor/keep/**"}})
+	want := []string{"vendor/keep/patched.go", "main.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("applyFilters = %v, want %v", got, want)
+	}
+
+	// The last matching pattern wins: a later exclude undoes the negation.
+	got = applyFilters(files, DiffOptions{Exclude: []string{"vendor/**", "!vendor/keep/**", "**/patched.go"}})
+	if !reflect.DeepEqual(got, []string{"main.go"}) {
+		t.Errorf("applyFilters = %v, want [main.go]", got)
+	}
+
+	// A negation cannot re-include a file the include list rejected.
+	got = applyFilters(files, DiffOptions{Include: []string{"main.go"}, Exclude: []string{"!vendor/**"}})
+	if !reflect.DeepEqual(got, []string{"main.go"}) {
+		t.Errorf("applyFilters = %v, want [main.go]", got)
+	}
+}
+
+func TestApplyFilters_CaseInsensitive(t *testing.T) {
+	files := []string{"Docs/README.MD", "src/Main.go", "Vendor/lib.go"}
+	opts := DiffOptions{Include: []string{"**/*.md", "SRC/**"}, Exclude: []string{"vendor/**"}}
+
+	if got := applyFilters(files, opts); len(got) != 0 {
+		t.Errorf("case-sensitive applyFilters = %v, want none", got)
+	}
+	opts.CaseInsensitive = true
+	want := []string{"Docs/README.MD", "src/Main.go"}
+	if got := applyFilters(files, opts); !reflect.DeepEqual(got, want) {
+		t.Errorf("case-insensitive applyFilters = %v, want %v", got, want)
+	}
+}
+
+func TestBuildResult_IncludeExcludeOverlap(t *testing.T) {
+	diff := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n+ok\n" +
+		"diff --git a/internal/gen/api.go b/internal/gen/api.go\n--- a/internal/gen/api.go\n+++ b/internal/gen/api.go\n@@ -1 +1 @@\n+gen\n" +
+		"diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n@@ -1 +1 @@\n+doc\n" +
+		"diff --git a/old/gone.go b/old/gone.go\ndeleted file mode 100644\n--- a/old/gone.go\n+++ /dev/null\n@@ -1 +0,0 @@\n-bye\n"
+	opts := overlapOpts
+	opts.Exclude = append([]string{"old/**"}, opts.Exclude...)
+
+	result, err := buildResult(diff, "staged", "", opts)
+	if err != nil {
+		t.Fatalf("buildResult error: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0] != "main.go" {
+		t.Errorf("Files = %v, want [main.go]", result.Files)
+	}
+	for _, dropped := range []string{"internal/gen/api.go", "README.md", "old/gone.go"} {
+		if strings.Contains(result.Diff, dropped) {
+			t.Errorf("diff should not contain %s", dropped)
+		}
+	}
+}
+
+func TestDir_IncludeExcludeOverlap(t *testing.T) {
+	root := t.TempDir()
+	for _, f := range []string{"main.go", "internal/gen/api.go", "README.md"} {
+		path := filepath.Join(root, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := Dir(root, overlapOpts)
+	if err != nil {
+		t.Fatalf("Dir error: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0] != "main.go" {
+		t.Errorf("Files = %v, want [main.go]", result.Files)
 	}
 }
 
@@ -273,6 +374,53 @@ func TestBuildResult_MetadataAndMode(t *testing.T) {
 	}
 }
 
+func TestBuildResult_PureRenameDropped(t *testing.T) {
+	diff := "diff --git a/old.go b/new.go\nsimilarity index 100%\nrename from old.go\nrename to new.go\n"
+	result, err := buildResult(diff, "staged", "", DiffOptions{})
+	if err != nil {
+		t.Fatalf("buildResult error: %v", err)
+	}
+	if strings.TrimSpace(result.Diff) != "" {
+		t.Errorf("pure rename should leave no prompt content, got %q", result.Diff)
+	}
+	if len(result.Files) != 0 {
+		t.Errorf("Files = %v, want none", result.Files)
+	}
+	want := []string{"old.go -> new.go (renamed)"}
+	if len(result.MetadataOnly) != 1 || result.MetadataOnly[0] != want[0] {
+		t.Errorf("MetadataOnly = %v, want %v", result.MetadataOnly, want)
+	}
+}
+
+func TestDropMetadataOnly(t *testing.T) {
+	modeOnly := "diff --git a/run.sh b/run.sh\nold mode 100644\nnew mode 100755\n"
+	renamed := "diff --git a/a.go b/b.go\nsimilarity index 90%\nrename from a.go\nrename to b.go\n--- a/a.go\n+++ b/b.go\n@@ -1 +1 @@\n-x\n+y\n"
+	binary := "diff --git a/logo.png b/logo.png\nindex 1234567..89abcde 100644\nBinary files a/logo.png and b/logo.png differ\n"
+	empty := "diff --git a/empty.txt b/empty.txt\nnew file mode 100644\nindex 0000000..e69de29\n"
+
+	got, dropped := DropMetadataOnly(modeOnly + renamed + binary + empty)
+	if got != renamed+binary {
+		t.Errorf("kept diff = %q, want rename-with-edits and binary sections", got)
+	}
+	want := []string{"run.sh (mode 100644 -> 100755)", "empty.txt (new empty file)"}
+	if len(dropped) != len(want) {
+		t.Fatalf("dropped = %v, want %v", dropped, want)
+	}
+	for i := range want {
+		if dropped[i] != want[i] {
+			t.Errorf("dropped[%d] = %q, want %q", i, dropped[i], want[i])
+		}
+	}
+}
+
+func TestDropMetadataOnly_NoChange(t *testing.T) {
+	diff := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n+ok\n"
+	got, dropped := DropMetadataOnly(diff)
+	if got != diff || dropped != nil {
+		t.Errorf("DropMetadataOnly changed a content diff: %q, %v", got, dropped)
+	}
+}
+
 func TestSnippet_WithBase(t *testing.T) {
 	base := "package main\n\nfunc main() {}\n"
 	content := "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"hello\") }\n"
@@ -317,7 +465,7 @@ func TestMatchesAny_EmptyPatterns(t *testing.T) {
 
 // setupTestRepo creates a temp git repo with some tracked files and returns
 // the path. Caller must defer cleanup.
-func setupTestRepo(t *testing.T) string {
+func setupTestRepo(t testing.TB) string {
 	t.Helper()
 	dir := t.TempDir()
 
@@ -394,6 +542,42 @@ func TestWalkFiles_WithInclude(t *testing.T) {
 	}
 }
 
+func TestUnstaged_IncludeMatchesNested(t *testing.T) {
+	dir := setupTestRepo(t)
+	t.Chdir(dir)
+	os.MkdirAll(filepath.Join(dir, "src", "api"), 0o755)
+	os.WriteFile(filepath.Join(dir, "src", "api", "handler.go"), []byte("package api\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "src", "README.md"), []byte("# src\n"), 0o644)
+	exec.Command("git", "-C", dir, "add", "-N", ".").Run()
+
+	tests := []struct {
+		include []string
+		want    string
+	}{
+		{[]string{"*.go"}, "src/api/handler.go"},
+		{[]string{"src"}, "src/README.md,src/api/handler.go"},
+		{[]string{"src/api/"}, "src/api/handler.go"},
+		{[]string{"src/*.go"}, ""},
+	}
+	for _, tt := range tests {
+		result, err := Unstaged(DiffOptions{Include: tt.include})
+		if err != nil {
+			t.Fatalf("Unstaged error: %v", err)
+		}
+		if got := strings.Join(result.Files, ","); got != tt.want {
+			t.Errorf("Include %v: Files = %q, want %q", tt.include, got, tt.want)
+		}
+	}
+}
+
+func TestIncludePatterns(t *testing.T) {
+	got := includePatterns([]string{"*.go", "src", "cmd/prism/", "**/*.md", "internal/*.go"})
+	want := "**/*.go,src,src/**,cmd/prism,cmd/prism/**,**/*.md,internal/*.go"
+	if strings.Join(got, ",") != want {
+		t.Errorf("includePatterns = %v, want %s", got, want)
+	}
+}
+
 func TestWalkFiles_WithExclude(t *testing.T) {
 	dir := setupTestRepo(t)
 	origDir, _ := os.Getwd()
@@ -412,6 +596,47 @@ func TestWalkFiles_WithExclude(t *testing.T) {
 	}
 }
 
+func TestWalkFiles_ModifiedSince(t *testing.T) {
+	dir := setupTestRepo(t)
+	origDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(origDir)
+
+	files, err := WalkFiles(DiffOptions{ModifiedSince: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("WalkFiles error: %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("no commits are newer than the cutoff, got %v", files)
+	}
+
+	files, err = WalkFiles(DiffOptions{
+		ModifiedSince: time.Now().Add(-time.Hour),
+		Exclude:       []string{"vendor/**"},
+	})
+	if err != nil {
+		t.Fatalf("WalkFiles error: %v", err)
+	}
+	if len(files) != 2 || files[0] != "main.go" || files[1] != "util.go" {
+		t.Errorf("files = %v, want [main.go util.go]", files)
+	}
+}
+
+func TestWalkFiles_ModifiedSinceFromSubdir(t *testing.T) {
+	dir := setupTestRepo(t)
+	origDir, _ := os.Getwd()
+	os.Chdir(filepath.Join(dir, "vendor"))
+	defer os.Chdir(origDir)
+
+	files, err := WalkFiles(DiffOptions{ModifiedSince: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("WalkFiles error: %v", err)
+	}
+	if len(files) != 1 || files[0] != "lib.go" {
+		t.Errorf("files = %v, want [lib.go]", files)
+	}
+}
+
 func TestCodebase(t *testing.T) {
 	dir := setupTestRepo(t)
 	origDir, _ := os.Getwd()
@@ -530,3 +755,438 @@ func TestCodebase_MaxDiffBytes(t *testing.T) {
 		t.Errorf("Diff should be limited by MaxDiffBytes, got %d bytes", len(result.Diff))
 	}
 }
+
+func TestIndex_PartiallyStaged(t *testing.T) {
+	dir := setupTestRepo(t)
+	origDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(origDir)
+
+	// Stage one change, then make a further unstaged edit to the same file
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {\n\tstaged()\n}\n"), 0o644)
+	if out, err := exec.Command("git", "add", "main.go").CombinedOutput(); err != nil {
+		t.Fatalf("git add failed: %v\n%s", err, out)
+	}
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {\n\tunstaged()\n\tstaged()\n}\n"), 0o644)
+
+	result, err := Index(DiffOptions{})
+	if err != nil {
+		t.Fatalf("Index error: %v", err)
+	}
+	if result.Mode != "index" {
+		t.Errorf("Mode = %q, want %q", result.Mode, "index")
+	}
+	if !strings.Contains(result.Diff, "+\tstaged()") {
+		t.Error("Diff should contain the staged change")
+	}
+	if strings.Contains(result.Diff, "unstaged()") {
+		t.Error("Diff should not contain working tree changes")
+	}
+	if len(result.PartiallyStaged) != 1 || result.PartiallyStaged[0] != "main.go" {
+		t.Errorf("PartiallyStaged = %v, want [main.go]", result.PartiallyStaged)
+	}
+}
+
+func TestAmend(t *testing.T) {
+	dir := setupTestRepo(t)
+	origDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(origDir)
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test",
+			"GIT_AUTHOR_EMAIL=test@test.com",
+			"GIT_COMMITTER_NAME=test",
+			"GIT_COMMITTER_EMAIL=test@test.com",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("command %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	// Root commit: the amended commit is diffed against the empty tree
+	result, err := Amend(DiffOptions{})
+	if err != nil {
+		t.Fatalf("Amend error on root commit: %v", err)
+	}
+	if !strings.Contains(result.Diff, "+++ b/main.go") {
+		t.Error("root amend diff should add main.go")
+	}
+
+	os.WriteFile(filepath.Join(dir, "a.go"), []byte("package main\n\nfunc a() {}\n"), 0o644)
+	run("git", "add", "a.go")
+	run("git", "commit", "-m", "add a.go")
+
+	// Stage a fix to the last commit's file
+	os.WriteFile(filepath.Join(dir, "a.go"), []byte("package main\n\nfunc a() {}\n\nfunc fixed() {}\n"), 0o644)
+	run("git", "add", "a.go")
+
+	result, err = Amend(DiffOptions{})
+	if err != nil {
+		t.Fatalf("Amend error: %v", err)
+	}
+	if result.Mode != "amend" || result.Range != "HEAD~1" {
+		t.Errorf("Mode, Range = %q, %q, want amend, HEAD~1", result.Mode, result.Range)
+	}
+	// The whole amended commit: the file is new relative to HEAD~1
+	if !strings.Contains(result.Diff, "new file mode") || !strings.Contains(result.Diff, "+func a() {}") {
+		t.Error("Amend diff should contain the committed content")
+	}
+	if !strings.Contains(result.Diff, "+func fixed() {}") {
+		t.Error("Amend diff should contain the staged change")
+	}
+	if len(result.Files) != 1 || result.Files[0] != "a.go" {
+		t.Errorf("Files = %v, want [a.go]", result.Files)
+	}
+}
+
+func TestGetRepoMeta_Detached(t *testing.T) {
+	dir := setupTestRepo(t)
+	origDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(origDir)
+
+	meta, err := GetRepoMeta()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Branch != "main" || meta.Detached {
+		t.Errorf("on a branch: Branch, Detached = %q, %v, want main, false", meta.Branch, meta.Detached)
+	}
+
+	if out, err := exec.Command("git", "checkout", "--detach").CombinedOutput(); err != nil {
+		t.Fatalf("git checkout --detach: %v\n%s", err, out)
+	}
+	meta, err = GetRepoMeta()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Branch != "" || !meta.Detached || meta.Head == "" {
+		t.Errorf("detached: got %+v, want empty Branch, Detached, and Head set", meta)
+	}
+}
+
+func TestShallowClone(t *testing.T) {
+	src := setupTestRepo(t)
+	os.WriteFile(filepath.Join(src, "main.go"), []byte("package main\n\nfunc main() { helper() }\n"), 0o644)
+	cmd := exec.Command("git", "commit", "-am", "second")
+	cmd.Dir = src
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test",
+		"GIT_AUTHOR_EMAIL=test@test.com",
+		"GIT_COMMITTER_NAME=test",
+		"GIT_COMMITTER_EMAIL=test@test.com",
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v\n%s", err, out)
+	}
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	if out, err := exec.Command("git", "clone", "--quiet", "--depth", "1", "file://"+src, clone).CombinedOutput(); err != nil {
+		t.Fatalf("git clone --depth 1: %v\n%s", err, out)
+	}
+	origDir, _ := os.Getwd()
+	os.Chdir(clone)
+	defer os.Chdir(origDir)
+
+	if !IsShallow() {
+		t.Fatal("IsShallow() = false for a --depth 1 clone")
+	}
+	_, err := Range("HEAD~1..HEAD", false, DiffOptions{})
+	if err == nil || !strings.Contains(err.Error(), "git fetch --deepen") {
+		t.Errorf("Range error should suggest deepening the clone, got: %v", err)
+	}
+
+	os.Chdir(src)
+	if IsShallow() {
+		t.Error("IsShallow() = true for a full repository")
+	}
+}
+
+func TestDir_NoGit(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644)
+	os.MkdirAll(filepath.Join(dir, "vendor"), 0o755)
+	os.WriteFile(filepath.Join(dir, "vendor", "lib.go"), []byte("package vendor\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "image.bin"), []byte{0x89, 0x50, 0x00, 0x01}, 0o644)
+
+	result, err := Dir(dir, DiffOptions{Exclude: []string{"vendor/**"}})
+	if err != nil {
+		t.Fatalf("Dir error: %v", err)
+	}
+	if result.Mode != "dir" {
+		t.Errorf("Mode = %q, want %q", result.Mode, "dir")
+	}
+	if len(result.Files) != 1 || result.Files[0] != "main.go" {
+		t.Errorf("Files = %v, want [main.go]", result.Files)
+	}
+	if !strings.Contains(result.Diff, "+++ b/main.go") || !strings.Contains(result.Diff, "+package main") {
+		t.Error("Diff should contain main.go as a synthetic new file")
+	}
+}
+
+func TestDir_RepoMetaFromRoot(t *testing.T) {
+	// The test runs inside this module's repository, which must not leak
+	// into the metadata of the directory under review.
+	repo := setupTestRepo(t)
+	result, err := Dir(repo, DiffOptions{})
+	if err != nil {
+		t.Fatalf("Dir error: %v", err)
+	}
+	want, _ := filepath.EvalSymlinks(repo)
+	if got, _ := filepath.EvalSymlinks(result.Repo.Root); got != want {
+		t.Errorf("Repo.Root = %q, want the reviewed repo %q", result.Repo.Root, repo)
+	}
+
+	result, err = Dir(t.TempDir(), DiffOptions{})
+	if err != nil {
+		t.Fatalf("Dir error: %v", err)
+	}
+	if result.Repo.Root != "" {
+		t.Errorf("Repo.Root = %q, want none outside a repository", result.Repo.Root)
+	}
+}
+
+func TestFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "pkg"), 0o755)
+	os.MkdirAll(filepath.Join(dir, "vendor"), 0o755)
+	os.WriteFile(filepath.Join(dir, "pkg", "a.go"), []byte("package pkg\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "vendor", "lib.go"), []byte("package vendor\n"), 0o644)
+	origDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(origDir)
+
+	paths := []string{"./pkg/a.go", "", "vendor/lib.go", "pkg/a.go", "deleted.go"}
+	result := FromFiles(paths, DiffOptions{Exclude: []string{"vendor/**"}})
+	if result.Mode != "files" {
+		t.Errorf("Mode = %q, want %q", result.Mode, "files")
+	}
+	if len(result.Files) != 1 || result.Files[0] != "pkg/a.go" {
+		t.Errorf("Files = %v, want [pkg/a.go]", result.Files)
+	}
+	if !strings.Contains(result.Diff, "+++ b/pkg/a.go") || strings.Contains(result.Diff, "vendor/") {
+		t.Errorf("Diff should hold only pkg/a.go, got:\n%s", result.Diff)
+	}
+	if len(result.Missing) != 1 || result.Missing[0] != "deleted.go" {
+		t.Errorf("Missing = %v, want [deleted.go]", result.Missing)
+	}
+}
+
+func TestFromFiles_RelativeToRepoRoot(t *testing.T) {
+	dir := setupTestRepo(t)
+	origDir, _ := os.Getwd()
+	os.Chdir(filepath.Join(dir, "vendor"))
+	defer os.Chdir(origDir)
+
+	result := FromFiles([]string{"main.go", "vendor/lib.go"}, DiffOptions{})
+	if len(result.Files) != 2 || result.Files[0] != "main.go" || result.Files[1] != "vendor/lib.go" {
+		t.Errorf("Files = %v, want [main.go vendor/lib.go] read from the repository root", result.Files)
+	}
+	if len(result.Missing) != 0 {
+		t.Errorf("Missing = %v, want none", result.Missing)
+	}
+}
+
+func TestDir_ModifiedSince(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"old.go", "new.go"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("package x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().AddDate(0, 0, -30)
+	if err := os.Chtimes(filepath.Join(root, "old.go"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Dir(root, DiffOptions{ModifiedSince: time.Now().AddDate(0, 0, -7)})
+	if err != nil {
+		t.Fatalf("Dir error: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0] != "new.go" {
+		t.Errorf("Files = %v, want [new.go]", result.Files)
+	}
+}
+
+func TestDir_NotADirectory(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "file.txt")
+	os.WriteFile(f, []byte("x"), 0o644)
+	if _, err := Dir(f, DiffOptions{}); err == nil {
+		t.Error("expected error for non-directory path")
+	}
+}
+
+func TestCodebase_DeterministicOrder(t *testing.T) {
+	dir := setupTestRepo(t)
+	for i := 0; i < 50; i++ {
+		name := filepath.Join(dir, "pkg", fmt.Sprintf("file%03d.go", i))
+		os.MkdirAll(filepath.Dir(name), 0o755)
+		os.WriteFile(name, []byte(fmt.Sprintf("package pkg\n\nconst N%d = %d\n", i, i)), 0o644)
+	}
+	if out, err := exec.Command("git", "-C", dir, "add", "-A").CombinedOutput(); err != nil {
+		t.Fatalf("git add failed: %v\n%s", err, out)
+	}
+	origDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(origDir)
+
+	first, err := Codebase(DiffOptions{})
+	if err != nil {
+		t.Fatalf("Codebase error: %v", err)
+	}
+	if len(first.Files) < 50 {
+		t.Fatalf("expected at least 50 files, got %d", len(first.Files))
+	}
+	if !sort.StringsAreSorted(first.Files) {
+		t.Errorf("Files should be in path order: %v", first.Files)
+	}
+	for i := 0; i < 5; i++ {
+		again, err := Codebase(DiffOptions{})
+		if err != nil {
+			t.Fatalf("Codebase error: %v", err)
+		}
+		if again.Diff != first.Diff {
+			t.Fatal("Codebase output should be identical across runs")
+		}
+	}
+}
+
+func BenchmarkCodebase(b *testing.B) {
+	dir := setupTestRepo(b)
+	content := strings.Repeat("// filler line for benchmark input\n", 200)
+	for i := 0; i < 500; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("pkg%02d", i%20), fmt.Sprintf("file%03d.go", i))
+		os.MkdirAll(filepath.Dir(name), 0o755)
+		os.WriteFile(name, []byte("package pkg\n"+content), 0o644)
+	}
+	if out, err := exec.Command("git", "-C", dir, "add", "-A").CombinedOutput(); err != nil {
+		b.Fatalf("git add failed: %v\n%s", err, out)
+	}
+	origDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(origDir)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Codebase(DiffOptions{}); err != nil {
+			b.Fatalf("Codebase error: %v", err)
+		}
+	}
+}
+
+func TestDefaultBranch(t *testing.T) {
+	dir := setupTestRepo(t)
+	origDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(origDir)
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("command %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	// No remote: fall back to the local main branch.
+	run("git", "checkout", "-b", "feature")
+	base, err := DefaultBranch()
+	if err != nil {
+		t.Fatalf("DefaultBranch error: %v", err)
+	}
+	if base != "main" {
+		t.Errorf("DefaultBranch() = %q, want %q", base, "main")
+	}
+
+	// origin/HEAD takes precedence over the fallbacks.
+	run("git", "update-ref", "refs/remotes/origin/develop", "HEAD")
+	run("git", "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/develop")
+	base, err = DefaultBranch()
+	if err != nil {
+		t.Fatalf("DefaultBranch error: %v", err)
+	}
+	if base != "origin/develop" {
+		t.Errorf("DefaultBranch() = %q, want %q", base, "origin/develop")
+	}
+}
+
+func TestDefaultBranch_NoneFound(t *testing.T) {
+	dir := setupTestRepo(t)
+	origDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(origDir)
+
+	cmd := exec.Command("git", "branch", "-m", "main", "trunk")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git branch -m failed: %v\n%s", err, out)
+	}
+	if _, err := DefaultBranch(); err == nil {
+		t.Error("expected an error when no default branch can be detected")
+	}
+}
+
+func TestCodebase_RedactPaths(t *testing.T) {
+	dir := setupTestRepo(t)
+	origDir, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(origDir)
+
+	os.MkdirAll(filepath.Join(dir, "config"), 0o755)
+	os.WriteFile(filepath.Join(dir, "config", "secrets.yaml"), []byte("db_password: hunter2hunter2\n"), 0o644)
+	cmd := exec.Command("git", "add", "-A")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git add failed: %v\n%s", err, out)
+	}
+
+	result, err := Codebase(DiffOptions{RedactPaths: []string{"**/*secrets*"}})
+	if err != nil {
+		t.Fatalf("Codebase error: %v", err)
+	}
+	if strings.Contains(result.Diff, "hunter2") {
+		t.Error("redacted file content leaked into the codebase diff")
+	}
+	if !strings.Contains(result.Diff, "+++ b/config/secrets.yaml") || !strings.Contains(result.Diff, "[REDACTED]") {
+		t.Errorf("expected a placeholder section for config/secrets.yaml, got:\n%s", result.Diff)
+	}
+	if !strings.Contains(result.Diff, "+package main") {
+		t.Error("non-matching files should keep their content")
+	}
+}
+
+func TestDir_RedactPaths(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ".env"), []byte("API_KEY=supersecretvalue\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644)
+
+	result, err := Dir(dir, DiffOptions{RedactPaths: []string{"**/.env"}})
+	if err != nil {
+		t.Fatalf("Dir error: %v", err)
+	}
+	if strings.Contains(result.Diff, "supersecretvalue") {
+		t.Error("redacted file content leaked into the dir diff")
+	}
+	if !strings.Contains(result.Diff, "+package main") {
+		t.Error("non-matching files should keep their content")
+	}
+}
+
+func TestSnippetWithOptions_RedactPaths(t *testing.T) {
+	result, err := SnippetWithOptions("token: abcdefgh12345678\n", "deploy/.env", "", "", DiffOptions{RedactPaths: []string{"**/.env"}})
+	if err != nil {
+		t.Fatalf("SnippetWithOptions error: %v", err)
+	}
+	if strings.Contains(result.Diff, "abcdefgh12345678") {
+		t.Error("redacted snippet content leaked into the diff")
+	}
+	if !strings.Contains(result.Diff, "[REDACTED]") {
+		t.Errorf("expected placeholder in diff, got:\n%s", result.Diff)
+	}
+}
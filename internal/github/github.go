@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -175,24 +176,27 @@ func (c *Client) PostReview(ctx context.Context, owner, repo string, prNumber in
 	return nil
 }
 
+// ReviewOptions controls optional sections of the PR review summary body.
+type ReviewOptions struct {
+	// Owners maps changed files to their code owners; when set, the body
+	// lists the owners who should review.
+	Owners map[string][]string
+}
+
 // BuildGitHubReview converts review findings into a GitHub PR review request.
 // diffFiles is the set of files in the PR diff. Findings for files not in the diff
 // are included in the summary body only.
 func BuildGitHubReview(findings []review.Finding, diffFiles map[string]bool) ReviewRequest {
-	var high, medium, low int
+	return BuildGitHubReviewWithOptions(findings, diffFiles, ReviewOptions{})
+}
+
+// BuildGitHubReviewWithOptions is BuildGitHubReview with optional summary
+// sections configured by opts.
+func BuildGitHubReviewWithOptions(findings []review.Finding, diffFiles map[string]bool, opts ReviewOptions) ReviewRequest {
 	var bodyComments []string
 	var comments []ReviewComment
 
 	for _, f := range findings {
-		switch f.Severity {
-		case review.SeverityHigh:
-			high++
-		case review.SeverityMedium:
-			medium++
-		case review.SeverityLow:
-			low++
-		}
-
 		// Check if finding has a valid location in the diff
 		if len(f.Locations) > 0 && f.Locations[0].Path != "" && diffFiles[f.Locations[0].Path] {
 			loc := f.Locations[0]
@@ -220,10 +224,9 @@ func BuildGitHubReview(findings []review.Finding, diffFiles map[string]bool) Rev
 	// Build summary body
 	var sb strings.Builder
 	sb.WriteString("## Prism Code Review\n\n")
-	sb.WriteString(fmt.Sprintf("| Severity | Count |\n|----------|-------|\n"))
-	sb.WriteString(fmt.Sprintf("| High | %d |\n", high))
-	sb.WriteString(fmt.Sprintf("| Medium | %d |\n", medium))
-	sb.WriteString(fmt.Sprintf("| Low | %d |\n\n", low))
+	sb.WriteString("| Severity | Count |\n|----------|-------|\n")
+	sb.WriteString(severityRows(review.ComputeSummary(findings).Counts))
+	sb.WriteString("\n")
 
 	if len(bodyComments) > 0 {
 		sb.WriteString("### General Findings\n\n")
@@ -233,6 +236,12 @@ func BuildGitHubReview(findings []review.Finding, diffFiles map[string]bool) Rev
 		}
 	}
 
+	if len(opts.Owners) > 0 {
+		sb.WriteString("### Code Owners\n\n")
+		sb.WriteString(formatOwners(opts.Owners))
+		sb.WriteString("\n")
+	}
+
 	return ReviewRequest{
 		Body:     sb.String(),
 		Event:    "COMMENT",
@@ -240,6 +249,30 @@ func BuildGitHubReview(findings []review.Finding, diffFiles map[string]bool) Rev
 	}
 }
 
+// formatOwners lists each owner with the changed files they own, sorted
+// by owner.
+func formatOwners(owners map[string][]string) string {
+	byOwner := make(map[string][]string)
+	for path, list := range owners {
+		for _, o := range list {
+			byOwner[o] = append(byOwner[o], path)
+		}
+	}
+	names := make([]string, 0, len(byOwner))
+	for o := range byOwner {
+		names = append(names, o)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, o := range names {
+		paths := byOwner[o]
+		sort.Strings(paths)
+		sb.WriteString(fmt.Sprintf("- %s: `%s`\n", o, strings.Join(paths, "`, `")))
+	}
+	return sb.String()
+}
+
 func formatInlineComment(f review.Finding) string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("**%s** (%s, %s, confidence: %.0f%%)\n\n", f.Title, f.Severity, f.Category, f.Confidence*100))
@@ -287,3 +320,12 @@ func ParseRemoteURL(url string) (owner, repo string, err error) {
 	}
 	return "", "", fmt.Errorf("cannot parse owner/repo from remote URL: %s", url)
 }
+
+// severityRows renders the summary table rows for the shown severities.
+func severityRows(c review.SeverityCounts) string {
+	var b strings.Builder
+	for _, s := range c.Shown() {
+		fmt.Fprintf(&b, "| %s | %d |\n", strings.ToUpper(string(s[:1]))+string(s[1:]), c.Count(s))
+	}
+	return b.String()
+}
@@ -266,3 +266,25 @@ func TestBuildGitHubReview(t *testing.T) {
 		t.Errorf("Summary should mention severity counts, got: %s", rev.Body)
 	}
 }
+
+func TestBuildGitHubReviewWithOptions_Owners(t *testing.T) {
+	owners := map[string][]string{
+		"api/handler.go": {"@org/backend", "@alice"},
+		"api/routes.go":  {"@org/backend"},
+	}
+	rev := BuildGitHubReviewWithOptions(nil, nil, ReviewOptions{Owners: owners})
+
+	if !strings.Contains(rev.Body, "### Code Owners") {
+		t.Fatalf("expected code owners section, got:\n%s", rev.Body)
+	}
+	if !strings.Contains(rev.Body, "- @alice: `api/handler.go`\n") {
+		t.Errorf("expected @alice line, got:\n%s", rev.Body)
+	}
+	if !strings.Contains(rev.Body, "- @org/backend: `api/handler.go`, `api/routes.go`\n") {
+		t.Errorf("expected @org/backend line with sorted paths, got:\n%s", rev.Body)
+	}
+
+	if strings.Contains(BuildGitHubReview(nil, nil).Body, "Code Owners") {
+		t.Error("owners section should be omitted without owners")
+	}
+}
@@ -0,0 +1,8 @@
+// Package gitlab provides a minimal GitLab REST API client for posting prism
+// findings as merge-request discussion comments.
+//
+// It detects the current project from CI_PROJECT_PATH or the local git
+// remote and authenticates with the GITLAB_TOKEN environment variable.
+// Findings on changed lines are posted as inline diff discussions anchored
+// to the merge request's diff refs; the rest go into a summary note.
+package gitlab
@@ -0,0 +1,447 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+const defaultAPIURL = "https://gitlab.com/api/v4"
+
+// Client provides access to the GitLab REST API.
+type Client struct {
+	token   string
+	apiURL  string
+	httpCli *http.Client
+}
+
+// NewClient creates a new GitLab client. Requires GITLAB_TOKEN env var.
+// The API URL comes from GITLAB_API_URL, then CI_API_V4_URL (set in GitLab
+// CI jobs), then gitlab.com.
+func NewClient() (*Client, error) {
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("GITLAB_TOKEN environment variable is not set")
+	}
+
+	return &Client{
+		token:   token,
+		apiURL:  apiURLFromEnv(),
+		httpCli: &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// apiURLFromEnv returns the API URL from GITLAB_API_URL, then
+// CI_API_V4_URL, then gitlab.com, without a trailing slash.
+func apiURLFromEnv() string {
+	apiURL := os.Getenv("GITLAB_API_URL")
+	if apiURL == "" {
+		apiURL = os.Getenv("CI_API_V4_URL")
+	}
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+	return strings.TrimRight(apiURL, "/")
+}
+
+// DiffRefs identifies the merge request version that inline comments are
+// anchored to.
+type DiffRefs struct {
+	BaseSHA  string `json:"base_sha"`
+	HeadSHA  string `json:"head_sha"`
+	StartSHA string `json:"start_sha"`
+}
+
+// mrChange is one file in a merge request changes response.
+type mrChange struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	Diff        string `json:"diff"`
+	NewFile     bool   `json:"new_file"`
+	DeletedFile bool   `json:"deleted_file"`
+}
+
+// MergeRequest holds a merge request's diff and the metadata needed to
+// comment on it.
+type MergeRequest struct {
+	// Diff is the merge request's changes as one unified diff.
+	Diff string
+	// Files lists the new-side paths of the changed files.
+	Files []string
+	// OldPaths maps each changed file's new path to its path before the
+	// change, which differs for renames.
+	OldPaths map[string]string
+	Refs     DiffRefs
+}
+
+// projectURL returns the API URL for project, which may be a numeric ID or
+// a namespaced path such as "group/subgroup/project".
+func (c *Client) projectURL(project string) string {
+	return fmt.Sprintf("%s/projects/%s", c.apiURL, url.PathEscape(project))
+}
+
+// GetMergeRequest fetches the diff and diff refs for a merge request.
+func (c *Client) GetMergeRequest(ctx context.Context, project string, iid int) (*MergeRequest, error) {
+	url := fmt.Sprintf("%s/merge_requests/%d/changes", c.projectURL(project), iid)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+
+	resp, err := c.httpCli.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching MR changes: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode == 404 {
+		return nil, fmt.Errorf("MR !%d not found in %s", iid, project)
+	}
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		return nil, fmt.Errorf("authentication failed: %s", string(body))
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("GitLab API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var parsed struct {
+		Changes  []mrChange `json:"changes"`
+		DiffRefs DiffRefs   `json:"diff_refs"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	mr := &MergeRequest{
+		OldPaths: make(map[string]string, len(parsed.Changes)),
+		Refs:     parsed.DiffRefs,
+	}
+	var sb strings.Builder
+	for _, ch := range parsed.Changes {
+		sb.WriteString(unifiedSection(ch))
+		mr.Files = append(mr.Files, ch.NewPath)
+		mr.OldPaths[ch.NewPath] = ch.OldPath
+	}
+	mr.Diff = sb.String()
+	return mr, nil
+}
+
+// unifiedSection renders one changed file as a git-style diff section. The
+// API returns only the hunks, so the file headers are rebuilt here.
+func unifiedSection(ch mrChange) string {
+	oldName, newName := "a/"+ch.OldPath, "b/"+ch.NewPath
+	if ch.NewFile {
+		oldName = "/dev/null"
+	}
+	if ch.DeletedFile {
+		newName = "/dev/null"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "diff --git a/%s b/%s\n", ch.OldPath, ch.NewPath)
+	if ch.Diff != "" {
+		fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+		sb.WriteString(ch.Diff)
+		if !strings.HasSuffix(ch.Diff, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// InlineComment is a finding to post on a changed line of the merge request.
+type InlineComment struct {
+	Path string
+	Line int
+	Body string
+}
+
+// Review is the set of comments to post on a merge request.
+type Review struct {
+	// Summary is posted as a general merge request note.
+	Summary  string
+	Comments []InlineComment
+}
+
+// position anchors a discussion to a line of the merge request diff.
+type position struct {
+	PositionType string `json:"position_type"`
+	BaseSHA      string `json:"base_sha"`
+	HeadSHA      string `json:"head_sha"`
+	StartSHA     string `json:"start_sha"`
+	OldPath      string `json:"old_path"`
+	NewPath      string `json:"new_path"`
+	NewLine      int    `json:"new_line"`
+}
+
+// PostReview posts the summary as a merge request note and each inline
+// comment as a diff discussion on mr. GitLab rejects discussions on lines
+// outside the diff, so such a comment is posted as a general note naming
+// its location instead.
+func (c *Client) PostReview(ctx context.Context, project string, iid int, mr *MergeRequest, rv Review) error {
+	base := fmt.Sprintf("%s/merge_requests/%d", c.projectURL(project), iid)
+
+	if err := c.post(ctx, base+"/notes", map[string]any{"body": rv.Summary}); err != nil {
+		return fmt.Errorf("posting summary note: %w", err)
+	}
+
+	for _, cm := range rv.Comments {
+		oldPath := mr.OldPaths[cm.Path]
+		if oldPath == "" {
+			oldPath = cm.Path
+		}
+		err := c.post(ctx, base+"/discussions", map[string]any{
+			"body": cm.Body,
+			"position": position{
+				PositionType: "text",
+				BaseSHA:      mr.Refs.BaseSHA,
+				HeadSHA:      mr.Refs.HeadSHA,
+				StartSHA:     mr.Refs.StartSHA,
+				OldPath:      oldPath,
+				NewPath:      cm.Path,
+				NewLine:      cm.Line,
+			},
+		})
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.status == 400 {
+			err = c.post(ctx, base+"/notes", map[string]any{
+				"body": fmt.Sprintf("`%s:%d`\n\n%s", cm.Path, cm.Line, cm.Body),
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("posting comment on %s:%d: %w", cm.Path, cm.Line, err)
+		}
+	}
+	return nil
+}
+
+// apiError is a non-2xx response from the GitLab API.
+type apiError struct {
+	status int
+	body   string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("GitLab API error (status %d): %s", e.status, e.body)
+}
+
+// post sends payload as JSON to url and checks for a 2xx response.
+func (c *Client) post(ctx context.Context, url string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpCli.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &apiError{status: resp.StatusCode, body: string(body)}
+	}
+	return nil
+}
+
+// ReviewOptions controls optional sections of the summary note.
+type ReviewOptions struct {
+	// Owners maps changed files to their code owners; when set, the
+	// summary lists the owners who should review.
+	Owners map[string][]string
+}
+
+// BuildGitLabReview converts review findings into merge request comments.
+// diffFiles is the set of files in the MR diff. Findings for files not in
+// the diff, or without a line, are included in the summary note only.
+func BuildGitLabReview(findings []review.Finding, diffFiles map[string]bool) Review {
+	return BuildGitLabReviewWithOptions(findings, diffFiles, ReviewOptions{})
+}
+
+// BuildGitLabReviewWithOptions is BuildGitLabReview with optional summary
+// sections configured by opts.
+func BuildGitLabReviewWithOptions(findings []review.Finding, diffFiles map[string]bool, opts ReviewOptions) Review {
+	var bodyComments []string
+	var comments []InlineComment
+
+	for _, f := range findings {
+		if len(f.Locations) > 0 && f.Locations[0].Path != "" && diffFiles[f.Locations[0].Path] {
+			loc := f.Locations[0]
+			line := loc.Lines.End
+			if line == 0 {
+				line = loc.Lines.Start
+			}
+			if line > 0 {
+				comments = append(comments, InlineComment{
+					Path: loc.Path,
+					Line: line,
+					Body: formatInlineComment(f),
+				})
+				continue
+			}
+		}
+		bodyComments = append(bodyComments, formatFindingBody(f))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Prism Code Review\n\n")
+	sb.WriteString("| Severity | Count |\n|----------|-------|\n")
+	sb.WriteString(severityRows(review.ComputeSummary(findings).Counts))
+	sb.WriteString("\n")
+
+	if len(bodyComments) > 0 {
+		sb.WriteString("### General Findings\n\n")
+		for _, c := range bodyComments {
+			sb.WriteString(c)
+			sb.WriteString("\n\n")
+		}
+	}
+
+	if len(opts.Owners) > 0 {
+		sb.WriteString("### Code Owners\n\n")
+		sb.WriteString(formatOwners(opts.Owners))
+		sb.WriteString("\n")
+	}
+
+	return Review{
+		Summary:  sb.String(),
+		Comments: comments,
+	}
+}
+
+// formatOwners lists each owner with the changed files they own, sorted
+// by owner.
+func formatOwners(owners map[string][]string) string {
+	byOwner := make(map[string][]string)
+	for path, list := range owners {
+		for _, o := range list {
+			byOwner[o] = append(byOwner[o], path)
+		}
+	}
+	names := make([]string, 0, len(byOwner))
+	for o := range byOwner {
+		names = append(names, o)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, o := range names {
+		paths := byOwner[o]
+		sort.Strings(paths)
+		sb.WriteString(fmt.Sprintf("- %s: `%s`\n", o, strings.Join(paths, "`, `")))
+	}
+	return sb.String()
+}
+
+func formatInlineComment(f review.Finding) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**%s** (%s, %s, confidence: %.0f%%)\n\n", f.Title, f.Severity, f.Category, f.Confidence*100))
+	sb.WriteString(f.Message)
+	if f.Suggestion != "" {
+		sb.WriteString(fmt.Sprintf("\n\n**Suggestion:**\n```\n%s\n```", f.Suggestion))
+	}
+	return sb.String()
+}
+
+func formatFindingBody(f review.Finding) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("- **%s** (%s, %s): %s", f.Title, f.Severity, f.Category, f.Message))
+	if f.Suggestion != "" {
+		sb.WriteString(fmt.Sprintf(" — *Suggestion: %s*", f.Suggestion))
+	}
+	return sb.String()
+}
+
+var (
+	httpsRemoteRe  = regexp.MustCompile(`^https?://[^/]+/(.+)$`)
+	sshURLRemoteRe = regexp.MustCompile(`^ssh://[^/]+/(.+)$`)
+	scpRemoteRe    = regexp.MustCompile(`^[^@/]+@[^:]+:(.+)$`)
+)
+
+// DetectProject returns the project path, from CI_PROJECT_PATH in GitLab CI
+// or else the git remote origin URL, read against the API URL NewClient
+// uses.
+func DetectProject() (string, error) {
+	if p := os.Getenv("CI_PROJECT_PATH"); p != "" {
+		return p, nil
+	}
+	out, err := exec.Command("git", "remote", "get-url", "origin").Output()
+	if err != nil {
+		return "", fmt.Errorf("cannot detect project: git remote get-url origin failed: %w", err)
+	}
+	return ParseRemoteURL(strings.TrimSpace(string(out)), apiURLFromEnv())
+}
+
+// ParseRemoteURL extracts the namespaced project path, such as
+// "group/subgroup/project", from a git remote URL. apiURL is the instance's
+// API URL; for an instance served under a relative path, such as
+// https://host/gitlab/api/v4, that path ("/gitlab") is removed from HTTP(S)
+// remotes.
+func ParseRemoteURL(remote, apiURL string) (string, error) {
+	remote = strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+
+	if m := httpsRemoteRe.FindStringSubmatch(remote); len(m) == 2 {
+		if root := relativeRoot(apiURL); root != "" {
+			m[1] = strings.TrimPrefix(m[1], root+"/")
+		}
+		if strings.Contains(m[1], "/") {
+			return m[1], nil
+		}
+	}
+	for _, re := range []*regexp.Regexp{sshURLRemoteRe, scpRemoteRe} {
+		if m := re.FindStringSubmatch(remote); len(m) == 2 && strings.Contains(m[1], "/") {
+			return strings.TrimPrefix(m[1], "/"), nil
+		}
+	}
+	return "", fmt.Errorf("cannot parse project path from remote URL: %s", remote)
+}
+
+// relativeRoot returns the relative path an instance is served under, taken
+// from its API URL without the leading slash and "/api/v4", or "" for an
+// instance at the root of its host.
+func relativeRoot(apiURL string) string {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return ""
+	}
+	return strings.Trim(strings.TrimSuffix(strings.TrimRight(u.Path, "/"), "/api/v4"), "/")
+}
+
+// severityRows renders the summary table rows for the shown severities.
+func severityRows(c review.SeverityCounts) string {
+	var b strings.Builder
+	for _, s := range c.Shown() {
+		fmt.Fprintf(&b, "| %s | %d |\n", strings.ToUpper(string(s[:1]))+string(s[1:]), c.Count(s))
+	}
+	return b.String()
+}
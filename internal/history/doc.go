@@ -0,0 +1,13 @@
+// Package history records review reports in a local JSON-lines file so
+// finding counts can be tracked across runs.
+//
+// Each line of the history file is one Record: the run ID, time, repository
+// position (branch and HEAD commit), the review mode, the severity counts,
+// and a compact entry per finding. Findings are matched between runs by
+// their line-independent StableKey, so Compare can tell which findings were
+// introduced, fixed, or carried over.
+//
+// The default history file is $XDG_DATA_HOME/prism/history.jsonl (or the
+// OS-appropriate equivalent). Only finding metadata is stored, never the
+// reviewed code.
+package history
@@ -0,0 +1,219 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+// Record is one review run stored in the history file.
+type Record struct {
+	RunID    string                `json:"runId"`
+	Time     time.Time             `json:"time"`
+	Repo     string                `json:"repo,omitempty"`
+	Branch   string                `json:"branch,omitempty"`
+	Commit   string                `json:"commit,omitempty"`
+	Mode     string                `json:"mode"`
+	Range    string                `json:"range,omitempty"`
+	Counts   review.SeverityCounts `json:"counts"`
+	Verdict  string                `json:"verdict,omitempty"`
+	Findings []Entry               `json:"findings"`
+}
+
+// Entry is the stored form of one finding.
+type Entry struct {
+	Key      string `json:"key"`
+	Severity string `json:"severity"`
+	Category string `json:"category"`
+	Title    string `json:"title"`
+	Path     string `json:"path,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// Total returns the number of findings in the run.
+func (r Record) Total() int {
+	return r.Counts.Total()
+}
+
+// FromReport builds the record of report, stamped with the current time.
+func FromReport(report *review.Report) Record {
+	rec := Record{
+		RunID:    report.RunID,
+		Time:     time.Now().UTC(),
+		Repo:     report.Repo.Root,
+		Branch:   report.Repo.Branch,
+		Commit:   report.Repo.Head,
+		Mode:     report.Inputs.Mode,
+		Range:    report.Inputs.Range,
+		Counts:   report.Summary.Counts,
+		Verdict:  report.Summary.Verdict,
+		Findings: make([]Entry, 0, len(report.Findings)),
+	}
+	for _, f := range report.Findings {
+		e := Entry{
+			Key:      f.StableKey,
+			Severity: string(f.Severity),
+			Category: string(f.Category),
+			Title:    f.Title,
+		}
+		if len(f.Locations) > 0 {
+			e.Path = f.Locations[0].Path
+			e.Line = f.Locations[0].Lines.Start
+		}
+		if e.Key == "" {
+			// Reports edited by a post-hook may drop the key
+			e.Key = e.Path + ":" + e.Category + ":" + strings.ToLower(e.Title)
+		}
+		rec.Findings = append(rec.Findings, e)
+	}
+	return rec
+}
+
+// DefaultPath returns the platform-appropriate default history file.
+func DefaultPath() (string, error) {
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+		return filepath.Join(xdg, "prism", "history.jsonl"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(home, "Library", "Application Support", "prism", "history.jsonl"), nil
+	case "windows":
+		if localAppData := os.Getenv("LOCALAPPDATA"); localAppData != "" {
+			return filepath.Join(localAppData, "prism", "history.jsonl"), nil
+		}
+		return filepath.Join(home, "AppData", "Local", "prism", "history.jsonl"), nil
+	default:
+		return filepath.Join(home, ".local", "share", "prism", "history.jsonl"), nil
+	}
+}
+
+// Append adds rec as one line at the end of the history file at path,
+// creating the file and its directory if needed.
+func Append(path string, rec Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encoding history record: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating history directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening history file: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("writing history file: %w", err)
+	}
+	return f.Close()
+}
+
+// Load reads every record in the history file at path, oldest first. A
+// missing file has no records.
+func Load(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading history file: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid history record: %w", path, n, err)
+		}
+		records = append(records, rec)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading history file: %w", err)
+	}
+	return records, nil
+}
+
+// Find returns the record whose run ID is ref or starts with ref. "latest"
+// names the newest record and "latest~N" the one N runs before it.
+func Find(records []Record, ref string) (Record, error) {
+	if rest, ok := strings.CutPrefix(ref, "latest"); ok {
+		back := 0
+		if rest != "" {
+			if _, err := fmt.Sscanf(rest, "~%d", &back); err != nil || back < 0 || fmt.Sprintf("~%d", back) != rest {
+				return Record{}, fmt.Errorf("invalid run reference %q (want latest or latest~N)", ref)
+			}
+		}
+		if back >= len(records) {
+			return Record{}, fmt.Errorf("run %q not found: history has %d run(s)", ref, len(records))
+		}
+		return records[len(records)-1-back], nil
+	}
+
+	var match *Record
+	for i := range records {
+		if !strings.HasPrefix(records[i].RunID, ref) || ref == "" {
+			continue
+		}
+		if match != nil && match.RunID != records[i].RunID {
+			return Record{}, fmt.Errorf("run ID prefix %q is ambiguous", ref)
+		}
+		match = &records[i]
+	}
+	if match == nil {
+		return Record{}, fmt.Errorf("run %q not found in history", ref)
+	}
+	return *match, nil
+}
+
+// Comparison is the difference between two runs.
+type Comparison struct {
+	// New holds findings in the later run that the earlier one lacked.
+	New []Entry
+	// Fixed holds findings in the earlier run that the later one lacks.
+	Fixed []Entry
+	// Persisting holds findings present in both runs, as the later run
+	// reports them.
+	Persisting []Entry
+}
+
+// Compare matches the findings of from and to by key.
+func Compare(from, to Record) Comparison {
+	before := make(map[string]bool, len(from.Findings))
+	for _, e := range from.Findings {
+		before[e.Key] = true
+	}
+	after := make(map[string]bool, len(to.Findings))
+	var c Comparison
+	for _, e := range to.Findings {
+		after[e.Key] = true
+		if before[e.Key] {
+			c.Persisting = append(c.Persisting, e)
+		} else {
+			c.New = append(c.New, e)
+		}
+	}
+	for _, e := range from.Findings {
+		if !after[e.Key] {
+			c.Fixed = append(c.Fixed, e)
+		}
+	}
+	return c
+}
@@ -0,0 +1,121 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+func testReport(runID string, keys ...string) *review.Report {
+	report := &review.Report{
+		RunID:  runID,
+		Repo:   review.RepoInfo{Root: "/repo", Branch: "main", Head: "abc123"},
+		Inputs: review.InputInfo{Mode: "staged"},
+	}
+	for _, k := range keys {
+		report.Findings = append(report.Findings, review.Finding{
+			Severity:  review.SeverityMedium,
+			Category:  review.CategoryBug,
+			Title:     "Finding " + k,
+			StableKey: k,
+			Locations: []review.Location{{Path: "main.go", Lines: review.LineRange{Start: 4, End: 6}}},
+		})
+	}
+	report.Summary = review.ComputeSummary(report.Findings)
+	return report
+}
+
+func TestAppendLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.jsonl")
+
+	records, err := Load(path)
+	if err != nil || records != nil {
+		t.Fatalf("Load(missing) = %v, %v; want no records", records, err)
+	}
+
+	for _, report := range []*review.Report{testReport("run1", "a"), testReport("run2", "a", "b")} {
+		if err := Append(path, FromReport(report)); err != nil {
+			t.Fatalf("Append error: %v", err)
+		}
+	}
+	records, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if len(records) != 2 || records[0].RunID != "run1" || records[1].RunID != "run2" {
+		t.Fatalf("records = %+v, want run1 then run2", records)
+	}
+	rec := records[1]
+	if rec.Commit != "abc123" || rec.Branch != "main" || rec.Mode != "staged" || rec.Counts.Medium != 2 || rec.Total() != 2 {
+		t.Errorf("record = %+v", rec)
+	}
+	if rec.Findings[1] != (Entry{Key: "b", Severity: "medium", Category: "bug", Title: "Finding b", Path: "main.go", Line: 4}) {
+		t.Errorf("entry = %+v", rec.Findings[1])
+	}
+}
+
+func TestLoad_Malformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	os.WriteFile(path, []byte(`{"runId":"run1"}`+"\n\nnot json\n"), 0o644)
+
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), "history.jsonl:3") {
+		t.Errorf("err = %v, want an error naming line 3", err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	records := []Record{{RunID: "aa11"}, {RunID: "ab22"}, {RunID: "bb33"}}
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{"latest", "bb33", false},
+		{"latest~2", "aa11", false},
+		{"latest~3", "", true},
+		{"latest~x", "", true},
+		{"ab", "ab22", false},
+		{"bb33", "bb33", false},
+		{"a", "", true},
+		{"cc", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := Find(records, tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Find(%q) = %q, want error", tt.ref, got.RunID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Find(%q) error: %v", tt.ref, err)
+			}
+			if got.RunID != tt.want {
+				t.Errorf("Find(%q) = %q, want %q", tt.ref, got.RunID, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	from := FromReport(testReport("run1", "a", "b"))
+	to := FromReport(testReport("run2", "b", "c"))
+
+	c := Compare(from, to)
+	if len(c.New) != 1 || c.New[0].Key != "c" {
+		t.Errorf("New = %+v, want c", c.New)
+	}
+	if len(c.Fixed) != 1 || c.Fixed[0].Key != "a" {
+		t.Errorf("Fixed = %+v, want a", c.Fixed)
+	}
+	if len(c.Persisting) != 1 || c.Persisting[0].Key != "b" {
+		t.Errorf("Persisting = %+v, want b", c.Persisting)
+	}
+}
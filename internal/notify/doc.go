@@ -0,0 +1,7 @@
+// Package notify posts a short summary of a prism review to chat services
+// after the review completes.
+//
+// The summary holds the verdict, the counts by severity, the most severe
+// findings, and a link to the CI run when one is detected from the
+// environment. Slack incoming webhooks are the only target.
+package notify
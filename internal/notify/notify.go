@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TargetSlack posts to a Slack incoming webhook.
+const TargetSlack = "slack"
+
+// ValidateTargets checks that every --notify target is known.
+func ValidateTargets(targets []string) error {
+	for _, t := range targets {
+		if t != TargetSlack {
+			return fmt.Errorf("unknown --notify target %q (want %s)", t, TargetSlack)
+		}
+	}
+	return nil
+}
+
+// CIRunURL returns the web URL of the current CI run, detected from the
+// environment of GitHub Actions, GitLab CI, CircleCI, Buildkite, or
+// Jenkins. It returns "" outside CI.
+func CIRunURL() string {
+	if id := os.Getenv("GITHUB_RUN_ID"); id != "" && os.Getenv("GITHUB_REPOSITORY") != "" {
+		server := os.Getenv("GITHUB_SERVER_URL")
+		if server == "" {
+			server = "https://github.com"
+		}
+		return fmt.Sprintf("%s/%s/actions/runs/%s", strings.TrimRight(server, "/"), os.Getenv("GITHUB_REPOSITORY"), id)
+	}
+	for _, env := range []string{"CI_JOB_URL", "CI_PIPELINE_URL", "CIRCLE_BUILD_URL", "BUILDKITE_BUILD_URL", "BUILD_URL"} {
+		if v := os.Getenv(env); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,32 @@
+package notify
+
+import "testing"
+
+func TestValidateTargets(t *testing.T) {
+	if err := ValidateTargets([]string{"slack"}); err != nil {
+		t.Errorf("slack: %v", err)
+	}
+	if err := ValidateTargets([]string{"teams"}); err == nil {
+		t.Error("expected an error for an unknown target")
+	}
+}
+
+func TestCIRunURL(t *testing.T) {
+	for _, env := range []string{"GITHUB_RUN_ID", "GITHUB_REPOSITORY", "GITHUB_SERVER_URL", "CI_JOB_URL", "CI_PIPELINE_URL", "CIRCLE_BUILD_URL", "BUILDKITE_BUILD_URL", "BUILD_URL"} {
+		t.Setenv(env, "")
+	}
+	if got := CIRunURL(); got != "" {
+		t.Errorf("outside CI: CIRunURL() = %q, want empty", got)
+	}
+
+	t.Setenv("CI_JOB_URL", "https://gitlab.example.com/g/p/-/jobs/9")
+	if got := CIRunURL(); got != "https://gitlab.example.com/g/p/-/jobs/9" {
+		t.Errorf("GitLab: CIRunURL() = %q", got)
+	}
+
+	t.Setenv("GITHUB_RUN_ID", "42")
+	t.Setenv("GITHUB_REPOSITORY", "octo/app")
+	if got := CIRunURL(); got != "https://github.com/octo/app/actions/runs/42" {
+		t.Errorf("GitHub: CIRunURL() = %q", got)
+	}
+}
@@ -0,0 +1,185 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+// topFindings is the number of findings listed in a notification.
+const topFindings = 5
+
+// maxTitleLen bounds each listed finding title so the message stays within
+// Slack's block text limit.
+const maxTitleLen = 150
+
+// Slack posts review summaries to a Slack incoming webhook.
+type Slack struct {
+	webhookURL string
+	httpCli    *http.Client
+}
+
+// NewSlack creates a sender for the given incoming webhook URL.
+func NewSlack(webhookURL string) (*Slack, error) {
+	if webhookURL == "" {
+		return nil, fmt.Errorf("no Slack webhook URL; set PRISM_SLACK_WEBHOOK_URL or notify.slackWebhookUrl in the config file")
+	}
+	return &Slack{
+		webhookURL: webhookURL,
+		httpCli:    &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// SlackMessage is the webhook payload. Text is the fallback shown in
+// notifications; Blocks is the rendered message.
+type SlackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []SlackBlock `json:"blocks"`
+}
+
+// SlackBlock is one Block Kit block.
+type SlackBlock struct {
+	Type     string      `json:"type"`
+	Text     *SlackText  `json:"text,omitempty"`
+	Elements []SlackText `json:"elements,omitempty"`
+}
+
+// SlackText is a Block Kit text object.
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Send posts the summary of report, linking to runURL if it is set.
+func (s *Slack) Send(ctx context.Context, report *review.Report, runURL string) error {
+	data, err := json.Marshal(BuildSlackMessage(report, runURL))
+	if err != nil {
+		return fmt.Errorf("marshaling Slack message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.webhookURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpCli.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting to Slack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("slack webhook error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// BuildSlackMessage renders report as a header, the verdict and counts by
+// severity, the most severe findings, and a context line with the
+// repository and the CI run link.
+func BuildSlackMessage(report *review.Report, runURL string) SlackMessage {
+	summary := review.SummaryLine(report)
+	if report.Summary.Verdict != "" {
+		summary += " (" + strings.ToUpper(report.Summary.Verdict) + ")"
+	}
+	msg := SlackMessage{Text: summary}
+	msg.Blocks = append(msg.Blocks, SlackBlock{
+		Type: "header",
+		Text: &SlackText{Type: "plain_text", Text: "Prism Code Review"},
+	})
+
+	var head strings.Builder
+	if report.Summary.Verdict != "" {
+		fmt.Fprintf(&head, "*Verdict: %s*\n", strings.ToUpper(report.Summary.Verdict))
+	}
+	head.WriteString(escape(report.Summary.Counts.Breakdown()))
+	msg.Blocks = append(msg.Blocks, mrkdwnSection(head.String()))
+
+	if top := mostSevere(report.Findings, topFindings); len(top) > 0 {
+		var b strings.Builder
+		for _, f := range top {
+			fmt.Fprintf(&b, "• *%s* `%s` %s\n", strings.ToUpper(string(f.Severity)), location(f), escape(truncate(f.Title, maxTitleLen)))
+		}
+		if more := len(report.Findings) - len(top); more > 0 {
+			fmt.Fprintf(&b, "_and %d more_\n", more)
+		}
+		msg.Blocks = append(msg.Blocks, mrkdwnSection(strings.TrimSuffix(b.String(), "\n")))
+	}
+
+	var footer []string
+	if report.Repo.Root != "" {
+		repo := filepath.Base(report.Repo.Root)
+		if report.Repo.Branch != "" {
+			repo += "@" + report.Repo.Branch
+		}
+		footer = append(footer, escape(repo))
+	}
+	if report.Inputs.Mode != "" {
+		footer = append(footer, escape(report.Inputs.Mode)+" review")
+	}
+	if runURL != "" {
+		footer = append(footer, fmt.Sprintf("<%s|View CI run>", runURL))
+	}
+	if len(footer) > 0 {
+		msg.Blocks = append(msg.Blocks, SlackBlock{
+			Type:     "context",
+			Elements: []SlackText{{Type: "mrkdwn", Text: strings.Join(footer, " · ")}},
+		})
+	}
+	return msg
+}
+
+func mrkdwnSection(text string) SlackBlock {
+	return SlackBlock{Type: "section", Text: &SlackText{Type: "mrkdwn", Text: text}}
+}
+
+// mostSevere returns up to n findings, most severe first, keeping the
+// report order among findings of the same severity.
+func mostSevere(findings []review.Finding, n int) []review.Finding {
+	sorted := append([]review.Finding(nil), findings...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return review.SeverityRank(sorted[i].Severity) > review.SeverityRank(sorted[j].Severity)
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// location formats a finding's primary location as path:line, or just the
+// path for file-level findings.
+func location(f review.Finding) string {
+	if len(f.Locations) == 0 {
+		return "unknown"
+	}
+	loc := f.Locations[0]
+	if loc.Lines.Start == 0 {
+		return loc.Path
+	}
+	return fmt.Sprintf("%s:%d", loc.Path, loc.Lines.Start)
+}
+
+// escape replaces the characters Slack treats as control sequences in
+// mrkdwn text.
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(r[:n])) + "…"
+}
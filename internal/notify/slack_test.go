@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+func testReport() *review.Report {
+	findings := []review.Finding{
+		{Severity: review.SeverityLow, Title: "Unused variable",
+			Locations: []review.Location{{Path: "util.go", Lines: review.LineRange{Start: 4, End: 4}}}},
+		{Severity: review.SeverityCritical, Title: "SQL built from <user> input",
+			Locations: []review.Location{{Path: "db.go", Lines: review.LineRange{Start: 12, End: 14}}}},
+		{Severity: review.SeverityHigh, Title: "Missing error check",
+			Locations: []review.Location{{Path: "main.go"}}},
+	}
+	return &review.Report{
+		Repo:     review.RepoInfo{Root: "/src/app", Branch: "main"},
+		Inputs:   review.InputInfo{Mode: "staged"},
+		Stats:    review.DiffStats{FilesChanged: 3},
+		Findings: findings,
+		Summary:  review.ComputeSummary(findings),
+	}
+}
+
+func TestBuildSlackMessage(t *testing.T) {
+	msg := BuildSlackMessage(testReport(), "https://ci.example.com/runs/7")
+
+	if msg.Text != "prism: 1 critical, 1 high, 0 medium, 1 low in 3 files (BLOCK)" {
+		t.Errorf("Text = %q", msg.Text)
+	}
+	if len(msg.Blocks) != 4 {
+		t.Fatalf("Blocks = %d, want header, summary, findings, context", len(msg.Blocks))
+	}
+	if head := msg.Blocks[1].Text.Text; !strings.Contains(head, "*Verdict: BLOCK*") || !strings.Contains(head, "1 critical, 1 high") {
+		t.Errorf("summary block = %q", head)
+	}
+	list := msg.Blocks[2].Text.Text
+	lines := strings.Split(list, "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], "*CRITICAL* `db.go:12` SQL built from &lt;user&gt; input") {
+		t.Errorf("findings block = %q, want critical first with escaped title", list)
+	}
+	if !strings.Contains(lines[1], "`main.go` Missing error check") {
+		t.Errorf("file-level finding = %q, want the bare path", lines[1])
+	}
+	if ctx := msg.Blocks[3].Elements[0].Text; ctx != "app@main · staged review · <https://ci.example.com/runs/7|View CI run>" {
+		t.Errorf("context = %q", ctx)
+	}
+}
+
+func TestBuildSlackMessage_NoFindings(t *testing.T) {
+	report := &review.Report{Summary: review.ComputeSummary(nil), Findings: []review.Finding{}}
+	msg := BuildSlackMessage(report, "")
+	if len(msg.Blocks) != 2 {
+		t.Errorf("Blocks = %+v, want only the header and summary", msg.Blocks)
+	}
+}
+
+func TestBuildSlackMessage_TopFindings(t *testing.T) {
+	var findings []review.Finding
+	for i := 0; i < 8; i++ {
+		findings = append(findings, review.Finding{Severity: review.SeverityMedium, Title: "m"})
+	}
+	report := &review.Report{Findings: findings, Summary: review.ComputeSummary(findings)}
+	list := BuildSlackMessage(report, "").Blocks[2].Text.Text
+	if strings.Count(list, "•") != topFindings || !strings.HasSuffix(list, "_and 3 more_") {
+		t.Errorf("findings block = %q, want %d findings and a remainder line", list, topFindings)
+	}
+}
+
+func TestSlackSend(t *testing.T) {
+	var got SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request = %s %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	s, err := NewSlack(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(context.Background(), testReport(), ""); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if got.Text == "" || len(got.Blocks) == 0 {
+		t.Errorf("posted message = %+v", got)
+	}
+}
+
+func TestSlackSend_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		w.Write([]byte("no_service"))
+	}))
+	defer server.Close()
+
+	s, _ := NewSlack(server.URL)
+	err := s.Send(context.Background(), testReport(), "")
+	if err == nil || !strings.Contains(err.Error(), "no_service") {
+		t.Errorf("err = %v, want the webhook's error", err)
+	}
+}
+
+func TestNewSlack_NoURL(t *testing.T) {
+	if _, err := NewSlack(""); err == nil || !strings.Contains(err.Error(), "PRISM_SLACK_WEBHOOK_URL") {
+		t.Errorf("err = %v, want a hint naming PRISM_SLACK_WEBHOOK_URL", err)
+	}
+}
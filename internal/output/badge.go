@@ -0,0 +1,102 @@
+package output
+
+import (
+	"encoding/json"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+const badgeLabel = "prism"
+
+// BadgeWriter renders a small status badge from a report's summary, either as
+// a flat SVG or as a shields.io endpoint JSON document.
+type BadgeWriter struct {
+	JSON bool // true = shields.io endpoint JSON instead of SVG
+}
+
+// shieldsEndpoint is the shields.io endpoint badge schema.
+type shieldsEndpoint struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+func (b *BadgeWriter) Write(w io.Writer, report *review.Report) error {
+	message := badgeMessage(report.Summary)
+	color := badgeColor(report.Summary.HighestSeverity)
+
+	if b.JSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(shieldsEndpoint{
+			SchemaVersion: 1,
+			Label:         badgeLabel,
+			Message:       message,
+			Color:         color,
+		})
+	}
+
+	// Approximate Verdana 11px text width, as shields.io's flat style does.
+	labelW := badgeTextWidth(badgeLabel)
+	msgW := badgeTextWidth(message)
+	total := labelW + msgW
+
+	ew := &errWriter{w: w}
+	ew.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`+"\n",
+		total, badgeLabel, html.EscapeString(message))
+	ew.printf(`  <title>%s: %s</title>`+"\n", badgeLabel, html.EscapeString(message))
+	ew.println(`  <linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	ew.printf(`  <clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`+"\n", total)
+	ew.println(`  <g clip-path="url(#r)">`)
+	ew.printf(`    <rect width="%d" height="20" fill="#555"/>`+"\n", labelW)
+	ew.printf(`    <rect x="%d" width="%d" height="20" fill="%s"/>`+"\n", labelW, msgW, badgeHex[color])
+	ew.printf(`    <rect width="%d" height="20" fill="url(#s)"/>`+"\n", total)
+	ew.println(`  </g>`)
+	ew.println(`  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	ew.printf(`    <text x="%d" y="14">%s</text>`+"\n", labelW/2, badgeLabel)
+	ew.printf(`    <text x="%d" y="14">%s</text>`+"\n", labelW+msgW/2, html.EscapeString(message))
+	ew.println(`  </g>`)
+	ew.println("</svg>")
+	return ew.err
+}
+
+// badgeMessage summarizes the severity counts, e.g. "2 high | 1 medium | 0 low".
+func badgeMessage(s review.Summary) string {
+	if s.Counts.Total() == 0 {
+		return "no issues"
+	}
+	return strings.ReplaceAll(s.Counts.Breakdown(), ", ", " | ")
+}
+
+// badgeColor maps the highest severity to a shields.io named color.
+func badgeColor(s review.Severity) string {
+	switch s {
+	case review.SeverityCritical, review.SeverityHigh:
+		return "red"
+	case review.SeverityMedium:
+		return "orange"
+	case review.SeverityLow:
+		return "yellow"
+	case review.SeverityInfo:
+		return "blue"
+	default:
+		return "brightgreen"
+	}
+}
+
+// badgeHex holds the hex values shields.io uses for its named colors.
+var badgeHex = map[string]string{
+	"red":         "#e05d44",
+	"orange":      "#fe7d37",
+	"yellow":      "#dfb317",
+	"blue":        "#007ec6",
+	"brightgreen": "#4c1",
+}
+
+func badgeTextWidth(s string) int {
+	return len(s)*7 + 10
+}
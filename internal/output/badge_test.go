@@ -0,0 +1,61 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+func TestBadgeWriter_SVG(t *testing.T) {
+	report := &review.Report{
+		Summary: review.Summary{
+			Counts:          review.SeverityCounts{High: 1, Medium: 2, Low: 3},
+			HighestSeverity: review.SeverityHigh,
+		},
+	}
+	var buf bytes.Buffer
+	if err := (&BadgeWriter{}).Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "<svg") || !strings.HasSuffix(out, "</svg>\n") {
+		t.Errorf("output is not an SVG document:\n%s", out)
+	}
+	if !strings.Contains(out, "1 high | 2 medium | 3 low") {
+		t.Errorf("missing counts in badge:\n%s", out)
+	}
+	if !strings.Contains(out, badgeHex["red"]) {
+		t.Errorf("high severity badge should be red:\n%s", out)
+	}
+}
+
+func TestBadgeWriter_JSON(t *testing.T) {
+	tests := []struct {
+		summary review.Summary
+		message string
+		color   string
+	}{
+		{review.Summary{}, "no issues", "brightgreen"},
+		{review.Summary{Counts: review.SeverityCounts{Low: 2}, HighestSeverity: review.SeverityLow}, "0 high | 0 medium | 2 low", "yellow"},
+		{review.Summary{Counts: review.SeverityCounts{Medium: 1}, HighestSeverity: review.SeverityMedium}, "0 high | 1 medium | 0 low", "orange"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := (&BadgeWriter{JSON: true}).Write(&buf, &review.Report{Summary: tt.summary}); err != nil {
+			t.Fatalf("Write error: %v", err)
+		}
+		var got shieldsEndpoint
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+		if got.SchemaVersion != 1 || got.Label != "prism" {
+			t.Errorf("unexpected endpoint header: %+v", got)
+		}
+		if got.Message != tt.message || got.Color != tt.color {
+			t.Errorf("got (%q, %q), want (%q, %q)", got.Message, got.Color, tt.message, tt.color)
+		}
+	}
+}
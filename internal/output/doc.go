@@ -1,12 +1,16 @@
 // Package output formats review reports for display or machine consumption.
 //
-// Four formats are supported:
+// Seven formats are supported:
 //   - text     — human-readable terminal output (default)
 //   - json     — full structured JSON report
 //   - markdown — PR-comment-friendly with collapsible sections per finding
 //   - sarif    — SARIF v2.1.0 for upload to GitHub Advanced Security and other CI tools
+//   - summary  — a single line of counts for chat notifications
+//   - html     — a self-contained page with filters and charts for CI artifacts
+//   - junit    — JUnit XML, one failed testcase per finding, for CI test reports
 //
 // Use [GetWriter] to obtain a [Writer] for a given format string, then call
-// [Writer.Write] with an [io.Writer] and a [*review.Report].  [WriteToFile]
-// and [WriteToStdout] are convenience helpers that handle destination selection.
+// [Writer.Write] with an [io.Writer] and a [*review.Report].  [WriteReport]
+// renders a report and delivers it to stdout, a file, an http(s) URL, or an
+// S3 object via [OpenSink].
 package output
@@ -0,0 +1,143 @@
+package output
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+// fieldTree is a parsed set of dotted field paths. A nil child marks a
+// selected leaf whose whole value is kept.
+type fieldTree map[string]fieldTree
+
+// ValidateFields checks dotted JSON field paths, such as "locations.path",
+// against the fields of a finding.
+func ValidateFields(fields []string) error {
+	_, err := parseFields(fields)
+	return err
+}
+
+// parseFields validates field paths and returns them as a tree for
+// projection. Paths may descend into nested objects and lists of objects.
+func parseFields(fields []string) (fieldTree, error) {
+	tree := fieldTree{}
+	findingType := reflect.TypeOf(review.Finding{})
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		t := findingType
+		node := tree
+		parts := strings.Split(field, ".")
+		for i, name := range parts {
+			ft, ok := jsonField(t, name)
+			if !ok {
+				return nil, fmt.Errorf("--fields: unknown field %q (valid fields here: %s)",
+					field, strings.Join(jsonFieldNames(t), ", "))
+			}
+			if i == len(parts)-1 {
+				node[name] = nil // a leaf keeps the whole value
+				break
+			}
+			child, seen := node[name]
+			if seen && child == nil {
+				break // the parent is already selected whole
+			}
+			if child == nil {
+				child = fieldTree{}
+				node[name] = child
+			}
+			node, t = child, ft
+		}
+	}
+	if len(tree) == 0 {
+		return nil, fmt.Errorf("--fields: no fields given")
+	}
+	return tree, nil
+}
+
+// jsonField returns the element type of the struct field of t whose JSON
+// name is name, looking through pointers and slices.
+func jsonField(t reflect.Type, name string) (reflect.Type, bool) {
+	t = elemType(t)
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if jsonName(f) == name {
+			return elemType(f.Type), true
+		}
+	}
+	return nil, false
+}
+
+// jsonFieldNames lists the JSON names of t's fields, sorted.
+func jsonFieldNames(t reflect.Type) []string {
+	t = elemType(t)
+	if t.Kind() != reflect.Struct {
+		return []string{"(none; not an object)"}
+	}
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonName(t.Field(i)); name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func jsonName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return f.Name
+}
+
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t
+}
+
+// project keeps the parts of a decoded JSON value selected by tree. Lists
+// are projected element by element.
+func project(v any, tree fieldTree) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(tree))
+		for name, child := range tree {
+			val, ok := v[name]
+			if !ok {
+				continue
+			}
+			if child == nil {
+				out[name] = val
+			} else {
+				out[name] = project(val, child)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = project(e, tree)
+		}
+		return out
+	default:
+		return v
+	}
+}
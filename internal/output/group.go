@@ -0,0 +1,98 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+// Grouping modes for the text and markdown writers.
+const (
+	GroupBySeverity = "severity"
+	GroupByCategory = "category"
+	GroupByFile     = "file"
+	GroupByCommit   = "commit"
+)
+
+// ValidateGroupBy checks a --group-by mode. Empty selects the default,
+// severity grouping.
+func ValidateGroupBy(by string) error {
+	switch by {
+	case "", GroupBySeverity, GroupByCategory, GroupByFile, GroupByCommit:
+		return nil
+	default:
+		return fmt.Errorf("--group-by: unknown mode %q (valid: severity, category, file, commit)", by)
+	}
+}
+
+// findingGroup is one section of a rendered report.
+type findingGroup struct {
+	label    string          // section heading
+	severity review.Severity // most severe finding in the section
+	findings []review.Finding
+}
+
+// groupFindings splits findings into sections by severity, category, file
+// path, or commit. Severity sections run from critical to info; the others are
+// ordered by their most severe finding, then by label. Within
+// a section findings are ordered by severity, then file path.
+func groupFindings(findings []review.Finding, by string) []findingGroup {
+	if by == "" {
+		by = GroupBySeverity
+	}
+	key := func(f review.Finding) string {
+		switch by {
+		case GroupByCategory:
+			return strings.ToUpper(string(f.Category))
+		case GroupByFile:
+			return filePath(f)
+		case GroupByCommit:
+			if c := primaryLocation(f).Commit; c != "" {
+				return c
+			}
+			return "(no commit)"
+		default:
+			return strings.ToUpper(string(f.Severity))
+		}
+	}
+
+	index := make(map[string]int)
+	var groups []findingGroup
+	for _, f := range findings {
+		if by == GroupBySeverity && review.SeverityRank(f.Severity) == 0 {
+			continue // only known severities have a section
+		}
+		k := key(f)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, findingGroup{label: k, severity: f.Severity})
+		}
+		g := &groups[i]
+		g.findings = append(g.findings, f)
+		if review.SeverityRank(f.Severity) > review.SeverityRank(g.severity) {
+			g.severity = f.Severity
+		}
+	}
+
+	for _, g := range groups {
+		sort.SliceStable(g.findings, func(i, j int) bool {
+			ri, rj := review.SeverityRank(g.findings[i].Severity), review.SeverityRank(g.findings[j].Severity)
+			if ri != rj {
+				return ri > rj
+			}
+			return filePath(g.findings[i]) < filePath(g.findings[j])
+		})
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		ri, rj := review.SeverityRank(groups[i].severity), review.SeverityRank(groups[j].severity)
+		if ri != rj {
+			return ri > rj
+		}
+		return groups[i].label < groups[j].label
+	})
+	return groups
+}
@@ -0,0 +1,136 @@
+package output
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+func groupTestFindings() []review.Finding {
+	loc := func(path string) []review.Location {
+		return []review.Location{{Path: path, Lines: review.LineRange{Start: 1, End: 1}}}
+	}
+	return []review.Finding{
+		{ID: "1", Severity: review.SeverityLow, Category: review.CategoryStyle, Title: "Naming", Locations: loc("b.go")},
+		{ID: "2", Severity: review.SeverityMedium, Category: review.CategoryPerformance, Title: "Alloc in loop", Locations: loc("a.go")},
+		{ID: "3", Severity: review.SeverityLow, Category: review.CategoryBug, Title: "Off by one", Locations: loc("c.go")},
+		{ID: "4", Severity: review.SeverityHigh, Category: review.CategorySecurity, Title: "SQL injection", Locations: loc("b.go")},
+		{ID: "5", Severity: review.SeverityHigh, Category: review.CategoryBug, Title: "Nil deref", Locations: loc("a.go")},
+		{ID: "6", Severity: review.SeverityLow, Category: review.CategorySecurity, Title: "Weak hash", Locations: loc("a.go")},
+	}
+}
+
+func groupSummary(groups []findingGroup) [][]string {
+	var out [][]string
+	for _, g := range groups {
+		row := []string{g.label, string(g.severity)}
+		for _, f := range g.findings {
+			row = append(row, f.ID)
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
+func TestGroupFindings_Category(t *testing.T) {
+	got := groupSummary(groupFindings(groupTestFindings(), GroupByCategory))
+	// Categories whose worst finding is high come first, ties broken by
+	// name; findings within a category run by severity, then path.
+	want := [][]string{
+		{"BUG", "high", "5", "3"},
+		{"SECURITY", "high", "4", "6"},
+		{"PERFORMANCE", "medium", "2"},
+		{"STYLE", "low", "1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groups = %v, want %v", got, want)
+	}
+}
+
+func TestGroupFindings_SeverityAndFile(t *testing.T) {
+	got := groupSummary(groupFindings(groupTestFindings(), ""))
+	want := [][]string{
+		{"HIGH", "high", "5", "4"},
+		{"MEDIUM", "medium", "2"},
+		{"LOW", "low", "6", "1", "3"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("severity groups = %v, want %v", got, want)
+	}
+
+	got = groupSummary(groupFindings(groupTestFindings(), GroupByFile))
+	want = [][]string{
+		{"a.go", "high", "5", "2", "6"},
+		{"b.go", "high", "4", "1"},
+		{"c.go", "low", "3"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("file groups = %v, want %v", got, want)
+	}
+}
+
+func TestGroupFindings_Commit(t *testing.T) {
+	findings := groupTestFindings()
+	for i := range findings {
+		switch findings[i].ID {
+		case "1", "2":
+			findings[i].Locations[0].Commit = "aaaaaaa"
+		case "4", "5":
+			findings[i].Locations[0].Commit = "bbbbbbb"
+		}
+	}
+	got := groupSummary(groupFindings(findings, GroupByCommit))
+	want := [][]string{
+		{"bbbbbbb", "high", "5", "4"},
+		{"aaaaaaa", "medium", "2", "1"},
+		{"(no commit)", "low", "6", "3"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("commit groups = %v, want %v", got, want)
+	}
+}
+
+func TestValidateGroupBy(t *testing.T) {
+	for _, by := range []string{"", "severity", "category", "file", "commit"} {
+		if err := ValidateGroupBy(by); err != nil {
+			t.Errorf("ValidateGroupBy(%q) = %v", by, err)
+		}
+	}
+	if err := ValidateGroupBy("author"); err == nil {
+		t.Error("ValidateGroupBy(author) should fail")
+	}
+}
+
+func TestWriters_GroupByCategory(t *testing.T) {
+	report := &review.Report{Tool: "prism", Findings: groupTestFindings()}
+	report.Summary = review.ComputeSummary(report.Findings)
+
+	for _, format := range []string{"text", "markdown"} {
+		w, err := GetWriterWithOptions(format, WriterOptions{GroupBy: GroupByCategory, NoTiming: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := w.Write(&buf, report); err != nil {
+			t.Fatalf("%s: Write error: %v", format, err)
+		}
+		out := buf.String()
+		last := -1
+		for _, heading := range []string{"BUG", "SECURITY", "PERFORMANCE", "STYLE"} {
+			i := strings.Index(out, heading)
+			if i < 0 || i < last {
+				t.Errorf("%s: heading %s missing or out of order:\n%s", format, heading, out)
+			}
+			last = i
+		}
+		if strings.Contains(out, "\nHIGH") || strings.Contains(out, ">HIGH") {
+			t.Errorf("%s: category grouping should not print severity sections:\n%s", format, out)
+		}
+		if !strings.Contains(out, "high") {
+			t.Errorf("%s: findings should still show their severity:\n%s", format, out)
+		}
+	}
+}
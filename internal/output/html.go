@@ -0,0 +1,352 @@
+package output
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dshills/prism/internal/gitctx"
+	"github.com/dshills/prism/internal/providers"
+	"github.com/dshills/prism/internal/review"
+)
+
+// HTMLWriter outputs a self-contained HTML page for browsing a report: a
+// summary chart, severity and text filters, and collapsible sections per
+// file. It loads nothing from the network, so the file can be attached to
+// a CI run as an artifact.
+type HTMLWriter struct {
+	NoTiming bool // omit the "Reviewed in" footer
+}
+
+// htmlReport is the view model rendered by htmlTemplate.
+type htmlReport struct {
+	*review.Report
+	Total      int
+	Severities []htmlBar
+	Categories []htmlBar
+	Files      []htmlFile
+	NoTiming   bool
+}
+
+// htmlBar is one bar of a summary chart. Pct is its width relative to the
+// largest bar in the chart.
+type htmlBar struct {
+	Label string
+	Count int
+	Pct   int
+}
+
+type htmlFile struct {
+	Path     string
+	Severity review.Severity
+	Findings []review.Finding
+}
+
+func (h *HTMLWriter) Write(w io.Writer, report *review.Report) error {
+	c := report.Summary.Counts
+	data := htmlReport{
+		Report:   report,
+		Total:    c.Total(),
+		NoTiming: h.NoTiming,
+	}
+	var bars []htmlBar
+	for _, s := range c.Shown() {
+		bars = append(bars, htmlBar{Label: string(s), Count: c.Count(s)})
+	}
+	data.Severities = chartBars(bars)
+
+	byCategory := make(map[string]int)
+	for _, f := range report.Findings {
+		byCategory[string(f.Category)]++
+	}
+	for cat, n := range byCategory {
+		data.Categories = append(data.Categories, htmlBar{Label: cat, Count: n})
+	}
+	sort.Slice(data.Categories, func(i, j int) bool {
+		a, b := data.Categories[i], data.Categories[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Label < b.Label
+	})
+	data.Categories = chartBars(data.Categories)
+
+	for _, g := range groupFindings(report.Findings, GroupByFile) {
+		data.Files = append(data.Files, htmlFile{Path: g.label, Severity: g.severity, Findings: g.findings})
+	}
+
+	return htmlTemplate.Execute(w, data)
+}
+
+// chartBars sets each bar's width relative to the largest count.
+func chartBars(bars []htmlBar) []htmlBar {
+	max := 0
+	for _, b := range bars {
+		if b.Count > max {
+			max = b.Count
+		}
+	}
+	for i := range bars {
+		if max > 0 {
+			bars[i].Pct = bars[i].Count * 100 / max
+		}
+	}
+	return bars
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"location": func(f review.Finding) string { return formatLocation(primaryLocation(f)) },
+	"primary":  primaryLocation,
+	"percent":  func(c float64) int { return int(c*100 + 0.5) },
+	"upper":    strings.ToUpper,
+	"label":    func(s string) string { return severityLabel(review.Severity(s)) },
+	"short":    gitctx.ShortCommit,
+	"stats":    formatStats,
+	"usage":    func(u *providers.Usage) string { return formatUsage(*u) },
+	"baseline": review.BaselineLabel,
+	"isCode":   looksLikeCode,
+	"highlight": func(code, path string) template.HTML {
+		return highlightCode(code, inferLang(path))
+	},
+	"diffLines": func(hunk string) []htmlDiffLine {
+		lines := strings.Split(strings.TrimRight(hunk, "\n"), "\n")
+		out := make([]htmlDiffLine, len(lines))
+		for i, l := range lines {
+			out[i] = htmlDiffLine{Text: l}
+			if l != "" {
+				switch l[0] {
+				case '+':
+					out[i].Class = "add"
+				case '-':
+					out[i].Class = "del"
+				case '@':
+					out[i].Class = "hunk"
+				}
+			}
+		}
+		return out
+	},
+}).Parse(htmlPage))
+
+type htmlDiffLine struct {
+	Class string
+	Text  string
+}
+
+// codeKeywords are highlighted in suggestions. The set is shared across
+// languages; a word that is a keyword in one language and an identifier in
+// another is rare enough in short suggestions not to matter.
+var codeKeywords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true,
+	"continue": true, "def": true, "default": true, "defer": true, "do": true,
+	"elif": true, "else": true, "enum": true, "except": true, "export": true,
+	"extends": true, "false": true, "finally": true, "fn": true, "for": true,
+	"from": true, "func": true, "function": true, "go": true, "if": true,
+	"impl": true, "import": true, "in": true, "interface": true, "let": true,
+	"map": true, "match": true, "mut": true, "new": true, "nil": true,
+	"None": true, "null": true, "package": true, "pub": true, "raise": true,
+	"range": true, "return": true, "select": true, "self": true, "static": true,
+	"struct": true, "switch": true, "this": true, "throw": true, "True": true,
+	"False": true, "true": true, "try": true, "type": true, "undefined": true,
+	"use": true, "var": true, "while": true, "with": true, "yield": true,
+}
+
+// highlightCode renders code as escaped HTML with comments, strings,
+// numbers, and keywords wrapped in spans for the page's CSS. It is a
+// lexical approximation, not a parser: anything it does not recognize is
+// left plain.
+func highlightCode(code, lang string) template.HTML {
+	hashComments := lang == "python" || lang == "ruby" || lang == "bash" || lang == "yaml" || lang == "hcl"
+	var b strings.Builder
+	span := func(class, text string) {
+		b.WriteString(`<span class="` + class + `">`)
+		b.WriteString(template.HTMLEscapeString(text))
+		b.WriteString("</span>")
+	}
+
+	for i := 0; i < len(code); {
+		c := code[i]
+		switch {
+		case strings.HasPrefix(code[i:], "//") || (hashComments && c == '#'):
+			end := strings.IndexByte(code[i:], '\n')
+			if end < 0 {
+				end = len(code) - i
+			}
+			span("tok-comment", code[i:i+end])
+			i += end
+		case c == '"' || c == '\'' || c == '`':
+			end := i + 1
+			for end < len(code) && code[end] != c && (c == '`' || code[end] != '\n') {
+				if code[end] == '\\' && c != '`' {
+					end++
+				}
+				end++
+			}
+			if end < len(code) && code[end] == c {
+				end++
+			}
+			end = min(end, len(code))
+			span("tok-string", code[i:end])
+			i = end
+		case c >= '0' && c <= '9':
+			end := i
+			for end < len(code) && (isWordByte(code[end]) || code[end] == '.') {
+				end++
+			}
+			span("tok-number", code[i:end])
+			i = end
+		case isWordByte(c):
+			end := i
+			for end < len(code) && isWordByte(code[end]) {
+				end++
+			}
+			if word := code[i:end]; codeKeywords[word] {
+				span("tok-keyword", word)
+			} else {
+				b.WriteString(template.HTMLEscapeString(word))
+			}
+			i = end
+		default:
+			b.WriteString(template.HTMLEscapeString(code[i : i+1]))
+			i++
+		}
+	}
+	return template.HTML(b.String())
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+const htmlPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Prism Code Review{{if .Inputs.Range}} — {{.Inputs.Range}}{{end}}</title>
+<style>
+body { font: 14px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0 auto; max-width: 1100px; padding: 24px; color: #1f2328; }
+h1 { margin: 0 0 4px; font-size: 24px; }
+.meta { color: #656d76; margin: 2px 0; }
+.verdict { display: inline-block; font-weight: 600; padding: 2px 10px; border-radius: 12px; background: #eaeef2; }
+.verdict-block { background: #ffebe9; color: #cf222e; }
+.verdict-review-needed { background: #fff8c5; color: #9a6700; }
+.verdict-pass { background: #dafbe1; color: #1a7f37; }
+.charts { display: flex; flex-wrap: wrap; gap: 32px; margin: 20px 0; }
+.chart { flex: 1; min-width: 280px; }
+.chart h2 { font-size: 15px; margin: 0 0 8px; }
+.bar-row { display: flex; align-items: center; gap: 8px; margin: 4px 0; }
+.bar-label { width: 110px; text-align: right; color: #656d76; }
+.bar-track { flex: 1; background: #f6f8fa; border-radius: 3px; height: 14px; }
+.bar { height: 14px; border-radius: 3px; background: #8c959f; }
+.bar-count { width: 32px; }
+.sev-critical { background: #82071e; }
+.sev-high { background: #cf222e; }
+.sev-medium { background: #bf8700; }
+.sev-low { background: #0969da; }
+.sev-info { background: #6e7781; }
+.filters { position: sticky; top: 0; background: #fff; border-bottom: 1px solid #d0d7de; padding: 10px 0; margin-bottom: 12px; display: flex; flex-wrap: wrap; gap: 16px; align-items: center; }
+.filters input[type=search] { flex: 1; min-width: 200px; padding: 4px 8px; border: 1px solid #d0d7de; border-radius: 6px; }
+details.file { border: 1px solid #d0d7de; border-radius: 6px; margin: 10px 0; }
+details.file > summary { cursor: pointer; padding: 8px 12px; background: #f6f8fa; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; }
+.finding { border-top: 1px solid #d0d7de; padding: 10px 14px; }
+.finding h3 { font-size: 15px; margin: 0 0 4px; }
+.badge { display: inline-block; color: #fff; font-size: 12px; font-weight: 600; padding: 0 8px; border-radius: 10px; text-transform: uppercase; }
+.tags { color: #656d76; font-size: 12px; }
+code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: 12px; }
+pre { background: #f6f8fa; padding: 10px; border-radius: 6px; overflow-x: auto; }
+.tok-keyword { color: #cf222e; }
+.tok-string { color: #0a3069; }
+.tok-comment { color: #6e7781; font-style: italic; }
+.tok-number { color: #0550ae; }
+.diff .add { background: #dafbe1; }
+.diff .del { background: #ffebe9; }
+.diff .hunk { color: #8250df; }
+.hidden { display: none; }
+footer { color: #656d76; margin-top: 24px; font-size: 12px; }
+</style>
+</head>
+<body>
+<header>
+<h1>Prism Code Review</h1>
+<p class="meta">{{.Inputs.Mode}} mode{{if .Inputs.Range}} · {{.Inputs.Range}}{{end}}{{if .Stats.FilesChanged}} · {{stats .Stats}}{{end}}</p>
+{{if .Repo.Root}}<p class="meta">{{.Repo.Root}}{{if .Repo.Detached}} (detached HEAD at {{short .Repo.Head}}){{else if .Repo.Branch}} (branch: {{.Repo.Branch}}){{end}}</p>{{end}}
+{{if .Summary.Verdict}}<p><span class="verdict verdict-{{.Summary.Verdict}}">Verdict: {{upper .Summary.Verdict}}</span></p>{{end}}
+</header>
+
+<section class="charts">
+<div class="chart">
+<h2>Findings by severity ({{.Total}})</h2>
+{{range .Severities}}<div class="bar-row"><span class="bar-label">{{.Label}}</span><span class="bar-track"><span class="bar sev-{{.Label}}" style="display:block;width:{{.Pct}}%"></span></span><span class="bar-count">{{.Count}}</span></div>
+{{end}}</div>
+{{if .Categories}}<div class="chart">
+<h2>Findings by category</h2>
+{{range .Categories}}<div class="bar-row"><span class="bar-label">{{.Label}}</span><span class="bar-track"><span class="bar" style="display:block;width:{{.Pct}}%"></span></span><span class="bar-count">{{.Count}}</span></div>
+{{end}}</div>{{end}}
+</section>
+
+{{if .Files}}
+<section class="filters">
+{{range .Severities}}<label><input type="checkbox" class="sev-filter" value="{{.Label}}" checked> {{label .Label}}</label>
+{{end}}<input type="search" id="search" placeholder="Filter by text, path, category, or tag">
+<span id="shown"></span>
+</section>
+
+{{range .Files}}
+<details class="file" open>
+<summary><span class="badge sev-{{.Severity}}">{{.Severity}}</span> {{.Path}} (<span class="file-count">{{len .Findings}}</span>)</summary>
+{{range .Findings}}
+<article class="finding" id="{{.ID}}" data-severity="{{.Severity}}">
+<h3><span class="badge sev-{{.Severity}}">{{.Severity}}</span> {{.Title}}</h3>
+<p class="meta"><code>{{location .}}</code> · {{.Category}} · Confidence {{percent .Confidence}}%{{with (primary .).Commit}} · Commit <code>{{short .}}</code>{{end}}{{with baseline .}} · Baseline: {{.}}{{end}}</p>
+<p>{{.Message}}</p>
+{{if .Suggestion}}<p><strong>Suggestion:</strong></p>
+{{if isCode .Suggestion}}<pre><code>{{highlight .Suggestion (primary .).Path}}</code></pre>{{else}}<p>{{.Suggestion}}</p>{{end}}{{end}}
+{{if .Tags}}<p class="tags">{{range $i, $t := .Tags}}{{if $i}}, {{end}}#{{$t}}{{end}}</p>{{end}}
+{{if .Hunk}}<details><summary>Diff hunk</summary><pre class="diff">{{range diffLines .Hunk}}<span class="{{.Class}}">{{.Text}}</span>
+{{end}}</pre></details>{{end}}
+</article>
+{{end}}
+</details>
+{{end}}
+{{else}}
+<p>No issues found.</p>
+{{if .ReviewNote}}<blockquote>{{.ReviewNote}}</blockquote>{{end}}
+{{end}}
+
+{{if not .NoTiming}}<footer>Reviewed in {{.Timing.TotalMs}}ms (git: {{.Timing.GitMs}}ms, LLM: {{.Timing.LLMMs}}ms){{with .Timing.Usage}} · {{usage .}}{{end}}</footer>{{end}}
+
+{{if .Files}}
+<script>
+(function () {
+  var boxes = document.querySelectorAll(".sev-filter");
+  var search = document.getElementById("search");
+  function apply() {
+    var sevs = {};
+    boxes.forEach(function (b) { sevs[b.value] = b.checked; });
+    var q = search.value.toLowerCase();
+    var shown = 0, total = 0;
+    document.querySelectorAll("details.file").forEach(function (file) {
+      var visible = 0;
+      file.querySelectorAll(".finding").forEach(function (f) {
+        total++;
+        var ok = sevs[f.dataset.severity] !== false && (q === "" || f.textContent.toLowerCase().indexOf(q) >= 0 || file.querySelector("summary").textContent.toLowerCase().indexOf(q) >= 0);
+        f.classList.toggle("hidden", !ok);
+        if (ok) visible++;
+      });
+      file.querySelector(".file-count").textContent = visible;
+      file.classList.toggle("hidden", visible === 0);
+      shown += visible;
+    });
+    document.getElementById("shown").textContent = shown + " of " + total + " findings shown";
+  }
+  boxes.forEach(function (b) { b.addEventListener("change", apply); });
+  search.addEventListener("input", apply);
+  apply();
+})();
+</script>
+{{end}}
+</body>
+</html>
+`
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+func TestHTMLWriter(t *testing.T) {
+	findings := []review.Finding{
+		{
+			ID:         "f1",
+			Severity:   review.SeverityHigh,
+			Category:   review.CategorySecurity,
+			Title:      "SQL <injection>",
+			Message:    "Query built with <script>alert(1)</script>",
+			Suggestion: "if err != nil {\n\treturn \"x\" // done\n}",
+			Confidence: 0.9,
+			Locations:  []review.Location{{Path: "db.go", Lines: review.LineRange{Start: 4, End: 6}}},
+			Hunk:       "@@ -1 +1 @@\n-old\n+new",
+		},
+		{
+			ID:        "f2",
+			Severity:  review.SeverityLow,
+			Category:  review.CategoryStyle,
+			Title:     "Naming",
+			Message:   "m",
+			Locations: []review.Location{{Path: "api.go", Lines: review.LineRange{Start: 1, End: 1}}},
+		},
+	}
+	report := &review.Report{
+		Inputs:   review.InputInfo{Mode: "range", Range: "main...HEAD"},
+		Findings: findings,
+		Summary:  review.ComputeSummary(findings),
+		Timing:   review.Timing{TotalMs: 1234},
+	}
+
+	var buf bytes.Buffer
+	if err := (&HTMLWriter{}).Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<title>Prism Code Review — main...HEAD</title>",
+		`class="verdict verdict-block"`,
+		`<article class="finding" id="f1" data-severity="high">`,
+		"SQL &lt;injection&gt;",
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+		`<span class="tok-keyword">if</span>`,
+		`<span class="tok-string">&#34;x&#34;</span>`,
+		`<span class="tok-comment">// done</span>`,
+		`<span class="add">&#43;new</span>`,
+		`class="sev-filter" value="medium"`,
+		"Reviewed in 1234ms",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+	if strings.Contains(out, "<script>alert") {
+		t.Error("finding text must be escaped")
+	}
+	// Files are ordered by their most severe finding
+	if strings.Index(out, "db.go (") > strings.Index(out, "api.go (") {
+		t.Error("db.go (high) should come before api.go (low)")
+	}
+	if strings.Contains(out, "http://") || strings.Contains(out, "https://") {
+		t.Error("page should not load external resources")
+	}
+}
+
+func TestHTMLWriter_Empty(t *testing.T) {
+	report := &review.Report{
+		Inputs:     review.InputInfo{Mode: "staged"},
+		Summary:    review.ComputeSummary(nil),
+		ReviewNote: "Checked error paths.",
+	}
+	var buf bytes.Buffer
+	if err := (&HTMLWriter{NoTiming: true}).Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "No issues found.") || !strings.Contains(out, "Checked error paths.") {
+		t.Errorf("expected the no-issues message and review note:\n%s", out)
+	}
+	if strings.Contains(out, "Reviewed in") || strings.Contains(out, "<script>") {
+		t.Error("empty report with NoTiming should have no footer or filter script")
+	}
+}
+
+func TestHighlightCode(t *testing.T) {
+	got := string(highlightCode("x = 'a<b' # note\nreturn 42", "python"))
+	want := `x = <span class="tok-string">&#39;a&lt;b&#39;</span> <span class="tok-comment"># note</span>` + "\n" +
+		`<span class="tok-keyword">return</span> <span class="tok-number">42</span>`
+	if got != want {
+		t.Errorf("highlightCode =\n%s\nwant\n%s", got, want)
+	}
+}
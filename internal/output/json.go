@@ -9,10 +9,22 @@ import (
 )
 
 // JSONWriter outputs the full report as JSON.
-type JSONWriter struct{}
+type JSONWriter struct {
+	// Fields, if set, reduces each finding to these dotted JSON field
+	// paths (see ValidateFields). The rest of the report is unchanged.
+	Fields []string
+}
 
 func (j *JSONWriter) Write(w io.Writer, report *review.Report) error {
-	data, err := json.MarshalIndent(report, "", "  ")
+	var v any = report
+	if len(j.Fields) > 0 {
+		projected, err := j.project(report)
+		if err != nil {
+			return err
+		}
+		v = projected
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling JSON: %w", err)
 	}
@@ -23,3 +35,22 @@ func (j *JSONWriter) Write(w io.Writer, report *review.Report) error {
 	_, err = fmt.Fprintln(w)
 	return err
 }
+
+// project returns report as generic JSON with its findings reduced to
+// j.Fields.
+func (j *JSONWriter) project(report *review.Report) (map[string]any, error) {
+	tree, err := parseFields(j.Fields)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling JSON: %w", err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("projecting JSON: %w", err)
+	}
+	out["findings"] = project(out["findings"], tree)
+	return out, nil
+}
@@ -55,3 +55,97 @@ func TestJSONWriter(t *testing.T) {
 		t.Errorf("Finding title = %q, want %q", parsed.Findings[0].Title, "Test")
 	}
 }
+
+func TestJSONWriter_Metadata(t *testing.T) {
+	report := &review.Report{
+		Tool:     "prism",
+		Findings: []review.Finding{},
+		Metadata: map[string]string{"env": "prod"},
+	}
+
+	var buf bytes.Buffer
+	w := &JSONWriter{}
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+
+	var parsed review.Report
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if parsed.Metadata["env"] != "prod" {
+		t.Errorf("Metadata = %v, want env=prod", parsed.Metadata)
+	}
+}
+
+func TestJSONWriter_Fields(t *testing.T) {
+	report := &review.Report{
+		Tool: "prism",
+		Findings: []review.Finding{{
+			ID:         "abc",
+			Severity:   review.SeverityHigh,
+			Category:   review.CategoryBug,
+			Title:      "Test",
+			Message:    "long message",
+			Suggestion: "long suggestion",
+			Locations: []review.Location{
+				{Path: "main.go", Lines: review.LineRange{Start: 3, End: 4}},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	w := &JSONWriter{Fields: []string{"id", "severity", "locations.path", "title"}}
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+
+	var parsed struct {
+		Tool     string           `json:"tool"`
+		Findings []map[string]any `json:"findings"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if parsed.Tool != "prism" {
+		t.Errorf("Tool = %q, report fields outside findings should be kept", parsed.Tool)
+	}
+	if len(parsed.Findings) != 1 {
+		t.Fatalf("Findings count = %d, want 1", len(parsed.Findings))
+	}
+	f := parsed.Findings[0]
+	if len(f) != 4 || f["id"] != "abc" || f["severity"] != "high" || f["title"] != "Test" {
+		t.Errorf("projected finding = %v, want only id, severity, locations, title", f)
+	}
+	locs, _ := f["locations"].([]any)
+	if len(locs) != 1 {
+		t.Fatalf("locations = %v, want one entry", f["locations"])
+	}
+	loc, _ := locs[0].(map[string]any)
+	if len(loc) != 1 || loc["path"] != "main.go" {
+		t.Errorf("location = %v, want only path", loc)
+	}
+}
+
+func TestValidateFields(t *testing.T) {
+	for _, fields := range [][]string{
+		{"id", "severity"},
+		{"locations.path", "locations.lines.start"},
+		{"locations", "locations.path"},
+		{"tags"},
+	} {
+		if err := ValidateFields(fields); err != nil {
+			t.Errorf("ValidateFields(%v) = %v, want nil", fields, err)
+		}
+	}
+	for _, fields := range [][]string{
+		{"severty"},
+		{"locations.file"},
+		{"title.length"},
+		{""},
+	} {
+		if err := ValidateFields(fields); err == nil {
+			t.Errorf("ValidateFields(%v) = nil, want an error", fields)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+// JUnitWriter outputs findings as a JUnit XML test report so CI systems
+// (Jenkins, GitLab, CircleCI) show them in their test UI. Each file with
+// findings becomes a testsuite and each finding a failed testcase. A report
+// without findings has a single passing "prism" testcase, so the CI still
+// records that the review ran.
+type JUnitWriter struct{}
+
+func (j *JUnitWriter) Write(w io.Writer, report *review.Report) error {
+	data, err := xml.MarshalIndent(buildJUnit(report), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling JUnit XML: %w", err)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("writing JUnit XML: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("writing JUnit XML: %w", err)
+	}
+	_, err = fmt.Fprintln(w)
+	return err
+}
+
+// JUnit XML schema types, as read by common CI test reporters
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func buildJUnit(report *review.Report) junitTestSuites {
+	root := junitTestSuites{
+		Name: "prism",
+		Time: fmt.Sprintf("%.3f", float64(report.Timing.TotalMs)/1000),
+	}
+
+	if len(report.Findings) == 0 {
+		root.Tests = 1
+		root.Suites = []junitTestSuite{{
+			Name:  "prism",
+			Tests: 1,
+			Cases: []junitTestCase{{Name: "review", ClassName: "prism"}},
+		}}
+		return root
+	}
+
+	// Suites in path order, findings within a file by line
+	groups := groupFindings(report.Findings, GroupByFile)
+	sort.Slice(groups, func(i, j int) bool { return groups[i].label < groups[j].label })
+	for _, g := range groups {
+		findings := append([]review.Finding(nil), g.findings...)
+		sort.SliceStable(findings, func(i, j int) bool {
+			return primaryLocation(findings[i]).Lines.Start < primaryLocation(findings[j]).Lines.Start
+		})
+
+		suite := junitTestSuite{Name: g.label, Tests: len(findings), Failures: len(findings)}
+		for _, f := range findings {
+			suite.Cases = append(suite.Cases, junitCase(f))
+		}
+		root.Suites = append(root.Suites, suite)
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+	}
+	return root
+}
+
+// junitCase maps a finding to a failed testcase named after its location
+// and title, with the message and suggestion as the failure text.
+func junitCase(f review.Finding) junitTestCase {
+	loc := primaryLocation(f)
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s\n\nSeverity: %s | Category: %s | Confidence: %.0f%%\n", f.Message, f.Severity, f.Category, f.Confidence*100)
+	if f.Suggestion != "" {
+		fmt.Fprintf(&body, "\nSuggestion:\n%s\n", f.Suggestion)
+	}
+	tc := junitTestCase{
+		Name:      fmt.Sprintf("%s: %s", formatLocation(loc), f.Title),
+		ClassName: loc.Path,
+		File:      loc.Path,
+		Failure: &junitFailure{
+			Message: f.Title,
+			Type:    string(f.Severity),
+			Body:    body.String(),
+		},
+	}
+	if !loc.Lines.FileLevel() {
+		tc.Line = loc.Lines.Start
+	}
+	return tc
+}
@@ -0,0 +1,86 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+func TestJUnitWriter(t *testing.T) {
+	findings := []review.Finding{
+		{
+			Severity:   review.SeverityLow,
+			Category:   review.CategoryStyle,
+			Title:      "Late",
+			Message:    "m",
+			Locations:  []review.Location{{Path: "b.go", Lines: review.LineRange{Start: 20, End: 20}}},
+			Confidence: 0.5,
+		},
+		{
+			Severity:   review.SeverityHigh,
+			Category:   review.CategoryBug,
+			Title:      "Nil <deref>",
+			Message:    "p & q may be nil",
+			Suggestion: "check p",
+			Locations:  []review.Location{{Path: "b.go", Lines: review.LineRange{Start: 3, End: 4}}},
+		},
+		{
+			Severity:  review.SeverityMedium,
+			Category:  review.CategoryMaintainability,
+			Title:     "File-level",
+			Message:   "m",
+			Locations: []review.Location{{Path: "a.go"}},
+		},
+	}
+	report := &review.Report{Findings: findings, Timing: review.Timing{TotalMs: 1500}}
+
+	var buf bytes.Buffer
+	if err := (&JUnitWriter{}).Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), `<?xml version="1.0" encoding="UTF-8"?>`) {
+		t.Errorf("missing XML header:\n%s", buf.String())
+	}
+
+	var got junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, buf.String())
+	}
+	if got.Tests != 3 || got.Failures != 3 || got.Time != "1.500" {
+		t.Errorf("testsuites = tests %d, failures %d, time %s", got.Tests, got.Failures, got.Time)
+	}
+	if len(got.Suites) != 2 || got.Suites[0].Name != "a.go" || got.Suites[1].Name != "b.go" {
+		t.Fatalf("suites = %+v, want a.go then b.go", got.Suites)
+	}
+	b := got.Suites[1]
+	if b.Tests != 2 || b.Cases[0].Name != "b.go:3-4: Nil <deref>" || b.Cases[1].Line != 20 {
+		t.Errorf("b.go cases = %+v, want ordered by line", b.Cases)
+	}
+	fail := b.Cases[0].Failure
+	if fail == nil || fail.Type != "high" || fail.Message != "Nil <deref>" {
+		t.Fatalf("failure = %+v", fail)
+	}
+	if !strings.Contains(fail.Body, "p & q may be nil") || !strings.Contains(fail.Body, "Suggestion:\ncheck p") {
+		t.Errorf("failure body = %q", fail.Body)
+	}
+	if a := got.Suites[0].Cases[0]; a.Line != 0 || a.File != "a.go" {
+		t.Errorf("file-level case = %+v, want no line", a)
+	}
+}
+
+func TestJUnitWriter_NoFindings(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&JUnitWriter{}).Write(&buf, &review.Report{}); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	var got junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Tests != 1 || got.Failures != 0 || len(got.Suites) != 1 || got.Suites[0].Cases[0].Failure != nil {
+		t.Errorf("got %+v, want one passing testcase", got)
+	}
+}
@@ -2,76 +2,111 @@ package output
 
 import (
 	"io"
-	"sort"
 	"strings"
 
 	"github.com/dshills/prism/internal/review"
 )
 
 // MarkdownWriter outputs a PR-comment-friendly markdown report.
-type MarkdownWriter struct{}
+type MarkdownWriter struct {
+	Icons    map[review.Severity]string // nil = default icons
+	NoTiming bool                       // omit the "Reviewed in" footer
+	TOC      bool                       // add a table of contents linking to each finding
+	GroupBy  string                     // severity (default), category, file, or commit
+}
 
 func (m *MarkdownWriter) Write(w io.Writer, report *review.Report) error {
 	ew := &errWriter{w: w}
-	total := report.Summary.Counts.High + report.Summary.Counts.Medium + report.Summary.Counts.Low
+	total := report.Summary.Counts.Total()
 
 	// Heading
 	ew.printf("## Prism Code Review\n\n")
+	if report.Summary.Verdict != "" {
+		ew.printf("**Verdict: %s**\n\n", strings.ToUpper(report.Summary.Verdict))
+	}
+	if report.Stats.FilesChanged > 0 {
+		ew.printf("_%s_\n\n", formatStats(report.Stats))
+	}
 
 	// Summary table
 	ew.printf("| Severity | Count |\n")
 	ew.printf("|----------|-------|\n")
-	ew.printf("| High     | %d    |\n", report.Summary.Counts.High)
-	ew.printf("| Medium   | %d    |\n", report.Summary.Counts.Medium)
-	ew.printf("| Low      | %d    |\n", report.Summary.Counts.Low)
+	for _, s := range report.Summary.Counts.Shown() {
+		ew.printf("| %-8s | %d    |\n", severityLabel(s), report.Summary.Counts.Count(s))
+	}
 	ew.printf("| **Total** | **%d** |\n\n", total)
 
 	if total == 0 {
 		ew.println("No issues found. :white_check_mark:")
+		if report.ReviewNote != "" {
+			ew.printf("\n> %s\n", mdEscapeText(report.ReviewNote))
+		}
 		return ew.err
 	}
 
-	// Collapsible sections by severity
-	grouped := groupFindingsBySeverity(report.Findings)
-	for _, sev := range []review.Severity{review.SeverityHigh, review.SeverityMedium, review.SeverityLow} {
-		findings := grouped[sev]
-		if len(findings) == 0 {
-			continue
-		}
+	groups := groupFindings(report.Findings, m.GroupBy)
+	bySeverity := m.GroupBy == "" || m.GroupBy == GroupBySeverity
 
-		icon := mdSeverityIcon(sev)
-		label := strings.ToUpper(string(sev))
+	if m.TOC {
+		ew.printf("**Contents**\n\n")
+		for _, g := range groups {
+			for _, f := range g.findings {
+				ew.printf("- [%s](#%s) (%s, %s)\n", mdLinkText(mdTitle(f)), mdAnchor(f),
+					f.Severity, mdCodeSpan(formatLocation(mdPrimaryLocation(f))))
+			}
+		}
+		ew.printf("\n")
+	}
 
-		ew.printf("<details>\n<summary>%s %s (%d)</summary>\n\n", icon, label, len(findings))
+	// Collapsible sections by severity, category, file, or commit
+	for _, g := range groups {
+		label := g.label
+		if m.GroupBy == GroupByFile || m.GroupBy == GroupByCommit {
+			label = mdCodeSpan(label)
+		}
+		heading := withIcon(resolveIcon(m.Icons, g.severity, mdSeverityIcon), label)
 
-		// Sort by file path within severity
-		sort.Slice(findings, func(i, j int) bool {
-			return mdFilePath(findings[i]) < mdFilePath(findings[j])
-		})
+		ew.printf("<details>\n<summary>%s (%d)</summary>\n\n", heading, len(g.findings))
 
-		for _, f := range findings {
+		for _, f := range g.findings {
 			loc := mdPrimaryLocation(f)
-			ew.printf("### %s\n\n", f.Title)
+			if m.TOC {
+				ew.printf("<a id=\"%s\"></a>\n\n", mdAnchor(f))
+			}
+			ew.printf("### %s\n\n", mdTitle(f))
+			category := string(f.Category)
+			if !bySeverity {
+				category = string(f.Severity) + " | " + category
+			}
 			if loc.Commit != "" {
-				ew.printf("**`%s:%d-%d`** | %s | Confidence: %.0f%% | Commit: `%s`\n\n",
-					loc.Path, loc.Lines.Start, loc.Lines.End, f.Category, f.Confidence*100, loc.Commit)
+				ew.printf("**%s** | %s | Confidence: %.0f%% | Commit: %s\n\n",
+					mdCodeSpan(formatLocation(loc)), category, f.Confidence*100, mdCodeSpan(loc.Commit))
 			} else {
-				ew.printf("**`%s:%d-%d`** | %s | Confidence: %.0f%%\n\n",
-					loc.Path, loc.Lines.Start, loc.Lines.End, f.Category, f.Confidence*100)
+				ew.printf("**%s** | %s | Confidence: %.0f%%\n\n",
+					mdCodeSpan(formatLocation(loc)), category, f.Confidence*100)
+			}
+			if label := review.BaselineLabel(f); label != "" {
+				ew.printf("**Baseline:** %s\n\n", label)
 			}
-			ew.printf("%s\n\n", f.Message)
+			ew.printf("%s\n\n", mdEscapeText(f.Message))
 
 			if f.Suggestion != "" {
 				ew.printf("**Suggestion:**\n\n")
 				// Wrap suggestion in code fence if it looks like code
 				if looksLikeCode(f.Suggestion) {
 					lang := inferLang(loc.Path)
-					ew.printf("```%s\n%s\n```\n\n", lang, f.Suggestion)
+					fence := mdFence(f.Suggestion)
+					ew.printf("%s%s\n%s\n%s\n\n", fence, lang, f.Suggestion, fence)
 				} else {
 					ew.printf("> %s\n\n", strings.ReplaceAll(f.Suggestion, "\n", "\n> "))
 				}
 			}
 
+			if f.Hunk != "" {
+				fence := mdFence(f.Hunk)
+				ew.printf("<details>\n<summary>Diff hunk</summary>\n\n%sdiff\n%s\n%s\n\n</details>\n\n", fence, f.Hunk, fence)
+			}
+
 			ew.printf("---\n\n")
 		}
 
@@ -79,18 +114,40 @@ func (m *MarkdownWriter) Write(w io.Writer, report *review.Report) error {
 	}
 
 	// Timing footer
-	ew.printf("*Reviewed in %dms (git: %dms, LLM: %dms)*\n",
-		report.Timing.TotalMs, report.Timing.GitMs, report.Timing.LLMMs)
+	if !m.NoTiming {
+		usage := ""
+		if u := report.Timing.Usage; u != nil {
+			usage = " · " + formatUsage(*u)
+		}
+		ew.printf("*Reviewed in %dms (git: %dms, LLM: %dms)%s*\n",
+			report.Timing.TotalMs, report.Timing.GitMs, report.Timing.LLMMs, usage)
+	}
 
 	return ew.err
 }
 
-func groupFindingsBySeverity(findings []review.Finding) map[review.Severity][]review.Finding {
-	m := make(map[review.Severity][]review.Finding)
-	for _, f := range findings {
-		m[f.Severity] = append(m[f.Severity], f)
+// mdTitle renders a finding title as one escaped markdown line.
+func mdTitle(f review.Finding) string {
+	return mdEscapeLine(strings.Join(strings.Fields(f.Title), " "))
+}
+
+// mdAnchor returns the stable anchor for a finding, derived from its ID so
+// links survive re-renders of the same report.
+func mdAnchor(f review.Finding) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(f.ID) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('-')
+		}
 	}
-	return m
+	return "prism-" + b.String()
+}
+
+// mdLinkText escapes square brackets so text cannot close a link label.
+func mdLinkText(s string) string {
+	return strings.NewReplacer("[", "\\[", "]", "\\]").Replace(s)
 }
 
 func mdPrimaryLocation(f review.Finding) review.Location {
@@ -100,26 +157,111 @@ func mdPrimaryLocation(f review.Finding) review.Location {
 	return review.Location{Path: "unknown"}
 }
 
-func mdFilePath(f review.Finding) string {
-	if len(f.Locations) > 0 {
-		return f.Locations[0].Path
+// mdFence returns a backtick code fence longer than any backtick run in
+// text, so the text cannot close the block early.
+func mdFence(text string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
 	}
-	return ""
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// severityLabel capitalizes a severity for a table row, e.g. "High".
+func severityLabel(s review.Severity) string {
+	if s == "" {
+		return ""
+	}
+	return strings.ToUpper(string(s[:1])) + string(s[1:])
 }
 
 func mdSeverityIcon(s review.Severity) string {
 	switch s {
+	case review.SeverityCritical:
+		return ":rotating_light:"
 	case review.SeverityHigh:
 		return ":red_circle:"
 	case review.SeverityMedium:
 		return ":orange_circle:"
 	case review.SeverityLow:
 		return ":yellow_circle:"
+	case review.SeverityInfo:
+		return ":large_blue_circle:"
 	default:
 		return ":white_circle:"
 	}
 }
 
+// mdEscapeLine makes model-written text safe on a single markdown line. It
+// escapes "|" outside code spans so the text cannot split a table row,
+// escapes a leading "#" so it cannot start a heading, and escapes an
+// unmatched final backtick so it cannot open a code span that swallows the
+// rest of the document.
+func mdEscapeLine(s string) string {
+	ticks := strings.Count(s, "`")
+	var b strings.Builder
+	if strings.HasPrefix(strings.TrimLeft(s, " "), "#") {
+		b.WriteString(s[:len(s)-len(strings.TrimLeft(s, " "))])
+		b.WriteString("\\")
+		s = strings.TrimLeft(s, " ")
+	}
+	inCode := false
+	seen := 0
+	for _, r := range s {
+		switch r {
+		case '`':
+			seen++
+			if ticks%2 == 1 && seen == ticks {
+				b.WriteString("\\`")
+				continue
+			}
+			inCode = !inCode
+		case '|':
+			if !inCode {
+				b.WriteString("\\|")
+				continue
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// mdEscapeText applies mdEscapeLine to each line of a multi-line message.
+func mdEscapeText(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = mdEscapeLine(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// mdCodeSpan wraps s in an inline code span, using a backtick fence longer
+// than any backtick run inside s so the span cannot be closed early.
+func mdCodeSpan(s string) string {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", longest+1)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
+
 func looksLikeCode(s string) bool {
 	codeIndicators := []string{
 		"func ", "if ", "for ", "return ", "var ", "const ",
@@ -2,6 +2,8 @@ package output
 
 import (
 	"bytes"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -35,6 +37,30 @@ func TestMarkdownWriter_Empty(t *testing.T) {
 	}
 }
 
+func TestMarkdownWriter_ReviewNote(t *testing.T) {
+	report := &review.Report{
+		Inputs:     review.InputInfo{Mode: "staged"},
+		Summary:    review.ComputeSummary(nil),
+		ReviewNote: "Checked error handling in a.go | b.go.",
+	}
+
+	var buf bytes.Buffer
+	if err := (&MarkdownWriter{}).Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "\n> Checked error handling in a.go \\| b.go.\n") {
+		t.Errorf("missing review note quote:\n%s", buf.String())
+	}
+
+	var text bytes.Buffer
+	if err := (&TextWriter{}).Write(&text, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if !strings.Contains(text.String(), "  Checked error handling in a.go | b.go.") {
+		t.Errorf("missing review note in text output:\n%s", text.String())
+	}
+}
+
 func TestMarkdownWriter_WithFindings(t *testing.T) {
 	findings := []review.Finding{
 		{
@@ -206,6 +232,47 @@ func TestMarkdownWriter_SuggestionNonCode(t *testing.T) {
 	}
 }
 
+func TestMarkdownWriter_Hunk(t *testing.T) {
+	report := &review.Report{
+		Tool:    "prism",
+		Version: "1.0",
+		Summary: review.ComputeSummary([]review.Finding{
+			{Severity: review.SeverityMedium},
+		}),
+		Findings: []review.Finding{
+			{
+				ID:         "x",
+				Severity:   review.SeverityMedium,
+				Category:   review.CategoryBug,
+				Title:      "Exit in library code",
+				Message:    "Do not call os.Exit from a library",
+				Confidence: 0.8,
+				Locations: []review.Location{
+					{Path: "a.go", Lines: review.LineRange{Start: 22, End: 22}},
+				},
+				Hunk: "@@ -20,2 +21,3 @@\n x := 1\n+os.Exit(x)",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := (&MarkdownWriter{}).Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "```diff\n@@ -20,2 +21,3 @@\n x := 1\n+os.Exit(x)\n```") {
+		t.Errorf("expected the hunk in a diff fence, got:\n%s", buf.String())
+	}
+}
+
+func TestMdFence(t *testing.T) {
+	if got := mdFence("x := 1"); got != "```" {
+		t.Errorf("mdFence = %q, want three backticks", got)
+	}
+	if got := mdFence("+// ```go\n+```` nested"); got != "`````" {
+		t.Errorf("mdFence = %q, want a fence longer than the text's longest run", got)
+	}
+}
+
 func TestLooksLikeCode(t *testing.T) {
 	tests := []struct {
 		input string
@@ -254,3 +321,242 @@ func TestMdSeverityIcon(t *testing.T) {
 		t.Error("Low severity should be yellow")
 	}
 }
+
+func TestMarkdownWriter_CriticalAndInfo(t *testing.T) {
+	findings := []review.Finding{
+		{Severity: review.SeverityCritical, Category: review.CategorySecurity, Title: "SQL injection",
+			Locations: []review.Location{{Path: "db.go", Lines: review.LineRange{Start: 3, End: 3}}}},
+		{Severity: review.SeverityInfo, Category: review.CategoryDocs, Title: "Note",
+			Locations: []review.Location{{Path: "db.go", Lines: review.LineRange{Start: 9, End: 9}}}},
+	}
+	report := &review.Report{Findings: findings, Summary: review.ComputeSummary(findings)}
+
+	var buf bytes.Buffer
+	if err := (&MarkdownWriter{}).Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"| Critical | 1    |", "| High     | 0    |", "| Info     | 1    |", "| **Total** | **2** |", ":rotating_light:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "SQL injection") > strings.Index(out, "Note") {
+		t.Error("critical findings should come before info findings")
+	}
+}
+
+func TestMarkdownWriter_CustomIcons(t *testing.T) {
+	report := &review.Report{
+		Summary: review.Summary{
+			Counts: review.SeverityCounts{High: 1, Medium: 1},
+		},
+		Findings: []review.Finding{
+			{Severity: review.SeverityHigh, Title: "H", Locations: []review.Location{{Path: "a.go"}}},
+			{Severity: review.SeverityMedium, Title: "M", Locations: []review.Location{{Path: "b.go"}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	w, err := GetWriterWithOptions("markdown", WriterOptions{Icons: map[review.Severity]string{
+		review.SeverityHigh: ":rotating_light:",
+	}})
+	if err != nil {
+		t.Fatalf("GetWriterWithOptions error: %v", err)
+	}
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "<summary>:rotating_light: HIGH (1)</summary>") {
+		t.Errorf("Output should use custom high icon, got:\n%s", out)
+	}
+	if !strings.Contains(out, "<summary>:orange_circle: MEDIUM (1)</summary>") {
+		t.Error("Medium should keep its default icon")
+	}
+}
+
+func TestMarkdownWriter_FileLevelFinding(t *testing.T) {
+	findings := []review.Finding{
+		{
+			Severity:   review.SeverityLow,
+			Category:   review.CategoryMaintainability,
+			Title:      "File is too large",
+			Message:    "Consider splitting this file",
+			Confidence: 0.7,
+			Locations:  []review.Location{{Path: "internal/big.go"}},
+		},
+	}
+	report := &review.Report{Findings: findings, Summary: review.ComputeSummary(findings)}
+
+	var buf bytes.Buffer
+	w := &MarkdownWriter{}
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "**`internal/big.go`**") {
+		t.Errorf("expected bare path for file-level finding, got:\n%s", out)
+	}
+	if strings.Contains(out, ":0-0") {
+		t.Errorf("file-level finding should not print :0-0, got:\n%s", out)
+	}
+}
+
+func TestMarkdownWriter_DiffStats(t *testing.T) {
+	report := &review.Report{
+		Stats:    review.DiffStats{FilesChanged: 1, Additions: 2, Deletions: 0},
+		Findings: []review.Finding{},
+	}
+
+	var buf bytes.Buffer
+	w := &MarkdownWriter{}
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "_1 file changed, +2 -0_") {
+		t.Errorf("expected diff stats under heading, got:\n%s", buf.String())
+	}
+}
+
+func TestMarkdownWriter_Verdict(t *testing.T) {
+	report := &review.Report{
+		Summary:  review.Summary{Verdict: review.VerdictBlock},
+		Findings: []review.Finding{},
+	}
+
+	var buf bytes.Buffer
+	w := &MarkdownWriter{}
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "**Verdict: BLOCK**") {
+		t.Errorf("expected verdict under heading, got:\n%s", buf.String())
+	}
+}
+
+func TestMarkdownWriter_EscapesTitleAndMessage(t *testing.T) {
+	report := &review.Report{
+		Summary: review.Summary{Counts: review.SeverityCounts{High: 1}},
+		Findings: []review.Finding{{
+			Severity: review.SeverityHigh,
+			Category: review.CategoryBug,
+			Title:    "Split on a | b breaks `parse(x | y)` and `cfg",
+			Message:  "# not a heading\nrows a | b",
+			Locations: []review.Location{
+				{Path: "a`b.go", Lines: review.LineRange{Start: 1, End: 1}},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	w := &MarkdownWriter{}
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"| High     | 1    |\n",
+		"### Split on a \\| b breaks `parse(x | y)` and \\`cfg\n",
+		"\\# not a heading\nrows a \\| b\n",
+		"**``a`b.go:1-1``**",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestMdCodeSpan(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"main.go:1", "`main.go:1`"},
+		{"a``b", "```a``b```"},
+		{"`x", "`` `x ``"},
+	}
+	for _, tt := range tests {
+		if got := mdCodeSpan(tt.in); got != tt.want {
+			t.Errorf("mdCodeSpan(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMarkdownWriter_NoTiming(t *testing.T) {
+	report := &review.Report{
+		Summary: review.Summary{Counts: review.SeverityCounts{Low: 1}},
+		Findings: []review.Finding{{
+			Severity:  review.SeverityLow,
+			Category:  review.CategoryStyle,
+			Title:     "Naming",
+			Locations: []review.Location{{Path: "a.go", Lines: review.LineRange{Start: 1, End: 1}}},
+		}},
+		Timing: review.Timing{TotalMs: 1234},
+	}
+
+	var buf bytes.Buffer
+	if err := (&MarkdownWriter{}).Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Reviewed in 1234ms") {
+		t.Errorf("expected timing footer by default, got:\n%s", buf.String())
+	}
+
+	w, err := GetWriterWithOptions("markdown", WriterOptions{NoTiming: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if strings.Contains(buf.String(), "Reviewed in") {
+		t.Errorf("timing footer should be omitted, got:\n%s", buf.String())
+	}
+}
+
+func TestMarkdownWriter_TOC(t *testing.T) {
+	report := &review.Report{
+		Summary: review.Summary{Counts: review.SeverityCounts{High: 1, Low: 2}},
+		Findings: []review.Finding{
+			{ID: "c3", Severity: review.SeverityLow, Title: "Naming [style]", Locations: []review.Location{{Path: "b.go", Lines: review.LineRange{Start: 2, End: 2}}}},
+			{ID: "a1", Severity: review.SeverityHigh, Title: "SQL injection", Locations: []review.Location{{Path: "db.go", Lines: review.LineRange{Start: 10, End: 12}}}},
+			{ID: "B2", Severity: review.SeverityLow, Title: "Typo", Locations: []review.Location{{Path: "a.go", Lines: review.LineRange{Start: 1, End: 1}}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := (&MarkdownWriter{TOC: true}).Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	out := buf.String()
+
+	linkRe := regexp.MustCompile(`(?m)^- \[.*\]\(#([a-z0-9-]+)\)`)
+	anchorRe := regexp.MustCompile(`<a id="([a-z0-9-]+)"></a>`)
+	var links, anchors []string
+	for _, m := range linkRe.FindAllStringSubmatch(out, -1) {
+		links = append(links, m[1])
+	}
+	for _, m := range anchorRe.FindAllStringSubmatch(out, -1) {
+		anchors = append(anchors, m[1])
+	}
+
+	want := []string{"prism-a1", "prism-b2", "prism-c3"}
+	if !reflect.DeepEqual(links, want) {
+		t.Errorf("TOC links = %v, want %v", links, want)
+	}
+	if !reflect.DeepEqual(anchors, links) {
+		t.Errorf("anchors %v do not match TOC links %v", anchors, links)
+	}
+	if !strings.Contains(out, `[Naming \[style\]](#prism-c3)`) {
+		t.Errorf("expected escaped link text, got:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := (&MarkdownWriter{}).Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if strings.Contains(buf.String(), "**Contents**") || strings.Contains(buf.String(), "<a id=") {
+		t.Error("TOC and anchors should be omitted by default")
+	}
+}
@@ -1,9 +1,10 @@
 package output
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
-	"os"
 
 	"github.com/dshills/prism/internal/review"
 )
@@ -13,40 +14,93 @@ type Writer interface {
 	Write(w io.Writer, report *review.Report) error
 }
 
+// WriterOptions customizes how writers render a report.
+type WriterOptions struct {
+	// Icons overrides the per-severity icon used by the text and markdown
+	// writers. Severities missing from the map keep the writer's default;
+	// an empty string disables the icon for that severity.
+	Icons map[review.Severity]string
+
+	// SARIFSuppressions marks matching SARIF results as suppressed.
+	SARIFSuppressions []SARIFSuppression
+
+	// NoTiming omits the timing footer from the text, markdown, and HTML
+	// writers
+	// so their output is deterministic.
+	NoTiming bool
+
+	// MarkdownTOC adds a table of contents with a link to each finding to
+	// markdown output.
+	MarkdownTOC bool
+
+	// TextTable renders text output as one aligned row per finding instead
+	// of the detailed view.
+	TextTable bool
+
+	// GroupBy sections text and markdown output by "severity" (the
+	// default), "category", "file", or "commit".
+	GroupBy string
+
+	// JSONFields reduces each finding in JSON output to these dotted field
+	// paths, such as "locations.path".
+	JSONFields []string
+}
+
 // GetWriter returns a writer for the specified format.
 func GetWriter(format string) (Writer, error) {
+	return GetWriterWithOptions(format, WriterOptions{})
+}
+
+// GetWriterWithOptions returns a writer for the specified format configured with opts.
+func GetWriterWithOptions(format string, opts WriterOptions) (Writer, error) {
 	switch format {
 	case "text":
-		return &TextWriter{}, nil
+		return &TextWriter{Icons: opts.Icons, NoTiming: opts.NoTiming, Table: opts.TextTable, GroupBy: opts.GroupBy}, nil
 	case "json":
-		return &JSONWriter{}, nil
+		return &JSONWriter{Fields: opts.JSONFields}, nil
 	case "markdown", "md":
-		return &MarkdownWriter{}, nil
+		return &MarkdownWriter{Icons: opts.Icons, NoTiming: opts.NoTiming, TOC: opts.MarkdownTOC, GroupBy: opts.GroupBy}, nil
 	case "sarif":
-		return &SARIFWriter{}, nil
+		return &SARIFWriter{Suppressions: opts.SARIFSuppressions}, nil
+	case "summary":
+		return &SummaryWriter{}, nil
+	case "html":
+		return &HTMLWriter{NoTiming: opts.NoTiming}, nil
+	case "junit":
+		return &JUnitWriter{}, nil
 	default:
 		return nil, fmt.Errorf("unsupported output format: %s", format)
 	}
 }
 
-// WriteReport writes the report to the specified output (file path or stdout).
+// WriteReport writes the report to the specified output: a file path, an
+// http(s) or s3 URL (see OpenSink), or stdout when outPath is empty.
 func WriteReport(report *review.Report, format, outPath string) error {
-	writer, err := GetWriter(format)
+	return WriteReportWithOptions(context.Background(), report, format, outPath, WriterOptions{})
+}
+
+// WriteReportWithOptions writes the report using a writer configured with
+// opts. An upload is cancelled with ctx.
+func WriteReportWithOptions(ctx context.Context, report *review.Report, format, outPath string, opts WriterOptions) error {
+	writer, err := GetWriterWithOptions(format, opts)
 	if err != nil {
 		return err
 	}
 
-	var w io.Writer
-	if outPath != "" {
-		f, err := os.Create(outPath)
-		if err != nil {
-			return fmt.Errorf("creating output file: %w", err)
-		}
-		defer f.Close()
-		w = f
-	} else {
-		w = os.Stdout
+	// Render fully before opening the destination so a rendering error
+	// never leaves a partial file or upload behind.
+	var buf bytes.Buffer
+	if err := writer.Write(&buf, report); err != nil {
+		return err
 	}
 
-	return writer.Write(w, report)
+	sink, err := OpenSink(ctx, outPath, contentTypeFor(format))
+	if err != nil {
+		return err
+	}
+	if _, err := buf.WriteTo(sink); err != nil {
+		sink.Close()
+		return err
+	}
+	return sink.Close()
 }
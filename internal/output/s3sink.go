@@ -0,0 +1,197 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// s3Sink buffers the report and uploads it to an S3 object with a PUT
+// signed with AWS Signature Version 4 on Close. Credentials and region come
+// from the standard AWS environment variables; AWS_ENDPOINT_URL_S3 (or
+// AWS_ENDPOINT_URL) points it at an S3-compatible store, addressed
+// path-style.
+type s3Sink struct {
+	ctx         context.Context
+	bucket, key string
+	contentType string
+	region      string
+	endpoint    string
+	creds       s3Credentials
+	buf         bytes.Buffer
+}
+
+type s3Credentials struct {
+	accessKey, secretKey, sessionToken string
+}
+
+// newS3Sink parses an s3://bucket/key destination and reads the AWS
+// settings from the environment.
+func newS3Sink(ctx context.Context, dest, contentType string) (*s3Sink, error) {
+	bucket, key, ok := strings.Cut(strings.TrimPrefix(dest, "s3://"), "/")
+	if !ok || bucket == "" || key == "" || strings.HasSuffix(key, "/") {
+		return nil, fmt.Errorf("invalid S3 destination %q: expected s3://bucket/key", dest)
+	}
+	creds := s3Credentials{
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if creds.accessKey == "" || creds.secretKey == "" {
+		return nil, fmt.Errorf("uploading to %s needs AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY", dest)
+	}
+	region := firstEnv("AWS_REGION", "AWS_DEFAULT_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &s3Sink{
+		ctx:         ctx,
+		bucket:      bucket,
+		key:         key,
+		contentType: contentType,
+		region:      region,
+		endpoint:    strings.TrimSuffix(firstEnv("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"), "/"),
+		creds:       creds,
+	}, nil
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func (s *s3Sink) Write(p []byte) (int, error) { return s.buf.Write(p) }
+
+// objectURL returns the URL of the object: virtual-hosted on AWS,
+// path-style on a custom endpoint.
+func (s *s3Sink) objectURL() string {
+	path := "/" + s3EscapePath(s.key)
+	if s.endpoint != "" {
+		return s.endpoint + "/" + s3EscapePath(s.bucket) + path
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com%s", s.bucket, s.region, path)
+}
+
+func (s *s3Sink) Close() error {
+	body := s.buf.Bytes()
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPut, s.objectURL(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating S3 upload request: %w", err)
+	}
+	if s.contentType != "" {
+		req.Header.Set("Content-Type", s.contentType)
+	}
+	signS3Request(req, body, s.creds, s.region, time.Now().UTC())
+
+	resp, err := uploadClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("uploading report to s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("uploading report to s3://%s/%s: status %d: %s", s.bucket, s.key, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// signS3Request adds the AWS Signature Version 4 headers for an S3 request
+// with the given body. Only the host, content type, and x-amz-* headers are
+// signed.
+func signS3Request(req *http.Request, body []byte, creds s3Credentials, region string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if creds.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	names := []string{"host"}
+	for _, name := range []string{"Content-Type", "X-Amz-Content-Sha256", "X-Amz-Date", "X-Amz-Security-Token"} {
+		if v := req.Header.Get(name); v != "" {
+			lower := strings.ToLower(name)
+			headers[lower] = strings.TrimSpace(v)
+			names = append(names, lower)
+		}
+	}
+	sort.Strings(names)
+	var canonHeaders strings.Builder
+	for _, name := range names {
+		canonHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+	signature := hex.EncodeToString(hmacSHA256(s3SigningKey(creds.secretKey, day, region, "s3"), stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.accessKey, scope, signedHeaders, signature))
+}
+
+// s3SigningKey derives the Signature Version 4 signing key for a day,
+// region, and service.
+func s3SigningKey(secret, day, region, service string) []byte {
+	k := hmacSHA256([]byte("AWS4"+secret), day)
+	k = hmacSHA256(k, region)
+	k = hmacSHA256(k, service)
+	return hmacSHA256(k, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+// s3EscapePath percent-encodes an object key the way Signature Version 4
+// expects: everything but unreserved characters, keeping the slashes.
+func s3EscapePath(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
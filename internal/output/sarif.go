@@ -10,10 +10,14 @@ import (
 )
 
 // SARIFWriter outputs findings in SARIF v2.1.0 format.
-type SARIFWriter struct{}
+type SARIFWriter struct {
+	// Suppressions marks matching results as suppressed instead of omitting
+	// them, so dashboards show them as known rather than new.
+	Suppressions []SARIFSuppression
+}
 
 func (s *SARIFWriter) Write(w io.Writer, report *review.Report) error {
-	sarif := buildSARIF(report)
+	sarif := buildSARIF(report, s.Suppressions)
 	data, err := json.MarshalIndent(sarif, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling SARIF: %w", err)
@@ -35,8 +39,9 @@ type sarifLog struct {
 }
 
 type sarifRun struct {
-	Tool    sarifTool     `json:"tool"`
-	Results []sarifResult `json:"results"`
+	Tool       sarifTool         `json:"tool"`
+	Results    []sarifResult     `json:"results"`
+	Properties map[string]string `json:"properties,omitempty"`
 }
 
 type sarifTool struct {
@@ -67,11 +72,19 @@ type sarifRuleProperties struct {
 }
 
 type sarifResult struct {
-	RuleID    string           `json:"ruleId"`
-	Level     string           `json:"level"`
-	Message   sarifMessage     `json:"message"`
-	Locations []sarifLocation  `json:"locations,omitempty"`
-	Fixes     []sarifFix       `json:"fixes,omitempty"`
+	RuleID              string             `json:"ruleId"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations,omitempty"`
+	Fixes               []sarifFix         `json:"fixes,omitempty"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints,omitempty"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status,omitempty"`
+	Justification string `json:"justification,omitempty"`
 }
 
 type sarifMessage struct {
@@ -84,7 +97,7 @@ type sarifLocation struct {
 
 type sarifPhysicalLocation struct {
 	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
-	Region           sarifRegion           `json:"region"`
+	Region           *sarifRegion          `json:"region,omitempty"` // nil for file-level findings
 }
 
 type sarifArtifactLocation struct {
@@ -100,7 +113,7 @@ type sarifFix struct {
 	Description sarifMessage `json:"description"`
 }
 
-func buildSARIF(report *review.Report) sarifLog {
+func buildSARIF(report *review.Report, suppressions []SARIFSuppression) sarifLog {
 	rulesMap := make(map[string]sarifRule)
 	var results []sarifResult
 
@@ -125,15 +138,17 @@ func buildSARIF(report *review.Report) sarifLog {
 		}
 
 		for _, loc := range f.Locations {
-			result.Locations = append(result.Locations, sarifLocation{
-				PhysicalLocation: sarifPhysicalLocation{
-					ArtifactLocation: sarifArtifactLocation{URI: loc.Path},
-					Region: sarifRegion{
-						StartLine: loc.Lines.Start,
-						EndLine:   loc.Lines.End,
-					},
-				},
-			})
+			phys := sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: loc.Path},
+			}
+			if !loc.Lines.FileLevel() {
+				end := loc.Lines.End
+				if end < loc.Lines.Start {
+					end = loc.Lines.Start
+				}
+				phys.Region = &sarifRegion{StartLine: loc.Lines.Start, EndLine: end}
+			}
+			result.Locations = append(result.Locations, sarifLocation{PhysicalLocation: phys})
 		}
 
 		if f.Suggestion != "" {
@@ -142,6 +157,18 @@ func buildSARIF(report *review.Report) sarifLog {
 			})
 		}
 
+		if f.StableKey != "" {
+			result.PartialFingerprints = map[string]string{stableKeyFingerprint: f.StableKey}
+		}
+
+		if sup, ok := matchSuppression(suppressions, ruleID, f); ok {
+			result.Suppressions = []sarifSuppression{{
+				Kind:          "external",
+				Status:        "accepted",
+				Justification: sup.Justification,
+			}}
+		}
+
 		results = append(results, result)
 	}
 
@@ -169,7 +196,8 @@ func buildSARIF(report *review.Report) sarifLog {
 						Rules:          rules,
 					},
 				},
-				Results: results,
+				Results:    results,
+				Properties: report.Metadata,
 			},
 		},
 	}
@@ -178,7 +206,7 @@ func buildSARIF(report *review.Report) sarifLog {
 // severityToLevel maps prism severity to SARIF level.
 func severityToLevel(s review.Severity) string {
 	switch s {
-	case review.SeverityHigh:
+	case review.SeverityCritical, review.SeverityHigh:
 		return "error"
 	case review.SeverityMedium:
 		return "warning"
@@ -3,6 +3,8 @@ package output
 import (
 	"bytes"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/dshills/prism/internal/review"
@@ -171,3 +173,136 @@ func TestGenerateRuleID_Different(t *testing.T) {
 		t.Error("Different findings should have different rule IDs")
 	}
 }
+
+func TestSARIFWriter_Metadata(t *testing.T) {
+	report := &review.Report{
+		Tool:     "prism",
+		Version:  "1.0",
+		Findings: []review.Finding{},
+		Metadata: map[string]string{"env": "staging", "ticket": "PRJ-42"},
+	}
+
+	var buf bytes.Buffer
+	w := &SARIFWriter{}
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+
+	var sarif sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &sarif); err != nil {
+		t.Fatalf("Invalid SARIF JSON: %v", err)
+	}
+	props := sarif.Runs[0].Properties
+	if props["env"] != "staging" || props["ticket"] != "PRJ-42" {
+		t.Errorf("run properties = %v, want metadata", props)
+	}
+}
+
+func TestSARIFWriter_Suppressions(t *testing.T) {
+	byRule := review.Finding{
+		ID: "aaa", StableKey: "key-a", Severity: review.SeverityHigh, Category: review.CategorySecurity,
+		Title: "SQL injection", Locations: []review.Location{{Path: "db.go", Lines: review.LineRange{Start: 1, End: 1}}},
+	}
+	byFingerprint := review.Finding{
+		ID: "bbb", StableKey: "key-b", Severity: review.SeverityMedium, Category: review.CategoryBug,
+		Title: "Nil map write", Locations: []review.Location{{Path: "m.go", Lines: review.LineRange{Start: 2, End: 2}}},
+	}
+	unsuppressed := review.Finding{
+		ID: "ccc", StableKey: "key-c", Severity: review.SeverityLow, Category: review.CategoryStyle,
+		Title: "Naming", Locations: []review.Location{{Path: "n.go", Lines: review.LineRange{Start: 3, End: 3}}},
+	}
+	report := &review.Report{
+		Tool:     "prism",
+		Version:  "1.0",
+		Findings: []review.Finding{byRule, byFingerprint, unsuppressed},
+	}
+
+	var buf bytes.Buffer
+	w := &SARIFWriter{Suppressions: []SARIFSuppression{
+		{RuleID: generateRuleID(byRule), Justification: "accepted risk"},
+		{Fingerprint: "key-b"},
+	}}
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+
+	var sarif sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &sarif); err != nil {
+		t.Fatalf("Invalid SARIF JSON: %v", err)
+	}
+	results := sarif.Runs[0].Results
+	if len(results) != 3 {
+		t.Fatalf("Results count = %d, want 3 (suppressed results are kept)", len(results))
+	}
+	if len(results[0].Suppressions) != 1 || results[0].Suppressions[0].Kind != "external" {
+		t.Errorf("Results[0] should carry an external suppression, got %+v", results[0].Suppressions)
+	}
+	if results[0].Suppressions[0].Justification != "accepted risk" {
+		t.Errorf("Justification = %q, want %q", results[0].Suppressions[0].Justification, "accepted risk")
+	}
+	if len(results[1].Suppressions) != 1 {
+		t.Errorf("Results[1] should be suppressed by fingerprint, got %+v", results[1].Suppressions)
+	}
+	if len(results[2].Suppressions) != 0 {
+		t.Errorf("Results[2] should not be suppressed, got %+v", results[2].Suppressions)
+	}
+	if results[1].PartialFingerprints[stableKeyFingerprint] != "key-b" {
+		t.Errorf("partialFingerprints = %v, want StableKey", results[1].PartialFingerprints)
+	}
+}
+
+func TestLoadSARIFSuppressions(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	os.WriteFile(good, []byte(`[{"ruleId":"prism/security/deadbeef"},{"fingerprint":"abc","justification":"known"}]`), 0o644)
+	sups, err := LoadSARIFSuppressions(good)
+	if err != nil {
+		t.Fatalf("LoadSARIFSuppressions error: %v", err)
+	}
+	if len(sups) != 2 || sups[1].Justification != "known" {
+		t.Errorf("unexpected suppressions: %+v", sups)
+	}
+
+	empty := filepath.Join(dir, "empty-entry.json")
+	os.WriteFile(empty, []byte(`[{"justification":"no target"}]`), 0o644)
+	if _, err := LoadSARIFSuppressions(empty); err == nil {
+		t.Error("expected error for entry without ruleId or fingerprint")
+	}
+
+	if _, err := LoadSARIFSuppressions(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestSARIFWriter_FileLevelFindingOmitsRegion(t *testing.T) {
+	report := &review.Report{
+		Tool:    "prism",
+		Version: "1.0",
+		Findings: []review.Finding{
+			{
+				Severity:  review.SeverityLow,
+				Category:  review.CategoryMaintainability,
+				Title:     "File is too large",
+				Locations: []review.Location{{Path: "internal/big.go"}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	w := &SARIFWriter{}
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte(`"region"`)) {
+		t.Errorf("file-level finding should omit region, got:\n%s", buf.String())
+	}
+
+	var sarif sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &sarif); err != nil {
+		t.Fatalf("Invalid SARIF JSON: %v", err)
+	}
+	loc := sarif.Runs[0].Results[0].Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "internal/big.go" {
+		t.Errorf("URI = %q, want %q", loc.ArtifactLocation.URI, "internal/big.go")
+	}
+}
@@ -0,0 +1,99 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Sink is a report destination. Writes may be buffered; Close completes the
+// delivery (closing the file or sending the upload) and reports its error.
+type Sink interface {
+	io.Writer
+	Close() error
+}
+
+// OpenSink resolves an output destination:
+//   - ""                    → stdout
+//   - http:// or https://   → the rendered report is POSTed to the URL on Close
+//   - s3://bucket/key       → the rendered report is uploaded to S3 on Close
+//   - anything else         → a file path, created or truncated
+//
+// contentType is sent with uploads, which are cancelled with ctx.
+func OpenSink(ctx context.Context, dest, contentType string) (Sink, error) {
+	switch {
+	case dest == "":
+		return nopCloser{os.Stdout}, nil
+	case strings.HasPrefix(dest, "http://"), strings.HasPrefix(dest, "https://"):
+		return &httpSink{ctx: ctx, url: dest, contentType: contentType}, nil
+	case strings.HasPrefix(dest, "s3://"):
+		return newS3Sink(ctx, dest, contentType)
+	default:
+		f, err := os.Create(dest)
+		if err != nil {
+			return nil, fmt.Errorf("creating output file: %w", err)
+		}
+		return f, nil
+	}
+}
+
+// uploadClient sends report uploads. Its timeout bounds an upload even when
+// the caller's context has no deadline.
+var uploadClient = &http.Client{Timeout: 60 * time.Second}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// httpSink buffers the report and POSTs it on Close.
+type httpSink struct {
+	ctx         context.Context
+	url         string
+	contentType string
+	buf         bytes.Buffer
+}
+
+func (s *httpSink) Write(p []byte) (int, error) { return s.buf.Write(p) }
+
+func (s *httpSink) Close() error {
+	req, err := http.NewRequestWithContext(s.ctx, "POST", s.url, &s.buf)
+	if err != nil {
+		return fmt.Errorf("creating upload request: %w", err)
+	}
+	if s.contentType != "" {
+		req.Header.Set("Content-Type", s.contentType)
+	}
+	resp, err := uploadClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("uploading report: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("uploading report: %s returned status %d: %s", s.url, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// contentTypeFor returns the MIME type of a rendered report format.
+func contentTypeFor(format string) string {
+	switch format {
+	case "json":
+		return "application/json"
+	case "sarif":
+		return "application/sarif+json"
+	case "markdown", "md":
+		return "text/markdown; charset=utf-8"
+	case "html":
+		return "text/html; charset=utf-8"
+	case "junit":
+		return "application/xml"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
@@ -0,0 +1,149 @@
+package output
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+func TestWriteReport_HTTPSink(t *testing.T) {
+	var gotBody, gotType, gotMethod string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		gotBody = string(data)
+		gotType = r.Header.Get("Content-Type")
+		gotMethod = r.Method
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	report := &review.Report{Tool: "prism", Findings: []review.Finding{}}
+	if err := WriteReport(report, "json", server.URL+"/reports"); err != nil {
+		t.Fatalf("WriteReport error: %v", err)
+	}
+	if gotMethod != "POST" {
+		t.Errorf("method = %q, want POST", gotMethod)
+	}
+	if gotType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", gotType)
+	}
+	if !strings.Contains(gotBody, `"tool": "prism"`) {
+		t.Errorf("body is not the rendered report: %s", gotBody)
+	}
+}
+
+func TestWriteReport_HTTPSinkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "quota exceeded", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := WriteReport(&review.Report{Tool: "prism"}, "text", server.URL)
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("expected an upload error with the status code, got %v", err)
+	}
+}
+
+func TestOpenSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.txt")
+	sink, err := OpenSink(context.Background(), path, "text/plain")
+	if err != nil {
+		t.Fatalf("OpenSink(file) error: %v", err)
+	}
+	io.WriteString(sink, "hello")
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "hello" {
+		t.Errorf("file contents = %q, %v; want %q", data, err, "hello")
+	}
+}
+
+func TestWriteReport_HTTPSinkCancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := WriteReportWithOptions(ctx, &review.Report{Tool: "prism"}, "json", server.URL, WriterOptions{})
+	if err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Errorf("expected the upload to stop with the context, got %v", err)
+	}
+}
+
+func TestWriteReport_S3Sink(t *testing.T) {
+	var gotPath, gotMethod, gotBody, gotAuth, gotHash, gotToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		gotPath, gotMethod, gotBody = r.URL.EscapedPath(), r.Method, string(data)
+		gotAuth = r.Header.Get("Authorization")
+		gotHash = r.Header.Get("X-Amz-Content-Sha256")
+		gotToken = r.Header.Get("X-Amz-Security-Token")
+	}))
+	defer server.Close()
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "token")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ENDPOINT_URL_S3", server.URL)
+
+	report := &review.Report{Tool: "prism", Findings: []review.Finding{}}
+	if err := WriteReport(report, "json", "s3://ci-reports/prism/run 1.json"); err != nil {
+		t.Fatalf("WriteReport error: %v", err)
+	}
+	if gotMethod != "PUT" || gotPath != "/ci-reports/prism/run%201.json" {
+		t.Errorf("request = %s %s, want PUT /ci-reports/prism/run%%201.json", gotMethod, gotPath)
+	}
+	if !strings.Contains(gotBody, `"tool": "prism"`) {
+		t.Errorf("body is not the rendered report: %s", gotBody)
+	}
+	sum := sha256.Sum256([]byte(gotBody))
+	if gotHash != hex.EncodeToString(sum[:]) {
+		t.Errorf("X-Amz-Content-Sha256 = %q, want the body hash", gotHash)
+	}
+	if gotToken != "token" {
+		t.Errorf("X-Amz-Security-Token = %q, want the session token", gotToken)
+	}
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+		!strings.Contains(gotAuth, "/eu-west-1/s3/aws4_request, SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date;x-amz-security-token, Signature=") {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+}
+
+func TestOpenSink_S3Errors(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	if _, err := OpenSink(context.Background(), "s3://bucket/report.json", "application/json"); err == nil || !strings.Contains(err.Error(), "AWS_ACCESS_KEY_ID") {
+		t.Errorf("missing credentials: err = %v", err)
+	}
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	for _, dest := range []string{"s3://bucket", "s3://bucket/", "s3:///key", "s3://bucket/dir/"} {
+		if _, err := OpenSink(context.Background(), dest, ""); err == nil {
+			t.Errorf("OpenSink(%q) should fail", dest)
+		}
+	}
+}
+
+func TestS3SigningKey(t *testing.T) {
+	// Example from the AWS Signature Version 4 documentation
+	key := s3SigningKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	if got := hex.EncodeToString(key); got != "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d" {
+		t.Errorf("signing key = %s", got)
+	}
+}
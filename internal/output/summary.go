@@ -0,0 +1,17 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+// SummaryWriter outputs only the one-line summary from review.SummaryLine,
+// for chat notifications and scripts.
+type SummaryWriter struct{}
+
+func (s *SummaryWriter) Write(w io.Writer, report *review.Report) error {
+	_, err := fmt.Fprintln(w, review.SummaryLine(report))
+	return err
+}
@@ -0,0 +1,28 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+func TestSummaryWriter(t *testing.T) {
+	report := &review.Report{
+		Summary: review.Summary{Counts: review.SeverityCounts{High: 2, Medium: 5, Low: 1}},
+		Stats:   review.DiffStats{FilesChanged: 8},
+	}
+
+	w, err := GetWriter("summary")
+	if err != nil {
+		t.Fatalf("GetWriter error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	want := "prism: 2 high, 5 medium, 1 low in 8 files\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,59 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dshills/prism/internal/review"
+)
+
+// stableKeyFingerprint is the partialFingerprints key under which SARIF
+// results carry the finding's line-independent StableKey.
+const stableKeyFingerprint = "prismStableKey/v1"
+
+// SARIFSuppression marks results as suppressed in SARIF output. An entry
+// matches a result by SARIF rule ID, or by fingerprint (the finding's
+// StableKey or ID).
+type SARIFSuppression struct {
+	RuleID        string `json:"ruleId,omitempty"`
+	Fingerprint   string `json:"fingerprint,omitempty"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// LoadSARIFSuppressions reads a JSON array of suppression entries from path.
+func LoadSARIFSuppressions(path string) ([]SARIFSuppression, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading suppressions file: %w", err)
+	}
+	var sups []SARIFSuppression
+	if err := json.Unmarshal(data, &sups); err != nil {
+		return nil, fmt.Errorf("invalid suppressions file %s: %w", path, err)
+	}
+	for i, s := range sups {
+		if s.RuleID == "" && s.Fingerprint == "" {
+			return nil, fmt.Errorf("suppression %d: ruleId or fingerprint is required", i)
+		}
+	}
+	return sups, nil
+}
+
+// matchSuppression returns the first suppression entry matching the result.
+func matchSuppression(sups []SARIFSuppression, ruleID string, f review.Finding) (SARIFSuppression, bool) {
+	for _, s := range sups {
+		if s.RuleID != "" && s.RuleID == ruleID {
+			return s, true
+		}
+		if s.Fingerprint != "" && (s.Fingerprint == f.StableKey || s.Fingerprint == f.ID) {
+			return s, true
+		}
+	}
+	return SARIFSuppression{}, false
+}
+
+// Suppressed reports whether an entry in sups matches f.
+func Suppressed(sups []SARIFSuppression, f review.Finding) bool {
+	_, ok := matchSuppression(sups, generateRuleID(f), f)
+	return ok
+}
@@ -3,72 +3,119 @@ package output
 import (
 	"fmt"
 	"io"
-	"sort"
 	"strings"
+	"text/tabwriter"
 
+	"github.com/dshills/prism/internal/gitctx"
+	"github.com/dshills/prism/internal/providers"
 	"github.com/dshills/prism/internal/review"
 )
 
 // TextWriter outputs a human-readable text report.
-type TextWriter struct{}
+type TextWriter struct {
+	Icons    map[review.Severity]string // nil = default icons
+	NoTiming bool                       // omit the "Completed in" footer
+	Table    bool                       // one aligned row per finding instead of the detailed view
+	GroupBy  string                     // severity (default), category, file, or commit
+}
 
 func (t *TextWriter) Write(w io.Writer, report *review.Report) error {
 	ew := &errWriter{w: w}
 
 	// Summary header
-	total := report.Summary.Counts.High + report.Summary.Counts.Medium + report.Summary.Counts.Low
+	total := report.Summary.Counts.Total()
 	ew.printf("Prism Code Review — %s mode\n", report.Inputs.Mode)
 	if report.Inputs.Range != "" {
 		ew.printf("Range: %s\n", report.Inputs.Range)
 	}
-	ew.printf("Repository: %s (branch: %s)\n", report.Repo.Root, report.Repo.Branch)
+	if report.Repo.Detached {
+		ew.printf("Repository: %s (detached HEAD at %s)\n", report.Repo.Root, gitctx.ShortCommit(report.Repo.Head))
+	} else {
+		ew.printf("Repository: %s (branch: %s)\n", report.Repo.Root, report.Repo.Branch)
+	}
+	if report.Stats.FilesChanged > 0 {
+		ew.printf("Changes: %s\n", formatStats(report.Stats))
+	}
 	ew.println(strings.Repeat("─", 60))
 	ew.printf("Findings: %d total", total)
 	if total > 0 {
-		ew.printf(" (%d high, %d medium, %d low)",
-			report.Summary.Counts.High,
-			report.Summary.Counts.Medium,
-			report.Summary.Counts.Low,
-		)
+		ew.printf(" (%s)", report.Summary.Counts.Breakdown())
 	}
 	ew.println("")
+	if report.Summary.Verdict != "" {
+		ew.printf("Verdict: %s\n", strings.ToUpper(report.Summary.Verdict))
+	}
 	ew.println(strings.Repeat("─", 60))
 
 	if total == 0 {
 		ew.println("\nNo issues found. Looks good!")
+		if report.ReviewNote != "" {
+			ew.println("")
+			for _, line := range wrapText(report.ReviewNote, 70) {
+				ew.printf("  %s\n", line)
+			}
+		}
 		return ew.err
 	}
 
-	// Group by severity (high first), then by file
-	grouped := groupBySeverity(report.Findings)
-	for _, sev := range []review.Severity{review.SeverityHigh, review.SeverityMedium, review.SeverityLow} {
-		findings := grouped[sev]
-		if len(findings) == 0 {
-			continue
+	if t.Table {
+		writeTable(ew, report.Findings, t.GroupBy)
+	} else {
+		t.writeDetailed(ew, report.Findings)
+	}
+
+	if !t.NoTiming {
+		ew.printf("\n%s\n", strings.Repeat("─", 60))
+		ew.printf("Completed in %dms (git: %dms, LLM: %dms)\n",
+			report.Timing.TotalMs, report.Timing.GitMs, report.Timing.LLMMs)
+		if report.Timing.Retries > 0 {
+			ew.printf("Provider retries: %d\n", report.Timing.Retries)
+		}
+		if u := report.Timing.Usage; u != nil {
+			ew.printf("Usage: %s\n", formatUsage(*u))
 		}
+	}
 
-		label := strings.ToUpper(string(sev))
-		ew.printf("\n%s %s\n", severityIcon(sev), label)
-		ew.println(strings.Repeat("─", 40))
+	return ew.err
+}
+
+// WriteFinding prints a one-line progress row for a finding that has just
+// arrived from a streaming review: icon, location, and title.
+func (t *TextWriter) WriteFinding(w io.Writer, f review.Finding) error {
+	_, err := fmt.Fprintf(w, "%s %s  %s\n", resolveIcon(t.Icons, f.Severity, severityIcon),
+		formatLocation(primaryLocation(f)), tableCell(f.Title))
+	return err
+}
 
-		// Sort by file path within severity
-		sort.Slice(findings, func(i, j int) bool {
-			pi := filePath(findings[i])
-			pj := filePath(findings[j])
-			return pi < pj
-		})
+// writeDetailed prints each finding with its message and suggestion,
+// grouped by severity unless GroupBy selects category, file, or commit
+// sections.
+// Section headings carry the icon of their most severe finding.
+func (t *TextWriter) writeDetailed(ew *errWriter, all []review.Finding) {
+	bySeverity := t.GroupBy == "" || t.GroupBy == GroupBySeverity
+	for _, g := range groupFindings(all, t.GroupBy) {
+		ew.printf("\n%s\n", withIcon(resolveIcon(t.Icons, g.severity, severityIcon), g.label))
+		ew.println(strings.Repeat("─", 40))
 
-		for _, f := range findings {
+		for _, f := range g.findings {
 			loc := primaryLocation(f)
 			if loc.Commit != "" {
-				ew.printf("\n  %s:%d-%d (%s)  %s\n",
-					loc.Path, loc.Lines.Start, loc.Lines.End, loc.Commit, f.Title)
+				ew.printf("\n  %s (%s)  %s\n",
+					formatLocation(loc), loc.Commit, f.Title)
 			} else {
-				ew.printf("\n  %s:%d-%d  %s\n",
-					loc.Path, loc.Lines.Start, loc.Lines.End, f.Title)
+				ew.printf("\n  %s  %s\n",
+					formatLocation(loc), f.Title)
+			}
+			if bySeverity {
+				ew.printf("  Category: %s | Confidence: %.0f%%\n",
+					f.Category, f.Confidence*100)
+			} else {
+				ew.printf("  Severity: %s | Category: %s | Confidence: %.0f%%\n",
+					f.Severity, f.Category, f.Confidence*100)
+			}
+			if label := review.BaselineLabel(f); label != "" {
+				ew.printf("  Baseline: %s\n", label)
 			}
-			ew.printf("  Category: %s | Confidence: %.0f%%\n",
-				f.Category, f.Confidence*100)
 
 			// Message (indented, wrapped)
 			for _, line := range wrapText(f.Message, 70) {
@@ -84,12 +131,27 @@ func (t *TextWriter) Write(w io.Writer, report *review.Report) error {
 			}
 		}
 	}
+}
 
-	ew.printf("\n%s\n", strings.Repeat("─", 60))
-	ew.printf("Completed in %dms (git: %dms, LLM: %dms)\n",
-		report.Timing.TotalMs, report.Timing.GitMs, report.Timing.LLMMs)
+// writeTable prints one aligned row per finding: severity, location,
+// category, and title, ordered like the detailed view for groupBy.
+func writeTable(ew *errWriter, all []review.Finding, groupBy string) {
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\nSEVERITY\tLOCATION\tCATEGORY\tTITLE")
+	for _, g := range groupFindings(all, groupBy) {
+		for _, f := range g.findings {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", strings.ToUpper(string(f.Severity)),
+				formatLocation(primaryLocation(f)), tableCell(string(f.Category)), tableCell(f.Title))
+		}
+	}
+	tw.Flush()
+	ew.printf("%s", b.String())
+}
 
-	return ew.err
+// tableCell flattens s onto one line so it cannot break the table layout.
+func tableCell(s string) string {
+	return strings.Join(strings.Fields(s), " ")
 }
 
 // errWriter wraps an io.Writer and captures the first error.
@@ -112,14 +174,6 @@ func (ew *errWriter) println(s string) {
 	_, ew.err = fmt.Fprintln(ew.w, s)
 }
 
-func groupBySeverity(findings []review.Finding) map[review.Severity][]review.Finding {
-	m := make(map[review.Severity][]review.Finding)
-	for _, f := range findings {
-		m[f.Severity] = append(m[f.Severity], f)
-	}
-	return m
-}
-
 func primaryLocation(f review.Finding) review.Location {
 	if len(f.Locations) > 0 {
 		return f.Locations[0]
@@ -136,17 +190,69 @@ func filePath(f review.Finding) string {
 
 func severityIcon(s review.Severity) string {
 	switch s {
+	case review.SeverityCritical:
+		return "[!!!]"
 	case review.SeverityHigh:
 		return "[!!]"
 	case review.SeverityMedium:
 		return "[!]"
 	case review.SeverityLow:
 		return "[-]"
+	case review.SeverityInfo:
+		return "[i]"
 	default:
 		return "[?]"
 	}
 }
 
+// resolveIcon returns the configured icon for s, falling back to def when
+// the severity has no override.
+func resolveIcon(icons map[review.Severity]string, s review.Severity, def func(review.Severity) string) string {
+	if icon, ok := icons[s]; ok {
+		return icon
+	}
+	return def(s)
+}
+
+// formatStats renders diff stats as "3 files changed, +120 -45".
+func formatStats(st review.DiffStats) string {
+	files := "files"
+	if st.FilesChanged == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("%d %s changed, +%d -%d", st.FilesChanged, files, st.Additions, st.Deletions)
+}
+
+// formatUsage renders token usage and estimated cost as
+// "12345 tokens, ~$0.0421".
+func formatUsage(u providers.Usage) string {
+	switch {
+	case u.UnpricedCalls == 0:
+		return fmt.Sprintf("%d tokens, ~$%.4f", u.TotalTokens, u.CostUSD)
+	case u.UnpricedCalls == u.Calls:
+		return fmt.Sprintf("%d tokens, cost unknown", u.TotalTokens)
+	default:
+		return fmt.Sprintf("%d tokens, ~$%.4f plus %d unpriced call(s)", u.TotalTokens, u.CostUSD, u.UnpricedCalls)
+	}
+}
+
+// formatLocation renders a location as "path:start-end", or just "path" for
+// file-level findings.
+func formatLocation(loc review.Location) string {
+	if loc.Lines.FileLevel() {
+		return loc.Path
+	}
+	return fmt.Sprintf("%s:%d-%d", loc.Path, loc.Lines.Start, loc.Lines.End)
+}
+
+// withIcon prefixes label with icon, omitting the separator when icon is empty.
+func withIcon(icon, label string) string {
+	if icon == "" {
+		return label
+	}
+	return icon + " " + label
+}
+
 func wrapText(text string, width int) []string {
 	if len(text) <= width {
 		return []string{text}
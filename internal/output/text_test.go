@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/dshills/prism/internal/providers"
 	"github.com/dshills/prism/internal/review"
 )
 
@@ -157,3 +158,265 @@ func TestTextWriter_WithFindings(t *testing.T) {
 		t.Error("Output should have LOW section")
 	}
 }
+
+func TestTextWriter_CustomIcons(t *testing.T) {
+	report := &review.Report{
+		Inputs: review.InputInfo{Mode: "staged"},
+		Summary: review.Summary{
+			Counts: review.SeverityCounts{High: 1, Low: 1},
+		},
+		Findings: []review.Finding{
+			{Severity: review.SeverityHigh, Title: "H", Locations: []review.Location{{Path: "a.go"}}},
+			{Severity: review.SeverityLow, Title: "L", Locations: []review.Location{{Path: "b.go"}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	w := &TextWriter{Icons: map[review.Severity]string{
+		review.SeverityHigh: "(X)",
+		review.SeverityLow:  "",
+	}}
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "\n(X) HIGH\n") {
+		t.Errorf("Output should use custom high icon, got:\n%s", out)
+	}
+	if !strings.Contains(out, "\nLOW\n") {
+		t.Errorf("Output should omit the low icon when configured empty, got:\n%s", out)
+	}
+	if strings.Contains(out, "[!!]") {
+		t.Error("Default high icon should be replaced")
+	}
+}
+
+func TestTextWriter_FileLevelFinding(t *testing.T) {
+	findings := []review.Finding{
+		{
+			Severity:   review.SeverityMedium,
+			Category:   review.CategoryMaintainability,
+			Title:      "Package mixes transport and storage concerns",
+			Message:    "Split the handlers from the persistence code",
+			Confidence: 0.8,
+			Locations:  []review.Location{{Path: "server/api.go"}},
+		},
+	}
+	report := &review.Report{Findings: findings, Summary: review.ComputeSummary(findings)}
+
+	var buf bytes.Buffer
+	w := &TextWriter{}
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "server/api.go  Package mixes") {
+		t.Errorf("expected bare path for file-level finding, got:\n%s", out)
+	}
+	if strings.Contains(out, ":0-0") {
+		t.Errorf("file-level finding should not print :0-0, got:\n%s", out)
+	}
+}
+
+func TestTextWriter_DiffStats(t *testing.T) {
+	report := &review.Report{
+		Inputs:   review.InputInfo{Mode: "staged"},
+		Stats:    review.DiffStats{FilesChanged: 3, Additions: 120, Deletions: 45},
+		Findings: []review.Finding{},
+	}
+
+	var buf bytes.Buffer
+	w := &TextWriter{}
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Changes: 3 files changed, +120 -45") {
+		t.Errorf("expected diff stats in header, got:\n%s", buf.String())
+	}
+}
+
+func TestTextWriter_Verdict(t *testing.T) {
+	report := &review.Report{
+		Inputs:   review.InputInfo{Mode: "staged"},
+		Summary:  review.Summary{Verdict: review.VerdictReviewNeeded},
+		Findings: []review.Finding{},
+	}
+
+	var buf bytes.Buffer
+	w := &TextWriter{}
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Verdict: REVIEW-NEEDED") {
+		t.Errorf("expected verdict in header, got:\n%s", buf.String())
+	}
+}
+
+func TestTextWriter_NoTiming(t *testing.T) {
+	report := &review.Report{
+		Findings: []review.Finding{},
+		Timing:   review.Timing{TotalMs: 1234},
+	}
+
+	w, err := GetWriterWithOptions("text", WriterOptions{NoTiming: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if strings.Contains(buf.String(), "Completed in") {
+		t.Errorf("timing footer should be omitted, got:\n%s", buf.String())
+	}
+}
+
+func TestTextWriter_BaselineLabel(t *testing.T) {
+	report := &review.Report{
+		Tool: "prism",
+		Findings: []review.Finding{{
+			Severity:         review.SeverityHigh,
+			Category:         review.CategoryBug,
+			Title:            "Unchecked error",
+			Locations:        []review.Location{{Path: "main.go", Lines: review.LineRange{Start: 3, End: 3}}},
+			Baseline:         review.BaselineEscalated,
+			PreviousSeverity: review.SeverityMedium,
+		}},
+	}
+	report.Summary = review.ComputeSummary(report.Findings)
+
+	var buf bytes.Buffer
+	if err := (&TextWriter{}).Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Baseline: escalated from medium to high") {
+		t.Errorf("output should label the escalation:\n%s", buf.String())
+	}
+}
+
+func TestTextWriter_Table(t *testing.T) {
+	report := &review.Report{
+		Tool: "prism",
+		Findings: []review.Finding{
+			{
+				Severity:  review.SeverityLow,
+				Category:  review.CategoryStyle,
+				Title:     "Long line",
+				Message:   "should not appear in the table",
+				Locations: []review.Location{{Path: "util.go", Lines: review.LineRange{Start: 5, End: 5}}},
+			},
+			{
+				Severity:  review.SeverityHigh,
+				Category:  review.CategorySecurity,
+				Title:     "SQL built\nfrom input",
+				Locations: []review.Location{{Path: "internal/db/query.go", Lines: review.LineRange{Start: 120, End: 124}}},
+			},
+		},
+	}
+	report.Summary = review.ComputeSummary(report.Findings)
+
+	w, err := GetWriterWithOptions("text", WriterOptions{TextTable: true, NoTiming: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := w.Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "should not appear") {
+		t.Errorf("table mode should omit messages:\n%s", out)
+	}
+
+	var rows []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "SEVERITY") || strings.HasPrefix(line, "HIGH") || strings.HasPrefix(line, "LOW") {
+			rows = append(rows, line)
+		}
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d:\n%s", len(rows), out)
+	}
+	if !strings.HasPrefix(rows[1], "HIGH") || !strings.HasPrefix(rows[2], "LOW") {
+		t.Errorf("rows should be ordered high first:\n%s", out)
+	}
+	if !strings.Contains(rows[1], "SQL built from input") {
+		t.Errorf("multi-line title should be flattened:\n%s", rows[1])
+	}
+	for _, col := range []struct{ header, value1, value2 string }{
+		{"LOCATION", "internal/db/query.go", "util.go"},
+		{"CATEGORY", "security", "style"},
+		{"TITLE", "SQL built", "Long line"},
+	} {
+		want := strings.Index(rows[0], col.header)
+		if strings.Index(rows[1], col.value1) != want || strings.Index(rows[2], col.value2) != want {
+			t.Errorf("column %s not aligned at %d:\n%s", col.header, want, strings.Join(rows, "\n"))
+		}
+	}
+}
+
+func TestTextWriter_DetachedHead(t *testing.T) {
+	report := &review.Report{
+		Tool:     "prism",
+		Repo:     review.RepoInfo{Root: "/tmp/repo", Head: "0123456789abcdef", Detached: true},
+		Findings: []review.Finding{},
+	}
+
+	var buf bytes.Buffer
+	if err := (&TextWriter{NoTiming: true}).Write(&buf, report); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Repository: /tmp/repo (detached HEAD at 0123456)") {
+		t.Errorf("output should note the detached HEAD:\n%s", buf.String())
+	}
+}
+
+func TestTextWriter_WriteFinding(t *testing.T) {
+	f := review.Finding{
+		Severity:  review.SeverityHigh,
+		Title:     "Nil\nderef",
+		Locations: []review.Location{{Path: "a.go", Lines: review.LineRange{Start: 3, End: 3}}},
+	}
+	var buf bytes.Buffer
+	tw := &TextWriter{Icons: map[review.Severity]string{review.SeverityHigh: "H"}}
+	if err := tw.WriteFinding(&buf, f); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "H a.go:3-3  Nil deref\n" {
+		t.Errorf("WriteFinding = %q", got)
+	}
+}
+
+func TestTextWriter_Usage(t *testing.T) {
+	findings := []review.Finding{{
+		Severity:  review.SeverityLow,
+		Category:  review.CategoryStyle,
+		Title:     "Naming",
+		Locations: []review.Location{{Path: "a.go", Lines: review.LineRange{Start: 1, End: 1}}},
+	}}
+	report := &review.Report{
+		Inputs:   review.InputInfo{Mode: "staged"},
+		Findings: findings,
+		Summary:  review.ComputeSummary(findings),
+		Timing: review.Timing{TotalMs: 10, Usage: &providers.Usage{
+			Calls: 2, TotalTokens: 1500, CostUSD: 0.0042,
+		}},
+	}
+	var buf bytes.Buffer
+	if err := (&TextWriter{}).Write(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Usage: 1500 tokens, ~$0.0042\n") {
+		t.Errorf("missing usage line:\n%s", buf.String())
+	}
+
+	report.Timing.Usage.UnpricedCalls = 2
+	buf.Reset()
+	if err := (&MarkdownWriter{}).Write(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "· 1500 tokens, cost unknown*") {
+		t.Errorf("missing usage in markdown footer:\n%s", buf.String())
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -18,31 +19,48 @@ const (
 
 // Anthropic implements the Reviewer interface for Anthropic's API.
 type Anthropic struct {
-	apiKey string
-	model  string
-	client *http.Client
+	apiKey  string
+	model   string
+	version string // anthropic-version header; empty = anthropicAPIVersion
+	beta    string // anthropic-beta header; empty = not sent
+	client  *http.Client
 }
 
-// NewAnthropic creates a new Anthropic provider.
+// NewAnthropic creates a new Anthropic provider. ANTHROPIC_VERSION overrides
+// the pinned API version and ANTHROPIC_BETA sets the anthropic-beta header
+// (a comma-separated list of beta features) to opt into new API features.
 func NewAnthropic(model string) (*Anthropic, error) {
 	key := os.Getenv("ANTHROPIC_API_KEY")
 	if key == "" {
 		return nil, fmt.Errorf("ANTHROPIC_API_KEY environment variable is not set")
 	}
 	return &Anthropic{
-		apiKey: key,
-		model:  model,
-		client: &http.Client{Timeout: 120 * time.Second},
+		apiKey:  key,
+		model:   model,
+		version: strings.TrimSpace(os.Getenv("ANTHROPIC_VERSION")),
+		beta:    strings.TrimSpace(os.Getenv("ANTHROPIC_BETA")),
+		client:  &http.Client{Timeout: 120 * time.Second},
 	}, nil
 }
 
 func (a *Anthropic) Name() string { return "anthropic" }
 
-func (a *Anthropic) Review(ctx context.Context, req ReviewRequest) (ReviewResponse, error) {
+// requestBody returns the JSON body sent to the Messages API for req.
+func (a *Anthropic) requestBody(req ReviewRequest) ([]byte, error) {
+	return a.buildRequest(req, false)
+}
+
+// streamRequestBody returns the JSON body of a streaming request for req.
+func (a *Anthropic) streamRequestBody(req ReviewRequest) ([]byte, error) {
+	return a.buildRequest(req, true)
+}
+
+func (a *Anthropic) buildRequest(req ReviewRequest, stream bool) ([]byte, error) {
 	maxTokens := req.MaxTokens
 	if maxTokens == 0 {
 		maxTokens = 4096
 	}
+	maxTokens = clampMaxTokens(a.model, maxTokens)
 
 	body := anthropicRequest{
 		Model:     a.model,
@@ -51,22 +69,56 @@ func (a *Anthropic) Review(ctx context.Context, req ReviewRequest) (ReviewRespon
 		Messages: []anthropicMessage{
 			{Role: "user", Content: req.UserPrompt},
 		},
+		Stream: stream,
 	}
+	// Findings come back as the input of a forced tool call, which the API
+	// validates against the schema. Streams stay plain text so findings can
+	// be parsed as they arrive.
+	if req.FindingsJSON && !stream {
+		body.Tools = []anthropicTool{{
+			Name:        anthropicFindingsTool,
+			Description: "Report the code review findings.",
+			InputSchema: findingsObjectSchema,
+		}}
+		body.ToolChoice = &anthropicToolChoice{Type: "tool", Name: anthropicFindingsTool}
+	}
+	return json.Marshal(body)
+}
+
+// Review sends req, streaming the response if ctx comes from WithStream.
+func (a *Anthropic) Review(ctx context.Context, req ReviewRequest) (ReviewResponse, error) {
+	return a.review(ctx, req, streamFrom(ctx))
+}
 
-	payload, err := json.Marshal(body)
+// ReviewStream sends req, passing response text to onDelta as it arrives.
+func (a *Anthropic) ReviewStream(ctx context.Context, req ReviewRequest, onDelta func(string)) (ReviewResponse, error) {
+	return a.review(ctx, req, onDelta)
+}
+
+// review sends req, streaming the response to onDelta when it is non-nil.
+func (a *Anthropic) review(ctx context.Context, req ReviewRequest, onDelta func(string)) (ReviewResponse, error) {
+	payload, err := a.buildRequest(req, onDelta != nil)
 	if err != nil {
 		return ReviewResponse{}, fmt.Errorf("marshaling request: %w", err)
 	}
 
 	var resp ReviewResponse
-	err = retryWithBackoff(ctx, 3, func() error {
+	resp.Retries, err = retryWithBackoffCount(ctx, 3, func() error {
 		httpReq, err := http.NewRequestWithContext(ctx, "POST", anthropicAPIURL, bytes.NewReader(payload))
 		if err != nil {
 			return fmt.Errorf("creating request: %w", err)
 		}
 		httpReq.Header.Set("Content-Type", "application/json")
 		httpReq.Header.Set("x-api-key", a.apiKey)
-		httpReq.Header.Set("anthropic-version", anthropicAPIVersion)
+		version := a.version
+		if version == "" {
+			version = anthropicAPIVersion
+		}
+		httpReq.Header.Set("anthropic-version", version)
+		if a.beta != "" {
+			httpReq.Header.Set("anthropic-beta", a.beta)
+		}
+		applyExtraHeaders(httpReq)
 
 		httpResp, err := a.client.Do(httpReq)
 		if err != nil {
@@ -74,6 +126,11 @@ func (a *Anthropic) Review(ctx context.Context, req ReviewRequest) (ReviewRespon
 		}
 		defer httpResp.Body.Close()
 
+		if onDelta != nil && httpResp.StatusCode == 200 {
+			resp, err = readAnthropicStream(httpResp.Body, onDelta)
+			return err
+		}
+
 		respBody, err := io.ReadAll(httpResp.Body)
 		if err != nil {
 			return fmt.Errorf("reading response: %w", err)
@@ -85,6 +142,9 @@ func (a *Anthropic) Review(ctx context.Context, req ReviewRequest) (ReviewRespon
 		if httpResp.StatusCode == 401 || httpResp.StatusCode == 403 {
 			return &authError{message: string(respBody)}
 		}
+		if isModelNotFound(httpResp.StatusCode, string(respBody)) {
+			return &modelError{provider: a.Name(), model: a.model, statusCode: httpResp.StatusCode, body: string(respBody)}
+		}
 		if httpResp.StatusCode >= 500 {
 			return &serverError{statusCode: httpResp.StatusCode, body: string(respBody)}
 		}
@@ -99,8 +159,11 @@ func (a *Anthropic) Review(ctx context.Context, req ReviewRequest) (ReviewRespon
 
 		var content string
 		for _, block := range result.Content {
-			if block.Type == "text" {
+			switch {
+			case block.Type == "text":
 				content += block.Text
+			case block.Type == "tool_use" && block.Name == anthropicFindingsTool:
+				content = unwrapFindings(block.Input)
 			}
 		}
 		if content == "" {
@@ -108,8 +171,13 @@ func (a *Anthropic) Review(ctx context.Context, req ReviewRequest) (ReviewRespon
 		}
 
 		resp = ReviewResponse{
-			Content:    content,
-			TokensUsed: result.Usage.InputTokens + result.Usage.OutputTokens,
+			Content:      content,
+			TokensUsed:   result.Usage.InputTokens + result.Usage.OutputTokens,
+			InputTokens:  result.Usage.InputTokens,
+			OutputTokens: result.Usage.OutputTokens,
+			FinishReason: result.StopReason,
+			Model:        result.Model,
+			Body:         respBody,
 		}
 		return nil
 	})
@@ -117,11 +185,29 @@ func (a *Anthropic) Review(ctx context.Context, req ReviewRequest) (ReviewRespon
 	return resp, err
 }
 
+// anthropicFindingsTool names the tool whose input carries the findings of
+// a structured review.
+const anthropicFindingsTool = "report_findings"
+
 type anthropicRequest struct {
-	Model     string             `json:"model"`
-	MaxTokens int                `json:"max_tokens"`
-	System    string             `json:"system,omitempty"`
-	Messages  []anthropicMessage `json:"messages"`
+	Model      string               `json:"model"`
+	MaxTokens  int                  `json:"max_tokens"`
+	System     string               `json:"system,omitempty"`
+	Messages   []anthropicMessage   `json:"messages"`
+	Stream     bool                 `json:"stream,omitempty"`
+	Tools      []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice *anthropicToolChoice `json:"tool_choice,omitempty"`
+}
+
+type anthropicTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
 }
 
 type anthropicMessage struct {
@@ -130,16 +216,79 @@ type anthropicMessage struct {
 }
 
 type anthropicResponse struct {
-	Content []anthropicBlock `json:"content"`
-	Usage   anthropicUsage   `json:"usage"`
+	Model      string           `json:"model"`
+	Content    []anthropicBlock `json:"content"`
+	StopReason string           `json:"stop_reason"`
+	Usage      anthropicUsage   `json:"usage"`
 }
 
 type anthropicBlock struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+	Type  string          `json:"type"`
+	Text  string          `json:"text"`
+	Name  string          `json:"name,omitempty"`  // tool_use
+	Input json.RawMessage `json:"input,omitempty"` // tool_use
 }
 
 type anthropicUsage struct {
 	InputTokens  int `json:"input_tokens"`
 	OutputTokens int `json:"output_tokens"`
 }
+
+// anthropicEvent is one server-sent event of a streaming Messages response.
+type anthropicEvent struct {
+	Type    string            `json:"type"`
+	Message anthropicResponse `json:"message"` // message_start
+	Delta   struct {
+		Type       string `json:"type"`
+		Text       string `json:"text"`        // content_block_delta
+		StopReason string `json:"stop_reason"` // message_delta
+	} `json:"delta"`
+	Usage anthropicUsage `json:"usage"` // message_delta
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// readAnthropicStream collects a streaming Messages response, passing each
+// text delta to onDelta.
+func readAnthropicStream(body io.Reader, onDelta func(string)) (ReviewResponse, error) {
+	var resp ReviewResponse
+	var raw bytes.Buffer
+	body = io.TeeReader(body, &raw)
+	var content strings.Builder
+	var usage anthropicUsage
+	err := readSSE(body, func(data string) error {
+		var ev anthropicEvent
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			return fmt.Errorf("parsing stream event: %w", err)
+		}
+		switch ev.Type {
+		case "message_start":
+			resp.Model = ev.Message.Model
+			usage.InputTokens = ev.Message.Usage.InputTokens
+		case "content_block_delta":
+			if ev.Delta.Type == "text_delta" && ev.Delta.Text != "" {
+				content.WriteString(ev.Delta.Text)
+				onDelta(ev.Delta.Text)
+			}
+		case "message_delta":
+			resp.FinishReason = ev.Delta.StopReason
+			usage.OutputTokens = ev.Usage.OutputTokens
+		case "error":
+			return fmt.Errorf("stream error (%s): %s", ev.Error.Type, ev.Error.Message)
+		}
+		return nil
+	})
+	if err != nil {
+		return ReviewResponse{}, err
+	}
+	if content.Len() == 0 {
+		return ReviewResponse{}, fmt.Errorf("empty text content in API response")
+	}
+	resp.Content = content.String()
+	resp.TokensUsed = usage.InputTokens + usage.OutputTokens
+	resp.InputTokens, resp.OutputTokens = usage.InputTokens, usage.OutputTokens
+	resp.Body = raw.Bytes()
+	return resp, nil
+}
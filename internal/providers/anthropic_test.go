@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -105,3 +106,125 @@ func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	}
 	return http.DefaultTransport.RoundTrip(req)
 }
+
+func TestAnthropic_FinishReasonAndModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := anthropicResponse{
+			Model:      "claude-sonnet-4-20250514",
+			Content:    []anthropicBlock{{Type: "text", Text: "[]"}},
+			StopReason: "max_tokens",
+			Usage:      anthropicUsage{InputTokens: 10, OutputTokens: 5},
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	a := &Anthropic{
+		apiKey: "test-key",
+		model:  "claude-sonnet-4-0",
+		client: &http.Client{
+			Transport: &rewriteTransport{
+				base:    server.Client().Transport,
+				baseURL: server.URL,
+			},
+		},
+	}
+
+	resp, err := a.Review(context.Background(), ReviewRequest{SystemPrompt: "test", UserPrompt: "test"})
+	if err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+	if resp.FinishReason != "max_tokens" {
+		t.Errorf("FinishReason = %q, want %q", resp.FinishReason, "max_tokens")
+	}
+	if resp.Model != "claude-sonnet-4-20250514" {
+		t.Errorf("Model = %q, want %q", resp.Model, "claude-sonnet-4-20250514")
+	}
+	if !resp.Truncated() {
+		t.Error("Truncated() should be true for max_tokens")
+	}
+}
+
+func TestAnthropic_VersionHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		beta        string
+		wantVersion string
+		wantBeta    string
+	}{
+		{"default", "", "", anthropicAPIVersion, ""},
+		{"override", "2024-10-22", "prompt-caching-2024-07-31,context-1m-2025-08-07", "2024-10-22", "prompt-caching-2024-07-31,context-1m-2025-08-07"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotVersion, gotBeta string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotVersion = r.Header.Get("anthropic-version")
+				gotBeta = r.Header.Get("anthropic-beta")
+				json.NewEncoder(w).Encode(anthropicResponse{Content: []anthropicBlock{{Type: "text", Text: "[]"}}})
+			}))
+			defer server.Close()
+
+			t.Setenv("ANTHROPIC_API_KEY", "test-key")
+			t.Setenv("ANTHROPIC_VERSION", tt.version)
+			t.Setenv("ANTHROPIC_BETA", tt.beta)
+			a, err := NewAnthropic("claude-sonnet-4-6")
+			if err != nil {
+				t.Fatal(err)
+			}
+			a.client = &http.Client{Transport: &rewriteTransport{base: server.Client().Transport, baseURL: server.URL}}
+
+			if _, err := a.Review(context.Background(), ReviewRequest{SystemPrompt: "test", UserPrompt: "test"}); err != nil {
+				t.Fatalf("Review error: %v", err)
+			}
+			if gotVersion != tt.wantVersion {
+				t.Errorf("anthropic-version = %q, want %q", gotVersion, tt.wantVersion)
+			}
+			if gotBeta != tt.wantBeta {
+				t.Errorf("anthropic-beta = %q, want %q", gotBeta, tt.wantBeta)
+			}
+		})
+	}
+}
+
+func TestAnthropic_FindingsToolUse(t *testing.T) {
+	var got anthropicRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"content":[{"type":"tool_use","id":"t1","name":"report_findings",` +
+			`"input":{"findings":[{"severity":"high","title":"Bug"}]}}],"stop_reason":"tool_use",` +
+			`"usage":{"input_tokens":5,"output_tokens":7}}`))
+	}))
+	defer server.Close()
+
+	a := &Anthropic{
+		apiKey: "test-key",
+		model:  "claude-sonnet-4-6",
+		client: &http.Client{
+			Transport: &rewriteTransport{base: server.Client().Transport, baseURL: server.URL},
+		},
+	}
+	resp, err := a.Review(context.Background(), ReviewRequest{SystemPrompt: "s", UserPrompt: "u", FindingsJSON: true})
+	if err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+	if resp.Content != `[{"severity":"high","title":"Bug"}]` {
+		t.Errorf("Content = %q, want the findings array from the tool input", resp.Content)
+	}
+	if len(got.Tools) != 1 || got.Tools[0].Name != anthropicFindingsTool || got.Tools[0].InputSchema["type"] != "object" {
+		t.Errorf("tools = %+v", got.Tools)
+	}
+	if got.ToolChoice == nil || got.ToolChoice.Type != "tool" || got.ToolChoice.Name != anthropicFindingsTool {
+		t.Errorf("tool_choice = %+v, want the findings tool forced", got.ToolChoice)
+	}
+
+	// Streamed requests stay plain text
+	body, err := a.streamRequestBody(ReviewRequest{UserPrompt: "u", FindingsJSON: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(body), "tools") {
+		t.Errorf("stream request should not use tools: %s", body)
+	}
+}
@@ -0,0 +1,112 @@
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// AuditEntry records one provider call by hash, so a run can prove what
+// was sent and received without retaining the text itself.
+type AuditEntry struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	// PromptHash is the SHA-256 of the exact request body sent, after
+	// redaction. Providers that cannot serialize their request hash the
+	// system and user prompts instead.
+	PromptHash string `json:"promptHash"`
+	// ResponseHash is the SHA-256 of the raw HTTP response body, before
+	// the review text or tool input is extracted from it. It is empty when
+	// the call failed.
+	ResponseHash string `json:"responseHash,omitempty"`
+}
+
+// AuditLog collects audit entries from concurrent provider calls.
+type AuditLog struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+// NewAuditLog returns an empty audit log.
+func NewAuditLog() *AuditLog {
+	return &AuditLog{}
+}
+
+// Entries returns the recorded entries in call completion order.
+func (l *AuditLog) Entries() []AuditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]AuditEntry(nil), l.entries...)
+}
+
+func (l *AuditLog) add(e AuditEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, e)
+}
+
+type auditLogKey struct{}
+
+// WithAuditLog returns a context whose provider calls are recorded in l.
+func WithAuditLog(ctx context.Context, l *AuditLog) context.Context {
+	if l == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, auditLogKey{}, l)
+}
+
+func auditLogFrom(ctx context.Context) *AuditLog {
+	l, _ := ctx.Value(auditLogKey{}).(*AuditLog)
+	return l
+}
+
+// auditReviewer records each call in the context's audit log, if any. It
+// wraps the concrete provider, so calls refused by the auth breaker or
+// canceled while waiting on the rate limiter are not recorded as sent.
+type auditReviewer struct {
+	Reviewer
+	model string
+}
+
+func withAudit(r Reviewer, model string) Reviewer {
+	return &auditReviewer{Reviewer: r, model: model}
+}
+
+func (r *auditReviewer) Review(ctx context.Context, req ReviewRequest) (ReviewResponse, error) {
+	resp, err := r.Reviewer.Review(ctx, req)
+	if l := auditLogFrom(ctx); l != nil {
+		entry := AuditEntry{
+			Provider:   r.Name(),
+			Model:      r.model,
+			PromptHash: promptHash(r.Reviewer, req, streamFrom(ctx) != nil),
+		}
+		if err == nil {
+			entry.ResponseHash = sha256Hex(resp.Body)
+		}
+		l.add(entry)
+	}
+	return resp, err
+}
+
+// promptHash hashes the request body r sends for req, falling back to the
+// prompts for providers without a request builder. stream selects the body
+// of a streaming request.
+func promptHash(r Reviewer, req ReviewRequest, stream bool) string {
+	if sb, ok := r.(streamRequestBuilder); ok && stream {
+		if body, err := sb.streamRequestBody(req); err == nil {
+			return sha256Hex(body)
+		}
+	}
+	if rb, ok := r.(requestBuilder); ok {
+		if body, err := rb.requestBody(req); err == nil {
+			return sha256Hex(body)
+		}
+	}
+	return sha256Hex([]byte(req.SystemPrompt + "\x00" + req.UserPrompt))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuditLog_RecordsHashes(t *testing.T) {
+	const respBody = `{"choices":[{"message":{"content":"[]"}}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(respBody))
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	r, err := New("ollama", "llama3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := ReviewRequest{SystemPrompt: "sys", UserPrompt: "diff", MaxTokens: 100}
+
+	// Without a log nothing is recorded
+	if _, err := r.Review(context.Background(), req); err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+
+	log := NewAuditLog()
+	if _, err := r.Review(WithAuditLog(context.Background(), log), req); err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+	entries := log.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Provider != "ollama" || e.Model != "llama3" {
+		t.Errorf("entry = %+v, want ollama llama3", e)
+	}
+	body, err := RequestBody(r, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.PromptHash != hashOf(string(body)) {
+		t.Errorf("PromptHash = %s, want hash of the request body", e.PromptHash)
+	}
+	if e.ResponseHash != hashOf(respBody) {
+		t.Errorf("ResponseHash = %s, want hash of the raw response body", e.ResponseHash)
+	}
+}
+
+func TestAuditLog_StreamHashesEventStream(t *testing.T) {
+	const events = "data: {\"choices\":[{\"delta\":{\"content\":\"[]\"}}]}\n\ndata: [DONE]\n\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(events))
+	}))
+	defer server.Close()
+
+	log := NewAuditLog()
+	o := &OpenAI{apiKey: "k", model: "gpt-4o", baseURL: server.URL, client: server.Client()}
+	ctx := WithStream(WithAuditLog(context.Background(), log), func(string) {})
+	resp, err := withAudit(o, "gpt-4o").Review(ctx, ReviewRequest{UserPrompt: "diff"})
+	if err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+	if resp.Content != "[]" {
+		t.Fatalf("Content = %q", resp.Content)
+	}
+	if got := log.Entries()[0].ResponseHash; got != hashOf(events) {
+		t.Errorf("ResponseHash = %s, want hash of the event stream", got)
+	}
+}
+
+func TestAuditLog_FailedCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	log := NewAuditLog()
+	r := withAudit(&Ollama{model: "llama3", baseURL: server.URL, client: server.Client()}, "llama3")
+	if _, err := r.Review(WithAuditLog(context.Background(), log), ReviewRequest{}); err == nil {
+		t.Fatal("expected error")
+	}
+	entries := log.Entries()
+	if len(entries) != 1 || entries[0].PromptHash == "" || entries[0].ResponseHash != "" {
+		t.Errorf("entries = %+v, want one entry with a prompt hash and no response hash", entries)
+	}
+}
+
+func hashOf(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
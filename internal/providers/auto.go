@@ -0,0 +1,92 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// autoOrderEnv names the environment variable that overrides the order in
+// which "auto" tries providers, as a comma-separated list of provider names.
+const autoOrderEnv = "PRISM_AUTO_PROVIDERS"
+
+// defaultAutoOrder is the order "auto" tries providers when
+// PRISM_AUTO_PROVIDERS is unset.
+var defaultAutoOrder = []string{"anthropic", "openai", "gemini", "mistral"}
+
+// providerKeyEnv maps providers to the environment variable holding their
+// API key. Local providers have no entry.
+var providerKeyEnv = map[string]string{
+	"anthropic":  "ANTHROPIC_API_KEY",
+	"openai":     "OPENAI_API_KEY",
+	"gemini":     "GEMINI_API_KEY",
+	"google":     "GEMINI_API_KEY",
+	"mistral":    "MISTRAL_API_KEY",
+	"openrouter": "OPENROUTER_API_KEY",
+}
+
+// autoDefaultModels is the model used when "auto" picks a provider that the
+// configured model does not belong to (e.g. a shared config naming a Claude
+// model on a machine with only an OpenAI key, or falling back to Ollama).
+var autoDefaultModels = map[string]string{
+	"anthropic":  "claude-sonnet-4-6",
+	"openai":     "gpt-5.2",
+	"gemini":     "gemini-2.5-pro",
+	"google":     "gemini-2.5-pro",
+	"mistral":    "mistral-large-latest",
+	"openrouter": "anthropic/claude-sonnet-4.6",
+	"ollama":     "llama3",
+}
+
+// ResolveAuto picks the first provider in the preference order whose API key
+// is set. Local providers (ollama, lmstudio) need no key and are chosen as
+// soon as they appear in the order, so list them last in
+// PRISM_AUTO_PROVIDERS to use them as a fallback. It returns the provider
+// and the model to use with it.
+func ResolveAuto(model string) (string, string, error) {
+	order := defaultAutoOrder
+	if v := os.Getenv(autoOrderEnv); v != "" {
+		order = nil
+		for _, p := range strings.Split(v, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				order = append(order, p)
+			}
+		}
+	}
+
+	var missing []string
+	for _, p := range order {
+		env, hasKey := providerKeyEnv[p]
+		if hasKey && os.Getenv(env) == "" {
+			missing = append(missing, env)
+			continue
+		}
+		if family := modelFamily(model); family != "" && providerKeyEnv[family] != providerKeyEnv[p] {
+			if def, ok := autoDefaultModels[p]; ok {
+				model = def
+			}
+		}
+		return p, model, nil
+	}
+	return "", "", fmt.Errorf("provider auto: no provider available (set one of %s, or choose a provider explicitly)",
+		strings.Join(missing, ", "))
+}
+
+// modelFamily returns the cloud provider a model name belongs to, judged by
+// its well-known prefix, or "" if it is not recognized.
+func modelFamily(model string) string {
+	m := strings.ToLower(model)
+	switch {
+	case strings.HasPrefix(m, "claude"):
+		return "anthropic"
+	case strings.HasPrefix(m, "gpt"), strings.HasPrefix(m, "o1"), strings.HasPrefix(m, "o3"), strings.HasPrefix(m, "o4"):
+		return "openai"
+	case strings.HasPrefix(m, "gemini"):
+		return "gemini"
+	case strings.HasPrefix(m, "mistral"), strings.HasPrefix(m, "codestral"), strings.HasPrefix(m, "devstral"),
+		strings.HasPrefix(m, "magistral"), strings.HasPrefix(m, "ministral"), strings.HasPrefix(m, "open-mistral"):
+		return "mistral"
+	default:
+		return ""
+	}
+}
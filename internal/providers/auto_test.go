@@ -0,0 +1,75 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+)
+
+func clearProviderKeys(t *testing.T) {
+	t.Helper()
+	for _, env := range []string{"ANTHROPIC_API_KEY", "OPENAI_API_KEY", "GEMINI_API_KEY", "MISTRAL_API_KEY", "OPENROUTER_API_KEY", autoOrderEnv} {
+		t.Setenv(env, "")
+	}
+}
+
+func TestResolveAuto(t *testing.T) {
+	tests := []struct {
+		name         string
+		keys         map[string]string
+		order        string
+		model        string
+		wantProvider string
+		wantModel    string
+	}{
+		{"first with key", map[string]string{"OPENAI_API_KEY": "k", "GEMINI_API_KEY": "k"}, "", "gpt-5.2", "openai", "gpt-5.2"},
+		{"default order prefers anthropic", map[string]string{"ANTHROPIC_API_KEY": "k", "OPENAI_API_KEY": "k"}, "", "claude-sonnet-4-6", "anthropic", "claude-sonnet-4-6"},
+		{"custom order", map[string]string{"ANTHROPIC_API_KEY": "k", "OPENAI_API_KEY": "k"}, "openai, anthropic", "gpt-5.2", "openai", "gpt-5.2"},
+		{"foreign model replaced", map[string]string{"GEMINI_API_KEY": "k"}, "", "claude-sonnet-4-6", "gemini", autoDefaultModels["gemini"]},
+		{"mistral model kept", map[string]string{"MISTRAL_API_KEY": "k"}, "", "codestral-latest", "mistral", "codestral-latest"},
+		{"local fallback keeps model", nil, "anthropic,ollama", "llama3", "ollama", "llama3"},
+		{"local fallback replaces cloud model", nil, "anthropic,ollama", "claude-sonnet-4-6", "ollama", autoDefaultModels["ollama"]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearProviderKeys(t)
+			for k, v := range tt.keys {
+				t.Setenv(k, v)
+			}
+			t.Setenv(autoOrderEnv, tt.order)
+
+			provider, model, err := ResolveAuto(tt.model)
+			if err != nil {
+				t.Fatalf("ResolveAuto error: %v", err)
+			}
+			if provider != tt.wantProvider || model != tt.wantModel {
+				t.Errorf("ResolveAuto(%q) = (%q, %q), want (%q, %q)", tt.model, provider, model, tt.wantProvider, tt.wantModel)
+			}
+		})
+	}
+}
+
+func TestResolveAuto_NoneAvailable(t *testing.T) {
+	clearProviderKeys(t)
+	_, _, err := ResolveAuto("claude-sonnet-4-6")
+	if err == nil {
+		t.Fatal("expected an error when no API key is set")
+	}
+	if !strings.Contains(err.Error(), "ANTHROPIC_API_KEY") {
+		t.Errorf("error should name the missing keys, got: %v", err)
+	}
+}
+
+func TestNew_Auto(t *testing.T) {
+	clearProviderKeys(t)
+	t.Setenv("OPENAI_API_KEY", "k")
+	r, err := New("auto", "claude-sonnet-4-6")
+	if err != nil {
+		t.Fatalf("New(auto) error: %v", err)
+	}
+	if r.Name() != "openai" {
+		t.Errorf("New(auto).Name() = %q, want %q", r.Name(), "openai")
+	}
+	if got := unwrap(r).(*OpenAI).model; got != autoDefaultModels["openai"] {
+		t.Errorf("model = %q, want %q", got, autoDefaultModels["openai"])
+	}
+}
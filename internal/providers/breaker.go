@@ -0,0 +1,99 @@
+package providers
+
+import (
+	"context"
+	"sync"
+)
+
+// authBreaker remembers the first authentication error seen for a provider.
+// Credentials come from the environment and cannot change during a run, so
+// once one call is rejected every other call to that provider would be too.
+type authBreaker struct {
+	mu      sync.Mutex
+	err     error
+	tripped chan struct{}
+}
+
+func newAuthBreaker() *authBreaker {
+	return &authBreaker{tripped: make(chan struct{})}
+}
+
+// trip records err and releases every call waiting on the breaker. Only the
+// first error is kept.
+func (b *authBreaker) trip(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err != nil {
+		return
+	}
+	b.err = err
+	close(b.tripped)
+}
+
+// failure returns the recorded authentication error, or nil.
+func (b *authBreaker) failure() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.err
+}
+
+// authBreakerReviewer fails fast with the shared authentication error once
+// any reviewer for the same provider has hit one. Calls in flight when the
+// breaker trips are canceled and return that error too.
+type authBreakerReviewer struct {
+	Reviewer
+	breaker *authBreaker
+}
+
+func (r *authBreakerReviewer) Review(ctx context.Context, req ReviewRequest) (ReviewResponse, error) {
+	if err := r.breaker.failure(); err != nil {
+		return ReviewResponse{}, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-r.breaker.tripped:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	resp, err := r.Reviewer.Review(ctx, req)
+	if err == nil {
+		return resp, nil
+	}
+	if IsAuthError(err) {
+		r.breaker.trip(err)
+		return resp, err
+	}
+	if bErr := r.breaker.failure(); bErr != nil {
+		return ReviewResponse{}, bErr
+	}
+	return resp, err
+}
+
+var (
+	breakersMu sync.Mutex
+	breakers   = map[string]*authBreaker{}
+)
+
+// breakerFor returns the process-wide auth breaker for a provider. Every
+// reviewer for the same provider shares it, so chunked and compare-mode
+// calls stop together after the first authentication error.
+func breakerFor(provider string) *authBreaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	b, ok := breakers[provider]
+	if !ok {
+		b = newAuthBreaker()
+		breakers[provider] = b
+	}
+	return b
+}
+
+// withAuthBreaker wraps r with the shared auth breaker for its provider.
+func withAuthBreaker(r Reviewer) Reviewer {
+	return &authBreakerReviewer{Reviewer: r, breaker: breakerFor(r.Name())}
+}
@@ -0,0 +1,88 @@
+package providers
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// authFailReviewer rejects the first call with an authentication error and
+// blocks every later call until its context is canceled.
+type authFailReviewer struct {
+	calls atomic.Int32
+}
+
+func (r *authFailReviewer) Review(ctx context.Context, req ReviewRequest) (ReviewResponse, error) {
+	if r.calls.Add(1) == 1 {
+		return ReviewResponse{}, &authError{message: "invalid x-api-key"}
+	}
+	<-ctx.Done()
+	return ReviewResponse{}, ctx.Err()
+}
+
+func (r *authFailReviewer) Name() string { return "authfail" }
+
+func TestAuthBreaker_FailsFastAcrossChunks(t *testing.T) {
+	t.Cleanup(func() {
+		breakersMu.Lock()
+		delete(breakers, "authfail")
+		breakersMu.Unlock()
+	})
+
+	mock := &authFailReviewer{}
+	chunk1 := withAuthBreaker(mock)
+	chunk2 := withAuthBreaker(mock)
+
+	// chunk2 starts first and is in flight when chunk1 is rejected.
+	var wg sync.WaitGroup
+	var err2 error
+	started := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		close(started)
+		_, err2 = chunk2.Review(context.Background(), ReviewRequest{})
+	}()
+	<-started
+	for mock.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err1 := chunk1.Review(context.Background(), ReviewRequest{})
+	if !IsAuthError(err1) {
+		t.Fatalf("chunk1 error = %v, want auth error", err1)
+	}
+
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("in-flight call was not aborted by the breaker")
+	}
+	if !IsAuthError(err2) {
+		t.Errorf("chunk2 error = %v, want the shared auth error", err2)
+	}
+
+	// Later calls fail without reaching the provider.
+	before := mock.calls.Load()
+	if _, err := chunk2.Review(context.Background(), ReviewRequest{}); !IsAuthError(err) {
+		t.Errorf("call after trip error = %v, want auth error", err)
+	}
+	if mock.calls.Load() != before {
+		t.Error("call after trip should not reach the provider")
+	}
+}
+
+func TestAuthBreaker_PassesOtherErrors(t *testing.T) {
+	b := newAuthBreaker()
+	r := &authBreakerReviewer{Reviewer: stubReviewer{}, breaker: b}
+	if _, err := r.Review(context.Background(), ReviewRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.failure() != nil {
+		t.Error("breaker should not trip on success")
+	}
+}
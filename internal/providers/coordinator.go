@@ -0,0 +1,105 @@
+package providers
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// maxSharedPause caps how long a rate-limit signal pauses all callers.
+const maxSharedPause = 32 * time.Second
+
+// RetryCoordinator shares retry state between concurrent provider calls,
+// such as the chunks of a chunked review or the models in compare mode.
+// When any call is rate limited, every call sharing the coordinator pauses
+// until the backoff expires instead of retrying independently, and the
+// pause grows while rate limiting persists. An optional budget caps the
+// total number of retries across all calls.
+type RetryCoordinator struct {
+	mu          sync.Mutex
+	pauseUntil  time.Time
+	rateLimited int // consecutive rate-limit signals since the last success
+	budget      int // remaining retries; ignored when unlimited
+	unlimited   bool
+}
+
+// NewRetryCoordinator creates a coordinator allowing at most budget retries
+// in total across all calls that share it. A budget of 0 means unlimited.
+func NewRetryCoordinator(budget int) *RetryCoordinator {
+	return &RetryCoordinator{budget: budget, unlimited: budget <= 0}
+}
+
+type retryCoordinatorKey struct{}
+
+// WithRetryCoordinator returns a context whose provider calls coordinate
+// their retries through c.
+func WithRetryCoordinator(ctx context.Context, c *RetryCoordinator) context.Context {
+	if c == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, retryCoordinatorKey{}, c)
+}
+
+func retryCoordinatorFrom(ctx context.Context) *RetryCoordinator {
+	c, _ := ctx.Value(retryCoordinatorKey{}).(*RetryCoordinator)
+	return c
+}
+
+// wait blocks until any shared rate-limit pause has expired.
+func (c *RetryCoordinator) wait(ctx context.Context) error {
+	c.mu.Lock()
+	d := time.Until(c.pauseUntil)
+	c.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// takeRetry consumes one retry from the shared budget. Returns false once
+// the budget is exhausted.
+func (c *RetryCoordinator) takeRetry() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.unlimited {
+		return true
+	}
+	if c.budget <= 0 {
+		return false
+	}
+	c.budget--
+	return true
+}
+
+// rateLimit records a rate-limit signal and extends the shared pause. The
+// pause doubles with each consecutive signal, with jitter, up to maxSharedPause.
+func (c *RetryCoordinator) rateLimit() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	shift := c.rateLimited
+	if shift > 5 {
+		shift = 5
+	}
+	c.rateLimited++
+	base := time.Duration(1<<uint(shift)) * time.Second
+	pause := time.Duration(float64(base) * (0.5 + rand.Float64()))
+	if pause > maxSharedPause {
+		pause = maxSharedPause
+	}
+	if until := time.Now().Add(pause); until.After(c.pauseUntil) {
+		c.pauseUntil = until
+	}
+}
+
+// success resets the consecutive rate-limit count.
+func (c *RetryCoordinator) success() {
+	c.mu.Lock()
+	c.rateLimited = 0
+	c.mu.Unlock()
+}
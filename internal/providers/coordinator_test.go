@@ -0,0 +1,83 @@
+package providers
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRetryCoordinator_BudgetExhausted(t *testing.T) {
+	c := NewRetryCoordinator(1)
+	if !c.takeRetry() {
+		t.Fatal("first retry should be allowed")
+	}
+
+	attempts := 0
+	ctx := WithRetryCoordinator(context.Background(), c)
+	err := retryWithBackoff(ctx, 3, func() error {
+		attempts++
+		return &rateLimitError{}
+	})
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt once the shared budget is spent, got %d", attempts)
+	}
+	if _, ok := err.(*rateLimitError); !ok {
+		t.Errorf("Expected rate limit error, got: %v", err)
+	}
+}
+
+func TestRetryCoordinator_Unlimited(t *testing.T) {
+	c := NewRetryCoordinator(0)
+	for i := 0; i < 100; i++ {
+		if !c.takeRetry() {
+			t.Fatalf("unlimited coordinator refused retry %d", i)
+		}
+	}
+}
+
+func TestRetryCoordinator_RateLimitPausesOtherCalls(t *testing.T) {
+	c := NewRetryCoordinator(0)
+	c.rateLimit()
+
+	// Another caller sharing the coordinator must wait out the pause.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	calls := 0
+	err := retryWithBackoff(WithRetryCoordinator(ctx, c), 3, func() error {
+		calls++
+		return nil
+	})
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected caller to block on the shared pause, got: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no calls during the shared pause, got %d", calls)
+	}
+}
+
+func TestRetryCoordinator_PauseGrowsAndResets(t *testing.T) {
+	c := NewRetryCoordinator(0)
+	c.rateLimit()
+	c.rateLimit()
+	c.rateLimit()
+	if c.rateLimited != 3 {
+		t.Errorf("rateLimited = %d, want 3", c.rateLimited)
+	}
+	if d := time.Until(c.pauseUntil); d < time.Second || d > maxSharedPause {
+		t.Errorf("pause = %v, want between 1s and %v after repeated rate limits", d, maxSharedPause)
+	}
+	c.success()
+	if c.rateLimited != 0 {
+		t.Errorf("rateLimited = %d after success, want 0", c.rateLimited)
+	}
+}
+
+func TestWithRetryCoordinator_Nil(t *testing.T) {
+	ctx := context.Background()
+	if got := WithRetryCoordinator(ctx, nil); got != ctx {
+		t.Error("nil coordinator should return the context unchanged")
+	}
+	if retryCoordinatorFrom(ctx) != nil {
+		t.Error("plain context should have no coordinator")
+	}
+}
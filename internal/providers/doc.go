@@ -1,13 +1,29 @@
 // Package providers implements the Reviewer interface for each supported LLM
 // provider.
 //
-// Supported providers: Anthropic (Claude), OpenAI (GPT), Google (Gemini), and
-// Ollama / LMStudio for local models.
+// Supported providers: Anthropic (Claude), OpenAI (GPT), Google (Gemini),
+// Mistral (Mistral Large, Codestral), OpenRouter (many vendors' models behind
+// one key), and Ollama / LMStudio for local models.
 //
 // All providers share a common retry helper with exponential back-off and
 // rate-limit handling. HTTP clients are injected via a transport field so that
 // tests can redirect calls to local httptest servers without making live API
 // requests.
 //
+// PRISM_<PROVIDER>_TPM and PRISM_<PROVIDER>_RPM enable a client-side
+// [RateLimiter] shared by every reviewer for that provider, so chunked and
+// compare-mode reviews stay under org-wide quotas.
+//
+// A [UsageTracker] attached with [WithUsageTracker] totals the tokens and
+// estimated cost (pricing.go) of every call made with that context, and can
+// refuse new calls once a budget is spent.
+//
+// Findings requests (ReviewRequest.FindingsJSON) use each provider's
+// structured output where available: an Anthropic tool call, an OpenAI
+// json_schema response format, or Gemini's JSON response mode (schema.go).
+//
+// [RequestBody] returns the JSON body a provider would send for a request
+// without sending it, so tests can pin request stability across versions.
+//
 // Use [New] to obtain a Reviewer by provider name and model string.
 package providers
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -18,6 +21,9 @@ type Gemini struct {
 	apiKey string
 	model  string
 	client *http.Client
+	// noJSONMode is set once the model rejects JSON response mode, so later
+	// requests skip it.
+	noJSONMode atomic.Bool
 }
 
 // NewGemini creates a new Gemini provider.
@@ -41,6 +47,21 @@ func (g *Gemini) Name() string { return "gemini" }
 func (g *Gemini) Review(ctx context.Context, req ReviewRequest) (ReviewResponse, error) {
 	url := fmt.Sprintf("%s/%s:generateContent", geminiAPIURL, g.model)
 
+	jsonMode := req.FindingsJSON && !g.noJSONMode.Load()
+	resp, err := g.send(ctx, url, g.buildRequest(req, jsonMode))
+	var rejected *jsonModeError
+	if jsonMode && errors.As(err, &rejected) {
+		// Older models and API versions reject JSON mode; fall back to a
+		// plain text request and rely on fence stripping and repair.
+		g.noJSONMode.Store(true)
+		return g.send(ctx, url, g.buildRequest(req, false))
+	}
+	return resp, err
+}
+
+// buildRequest assembles the generateContent request for req, asking for a
+// findings JSON response when jsonMode is set.
+func (g *Gemini) buildRequest(req ReviewRequest, jsonMode bool) geminiRequest {
 	body := geminiRequest{
 		SystemInstruction: &geminiContent{
 			Parts: []geminiPart{{Text: req.SystemPrompt}},
@@ -58,23 +79,37 @@ func (g *Gemini) Review(ctx context.Context, req ReviewRequest) (ReviewResponse,
 	if body.GenerationConfig.MaxOutputTokens == 0 {
 		body.GenerationConfig.MaxOutputTokens = 4096
 	}
+	body.GenerationConfig.MaxOutputTokens = clampMaxTokens(g.model, body.GenerationConfig.MaxOutputTokens)
 	if req.Temperature > 0 {
 		body.GenerationConfig.Temperature = &req.Temperature
 	}
+	if jsonMode {
+		body.GenerationConfig.ResponseMimeType = "application/json"
+		body.GenerationConfig.ResponseSchema = geminiFindingsSchema
+	}
+	return body
+}
 
+// requestBody returns the JSON body the next Review call would send for req.
+func (g *Gemini) requestBody(req ReviewRequest) ([]byte, error) {
+	return json.Marshal(g.buildRequest(req, req.FindingsJSON && !g.noJSONMode.Load()))
+}
+
+func (g *Gemini) send(ctx context.Context, url string, body geminiRequest) (ReviewResponse, error) {
 	payload, err := json.Marshal(body)
 	if err != nil {
 		return ReviewResponse{}, fmt.Errorf("marshaling request: %w", err)
 	}
 
 	var resp ReviewResponse
-	err = retryWithBackoff(ctx, 3, func() error {
+	resp.Retries, err = retryWithBackoffCount(ctx, 3, func() error {
 		httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
 		if err != nil {
 			return fmt.Errorf("creating request: %w", err)
 		}
 		httpReq.Header.Set("Content-Type", "application/json")
 		httpReq.Header.Set("x-goog-api-key", g.apiKey)
+		applyExtraHeaders(httpReq)
 
 		httpResp, err := g.client.Do(httpReq)
 		if err != nil {
@@ -93,6 +128,12 @@ func (g *Gemini) Review(ctx context.Context, req ReviewRequest) (ReviewResponse,
 		if httpResp.StatusCode == 401 || httpResp.StatusCode == 403 {
 			return &authError{message: string(respBody)}
 		}
+		if httpResp.StatusCode == 400 && body.GenerationConfig.ResponseMimeType != "" && mentionsJSONMode(string(respBody)) {
+			return &jsonModeError{body: string(respBody)}
+		}
+		if isModelNotFound(httpResp.StatusCode, string(respBody)) {
+			return &modelError{provider: g.Name(), model: g.model, statusCode: httpResp.StatusCode, body: string(respBody)}
+		}
 		if httpResp.StatusCode >= 500 {
 			return &serverError{statusCode: httpResp.StatusCode, body: string(respBody)}
 		}
@@ -118,8 +159,13 @@ func (g *Gemini) Review(ctx context.Context, req ReviewRequest) (ReviewResponse,
 		}
 
 		resp = ReviewResponse{
-			Content:    content,
-			TokensUsed: result.UsageMetadata.TotalTokenCount,
+			Content:      content,
+			TokensUsed:   result.UsageMetadata.TotalTokenCount,
+			InputTokens:  result.UsageMetadata.PromptTokenCount,
+			OutputTokens: result.UsageMetadata.CandidatesTokenCount,
+			FinishReason: result.Candidates[0].FinishReason,
+			Model:        result.ModelVersion,
+			Body:         respBody,
 		}
 		return nil
 	})
@@ -143,19 +189,72 @@ type geminiPart struct {
 }
 
 type geminiGenConfig struct {
-	MaxOutputTokens int      `json:"maxOutputTokens,omitempty"`
-	Temperature     *float64 `json:"temperature,omitempty"`
+	MaxOutputTokens  int            `json:"maxOutputTokens,omitempty"`
+	Temperature      *float64       `json:"temperature,omitempty"`
+	ResponseMimeType string         `json:"responseMimeType,omitempty"`
+	ResponseSchema   map[string]any `json:"responseSchema,omitempty"`
 }
 
 type geminiResponse struct {
 	Candidates    []geminiCandidate `json:"candidates"`
 	UsageMetadata geminiUsage       `json:"usageMetadata"`
+	ModelVersion  string            `json:"modelVersion"`
 }
 
 type geminiCandidate struct {
-	Content geminiContent `json:"content"`
+	Content      geminiContent `json:"content"`
+	FinishReason string        `json:"finishReason"`
 }
 
 type geminiUsage struct {
-	TotalTokenCount int `json:"totalTokenCount"`
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+	TotalTokenCount      int `json:"totalTokenCount"`
+}
+
+// geminiFindingsSchema is the response schema for findings requests, in the
+// OpenAPI subset Gemini accepts. It mirrors the array the review prompt asks
+// for.
+var geminiFindingsSchema = map[string]any{
+	"type": "ARRAY",
+	"items": map[string]any{
+		"type": "OBJECT",
+		"properties": map[string]any{
+			"severity":   map[string]any{"type": "STRING", "enum": []string{"critical", "high", "medium", "low", "info"}},
+			"category":   map[string]any{"type": "STRING"},
+			"title":      map[string]any{"type": "STRING"},
+			"message":    map[string]any{"type": "STRING"},
+			"suggestion": map[string]any{"type": "STRING"},
+			"confidence": map[string]any{"type": "NUMBER"},
+			"path":       map[string]any{"type": "STRING"},
+			"startLine":  map[string]any{"type": "INTEGER"},
+			"endLine":    map[string]any{"type": "INTEGER"},
+			"tags":       map[string]any{"type": "ARRAY", "items": map[string]any{"type": "STRING"}},
+		},
+		"required": []string{"severity", "category", "title", "message", "path", "startLine", "endLine"},
+	},
+}
+
+// jsonModeError is a provider refusing the JSON response mode or
+// structured output fields rather than the request as a whole. It is not
+// retried as is.
+type jsonModeError struct {
+	body string
+}
+
+func (e *jsonModeError) Error() string {
+	return "JSON response mode rejected: " + e.body
+}
+
+// mentionsJSONMode reports whether an error body names the JSON response
+// mode or structured output fields.
+func mentionsJSONMode(body string) bool {
+	b := strings.ToLower(body)
+	for _, s := range []string{"responsemimetype", "response_mime_type", "responseschema", "response_schema", "json mode",
+		"response_format", "json_schema"} {
+		if strings.Contains(b, s) {
+			return true
+		}
+	}
+	return false
 }
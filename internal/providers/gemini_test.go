@@ -55,3 +55,131 @@ func TestGemini_Review(t *testing.T) {
 		t.Errorf("TokensUsed = %d, want 75", resp.TokensUsed)
 	}
 }
+
+func TestGemini_FinishReasonAndModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := geminiResponse{
+			Candidates: []geminiCandidate{
+				{
+					Content:      geminiContent{Parts: []geminiPart{{Text: "[]"}}},
+					FinishReason: "MAX_TOKENS",
+				},
+			},
+			UsageMetadata: geminiUsage{TotalTokenCount: 75},
+			ModelVersion:  "gemini-2.0-flash-001",
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	g := &Gemini{
+		apiKey: "test-key",
+		model:  "gemini-2.0-flash",
+		client: &http.Client{
+			Transport: &rewriteTransport{
+				base:    server.Client().Transport,
+				baseURL: server.URL,
+			},
+		},
+	}
+
+	resp, err := g.Review(context.Background(), ReviewRequest{SystemPrompt: "test", UserPrompt: "test"})
+	if err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+	if resp.FinishReason != "MAX_TOKENS" {
+		t.Errorf("FinishReason = %q, want %q", resp.FinishReason, "MAX_TOKENS")
+	}
+	if resp.Model != "gemini-2.0-flash-001" {
+		t.Errorf("Model = %q, want %q", resp.Model, "gemini-2.0-flash-001")
+	}
+	if !resp.Truncated() {
+		t.Error("Truncated() should be true for MAX_TOKENS")
+	}
+}
+
+func TestGemini_JSONMode(t *testing.T) {
+	var gotConfigs []geminiGenConfig
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req geminiRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		gotConfigs = append(gotConfigs, *req.GenerationConfig)
+		json.NewEncoder(w).Encode(geminiResponse{
+			Candidates: []geminiCandidate{{Content: geminiContent{Parts: []geminiPart{{Text: "[]"}}}}},
+		})
+	}))
+	defer server.Close()
+
+	g := &Gemini{
+		apiKey: "test-key",
+		model:  "gemini-2.0-flash",
+		client: &http.Client{
+			Transport: &rewriteTransport{base: server.Client().Transport, baseURL: server.URL},
+		},
+	}
+
+	if _, err := g.Review(context.Background(), ReviewRequest{SystemPrompt: "s", UserPrompt: "u", FindingsJSON: true}); err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+	if _, err := g.Review(context.Background(), ReviewRequest{SystemPrompt: "s", UserPrompt: "u"}); err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+
+	if gotConfigs[0].ResponseMimeType != "application/json" {
+		t.Errorf("responseMimeType = %q, want application/json", gotConfigs[0].ResponseMimeType)
+	}
+	if gotConfigs[0].ResponseSchema["type"] != "ARRAY" {
+		t.Errorf("responseSchema = %v, want findings array schema", gotConfigs[0].ResponseSchema)
+	}
+	if gotConfigs[1].ResponseMimeType != "" || gotConfigs[1].ResponseSchema != nil {
+		t.Errorf("non-findings request should not use JSON mode, got %+v", gotConfigs[1])
+	}
+}
+
+func TestGemini_JSONModeFallback(t *testing.T) {
+	var mimeTypes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req geminiRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mimeTypes = append(mimeTypes, req.GenerationConfig.ResponseMimeType)
+		if req.GenerationConfig.ResponseMimeType != "" {
+			w.WriteHeader(400)
+			w.Write([]byte(`{"error":{"code":400,"message":"Invalid JSON payload received. Unknown name \"responseMimeType\" at 'generation_config'","status":"INVALID_ARGUMENT"}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(geminiResponse{
+			Candidates: []geminiCandidate{{Content: geminiContent{Parts: []geminiPart{{Text: "[]"}}}}},
+		})
+	}))
+	defer server.Close()
+
+	g := &Gemini{
+		apiKey: "test-key",
+		model:  "gemini-pro",
+		client: &http.Client{
+			Transport: &rewriteTransport{base: server.Client().Transport, baseURL: server.URL},
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := g.Review(context.Background(), ReviewRequest{SystemPrompt: "s", UserPrompt: "u", FindingsJSON: true})
+		if err != nil {
+			t.Fatalf("Review error: %v", err)
+		}
+		if resp.Content != "[]" {
+			t.Errorf("Content = %q, want []", resp.Content)
+		}
+	}
+	// One rejected JSON-mode attempt, then plain requests only.
+	want := []string{"application/json", "", ""}
+	if len(mimeTypes) != len(want) {
+		t.Fatalf("requests = %q, want %q", mimeTypes, want)
+	}
+	for i := range want {
+		if mimeTypes[i] != want[i] {
+			t.Errorf("request %d responseMimeType = %q, want %q", i, mimeTypes[i], want[i])
+		}
+	}
+}
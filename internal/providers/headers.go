@@ -0,0 +1,44 @@
+package providers
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// extraHeadersEnv names the environment variable holding additional HTTP
+// headers for provider requests, as comma-separated "Key:Value" pairs.
+const extraHeadersEnv = "PRISM_EXTRA_HEADERS"
+
+// parseExtraHeaders parses "Key:Value,Key2:Value2" into header pairs.
+// Malformed entries are skipped.
+func parseExtraHeaders(s string) http.Header {
+	h := make(http.Header)
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		h.Add(key, strings.TrimSpace(value))
+	}
+	return h
+}
+
+// applyExtraHeaders adds headers from PRISM_EXTRA_HEADERS to req. Headers
+// the provider has already set (authentication, content type, API version)
+// are never overridden.
+func applyExtraHeaders(req *http.Request) {
+	v := os.Getenv(extraHeadersEnv)
+	if v == "" {
+		return
+	}
+	for key, values := range parseExtraHeaders(v) {
+		if req.Header.Get(key) != "" || key == "Authorization" || key == "Content-Type" {
+			continue
+		}
+		for _, val := range values {
+			req.Header.Add(key, val)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseExtraHeaders(t *testing.T) {
+	h := parseExtraHeaders(" X-Org-Id: acme , X-Cost-Center:42,malformed,:novalue")
+	if h.Get("X-Org-Id") != "acme" {
+		t.Errorf("X-Org-Id = %q, want %q", h.Get("X-Org-Id"), "acme")
+	}
+	if h.Get("X-Cost-Center") != "42" {
+		t.Errorf("X-Cost-Center = %q, want %q", h.Get("X-Cost-Center"), "42")
+	}
+	if len(h) != 2 {
+		t.Errorf("got %d headers, want 2 (malformed entries skipped): %v", len(h), h)
+	}
+}
+
+func TestOpenAI_ExtraHeaders(t *testing.T) {
+	t.Setenv("PRISM_EXTRA_HEADERS", "X-Org-Id:acme,Authorization:Bearer evil,Content-Type:text/plain")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Org-Id") != "acme" {
+			t.Errorf("X-Org-Id = %q, want %q", r.Header.Get("X-Org-Id"), "acme")
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("Authorization was overridden: %q", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type was overridden: %q", r.Header.Get("Content-Type"))
+		}
+		json.NewEncoder(w).Encode(openaiResponse{
+			Choices: []openaiChoice{{Message: openaiMessage{Role: "assistant", Content: "[]"}}},
+		})
+	}))
+	defer server.Close()
+
+	o := &OpenAI{apiKey: "test-key", model: "gpt-4o", baseURL: server.URL, client: server.Client()}
+	if _, err := o.Review(context.Background(), ReviewRequest{SystemPrompt: "s", UserPrompt: "u"}); err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+}
+
+func TestOllama_ExtraHeadersNoAuthInjection(t *testing.T) {
+	t.Setenv("PRISM_EXTRA_HEADERS", "Authorization:Bearer injected,X-Team:core")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("Authorization should not come from extra headers, got %q", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("X-Team") != "core" {
+			t.Errorf("X-Team = %q, want %q", r.Header.Get("X-Team"), "core")
+		}
+		json.NewEncoder(w).Encode(openaiResponse{
+			Choices: []openaiChoice{{Message: openaiMessage{Role: "assistant", Content: "[]"}}},
+		})
+	}))
+	defer server.Close()
+
+	o := &Ollama{model: "llama3", baseURL: server.URL, client: server.Client()}
+	if _, err := o.Review(context.Background(), ReviewRequest{SystemPrompt: "s", UserPrompt: "u"}); err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// debugEnv names the environment variable that enables provider debug
+// notes on stderr.
+const debugEnv = "PRISM_DEBUG"
+
+// debugf writes a debug note to stderr when PRISM_DEBUG is set to a true
+// value.
+func debugf(format string, args ...any) {
+	if v, err := strconv.ParseBool(os.Getenv(debugEnv)); err != nil || !v {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "debug: "+format+"\n", args...)
+}
+
+// modelOutputLimits holds the maximum output tokens each known model
+// accepts per request. Entries are matched by exact name or as a prefix of
+// a dated or suffixed model name, longest first.
+var modelOutputLimits = map[string]int{
+	"claude-opus-4-6":        128000,
+	"claude-sonnet-4-6":      64000,
+	"claude-haiku-4-5":       64000,
+	"claude-3-5-haiku":       8192,
+	"claude-3-5-sonnet":      8192,
+	"claude-3-haiku":         4096,
+	"claude-3-opus":          4096,
+	"gpt-5":                  128000,
+	"gpt-4.1":                32768,
+	"gpt-4o":                 16384,
+	"gpt-4-turbo":            4096,
+	"gpt-4":                  8192,
+	"gpt-3.5-turbo":          4096,
+	"o1":                     100000,
+	"o3":                     100000,
+	"o4-mini":                100000,
+	"gemini-3-pro-preview":   65536,
+	"gemini-3-flash-preview": 65536,
+	"gemini-2.5":             65536,
+	"gemini-2.0":             8192,
+	"gemini-1.5":             8192,
+}
+
+// outputLimit returns the output token cap for model. ok is false for
+// models with no known cap.
+func outputLimit(model string) (limit int, ok bool) {
+	model = strings.ToLower(model)
+	if n, found := modelOutputLimits[model]; found {
+		return n, true
+	}
+	best := ""
+	for prefix, n := range modelOutputLimits {
+		if len(prefix) > len(best) && strings.HasPrefix(model, prefix) &&
+			(len(model) == len(prefix) || model[len(prefix)] == '-' || model[len(prefix)] == '.') {
+			best, limit = prefix, n
+		}
+	}
+	return limit, best != ""
+}
+
+// clampMaxTokens reduces maxTokens to model's output cap, so a default
+// sized for large models does not make a smaller one reject the request.
+// Models with no known cap are left alone.
+func clampMaxTokens(model string, maxTokens int) int {
+	limit, ok := outputLimit(model)
+	if !ok || maxTokens <= limit {
+		return maxTokens
+	}
+	debugf("clamping max tokens from %d to %d, the output limit of %s", maxTokens, limit, model)
+	return limit
+}
@@ -0,0 +1,60 @@
+package providers
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestClampMaxTokens(t *testing.T) {
+	tests := []struct {
+		model string
+		in    int
+		want  int
+	}{
+		{"gpt-4o", 8192, 8192},
+		{"gpt-4o", 32000, 16384},
+		{"gpt-4o-2024-08-06", 32000, 16384},
+		{"gpt-4o-mini", 32000, 16384},
+		{"gpt-4", 16000, 8192},
+		{"gpt-4-turbo", 8192, 4096},
+		{"claude-3-haiku-20240307", 8192, 4096},
+		{"Claude-3-Haiku-20240307", 8192, 4096},
+		{"claude-sonnet-4-6", 8192, 8192},
+		{"gemini-2.0-flash", 65536, 8192},
+		{"llama3", 1000000, 1000000},
+		{"gpt-40-custom", 1000000, 1000000},
+	}
+	for _, tt := range tests {
+		if got := clampMaxTokens(tt.model, tt.in); got != tt.want {
+			t.Errorf("clampMaxTokens(%q, %d) = %d, want %d", tt.model, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRequestBody_ClampsMaxTokens(t *testing.T) {
+	req := ReviewRequest{SystemPrompt: "sys", UserPrompt: "diff", MaxTokens: 8192}
+
+	body, err := RequestBody(&Anthropic{model: "claude-3-haiku-20240307"}, req)
+	if err != nil {
+		t.Fatalf("RequestBody error: %v", err)
+	}
+	var ar anthropicRequest
+	if err := json.Unmarshal(body, &ar); err != nil {
+		t.Fatal(err)
+	}
+	if ar.MaxTokens != 4096 {
+		t.Errorf("anthropic max_tokens = %d, want 4096", ar.MaxTokens)
+	}
+
+	body, err = RequestBody(&OpenAI{model: "gpt-4-turbo"}, req)
+	if err != nil {
+		t.Fatalf("RequestBody error: %v", err)
+	}
+	var or openaiRequest
+	if err := json.Unmarshal(body, &or); err != nil {
+		t.Fatal(err)
+	}
+	if or.MaxTokens != 4096 {
+		t.Errorf("openai max_tokens = %d, want 4096", or.MaxTokens)
+	}
+}
@@ -0,0 +1,170 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const defaultMistralURL = "https://api.mistral.ai/v1/chat/completions"
+
+// Mistral implements the Reviewer interface for Mistral's chat completions
+// API (Mistral Large, Codestral, and the other hosted models). The API is
+// OpenAI-compatible, so requests and responses share the OpenAI types.
+type Mistral struct {
+	apiKey  string
+	model   string
+	baseURL string
+	client  *http.Client
+}
+
+// NewMistral creates a new Mistral provider.
+func NewMistral(model string) (*Mistral, error) {
+	key := os.Getenv("MISTRAL_API_KEY")
+	if key == "" {
+		return nil, fmt.Errorf("MISTRAL_API_KEY environment variable is not set")
+	}
+	baseURL := os.Getenv("PRISM_MISTRAL_BASE_URL")
+	client := &http.Client{Timeout: 120 * time.Second}
+	if baseURL == "" {
+		baseURL = defaultMistralURL
+	} else {
+		// A custom base URL points at a self-hosted deployment.
+		baseURL = normalizeChatURL(baseURL)
+		client = selfHostedClient(baseURL, 120*time.Second)
+	}
+	return &Mistral{
+		apiKey:  key,
+		model:   model,
+		baseURL: baseURL,
+		client:  client,
+	}, nil
+}
+
+func (m *Mistral) Name() string { return "mistral" }
+
+// requestBody returns the JSON body sent to the chat completions API for
+// req.
+func (m *Mistral) requestBody(req ReviewRequest) ([]byte, error) {
+	return m.buildRequest(req, false)
+}
+
+// streamRequestBody returns the JSON body of a streaming request for req.
+func (m *Mistral) streamRequestBody(req ReviewRequest) ([]byte, error) {
+	return m.buildRequest(req, true)
+}
+
+func (m *Mistral) buildRequest(req ReviewRequest, stream bool) ([]byte, error) {
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = 4096
+	}
+	maxTokens = clampMaxTokens(m.model, maxTokens)
+
+	body := openaiRequest{
+		Model: m.model,
+		Messages: []openaiMessage{
+			{Role: "system", Content: req.SystemPrompt},
+			{Role: "user", Content: req.UserPrompt},
+		},
+		MaxTokens: maxTokens,
+	}
+	if req.Temperature > 0 {
+		body.Temperature = &req.Temperature
+	}
+	// Mistral reports usage in the final chunk of every stream and rejects
+	// OpenAI's stream_options.
+	body.Stream = stream
+	return json.Marshal(body)
+}
+
+// Review sends req, streaming the response if ctx comes from WithStream.
+func (m *Mistral) Review(ctx context.Context, req ReviewRequest) (ReviewResponse, error) {
+	return m.review(ctx, req, streamFrom(ctx))
+}
+
+// ReviewStream sends req, passing response text to onDelta as it arrives.
+func (m *Mistral) ReviewStream(ctx context.Context, req ReviewRequest, onDelta func(string)) (ReviewResponse, error) {
+	return m.review(ctx, req, onDelta)
+}
+
+// review sends req, streaming the response to onDelta when it is non-nil.
+func (m *Mistral) review(ctx context.Context, req ReviewRequest, onDelta func(string)) (ReviewResponse, error) {
+	payload, err := m.buildRequest(req, onDelta != nil)
+	if err != nil {
+		return ReviewResponse{}, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	var resp ReviewResponse
+	resp.Retries, err = retryWithBackoffCount(ctx, 3, func() error {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", m.baseURL, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("creating request: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Authorization", "Bearer "+m.apiKey)
+		applyExtraHeaders(httpReq)
+
+		httpResp, err := m.client.Do(httpReq)
+		if err != nil {
+			return fmt.Errorf("sending request: %w", err)
+		}
+		defer httpResp.Body.Close()
+
+		if onDelta != nil && httpResp.StatusCode == 200 {
+			resp, err = readOpenAIStream(httpResp.Body, onDelta)
+			return err
+		}
+
+		respBody, err := io.ReadAll(httpResp.Body)
+		if err != nil {
+			return fmt.Errorf("reading response: %w", err)
+		}
+
+		if httpResp.StatusCode == 429 {
+			return &rateLimitError{}
+		}
+		if httpResp.StatusCode == 401 || httpResp.StatusCode == 403 {
+			return &authError{message: string(respBody)}
+		}
+		if isModelNotFound(httpResp.StatusCode, string(respBody)) {
+			return &modelError{provider: m.Name(), model: m.model, statusCode: httpResp.StatusCode, body: string(respBody)}
+		}
+		if httpResp.StatusCode >= 500 {
+			return &serverError{statusCode: httpResp.StatusCode, body: string(respBody)}
+		}
+		if httpResp.StatusCode != 200 {
+			return fmt.Errorf("API error (status %d): %s", httpResp.StatusCode, string(respBody))
+		}
+
+		var result openaiResponse
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+
+		if len(result.Choices) == 0 {
+			return fmt.Errorf("no choices in response")
+		}
+		if result.Choices[0].Message.Content == "" {
+			return fmt.Errorf("empty text content in API response")
+		}
+
+		resp = ReviewResponse{
+			Content:      result.Choices[0].Message.Content,
+			TokensUsed:   result.Usage.TotalTokens,
+			InputTokens:  result.Usage.PromptTokens,
+			OutputTokens: result.Usage.CompletionTokens,
+			FinishReason: result.Choices[0].FinishReason,
+			Model:        result.Model,
+			Body:         respBody,
+		}
+		return nil
+	})
+
+	return resp, err
+}
@@ -0,0 +1,112 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMistral_Review(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Error("Missing or wrong Authorization header")
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(openaiResponse{
+			Model:   "mistral-large-2411",
+			Choices: []openaiChoice{{Message: openaiMessage{Role: "assistant", Content: "[]"}, FinishReason: "stop"}},
+			Usage:   openaiUsage{PromptTokens: 40, CompletionTokens: 10, TotalTokens: 50},
+		})
+	}))
+	defer server.Close()
+
+	m := &Mistral{apiKey: "test-key", model: "mistral-large-latest", baseURL: server.URL, client: server.Client()}
+	resp, err := m.Review(context.Background(), ReviewRequest{SystemPrompt: "sys", UserPrompt: "user", MaxTokens: 10})
+	if err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+	if resp.Content != "[]" || resp.Model != "mistral-large-2411" || resp.FinishReason != "stop" {
+		t.Errorf("resp = %+v", resp)
+	}
+	if resp.InputTokens != 40 || resp.OutputTokens != 10 || resp.TokensUsed != 50 {
+		t.Errorf("usage = %d/%d/%d, want 40/10/50", resp.InputTokens, resp.OutputTokens, resp.TokensUsed)
+	}
+	if body["model"] != "mistral-large-latest" || body["max_tokens"] != float64(10) {
+		t.Errorf("request body = %v", body)
+	}
+	if _, ok := body["stream_options"]; ok {
+		t.Error("request should not send stream_options")
+	}
+}
+
+func TestMistral_ReviewStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(data), `"stream":true`) || strings.Contains(string(data), "stream_options") {
+			t.Errorf("request body = %s", data)
+		}
+		io.WriteString(w, "data: {\"model\":\"codestral-2501\",\"choices\":[{\"delta\":{\"content\":\"[\"}}]}\n\n"+
+			"data: {\"choices\":[{\"delta\":{\"content\":\"]\"},\"finish_reason\":\"stop\"}],\"usage\":{\"prompt_tokens\":7,\"completion_tokens\":2,\"total_tokens\":9}}\n\n"+
+			"data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	m := &Mistral{apiKey: "k", model: "codestral-latest", baseURL: server.URL, client: server.Client()}
+	var deltas []string
+	resp, err := m.ReviewStream(context.Background(), ReviewRequest{UserPrompt: "u"}, func(s string) { deltas = append(deltas, s) })
+	if err != nil {
+		t.Fatalf("ReviewStream error: %v", err)
+	}
+	if resp.Content != "[]" || strings.Join(deltas, "|") != "[|]" || resp.TokensUsed != 9 {
+		t.Errorf("resp = %+v, deltas = %q", resp, deltas)
+	}
+}
+
+func TestMistral_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		check  func(error) bool
+	}{
+		{"auth", 401, `{"message":"Unauthorized"}`, IsAuthError},
+		{"model", 400, `{"message":"Invalid model: mistral-huge"}`, IsModelError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			m := &Mistral{apiKey: "k", model: "mistral-huge", baseURL: server.URL, client: server.Client()}
+			_, err := m.Review(context.Background(), ReviewRequest{UserPrompt: "u"})
+			if !tt.check(err) {
+				t.Errorf("err = %v", err)
+			}
+		})
+	}
+}
+
+func TestNewMistral(t *testing.T) {
+	t.Setenv("MISTRAL_API_KEY", "")
+	if _, err := NewMistral("mistral-large-latest"); err == nil || !strings.Contains(err.Error(), "MISTRAL_API_KEY") {
+		t.Errorf("err = %v, want a missing MISTRAL_API_KEY error", err)
+	}
+
+	t.Setenv("MISTRAL_API_KEY", "k")
+	t.Setenv("PRISM_MISTRAL_BASE_URL", "https://mistral.internal/v1")
+	m, err := NewMistral("codestral-latest")
+	if err != nil {
+		t.Fatalf("NewMistral error: %v", err)
+	}
+	if m.baseURL != "https://mistral.internal/v1/chat/completions" {
+		t.Errorf("baseURL = %q", m.baseURL)
+	}
+}
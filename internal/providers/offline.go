@@ -0,0 +1,50 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
+)
+
+// offlineEnv names the environment variable that restricts prism to local
+// providers.
+const offlineEnv = "PRISM_OFFLINE"
+
+// forceOffline is set by SetOffline (the --offline flag).
+var forceOffline atomic.Bool
+
+// SetOffline turns offline mode on or off for the process, as if
+// PRISM_OFFLINE were set.
+func SetOffline(on bool) {
+	forceOffline.Store(on)
+}
+
+// Offline reports whether offline mode is on, via SetOffline or
+// PRISM_OFFLINE set to a true value.
+func Offline() bool {
+	if forceOffline.Load() {
+		return true
+	}
+	v, err := strconv.ParseBool(os.Getenv(offlineEnv))
+	return err == nil && v
+}
+
+// IsLocal reports whether a provider runs on the local machine.
+func IsLocal(provider string) bool {
+	switch provider {
+	case "ollama", "lmstudio":
+		return true
+	default:
+		return false
+	}
+}
+
+// CheckOffline returns an error if offline mode is on and provider is not a
+// local provider. It makes no network calls.
+func CheckOffline(provider string) error {
+	if !Offline() || IsLocal(provider) {
+		return nil
+	}
+	return fmt.Errorf("offline mode (--offline or %s) only permits local providers (ollama, lmstudio); refusing %q", offlineEnv, provider)
+}
@@ -0,0 +1,40 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNew_Offline(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	t.Setenv(offlineEnv, "1")
+
+	if _, err := New("anthropic", ""); err == nil || !strings.Contains(err.Error(), "offline") {
+		t.Errorf("expected offline error for anthropic, got %v", err)
+	}
+	if _, err := New("ollama", "llama3"); err != nil {
+		t.Errorf("ollama should be allowed offline: %v", err)
+	}
+	if _, err := New("lmstudio", "local"); err != nil {
+		t.Errorf("lmstudio should be allowed offline: %v", err)
+	}
+}
+
+func TestSetOffline(t *testing.T) {
+	t.Setenv(offlineEnv, "")
+	t.Cleanup(func() { SetOffline(false) })
+
+	if Offline() {
+		t.Fatal("offline should be off by default")
+	}
+	SetOffline(true)
+	if !Offline() {
+		t.Error("SetOffline(true) should enable offline mode")
+	}
+	if err := CheckOffline("openai"); err == nil {
+		t.Error("expected openai to be refused offline")
+	}
+	if err := CheckOffline("ollama"); err != nil {
+		t.Errorf("ollama should be allowed offline: %v", err)
+	}
+}
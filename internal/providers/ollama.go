@@ -8,55 +8,122 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
 
-const defaultOllamaURL = "http://localhost:11434"
+const (
+	defaultOllamaURL   = "http://localhost:11434"
+	defaultLMStudioURL = "http://localhost:1234/v1"
+)
 
 // Ollama implements the Reviewer interface for Ollama and LM Studio (OpenAI-compatible API).
 type Ollama struct {
+	name    string
 	apiKey  string
 	model   string
 	baseURL string
 	client  *http.Client
+	// noSystem folds the system prompt into the user message for models
+	// whose chat template ignores the system role.
+	noSystem bool
 }
 
 // NewOllama creates a new Ollama provider. No API key is required by default.
+// The server address is read from OLLAMA_HOST.
 func NewOllama(model string) (*Ollama, error) {
-	baseURL := os.Getenv("OLLAMA_HOST")
-	if baseURL == "" {
-		baseURL = defaultOllamaURL
-	}
+	return newLocalOpenAICompat("ollama", model, os.Getenv("OLLAMA_HOST"), defaultOllamaURL), nil
+}
 
-	// Normalize URL: strip trailing /, /v1, /v1/chat/completions
-	baseURL = strings.TrimRight(baseURL, "/")
-	baseURL = strings.TrimSuffix(baseURL, "/v1/chat/completions")
-	baseURL = strings.TrimSuffix(baseURL, "/v1")
+// NewLMStudio creates a provider for LM Studio's local server, which speaks
+// the same OpenAI-compatible API as Ollama but listens on port 1234 by
+// default. The server address is read from LMSTUDIO_HOST.
+func NewLMStudio(model string) (*Ollama, error) {
+	return newLocalOpenAICompat("lmstudio", model, os.Getenv("LMSTUDIO_HOST"), defaultLMStudioURL), nil
+}
+
+// newLocalOpenAICompat builds a provider for a local OpenAI-compatible server
+// at host, falling back to defaultURL when host is empty.
+func newLocalOpenAICompat(name, model, host, defaultURL string) *Ollama {
+	if host == "" {
+		host = defaultURL
+	}
 
 	// Optional API key for servers that require it (e.g., LM Studio)
 	apiKey := os.Getenv("PRISM_OLLAMA_API_KEY")
 
+	baseURL := normalizeChatURL(host)
 	return &Ollama{
-		apiKey:  apiKey,
-		model:   model,
-		baseURL: baseURL + "/v1/chat/completions",
-		client:  &http.Client{Timeout: 300 * time.Second},
-	}, nil
+		name:     name,
+		apiKey:   apiKey,
+		model:    model,
+		baseURL:  baseURL,
+		client:   selfHostedClient(baseURL, 300*time.Second),
+		noSystem: noSystemRole(name, model),
+	}
 }
 
-func (o *Ollama) Name() string { return "ollama" }
+// noSystemRole reports whether PRISM_<PROVIDER>_NO_SYSTEM marks model as
+// lacking a system role. A true value ("1", "true", ...) applies to every
+// model; any other value is a comma-separated list of model names.
+func noSystemRole(provider, model string) bool {
+	v := strings.TrimSpace(os.Getenv(providerEnv(provider, "NO_SYSTEM")))
+	if v == "" {
+		return false
+	}
+	if all, err := strconv.ParseBool(v); err == nil {
+		return all
+	}
+	for _, m := range strings.Split(v, ",") {
+		if strings.EqualFold(strings.TrimSpace(m), model) {
+			return true
+		}
+	}
+	return false
+}
 
-func (o *Ollama) Review(ctx context.Context, req ReviewRequest) (ReviewResponse, error) {
+// foldSystemPrompt prepends the system prompt to the user prompt between
+// delimiters, for models that never see a system message.
+func foldSystemPrompt(system, user string) string {
+	return "--- BEGIN INSTRUCTIONS ---\n" + system + "\n--- END INSTRUCTIONS ---\n\n" + user
+}
+
+// normalizeChatURL turns a server address into its chat completions
+// endpoint. It accepts a bare host, a /v1 base, or the full endpoint, with
+// or without a trailing slash.
+func normalizeChatURL(baseURL string) string {
+	baseURL = strings.TrimRight(baseURL, "/")
+	baseURL = strings.TrimSuffix(baseURL, "/v1/chat/completions")
+	baseURL = strings.TrimSuffix(baseURL, "/v1")
+	return baseURL + "/v1/chat/completions"
+}
+
+func (o *Ollama) Name() string {
+	if o.name == "" {
+		return "ollama"
+	}
+	return o.name
+}
+
+// requestBody returns the JSON body sent to the chat completions endpoint
+// for req.
+func (o *Ollama) requestBody(req ReviewRequest) ([]byte, error) {
 	maxTokens := req.MaxTokens
 	if maxTokens == 0 {
 		maxTokens = 4096
 	}
+	maxTokens = clampMaxTokens(o.model, maxTokens)
 
 	messages := []openaiMessage{
 		{Role: "system", Content: req.SystemPrompt},
 		{Role: "user", Content: req.UserPrompt},
 	}
+	if o.noSystem {
+		messages = []openaiMessage{
+			{Role: "user", Content: foldSystemPrompt(req.SystemPrompt, req.UserPrompt)},
+		}
+	}
 
 	body := openaiRequest{
 		Model:     o.model,
@@ -66,14 +133,17 @@ func (o *Ollama) Review(ctx context.Context, req ReviewRequest) (ReviewResponse,
 	if req.Temperature > 0 {
 		body.Temperature = &req.Temperature
 	}
+	return json.Marshal(body)
+}
 
-	payload, err := json.Marshal(body)
+func (o *Ollama) Review(ctx context.Context, req ReviewRequest) (ReviewResponse, error) {
+	payload, err := o.requestBody(req)
 	if err != nil {
 		return ReviewResponse{}, fmt.Errorf("marshaling request: %w", err)
 	}
 
 	var resp ReviewResponse
-	err = retryWithBackoff(ctx, 3, func() error {
+	resp.Retries, err = retryWithBackoffCount(ctx, 3, func() error {
 		httpReq, err := http.NewRequestWithContext(ctx, "POST", o.baseURL, bytes.NewReader(payload))
 		if err != nil {
 			return fmt.Errorf("creating request: %w", err)
@@ -82,6 +152,7 @@ func (o *Ollama) Review(ctx context.Context, req ReviewRequest) (ReviewResponse,
 		if o.apiKey != "" {
 			httpReq.Header.Set("Authorization", "Bearer "+o.apiKey)
 		}
+		applyExtraHeaders(httpReq)
 
 		httpResp, err := o.client.Do(httpReq)
 		if err != nil {
@@ -100,6 +171,9 @@ func (o *Ollama) Review(ctx context.Context, req ReviewRequest) (ReviewResponse,
 		if httpResp.StatusCode == 401 || httpResp.StatusCode == 403 {
 			return &authError{message: string(respBody)}
 		}
+		if isModelNotFound(httpResp.StatusCode, string(respBody)) {
+			return &modelError{provider: o.Name(), model: o.model, statusCode: httpResp.StatusCode, body: string(respBody)}
+		}
 		if httpResp.StatusCode >= 500 {
 			return &serverError{statusCode: httpResp.StatusCode, body: string(respBody)}
 		}
@@ -120,8 +194,13 @@ func (o *Ollama) Review(ctx context.Context, req ReviewRequest) (ReviewResponse,
 		}
 
 		resp = ReviewResponse{
-			Content:    result.Choices[0].Message.Content,
-			TokensUsed: result.Usage.TotalTokens,
+			Content:      result.Choices[0].Message.Content,
+			TokensUsed:   result.Usage.TotalTokens,
+			InputTokens:  result.Usage.PromptTokens,
+			OutputTokens: result.Usage.CompletionTokens,
+			FinishReason: result.Choices[0].FinishReason,
+			Model:        result.Model,
+			Body:         respBody,
 		}
 		return nil
 	})
@@ -190,16 +190,145 @@ func TestNewOllama_URLNormalization(t *testing.T) {
 	}
 }
 
-func TestFactory_OllamaAliases(t *testing.T) {
+func TestNewLMStudio_URLNormalization(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    string
+		wantURL string
+	}{
+		{
+			name:    "default",
+			host:    "",
+			wantURL: "http://localhost:1234/v1/chat/completions",
+		},
+		{
+			name:    "bare host",
+			host:    "http://localhost:1234",
+			wantURL: "http://localhost:1234/v1/chat/completions",
+		},
+		{
+			name:    "with v1 and trailing slash",
+			host:    "http://localhost:1234/v1/",
+			wantURL: "http://localhost:1234/v1/chat/completions",
+		},
+		{
+			name:    "custom host with full path",
+			host:    "http://10.0.0.5:5000/v1/chat/completions",
+			wantURL: "http://10.0.0.5:5000/v1/chat/completions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LMSTUDIO_HOST", tt.host)
+			t.Setenv("OLLAMA_HOST", "http://ignored:11434")
+			t.Setenv("PRISM_OLLAMA_API_KEY", "")
+
+			o, err := NewLMStudio("qwen2.5-coder")
+			if err != nil {
+				t.Fatalf("NewLMStudio error: %v", err)
+			}
+			if o.baseURL != tt.wantURL {
+				t.Errorf("baseURL = %q, want %q", o.baseURL, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestFactory_LocalProviders(t *testing.T) {
 	t.Setenv("OLLAMA_HOST", "http://localhost:11434")
+	t.Setenv("LMSTUDIO_HOST", "")
 
-	for _, name := range []string{"ollama", "lmstudio"} {
-		r, err := New(name, "llama3")
+	tests := []struct {
+		provider string
+		wantName string
+		wantURL  string
+	}{
+		{"ollama", "ollama", "http://localhost:11434/v1/chat/completions"},
+		{"lmstudio", "lmstudio", "http://localhost:1234/v1/chat/completions"},
+	}
+	for _, tt := range tests {
+		r, err := New(tt.provider, "llama3")
 		if err != nil {
-			t.Fatalf("New(%q) error: %v", name, err)
+			t.Fatalf("New(%q) error: %v", tt.provider, err)
+		}
+		if r.Name() != tt.wantName {
+			t.Errorf("New(%q).Name() = %q, want %q", tt.provider, r.Name(), tt.wantName)
 		}
-		if r.Name() != "ollama" {
-			t.Errorf("New(%q).Name() = %q, want %q", name, r.Name(), "ollama")
+		if got := unwrap(r).(*Ollama).baseURL; got != tt.wantURL {
+			t.Errorf("New(%q) baseURL = %q, want %q", tt.provider, got, tt.wantURL)
 		}
 	}
 }
+
+func TestOllama_FinishReasonAndModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := openaiResponse{
+			Model: "llama3:latest",
+			Choices: []openaiChoice{
+				{Message: openaiMessage{Role: "assistant", Content: "[]"}, FinishReason: "length"},
+			},
+			Usage: openaiUsage{TotalTokens: 100},
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	o := &Ollama{
+		model:   "llama3",
+		baseURL: server.URL,
+		client:  server.Client(),
+	}
+
+	resp, err := o.Review(context.Background(), ReviewRequest{SystemPrompt: "test", UserPrompt: "test"})
+	if err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+	if resp.FinishReason != "length" {
+		t.Errorf("FinishReason = %q, want %q", resp.FinishReason, "length")
+	}
+	if resp.Model != "llama3:latest" {
+		t.Errorf("Model = %q, want %q", resp.Model, "llama3:latest")
+	}
+}
+
+func TestOllama_NoSystemRole(t *testing.T) {
+	t.Setenv("PRISM_OLLAMA_NO_SYSTEM", "gemma:2b, phi3")
+
+	o, err := NewOllama("phi3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := o.requestBody(ReviewRequest{SystemPrompt: "be strict", UserPrompt: "the diff"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var req openaiRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.Fatal(err)
+	}
+	if len(req.Messages) != 1 || req.Messages[0].Role != "user" {
+		t.Fatalf("messages = %+v, want a single user message", req.Messages)
+	}
+	want := "--- BEGIN INSTRUCTIONS ---\nbe strict\n--- END INSTRUCTIONS ---\n\nthe diff"
+	if req.Messages[0].Content != want {
+		t.Errorf("content = %q, want %q", req.Messages[0].Content, want)
+	}
+
+	// Models not in the list keep the system role
+	o, _ = NewOllama("llama3")
+	body, _ = o.requestBody(ReviewRequest{SystemPrompt: "be strict", UserPrompt: "the diff"})
+	req = openaiRequest{}
+	json.Unmarshal(body, &req)
+	if len(req.Messages) != 2 || req.Messages[0].Role != "system" {
+		t.Errorf("messages = %+v, want system and user messages", req.Messages)
+	}
+
+	t.Setenv("PRISM_OLLAMA_NO_SYSTEM", "1")
+	if !noSystemRole("ollama", "llama3") {
+		t.Error("a true value should apply to every model")
+	}
+	if noSystemRole("lmstudio", "llama3") {
+		t.Error("PRISM_OLLAMA_NO_SYSTEM should not apply to lmstudio")
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -20,6 +22,13 @@ type OpenAI struct {
 	model   string
 	baseURL string
 	client  *http.Client
+	// organization and project attribute usage for billing; sent as the
+	// OpenAI-Organization and OpenAI-Project headers when set.
+	organization string
+	project      string
+	// noJSONSchema is set once the model rejects structured outputs, so
+	// later requests skip them.
+	noJSONSchema atomic.Bool
 }
 
 // NewOpenAI creates a new OpenAI provider.
@@ -29,24 +38,51 @@ func NewOpenAI(model string) (*OpenAI, error) {
 		return nil, fmt.Errorf("OPENAI_API_KEY environment variable is not set")
 	}
 	baseURL := os.Getenv("PRISM_OPENAI_BASE_URL")
+	client := &http.Client{Timeout: 120 * time.Second}
 	if baseURL == "" {
 		baseURL = defaultOpenAIURL
+	} else {
+		// A custom base URL points at a self-hosted or compatible gateway.
+		client = selfHostedClient(baseURL, 120*time.Second)
 	}
 	return &OpenAI{
-		apiKey:  key,
-		model:   model,
-		baseURL: baseURL,
-		client:  &http.Client{Timeout: 120 * time.Second},
+		apiKey:       key,
+		model:        model,
+		baseURL:      baseURL,
+		client:       client,
+		organization: strings.TrimSpace(os.Getenv("OPENAI_ORG_ID")),
+		project:      strings.TrimSpace(os.Getenv("OPENAI_PROJECT_ID")),
 	}, nil
 }
 
 func (o *OpenAI) Name() string { return "openai" }
 
-func (o *OpenAI) Review(ctx context.Context, req ReviewRequest) (ReviewResponse, error) {
+// requestBody returns the JSON body sent to the chat completions API for
+// req.
+func (o *OpenAI) requestBody(req ReviewRequest) ([]byte, error) {
+	return o.buildRequest(req, false, o.jsonSchemaMode(req, false))
+}
+
+// streamRequestBody returns the JSON body of a streaming request for req.
+func (o *OpenAI) streamRequestBody(req ReviewRequest) ([]byte, error) {
+	return o.buildRequest(req, true, false)
+}
+
+// jsonSchemaMode reports whether req asks for structured outputs: findings
+// requests that are not streamed (streams stay plain text so findings can
+// be parsed as they arrive), unless the model has rejected them.
+func (o *OpenAI) jsonSchemaMode(req ReviewRequest, stream bool) bool {
+	return req.FindingsJSON && !stream && !o.noJSONSchema.Load()
+}
+
+// buildRequest assembles the request for req, constraining the response to
+// the findings schema when jsonSchema is set.
+func (o *OpenAI) buildRequest(req ReviewRequest, stream, jsonSchema bool) ([]byte, error) {
 	maxTokens := req.MaxTokens
 	if maxTokens == 0 {
 		maxTokens = 4096
 	}
+	maxTokens = clampMaxTokens(o.model, maxTokens)
 
 	messages := []openaiMessage{
 		{Role: "system", Content: req.SystemPrompt},
@@ -66,20 +102,68 @@ func (o *OpenAI) Review(ctx context.Context, req ReviewRequest) (ReviewResponse,
 	if req.Temperature > 0 {
 		body.Temperature = &req.Temperature
 	}
+	if stream {
+		body.Stream = true
+		body.StreamOptions = &openaiStreamOptions{IncludeUsage: true}
+	}
+	if jsonSchema {
+		body.ResponseFormat = &openaiResponseFormat{
+			Type: "json_schema",
+			JSONSchema: &openaiJSONSchema{
+				Name:   "findings",
+				Strict: true,
+				Schema: findingsObjectSchema,
+			},
+		}
+	}
+	return json.Marshal(body)
+}
+
+// Review sends req, streaming the response if ctx comes from WithStream.
+func (o *OpenAI) Review(ctx context.Context, req ReviewRequest) (ReviewResponse, error) {
+	return o.review(ctx, req, streamFrom(ctx))
+}
+
+// ReviewStream sends req, passing response text to onDelta as it arrives.
+func (o *OpenAI) ReviewStream(ctx context.Context, req ReviewRequest, onDelta func(string)) (ReviewResponse, error) {
+	return o.review(ctx, req, onDelta)
+}
+
+// review sends req, streaming the response to onDelta when it is non-nil.
+func (o *OpenAI) review(ctx context.Context, req ReviewRequest, onDelta func(string)) (ReviewResponse, error) {
+	jsonSchema := o.jsonSchemaMode(req, onDelta != nil)
+	resp, err := o.send(ctx, req, onDelta, jsonSchema)
+	var rejected *jsonModeError
+	if jsonSchema && errors.As(err, &rejected) {
+		// Older models and compatible gateways reject structured outputs;
+		// fall back to a plain request and rely on parsing and repair.
+		o.noJSONSchema.Store(true)
+		return o.send(ctx, req, onDelta, false)
+	}
+	return resp, err
+}
 
-	payload, err := json.Marshal(body)
+func (o *OpenAI) send(ctx context.Context, req ReviewRequest, onDelta func(string), jsonSchema bool) (ReviewResponse, error) {
+	payload, err := o.buildRequest(req, onDelta != nil, jsonSchema)
 	if err != nil {
 		return ReviewResponse{}, fmt.Errorf("marshaling request: %w", err)
 	}
 
 	var resp ReviewResponse
-	err = retryWithBackoff(ctx, 3, func() error {
+	resp.Retries, err = retryWithBackoffCount(ctx, 3, func() error {
 		httpReq, err := http.NewRequestWithContext(ctx, "POST", o.baseURL, bytes.NewReader(payload))
 		if err != nil {
 			return fmt.Errorf("creating request: %w", err)
 		}
 		httpReq.Header.Set("Content-Type", "application/json")
 		httpReq.Header.Set("Authorization", "Bearer "+o.apiKey)
+		if o.organization != "" {
+			httpReq.Header.Set("OpenAI-Organization", o.organization)
+		}
+		if o.project != "" {
+			httpReq.Header.Set("OpenAI-Project", o.project)
+		}
+		applyExtraHeaders(httpReq)
 
 		httpResp, err := o.client.Do(httpReq)
 		if err != nil {
@@ -87,6 +171,11 @@ func (o *OpenAI) Review(ctx context.Context, req ReviewRequest) (ReviewResponse,
 		}
 		defer httpResp.Body.Close()
 
+		if onDelta != nil && httpResp.StatusCode == 200 {
+			resp, err = readOpenAIStream(httpResp.Body, onDelta)
+			return err
+		}
+
 		respBody, err := io.ReadAll(httpResp.Body)
 		if err != nil {
 			return fmt.Errorf("reading response: %w", err)
@@ -98,6 +187,12 @@ func (o *OpenAI) Review(ctx context.Context, req ReviewRequest) (ReviewResponse,
 		if httpResp.StatusCode == 401 || httpResp.StatusCode == 403 {
 			return &authError{message: string(respBody)}
 		}
+		if httpResp.StatusCode == 400 && jsonSchema && mentionsJSONMode(string(respBody)) {
+			return &jsonModeError{body: string(respBody)}
+		}
+		if isModelNotFound(httpResp.StatusCode, string(respBody)) {
+			return &modelError{provider: o.Name(), model: o.model, statusCode: httpResp.StatusCode, body: string(respBody)}
+		}
 		if httpResp.StatusCode >= 500 {
 			return &serverError{statusCode: httpResp.StatusCode, body: string(respBody)}
 		}
@@ -117,9 +212,18 @@ func (o *OpenAI) Review(ctx context.Context, req ReviewRequest) (ReviewResponse,
 			return fmt.Errorf("empty text content in API response")
 		}
 
+		content := result.Choices[0].Message.Content
+		if jsonSchema {
+			content = unwrapFindings([]byte(content))
+		}
 		resp = ReviewResponse{
-			Content:    result.Choices[0].Message.Content,
-			TokensUsed: result.Usage.TotalTokens,
+			Content:      content,
+			TokensUsed:   result.Usage.TotalTokens,
+			InputTokens:  result.Usage.PromptTokens,
+			OutputTokens: result.Usage.CompletionTokens,
+			FinishReason: result.Choices[0].FinishReason,
+			Model:        result.Model,
+			Body:         respBody,
 		}
 		return nil
 	})
@@ -128,11 +232,29 @@ func (o *OpenAI) Review(ctx context.Context, req ReviewRequest) (ReviewResponse,
 }
 
 type openaiRequest struct {
-	Model               string          `json:"model"`
-	Messages            []openaiMessage `json:"messages"`
-	MaxTokens           int             `json:"max_tokens,omitempty"`
-	MaxCompletionTokens int             `json:"max_completion_tokens,omitempty"`
-	Temperature         *float64        `json:"temperature,omitempty"`
+	Model               string                `json:"model"`
+	Messages            []openaiMessage       `json:"messages"`
+	MaxTokens           int                   `json:"max_tokens,omitempty"`
+	MaxCompletionTokens int                   `json:"max_completion_tokens,omitempty"`
+	Temperature         *float64              `json:"temperature,omitempty"`
+	Stream              bool                  `json:"stream,omitempty"`
+	StreamOptions       *openaiStreamOptions  `json:"stream_options,omitempty"`
+	ResponseFormat      *openaiResponseFormat `json:"response_format,omitempty"`
+}
+
+type openaiResponseFormat struct {
+	Type       string            `json:"type"`
+	JSONSchema *openaiJSONSchema `json:"json_schema,omitempty"`
+}
+
+type openaiJSONSchema struct {
+	Name   string         `json:"name"`
+	Strict bool           `json:"strict"`
+	Schema map[string]any `json:"schema"`
+}
+
+type openaiStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
 }
 
 // usesMaxCompletionTokens returns true for models that require
@@ -150,14 +272,70 @@ type openaiMessage struct {
 }
 
 type openaiResponse struct {
+	Model   string         `json:"model"`
 	Choices []openaiChoice `json:"choices"`
 	Usage   openaiUsage    `json:"usage"`
 }
 
 type openaiChoice struct {
-	Message openaiMessage `json:"message"`
+	Message      openaiMessage `json:"message"`
+	FinishReason string        `json:"finish_reason"`
 }
 
 type openaiUsage struct {
-	TotalTokens int `json:"total_tokens"`
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// openaiChunk is one server-sent event of a streaming chat completion.
+type openaiChunk struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Delta        openaiMessage `json:"delta"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *openaiUsage `json:"usage"`
+}
+
+// readOpenAIStream collects a streaming chat completion, passing each
+// content delta to onDelta.
+func readOpenAIStream(body io.Reader, onDelta func(string)) (ReviewResponse, error) {
+	var resp ReviewResponse
+	var raw bytes.Buffer
+	body = io.TeeReader(body, &raw)
+	var content strings.Builder
+	err := readSSE(body, func(data string) error {
+		var chunk openaiChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("parsing stream chunk: %w", err)
+		}
+		if chunk.Model != "" {
+			resp.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			resp.TokensUsed = chunk.Usage.TotalTokens
+			resp.InputTokens = chunk.Usage.PromptTokens
+			resp.OutputTokens = chunk.Usage.CompletionTokens
+		}
+		if len(chunk.Choices) > 0 {
+			if text := chunk.Choices[0].Delta.Content; text != "" {
+				content.WriteString(text)
+				onDelta(text)
+			}
+			if r := chunk.Choices[0].FinishReason; r != "" {
+				resp.FinishReason = r
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return ReviewResponse{}, err
+	}
+	if content.Len() == 0 {
+		return ReviewResponse{}, fmt.Errorf("empty text content in API response")
+	}
+	resp.Content = content.String()
+	resp.Body = raw.Bytes()
+	return resp, nil
 }
@@ -86,3 +86,147 @@ func TestOpenAI_RateLimit(t *testing.T) {
 		t.Errorf("Expected 3 attempts (2 retries), got %d", attempts)
 	}
 }
+
+func TestOpenAI_FinishReasonAndModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := openaiResponse{
+			Model: "gpt-4o-2024-08-06",
+			Choices: []openaiChoice{
+				{Message: openaiMessage{Role: "assistant", Content: "[]"}, FinishReason: "stop"},
+			},
+			Usage: openaiUsage{TotalTokens: 50},
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	o := &OpenAI{
+		apiKey:  "test-key",
+		model:   "gpt-4o",
+		baseURL: server.URL,
+		client:  server.Client(),
+	}
+
+	resp, err := o.Review(context.Background(), ReviewRequest{SystemPrompt: "test", UserPrompt: "test"})
+	if err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+	if resp.FinishReason != "stop" {
+		t.Errorf("FinishReason = %q, want %q", resp.FinishReason, "stop")
+	}
+	if resp.Model != "gpt-4o-2024-08-06" {
+		t.Errorf("Model = %q, want %q", resp.Model, "gpt-4o-2024-08-06")
+	}
+	if resp.Truncated() {
+		t.Error("Truncated() should be false for stop")
+	}
+}
+
+func TestOpenAI_OrganizationHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		org     string
+		project string
+	}{
+		{"unset", "", ""},
+		{"set", "org-finance", "proj_review"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header.Clone()
+				json.NewEncoder(w).Encode(openaiResponse{Choices: []openaiChoice{{Message: openaiMessage{Content: "[]"}}}})
+			}))
+			defer server.Close()
+
+			t.Setenv("OPENAI_API_KEY", "test-key")
+			t.Setenv("PRISM_OPENAI_BASE_URL", server.URL)
+			t.Setenv("OPENAI_ORG_ID", tt.org)
+			t.Setenv("OPENAI_PROJECT_ID", tt.project)
+			o, err := NewOpenAI("gpt-4o")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := o.Review(context.Background(), ReviewRequest{SystemPrompt: "test", UserPrompt: "test"}); err != nil {
+				t.Fatalf("Review error: %v", err)
+			}
+			for name, want := range map[string]string{"OpenAI-Organization": tt.org, "OpenAI-Project": tt.project} {
+				if present := len(header.Values(name)) > 0; present != (want != "") || header.Get(name) != want {
+					t.Errorf("%s = %q (present %v), want %q", name, header.Get(name), present, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAI_StructuredOutputs(t *testing.T) {
+	var formats []*openaiResponseFormat
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openaiRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		formats = append(formats, req.ResponseFormat)
+		content := "[]"
+		if req.ResponseFormat != nil {
+			content = `{"findings":[{"severity":"low","title":"Nit"}]}`
+		}
+		json.NewEncoder(w).Encode(openaiResponse{
+			Choices: []openaiChoice{{Message: openaiMessage{Role: "assistant", Content: content}}},
+		})
+	}))
+	defer server.Close()
+
+	o := &OpenAI{apiKey: "test-key", model: "gpt-5.2", baseURL: server.URL, client: server.Client()}
+	resp, err := o.Review(context.Background(), ReviewRequest{SystemPrompt: "s", UserPrompt: "u", FindingsJSON: true})
+	if err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+	if resp.Content != `[{"severity":"low","title":"Nit"}]` {
+		t.Errorf("Content = %q, want the unwrapped findings array", resp.Content)
+	}
+	if _, err := o.Review(context.Background(), ReviewRequest{SystemPrompt: "s", UserPrompt: "u"}); err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+
+	f := formats[0]
+	if f == nil || f.Type != "json_schema" || f.JSONSchema == nil || !f.JSONSchema.Strict || f.JSONSchema.Schema["type"] != "object" {
+		t.Errorf("response_format = %+v, want a strict findings schema", f)
+	}
+	if formats[1] != nil {
+		t.Errorf("non-findings request should not set response_format, got %+v", formats[1])
+	}
+}
+
+func TestOpenAI_StructuredOutputsFallback(t *testing.T) {
+	var withSchema []bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openaiRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		withSchema = append(withSchema, req.ResponseFormat != nil)
+		if req.ResponseFormat != nil {
+			w.WriteHeader(400)
+			w.Write([]byte(`{"error":{"message":"Invalid parameter: 'response_format' of type 'json_schema' is not supported with this model.","type":"invalid_request_error"}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(openaiResponse{
+			Choices: []openaiChoice{{Message: openaiMessage{Role: "assistant", Content: "[]"}}},
+		})
+	}))
+	defer server.Close()
+
+	o := &OpenAI{apiKey: "test-key", model: "gpt-4-turbo", baseURL: server.URL, client: server.Client()}
+	for i := 0; i < 2; i++ {
+		resp, err := o.Review(context.Background(), ReviewRequest{SystemPrompt: "s", UserPrompt: "u", FindingsJSON: true})
+		if err != nil {
+			t.Fatalf("Review error: %v", err)
+		}
+		if resp.Content != "[]" {
+			t.Errorf("Content = %q, want []", resp.Content)
+		}
+	}
+	// One rejected structured attempt, then plain requests only.
+	if len(withSchema) != 3 || !withSchema[0] || withSchema[1] || withSchema[2] {
+		t.Errorf("requests with response_format = %v, want [true false false]", withSchema)
+	}
+}
@@ -0,0 +1,194 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const defaultOpenRouterURL = "https://openrouter.ai/api/v1/chat/completions"
+
+// OpenRouter implements the Reviewer interface for OpenRouter, which routes
+// OpenAI-compatible chat completions to models from many vendors with one
+// API key. Model names are OpenRouter's vendor-qualified slugs (e.g.
+// "anthropic/claude-3.5-sonnet") and are sent verbatim.
+type OpenRouter struct {
+	apiKey  string
+	model   string
+	baseURL string
+	client  *http.Client
+	// siteURL and appName identify the caller on OpenRouter's dashboards;
+	// sent as the HTTP-Referer and X-Title headers.
+	siteURL string
+	appName string
+}
+
+// NewOpenRouter creates a new OpenRouter provider.
+func NewOpenRouter(model string) (*OpenRouter, error) {
+	key := os.Getenv("OPENROUTER_API_KEY")
+	if key == "" {
+		return nil, fmt.Errorf("OPENROUTER_API_KEY environment variable is not set")
+	}
+	appName := strings.TrimSpace(os.Getenv("OPENROUTER_APP_NAME"))
+	if appName == "" {
+		appName = "prism"
+	}
+	return &OpenRouter{
+		apiKey:  key,
+		model:   model,
+		baseURL: defaultOpenRouterURL,
+		client:  &http.Client{Timeout: 120 * time.Second},
+		siteURL: strings.TrimSpace(os.Getenv("OPENROUTER_SITE_URL")),
+		appName: appName,
+	}, nil
+}
+
+func (o *OpenRouter) Name() string { return "openrouter" }
+
+// requestBody returns the JSON body sent to the chat completions API for
+// req.
+func (o *OpenRouter) requestBody(req ReviewRequest) ([]byte, error) {
+	return o.buildRequest(req, false)
+}
+
+// streamRequestBody returns the JSON body of a streaming request for req.
+func (o *OpenRouter) streamRequestBody(req ReviewRequest) ([]byte, error) {
+	return o.buildRequest(req, true)
+}
+
+func (o *OpenRouter) buildRequest(req ReviewRequest, stream bool) ([]byte, error) {
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = 4096
+	}
+	maxTokens = clampMaxTokens(vendorModel(o.model), maxTokens)
+
+	body := openaiRequest{
+		Model: o.model,
+		Messages: []openaiMessage{
+			{Role: "system", Content: req.SystemPrompt},
+			{Role: "user", Content: req.UserPrompt},
+		},
+		MaxTokens: maxTokens,
+	}
+	if req.Temperature > 0 {
+		body.Temperature = &req.Temperature
+	}
+	if stream {
+		body.Stream = true
+		body.StreamOptions = &openaiStreamOptions{IncludeUsage: true}
+	}
+	return json.Marshal(body)
+}
+
+// vendorModel maps an OpenRouter model slug to the vendor's own model name,
+// so "anthropic/claude-sonnet-4.6" matches the built-in tables for
+// "claude-sonnet-4-6". OpenRouter writes Claude versions with dots where
+// Anthropic uses dashes.
+func vendorModel(model string) string {
+	vendor, name, ok := strings.Cut(model, "/")
+	if !ok {
+		return model
+	}
+	if vendor == "anthropic" {
+		name = strings.ReplaceAll(name, ".", "-")
+	}
+	return name
+}
+
+// Review sends req, streaming the response if ctx comes from WithStream.
+func (o *OpenRouter) Review(ctx context.Context, req ReviewRequest) (ReviewResponse, error) {
+	return o.review(ctx, req, streamFrom(ctx))
+}
+
+// ReviewStream sends req, passing response text to onDelta as it arrives.
+func (o *OpenRouter) ReviewStream(ctx context.Context, req ReviewRequest, onDelta func(string)) (ReviewResponse, error) {
+	return o.review(ctx, req, onDelta)
+}
+
+// review sends req, streaming the response to onDelta when it is non-nil.
+func (o *OpenRouter) review(ctx context.Context, req ReviewRequest, onDelta func(string)) (ReviewResponse, error) {
+	payload, err := o.buildRequest(req, onDelta != nil)
+	if err != nil {
+		return ReviewResponse{}, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	var resp ReviewResponse
+	resp.Retries, err = retryWithBackoffCount(ctx, 3, func() error {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", o.baseURL, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("creating request: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Authorization", "Bearer "+o.apiKey)
+		if o.siteURL != "" {
+			httpReq.Header.Set("HTTP-Referer", o.siteURL)
+		}
+		httpReq.Header.Set("X-Title", o.appName)
+		applyExtraHeaders(httpReq)
+
+		httpResp, err := o.client.Do(httpReq)
+		if err != nil {
+			return fmt.Errorf("sending request: %w", err)
+		}
+		defer httpResp.Body.Close()
+
+		if onDelta != nil && httpResp.StatusCode == 200 {
+			resp, err = readOpenAIStream(httpResp.Body, onDelta)
+			return err
+		}
+
+		respBody, err := io.ReadAll(httpResp.Body)
+		if err != nil {
+			return fmt.Errorf("reading response: %w", err)
+		}
+
+		if httpResp.StatusCode == 429 {
+			return &rateLimitError{}
+		}
+		// 402 means the account is out of credits, which no retry fixes
+		if httpResp.StatusCode == 401 || httpResp.StatusCode == 402 || httpResp.StatusCode == 403 {
+			return &authError{message: string(respBody)}
+		}
+		if isModelNotFound(httpResp.StatusCode, string(respBody)) {
+			return &modelError{provider: o.Name(), model: o.model, statusCode: httpResp.StatusCode, body: string(respBody)}
+		}
+		if httpResp.StatusCode >= 500 {
+			return &serverError{statusCode: httpResp.StatusCode, body: string(respBody)}
+		}
+		if httpResp.StatusCode != 200 {
+			return fmt.Errorf("API error (status %d): %s", httpResp.StatusCode, string(respBody))
+		}
+
+		var result openaiResponse
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+
+		if len(result.Choices) == 0 {
+			return fmt.Errorf("no choices in response")
+		}
+		if result.Choices[0].Message.Content == "" {
+			return fmt.Errorf("empty text content in API response")
+		}
+
+		resp = ReviewResponse{
+			Content:      result.Choices[0].Message.Content,
+			TokensUsed:   result.Usage.TotalTokens,
+			InputTokens:  result.Usage.PromptTokens,
+			OutputTokens: result.Usage.CompletionTokens,
+			FinishReason: result.Choices[0].FinishReason,
+			Model:        result.Model,
+			Body:         respBody,
+		}
+		return nil
+	})
+
+	return resp, err
+}
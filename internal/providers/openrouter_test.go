@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenRouter_Review(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Error("Missing or wrong Authorization header")
+		}
+		if r.Header.Get("X-Title") != "prism" || r.Header.Get("HTTP-Referer") != "https://example.com" {
+			t.Errorf("attribution headers = %q, %q", r.Header.Get("X-Title"), r.Header.Get("HTTP-Referer"))
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(openaiResponse{
+			Model:   "anthropic/claude-3.5-sonnet",
+			Choices: []openaiChoice{{Message: openaiMessage{Role: "assistant", Content: "[]"}, FinishReason: "stop"}},
+			Usage:   openaiUsage{PromptTokens: 30, CompletionTokens: 5, TotalTokens: 35},
+		})
+	}))
+	defer server.Close()
+
+	o := &OpenRouter{
+		apiKey:  "test-key",
+		model:   "anthropic/claude-3.5-sonnet",
+		baseURL: server.URL,
+		client:  server.Client(),
+		siteURL: "https://example.com",
+		appName: "prism",
+	}
+	resp, err := o.Review(context.Background(), ReviewRequest{SystemPrompt: "sys", UserPrompt: "user", MaxTokens: 20000})
+	if err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+	if resp.Content != "[]" || resp.InputTokens != 30 || resp.OutputTokens != 5 {
+		t.Errorf("resp = %+v", resp)
+	}
+	if body["model"] != "anthropic/claude-3.5-sonnet" {
+		t.Errorf("model = %v, want the slug passed through verbatim", body["model"])
+	}
+	// claude-3-5-sonnet caps output at 8192 tokens
+	if body["max_tokens"] != float64(8192) {
+		t.Errorf("max_tokens = %v, want 8192", body["max_tokens"])
+	}
+}
+
+func TestOpenRouter_OutOfCredits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(402)
+		io.WriteString(w, `{"error":{"message":"Insufficient credits"}}`)
+	}))
+	defer server.Close()
+
+	o := &OpenRouter{apiKey: "k", model: "openai/gpt-5.2", baseURL: server.URL, client: server.Client(), appName: "prism"}
+	_, err := o.Review(context.Background(), ReviewRequest{UserPrompt: "u"})
+	if !IsAuthError(err) || !strings.Contains(err.Error(), "Insufficient credits") {
+		t.Errorf("err = %v, want a non-retried auth error", err)
+	}
+}
+
+func TestNew_OpenRouter(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "k")
+	t.Setenv("OPENROUTER_APP_NAME", "")
+	r, err := New("openrouter", "meta-llama/llama-3.1-8b-instruct:free")
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	o := unwrap(r).(*OpenRouter)
+	if o.model != "meta-llama/llama-3.1-8b-instruct:free" || o.appName != "prism" {
+		t.Errorf("provider = %+v", o)
+	}
+}
+
+func TestLookupPrice_OpenRouter(t *testing.T) {
+	tests := []struct {
+		model string
+		want  Price
+		ok    bool
+	}{
+		{"anthropic/claude-sonnet-4.6", modelPrices["claude-sonnet-4-6"], true},
+		{"openai/gpt-5.2", modelPrices["gpt-5.2"], true},
+		{"qwen/qwen3-coder", Price{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := LookupPrice("openrouter", tt.model)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("LookupPrice(openrouter, %q) = %v, %v; want %v, %v", tt.model, got, ok, tt.want, tt.ok)
+		}
+	}
+}
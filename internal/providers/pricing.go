@@ -0,0 +1,59 @@
+package providers
+
+import "strings"
+
+// Price is a model's list price in US dollars per million tokens.
+type Price struct {
+	InputPerMTok  float64
+	OutputPerMTok float64
+}
+
+// modelPrices holds list prices for the models prism knows about. Prices
+// change; they are used only for estimates: the pre-flight --max-cost check
+// and the cost of completed calls reported by UsageTracker.
+var modelPrices = map[string]Price{
+	"claude-opus-4-6":        {InputPerMTok: 5, OutputPerMTok: 25},
+	"claude-sonnet-4-6":      {InputPerMTok: 3, OutputPerMTok: 15},
+	"claude-haiku-4-5":       {InputPerMTok: 1, OutputPerMTok: 5},
+	"gpt-5.3-codex":          {InputPerMTok: 1.75, OutputPerMTok: 14},
+	"gpt-5.3-codex-spark":    {InputPerMTok: 1.75, OutputPerMTok: 14},
+	"gpt-5.2-codex":          {InputPerMTok: 1.75, OutputPerMTok: 14},
+	"gpt-5.2":                {InputPerMTok: 1.75, OutputPerMTok: 14},
+	"gpt-4.1-mini":           {InputPerMTok: 0.40, OutputPerMTok: 1.60},
+	"o3-mini":                {InputPerMTok: 1.10, OutputPerMTok: 4.40},
+	"gemini-3-pro-preview":   {InputPerMTok: 2, OutputPerMTok: 12},
+	"gemini-3-flash-preview": {InputPerMTok: 0.50, OutputPerMTok: 3},
+	"gemini-2.5-pro":         {InputPerMTok: 1.25, OutputPerMTok: 10},
+	"gemini-2.5-flash":       {InputPerMTok: 0.30, OutputPerMTok: 2.50},
+	"mistral-large-latest":   {InputPerMTok: 2, OutputPerMTok: 6},
+	"mistral-medium-latest":  {InputPerMTok: 0.40, OutputPerMTok: 2},
+	"mistral-small-latest":   {InputPerMTok: 0.10, OutputPerMTok: 0.30},
+	"codestral-latest":       {InputPerMTok: 0.30, OutputPerMTok: 0.90},
+}
+
+// LookupPrice returns the list price for a provider's model. Local
+// providers are free. OpenRouter slugs ("anthropic/claude-sonnet-4.6") are
+// priced as the vendor's model, since OpenRouter passes list prices through.
+// ok is false for models with no known price.
+func LookupPrice(provider, model string) (Price, bool) {
+	if IsLocal(provider) {
+		return Price{}, true
+	}
+	model = strings.ToLower(model)
+	if provider == "openrouter" {
+		model = vendorModel(model)
+	}
+	p, ok := modelPrices[model]
+	return p, ok
+}
+
+// Cost returns the price of the given token counts in US dollars.
+func (p Price) Cost(inputTokens, outputTokens int) float64 {
+	return (float64(inputTokens)*p.InputPerMTok + float64(outputTokens)*p.OutputPerMTok) / 1e6
+}
+
+// EstimateTokens approximates the prompt size of a request in tokens, at
+// four bytes per token.
+func EstimateTokens(req ReviewRequest) int {
+	return estimateTokens(req)
+}
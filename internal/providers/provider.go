@@ -3,6 +3,7 @@ package providers
 import (
 	"context"
 	"fmt"
+	"strings"
 )
 
 // ReviewRequest contains the data sent to an LLM for review.
@@ -11,12 +12,43 @@ type ReviewRequest struct {
 	UserPrompt   string
 	MaxTokens    int
 	Temperature  float64
+	// FindingsJSON marks a request whose response must be a JSON array of
+	// findings. Providers with a native JSON output mode use it to
+	// constrain the response.
+	FindingsJSON bool
 }
 
 // ReviewResponse contains the raw response from an LLM.
 type ReviewResponse struct {
 	Content    string
 	TokensUsed int
+	// InputTokens and OutputTokens split TokensUsed when the provider
+	// reports them separately; both are zero otherwise.
+	InputTokens  int
+	OutputTokens int
+	// FinishReason is the provider's stop reason as reported (e.g. "stop",
+	// "end_turn", "length", "max_tokens", "MAX_TOKENS").
+	FinishReason string
+	// Model is the model name echoed back by the provider, which may be a
+	// more specific version than the one requested.
+	Model string
+	// Retries is the number of times the request was retried after a rate
+	// limit or server error before it succeeded or gave up.
+	Retries int
+	// Body is the raw HTTP response body, the whole event stream for a
+	// streamed response. Content is extracted from it.
+	Body []byte
+}
+
+// Truncated reports whether the provider stopped because it hit the output
+// token limit.
+func (r ReviewResponse) Truncated() bool {
+	switch r.FinishReason {
+	case "length", "max_tokens", "MAX_TOKENS":
+		return true
+	default:
+		return false
+	}
 }
 
 // Reviewer is the provider abstraction interface.
@@ -25,18 +57,91 @@ type Reviewer interface {
 	Name() string
 }
 
-// New creates a provider by name.
+// New creates a provider by name. The name "auto" picks the first provider
+// with an API key set (see ResolveAuto). When PRISM_<PROVIDER>_TPM or
+// PRISM_<PROVIDER>_RPM is set, the provider is throttled client-side by a
+// limiter shared with every other reviewer for that provider. After one
+// reviewer for a provider gets an authentication error, the others fail
+// fast with the same error. Calls made with a context from WithAuditLog are
+// recorded in that log. In offline mode (see Offline) only local providers
+// are created.
 func New(provider, model string) (Reviewer, error) {
+	if provider == "auto" {
+		var err error
+		provider, model, err = ResolveAuto(model)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := CheckOffline(provider); err != nil {
+		return nil, err
+	}
+	var r Reviewer
+	var err error
 	switch provider {
 	case "anthropic":
-		return NewAnthropic(model)
+		r, err = NewAnthropic(model)
 	case "openai":
-		return NewOpenAI(model)
+		r, err = NewOpenAI(model)
 	case "gemini", "google":
-		return NewGemini(model)
-	case "ollama", "lmstudio":
-		return NewOllama(model)
+		r, err = NewGemini(model)
+	case "mistral":
+		r, err = NewMistral(model)
+	case "openrouter":
+		r, err = NewOpenRouter(model)
+	case "ollama":
+		r, err = NewOllama(model)
+	case "lmstudio":
+		r, err = NewLMStudio(model)
 	default:
 		return nil, fmt.Errorf("unknown provider: %s", provider)
 	}
+	if err != nil {
+		return nil, err
+	}
+	return withRateLimit(withAuthBreaker(withUsage(withAudit(r, model), model)))
+}
+
+// requestBuilder is implemented by providers that can serialize a request
+// without sending it.
+type requestBuilder interface {
+	requestBody(req ReviewRequest) ([]byte, error)
+}
+
+// RequestBody returns the JSON body r would send for req, without making a
+// network call. Identical inputs produce identical bodies, so tests can pin
+// it to catch prompt or request changes that would invalidate cached
+// reviews. Headers and credentials are not included.
+func RequestBody(r Reviewer, req ReviewRequest) ([]byte, error) {
+	r = unwrap(r)
+	b, ok := r.(requestBuilder)
+	if !ok {
+		return nil, fmt.Errorf("provider %s cannot build request bodies", r.Name())
+	}
+	return b.requestBody(req)
+}
+
+// unwrap returns the provider underneath the rate-limit, auth-breaker, and
+// audit wrappers added by New.
+func unwrap(r Reviewer) Reviewer {
+	if rl, ok := r.(*rateLimitedReviewer); ok {
+		r = rl.Reviewer
+	}
+	if ab, ok := r.(*authBreakerReviewer); ok {
+		r = ab.Reviewer
+	}
+	if u, ok := r.(*usageReviewer); ok {
+		r = u.Reviewer
+	}
+	if a, ok := r.(*auditReviewer); ok {
+		r = a.Reviewer
+	}
+	return r
+}
+
+// providerEnv returns the name of a per-provider environment variable,
+// PRISM_<PROVIDER>_<SUFFIX>, such as PRISM_OPENAI_TPM or
+// PRISM_OLLAMA_NO_SYSTEM.
+func providerEnv(provider, suffix string) string {
+	return "PRISM_" + strings.ToUpper(provider) + "_" + suffix
 }
@@ -3,9 +3,13 @@ package providers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNew_UnknownProvider(t *testing.T) {
@@ -340,6 +344,9 @@ func TestIsAuthError(t *testing.T) {
 	if !IsAuthError(&authError{message: "test"}) {
 		t.Error("authError should be auth error")
 	}
+	if !IsAuthError(fmt.Errorf("chunk 2: %w", &authError{message: "test"})) {
+		t.Error("a wrapped authError should be auth error")
+	}
 }
 
 func TestIsRetryable(t *testing.T) {
@@ -408,3 +415,253 @@ func TestRetryWithBackoff_Success(t *testing.T) {
 		t.Errorf("Expected nil error, got: %v", err)
 	}
 }
+
+func TestBackoffDelay_Capped(t *testing.T) {
+	for attempt := 0; attempt <= 40; attempt++ {
+		if d := backoffDelay(attempt, 3*time.Second); d > 3*time.Second || d <= 0 {
+			t.Errorf("backoffDelay(%d, 3s) = %v, want within (0, 3s]", attempt, d)
+		}
+	}
+	if d := backoffDelay(10, time.Hour); d < 512*time.Second {
+		t.Errorf("backoffDelay(10, 1h) = %v, want uncapped growth to at least 512s", d)
+	}
+}
+
+func TestRetryMaxDelay_Env(t *testing.T) {
+	t.Setenv(retryMaxDelayEnv, "")
+	if d := retryMaxDelay(); d != defaultRetryMaxDelay {
+		t.Errorf("unset: got %v, want %v", d, defaultRetryMaxDelay)
+	}
+	t.Setenv(retryMaxDelayEnv, "250ms")
+	if d := retryMaxDelay(); d != 250*time.Millisecond {
+		t.Errorf("250ms: got %v", d)
+	}
+	for _, v := range []string{"soon", "-1s", "0"} {
+		t.Setenv(retryMaxDelayEnv, v)
+		if d := retryMaxDelay(); d != defaultRetryMaxDelay {
+			t.Errorf("%q: got %v, want the default", v, d)
+		}
+	}
+}
+
+func TestRetryWithBackoffCount(t *testing.T) {
+	t.Setenv(retryMaxDelayEnv, "1ms")
+
+	attempts := 0
+	retries, err := retryWithBackoffCount(context.Background(), 3, func() error {
+		attempts++
+		if attempts < 3 {
+			return &serverError{statusCode: 503, body: "busy"}
+		}
+		return nil
+	})
+	if err != nil || retries != 2 {
+		t.Errorf("got retries=%d err=%v, want 2 retries and success", retries, err)
+	}
+
+	retries, err = retryWithBackoffCount(context.Background(), 3, func() error {
+		return &serverError{statusCode: 500, body: "down"}
+	})
+	if err == nil || retries != 3 {
+		t.Errorf("got retries=%d err=%v, want 3 retries and the last error", retries, err)
+	}
+
+	retries, _ = retryWithBackoffCount(context.Background(), 3, func() error {
+		return &authError{message: "bad"}
+	})
+	if retries != 0 {
+		t.Errorf("auth error: got %d retries, want 0", retries)
+	}
+}
+
+func TestReview_ReportsRetries(t *testing.T) {
+	t.Setenv(retryMaxDelayEnv, "1ms")
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			io.WriteString(w, "overloaded")
+			return
+		}
+		io.WriteString(w, `{"choices":[{"message":{"content":"[]"}}]}`)
+	}))
+	defer server.Close()
+
+	o := &Ollama{baseURL: server.URL, model: "llama3", name: "ollama", client: server.Client()}
+	resp, err := o.Review(context.Background(), ReviewRequest{SystemPrompt: "s", UserPrompt: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Retries != 1 {
+		t.Errorf("Retries = %d, want 1", resp.Retries)
+	}
+}
+
+func TestReview_ModelNotFound(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		build  func(url string, client *http.Client) Reviewer
+	}{
+		{
+			name:   "openai",
+			status: 404,
+			body:   `{"error":{"message":"The model ` + "`gpt-4oo`" + ` does not exist or you do not have access to it.","type":"invalid_request_error","code":"model_not_found"}}`,
+			build: func(url string, client *http.Client) Reviewer {
+				return &OpenAI{apiKey: "k", model: "gpt-4oo", baseURL: url, client: client}
+			},
+		},
+		{
+			name:   "anthropic",
+			status: 404,
+			body:   `{"type":"error","error":{"type":"not_found_error","message":"model: claude-sonet"}}`,
+			build: func(url string, client *http.Client) Reviewer {
+				return &Anthropic{apiKey: "k", model: "claude-sonet", client: &http.Client{
+					Transport: &rewriteTransport{base: client.Transport, baseURL: url},
+				}}
+			},
+		},
+		{
+			name:   "gemini",
+			status: 404,
+			body:   `{"error":{"code":404,"message":"models/gemini-9 is not found for API version v1beta","status":"NOT_FOUND"}}`,
+			build: func(url string, client *http.Client) Reviewer {
+				return &Gemini{apiKey: "k", model: "gemini-9", client: &http.Client{
+					Transport: &rewriteTransport{base: client.Transport, baseURL: url},
+				}}
+			},
+		},
+		{
+			name:   "ollama",
+			status: 404,
+			body:   `{"error":{"message":"model \"llama9\" not found, try pulling it first","type":"api_error"}}`,
+			build: func(url string, client *http.Client) Reviewer {
+				return &Ollama{model: "llama9", baseURL: url, client: client}
+			},
+		},
+		{
+			name:   "openai 400 invalid model",
+			status: 400,
+			body:   `{"error":{"message":"invalid model ID","type":"invalid_request_error"}}`,
+			build: func(url string, client *http.Client) Reviewer {
+				return &OpenAI{apiKey: "k", model: "gpt 4o", baseURL: url, client: client}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			_, err := tt.build(server.URL, server.Client()).Review(context.Background(), ReviewRequest{
+				SystemPrompt: "test",
+				UserPrompt:   "test",
+			})
+			if !IsModelError(err) {
+				t.Fatalf("expected model error, got: %v", err)
+			}
+			if attempts != 1 {
+				t.Errorf("model errors should not be retried, got %d attempts", attempts)
+			}
+			if !strings.Contains(err.Error(), "prism models list") {
+				t.Errorf("error should suggest `prism models list`: %v", err)
+			}
+		})
+	}
+}
+
+func TestIsModelError(t *testing.T) {
+	if IsModelError(nil) {
+		t.Error("nil should not be model error")
+	}
+	if IsModelError(&serverError{statusCode: 500}) {
+		t.Error("serverError should not be model error")
+	}
+	wrapped := fmt.Errorf("provider review: %w", &modelError{provider: "openai", model: "x", statusCode: 404})
+	if !IsModelError(wrapped) {
+		t.Error("wrapped modelError should be model error")
+	}
+	if isModelNotFound(400, `{"error":"max_tokens is too large"}`) {
+		t.Error("unrelated 400 should not be a model error")
+	}
+	if isModelNotFound(404, `404 page not found`) {
+		t.Error("404 without a model mention should not be a model error")
+	}
+}
+
+func TestRequestBody_Pinned(t *testing.T) {
+	req := ReviewRequest{SystemPrompt: "sys", UserPrompt: "diff", MaxTokens: 100}
+	tests := []struct {
+		name string
+		r    Reviewer
+		want string
+	}{
+		{"anthropic", &Anthropic{model: "claude-sonnet-4-6"},
+			`{"model":"claude-sonnet-4-6","max_tokens":100,"system":"sys","messages":[{"role":"user","content":"diff"}]}`},
+		{"openai", &OpenAI{model: "gpt-4o"},
+			`{"model":"gpt-4o","messages":[{"role":"system","content":"sys"},{"role":"user","content":"diff"}],"max_tokens":100}`},
+		{"ollama", &Ollama{model: "llama3"},
+			`{"model":"llama3","messages":[{"role":"system","content":"sys"},{"role":"user","content":"diff"}],"max_tokens":100}`},
+		{"gemini", &Gemini{model: "gemini-2.5-flash"},
+			`{"systemInstruction":{"parts":[{"text":"sys"}]},"contents":[{"role":"user","parts":[{"text":"diff"}]}],"generationConfig":{"maxOutputTokens":100}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RequestBody(tt.r, req)
+			if err != nil {
+				t.Fatalf("RequestBody error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("body changed (this invalidates cached reviews):\n got  %s\n want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestBody_MatchesSentBody(t *testing.T) {
+	var sent []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent, _ = io.ReadAll(r.Body)
+		w.Write([]byte(`{"choices":[{"message":{"content":"[]"}}]}`))
+	}))
+	defer server.Close()
+
+	o := &OpenAI{apiKey: "k", model: "gpt-4o", baseURL: server.URL, client: server.Client()}
+	req := ReviewRequest{SystemPrompt: "sys", UserPrompt: "diff", Temperature: 0.2}
+	if _, err := o.Review(context.Background(), req); err != nil {
+		t.Fatalf("Review error: %v", err)
+	}
+	body, err := RequestBody(o, req)
+	if err != nil {
+		t.Fatalf("RequestBody error: %v", err)
+	}
+	if string(body) != string(sent) {
+		t.Errorf("RequestBody = %s, but Review sent %s", body, sent)
+	}
+}
+
+func TestRequestBody_RateLimitedAndUnsupported(t *testing.T) {
+	rl := &rateLimitedReviewer{Reviewer: &Anthropic{model: "m"}, limiter: NewRateLimiter(0, 0)}
+	if _, err := RequestBody(rl, ReviewRequest{}); err != nil {
+		t.Errorf("rate-limited reviewer should expose its provider's body: %v", err)
+	}
+	if _, err := RequestBody(stubReviewer{}, ReviewRequest{}); err == nil {
+		t.Error("expected error for a reviewer without a request builder")
+	}
+}
+
+// stubReviewer is a Reviewer without a request builder.
+type stubReviewer struct{}
+
+func (stubReviewer) Review(context.Context, ReviewRequest) (ReviewResponse, error) {
+	return ReviewResponse{}, nil
+}
+
+func (stubReviewer) Name() string { return "stub" }
@@ -0,0 +1,165 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket that refills continuously at perMinute per
+// minute up to a burst of perMinute. Like golang.org/x/time/rate, a
+// reservation may drive the balance negative; later callers then wait for
+// the deficit to refill, so concurrent callers are served in order.
+type bucket struct {
+	perMinute float64
+	tokens    float64
+	last      time.Time
+}
+
+func newBucket(perMinute int) *bucket {
+	return &bucket{perMinute: float64(perMinute), tokens: float64(perMinute), last: time.Now()}
+}
+
+// reserve takes n tokens and returns how long the caller must wait before
+// proceeding. Requests larger than the burst are clamped to it so they can
+// still go through once the bucket is full.
+func (b *bucket) reserve(now time.Time, n float64) time.Duration {
+	if n > b.perMinute {
+		n = b.perMinute
+	}
+	b.tokens += now.Sub(b.last).Minutes() * b.perMinute
+	if b.tokens > b.perMinute {
+		b.tokens = b.perMinute
+	}
+	b.last = now
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.perMinute * float64(time.Minute))
+}
+
+// RateLimiter throttles provider calls client-side to stay under
+// tokens-per-minute and requests-per-minute quotas. A nil bucket means that
+// dimension is unlimited. It is safe for concurrent use.
+type RateLimiter struct {
+	mu       sync.Mutex
+	tokens   *bucket
+	requests *bucket
+}
+
+// NewRateLimiter creates a limiter allowing tpm estimated prompt tokens and
+// rpm requests per minute. Zero disables the corresponding limit.
+func NewRateLimiter(tpm, rpm int) *RateLimiter {
+	l := &RateLimiter{}
+	if tpm > 0 {
+		l.tokens = newBucket(tpm)
+	}
+	if rpm > 0 {
+		l.requests = newBucket(rpm)
+	}
+	return l
+}
+
+// Wait blocks until a request of the given estimated token count may be
+// sent, or until ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, tokens int) error {
+	l.mu.Lock()
+	now := time.Now()
+	var d time.Duration
+	if l.tokens != nil {
+		d = l.tokens.reserve(now, float64(tokens))
+	}
+	if l.requests != nil {
+		if rd := l.requests.reserve(now, 1); rd > d {
+			d = rd
+		}
+	}
+	l.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// estimateTokens approximates the prompt size of a request at four bytes
+// per token.
+func estimateTokens(req ReviewRequest) int {
+	return (len(req.SystemPrompt) + len(req.UserPrompt) + 3) / 4
+}
+
+// rateLimitedReviewer waits on a shared RateLimiter before each call.
+type rateLimitedReviewer struct {
+	Reviewer
+	limiter *RateLimiter
+}
+
+func (r *rateLimitedReviewer) Review(ctx context.Context, req ReviewRequest) (ReviewResponse, error) {
+	if err := r.limiter.Wait(ctx, estimateTokens(req)); err != nil {
+		return ReviewResponse{}, err
+	}
+	return r.Reviewer.Review(ctx, req)
+}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = map[string]*RateLimiter{}
+)
+
+// limiterFor returns the process-wide limiter for a provider, configured
+// from PRISM_<PROVIDER>_TPM and PRISM_<PROVIDER>_RPM. Every reviewer for the
+// same provider shares it, so chunked and compare-mode calls are throttled
+// together. Returns nil when neither variable is set.
+func limiterFor(provider string) (*RateLimiter, error) {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	if l, ok := limiters[provider]; ok {
+		return l, nil
+	}
+	tpm, err := rateLimitValue(providerEnv(provider, "TPM"))
+	if err != nil {
+		return nil, err
+	}
+	rpm, err := rateLimitValue(providerEnv(provider, "RPM"))
+	if err != nil {
+		return nil, err
+	}
+	var l *RateLimiter
+	if tpm > 0 || rpm > 0 {
+		l = NewRateLimiter(tpm, rpm)
+	}
+	limiters[provider] = l
+	return l, nil
+}
+
+func rateLimitValue(env string) (int, error) {
+	v := os.Getenv(env)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", env, v)
+	}
+	return n, nil
+}
+
+// withRateLimit wraps r with the shared limiter for its provider, if any.
+func withRateLimit(r Reviewer) (Reviewer, error) {
+	l, err := limiterFor(r.Name())
+	if err != nil {
+		return nil, err
+	}
+	if l == nil {
+		return r, nil
+	}
+	return &rateLimitedReviewer{Reviewer: r, limiter: l}, nil
+}
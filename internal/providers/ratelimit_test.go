@@ -0,0 +1,75 @@
+package providers
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBucket_Reserve(t *testing.T) {
+	b := newBucket(600) // 10 tokens per second
+	now := b.last
+
+	if d := b.reserve(now, 600); d != 0 {
+		t.Errorf("full bucket should not wait, got %v", d)
+	}
+	if d := b.reserve(now, 10); d != time.Second {
+		t.Errorf("empty bucket wait = %v, want 1s", d)
+	}
+	// Two seconds later 20 tokens have refilled, covering the 10-token deficit.
+	if d := b.reserve(now.Add(2*time.Second), 10); d != 0 {
+		t.Errorf("refilled bucket should not wait, got %v", d)
+	}
+	// Requests larger than the burst are clamped to it.
+	if d := b.reserve(now.Add(2*time.Second), 10000); d != time.Minute {
+		t.Errorf("oversized request wait = %v, want 1m", d)
+	}
+}
+
+func TestRateLimiter_WaitCanceled(t *testing.T) {
+	l := NewRateLimiter(0, 1)
+	if err := l.Wait(context.Background(), 0); err != nil {
+		t.Fatalf("first request should pass: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, 0); err == nil {
+		t.Error("second request should wait for the next minute and hit the deadline")
+	}
+}
+
+func TestNew_RateLimitEnv(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	t.Cleanup(func() {
+		limitersMu.Lock()
+		delete(limiters, "anthropic")
+		limitersMu.Unlock()
+	})
+
+	t.Setenv("PRISM_ANTHROPIC_TPM", "lots")
+	if _, err := New("anthropic", ""); err == nil {
+		t.Error("expected error for non-integer PRISM_ANTHROPIC_TPM")
+	}
+
+	t.Setenv("PRISM_ANTHROPIC_TPM", "90000")
+	t.Setenv("PRISM_ANTHROPIC_RPM", "50")
+	r1, err := New("anthropic", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := New("anthropic", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l1, ok1 := r1.(*rateLimitedReviewer)
+	l2, ok2 := r2.(*rateLimitedReviewer)
+	if !ok1 || !ok2 {
+		t.Fatalf("expected rate-limited reviewers, got %T and %T", r1, r2)
+	}
+	if l1.limiter != l2.limiter {
+		t.Error("reviewers for the same provider should share a limiter")
+	}
+	if r1.Name() != "anthropic" {
+		t.Errorf("Name() = %q, want anthropic", r1.Name())
+	}
+}
@@ -2,7 +2,11 @@ package providers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/rand"
+	"os"
+	"strings"
 	"time"
 )
 
@@ -27,10 +31,55 @@ func (e *authError) Error() string {
 	return "authentication error: " + e.message
 }
 
-// IsAuthError checks if an error is an authentication error.
+// IsAuthError checks if an error, or any error it wraps, is an
+// authentication error.
 func IsAuthError(err error) bool {
-	_, ok := err.(*authError)
-	return ok
+	var ae *authError
+	return errors.As(err, &ae)
+}
+
+// modelError reports that the provider does not recognize the requested
+// model. Retrying cannot help, so it is never retried.
+type modelError struct {
+	provider   string
+	model      string
+	statusCode int
+	body       string
+}
+
+func (e *modelError) Error() string {
+	return fmt.Sprintf("model %q not available from %s (status %d): %s; run `prism models list` to see available models",
+		e.model, e.provider, e.statusCode, strings.TrimSpace(e.body))
+}
+
+// IsModelError checks if an error, or any error it wraps, reports an
+// unknown or invalid model.
+func IsModelError(err error) bool {
+	var me *modelError
+	return errors.As(err, &me)
+}
+
+// isModelNotFound reports whether a response is the provider rejecting the
+// model name. Every provider answers an unknown model with a 404 whose body
+// names the model (OpenAI "model_not_found", Anthropic "not_found_error",
+// Gemini "models/x is not found", Ollama "model x not found"); some use a
+// 400 for invalid model names instead.
+func isModelNotFound(statusCode int, body string) bool {
+	b := strings.ToLower(body)
+	if !strings.Contains(b, "model") {
+		return false
+	}
+	switch statusCode {
+	case 404:
+		return true
+	case 400:
+		for _, s := range []string{"not found", "not_found", "does not exist", "invalid model", "unknown model", "not supported"} {
+			if strings.Contains(b, s) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func isRetryable(err error) bool {
@@ -44,34 +93,100 @@ func isRetryable(err error) bool {
 	}
 }
 
+// retryMaxDelayEnv names the environment variable that overrides the cap on
+// a single backoff delay, as a Go duration such as "10s".
+const retryMaxDelayEnv = "PRISM_RETRY_MAX_DELAY"
+
+// defaultRetryMaxDelay caps a single backoff delay, jitter included, when
+// PRISM_RETRY_MAX_DELAY is unset.
+const defaultRetryMaxDelay = 30 * time.Second
+
+// retryMaxDelay returns the cap on a single backoff delay. An invalid or
+// non-positive PRISM_RETRY_MAX_DELAY falls back to the default.
+func retryMaxDelay() time.Duration {
+	v := os.Getenv(retryMaxDelayEnv)
+	if v == "" {
+		return defaultRetryMaxDelay
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		debugf("ignoring invalid %s=%q", retryMaxDelayEnv, v)
+		return defaultRetryMaxDelay
+	}
+	return d
+}
+
+// backoffDelay returns the delay before retry attempt+1: 2^attempt seconds
+// with 50-150% jitter to avoid a thundering herd, capped at maxDelay.
+func backoffDelay(attempt int, maxDelay time.Duration) time.Duration {
+	if attempt > 30 {
+		attempt = 30
+	}
+	base := time.Duration(1<<uint(attempt)) * time.Second
+	d := time.Duration(float64(base) * (0.5 + rand.Float64()))
+	if d > maxDelay {
+		d = maxDelay
+	}
+	return d
+}
+
+// retryWithBackoff calls fn, retrying rate-limit and server errors with
+// exponential backoff. If ctx carries a RetryCoordinator, rate-limit backoff
+// is shared with the other calls using it and retries draw on its budget.
 func retryWithBackoff(ctx context.Context, maxRetries int, fn func() error) error {
+	_, err := retryWithBackoffCount(ctx, maxRetries, fn)
+	return err
+}
+
+// retryWithBackoffCount is retryWithBackoff that also returns the number of
+// retries performed, not counting the first attempt.
+func retryWithBackoffCount(ctx context.Context, maxRetries int, fn func() error) (int, error) {
+	coord := retryCoordinatorFrom(ctx)
+	maxDelay := retryMaxDelay()
 	var lastErr error
 	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if coord != nil {
+			if err := coord.wait(ctx); err != nil {
+				return attempt, err
+			}
+		}
+
 		lastErr = fn()
 		if lastErr == nil {
-			return nil
+			if coord != nil {
+				coord.success()
+			}
+			return attempt, nil
 		}
 
 		// Don't retry auth errors
 		if _, ok := lastErr.(*authError); ok {
-			return lastErr
+			return attempt, lastErr
 		}
 
 		// Only retry retryable errors (rate limit, server errors)
 		if !isRetryable(lastErr) {
-			return lastErr
+			return attempt, lastErr
 		}
 
 		if attempt < maxRetries {
-			base := time.Duration(1<<uint(attempt)) * time.Second
-			// Add jitter: 50-150% of base to avoid thundering herd
-			jitter := time.Duration(float64(base) * (0.5 + rand.Float64()))
+			if coord != nil {
+				if !coord.takeRetry() {
+					return attempt, lastErr
+				}
+				if _, ok := lastErr.(*rateLimitError); ok {
+					// The shared pause is applied by coord.wait above.
+					coord.rateLimit()
+					continue
+				}
+			}
+
 			select {
 			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(jitter):
+				return attempt, ctx.Err()
+			case <-time.After(backoffDelay(attempt, maxDelay)):
 			}
 		}
 	}
-	return lastErr
+	return maxRetries, lastErr
 }
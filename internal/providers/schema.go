@@ -0,0 +1,49 @@
+package providers
+
+import "encoding/json"
+
+// findingSchema is the JSON Schema of one finding, mirroring the objects the
+// review prompt asks for. Every property is required and no others are
+// allowed, as OpenAI's strict structured outputs demand.
+var findingSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"severity":   map[string]any{"type": "string", "enum": []string{"critical", "high", "medium", "low", "info"}},
+		"category":   map[string]any{"type": "string"},
+		"title":      map[string]any{"type": "string"},
+		"message":    map[string]any{"type": "string"},
+		"suggestion": map[string]any{"type": "string"},
+		"confidence": map[string]any{"type": "number"},
+		"path":       map[string]any{"type": "string"},
+		"startLine":  map[string]any{"type": "integer"},
+		"endLine":    map[string]any{"type": "integer"},
+		"tags":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+	},
+	"required": []string{"severity", "category", "title", "message", "suggestion", "confidence",
+		"path", "startLine", "endLine", "tags"},
+	"additionalProperties": false,
+}
+
+// findingsObjectSchema wraps the findings array in an object, since tool
+// inputs and OpenAI structured outputs must be JSON objects.
+var findingsObjectSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"findings": map[string]any{"type": "array", "items": findingSchema},
+	},
+	"required":             []string{"findings"},
+	"additionalProperties": false,
+}
+
+// unwrapFindings returns the findings array from a structured response
+// shaped by findingsObjectSchema. Anything else is returned as is, so the
+// review engine's parsing and repair still see what the model sent.
+func unwrapFindings(data []byte) string {
+	var obj struct {
+		Findings json.RawMessage `json:"findings"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil || len(obj.Findings) == 0 {
+		return string(data)
+	}
+	return string(obj.Findings)
+}
@@ -0,0 +1,104 @@
+package providers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// StreamReviewer is implemented by providers that can stream a response
+// while it is generated.
+type StreamReviewer interface {
+	Reviewer
+	// ReviewStream is Review, passing each piece of response text to
+	// onDelta as it arrives. The returned response holds the full content.
+	ReviewStream(ctx context.Context, req ReviewRequest, onDelta func(string)) (ReviewResponse, error)
+}
+
+// streamRequestBuilder is implemented by providers that can serialize the
+// streaming form of a request, for the audit log.
+type streamRequestBuilder interface {
+	streamRequestBody(req ReviewRequest) ([]byte, error)
+}
+
+type streamKey struct{}
+
+// WithStream returns a context whose calls to streaming providers pass
+// response text to onDelta as it arrives. Other providers ignore it.
+func WithStream(ctx context.Context, onDelta func(string)) context.Context {
+	if onDelta == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, streamKey{}, onDelta)
+}
+
+func streamFrom(ctx context.Context) func(string) {
+	onDelta, _ := ctx.Value(streamKey{}).(func(string))
+	return onDelta
+}
+
+// CanStream reports whether r's provider streams its responses.
+func CanStream(r Reviewer) bool {
+	_, ok := unwrap(r).(StreamReviewer)
+	return ok
+}
+
+// ReviewStream sends req to r like r.Review, passing response text to
+// onDelta as it arrives when r's provider streams (see CanStream). For
+// other providers onDelta receives the whole response once it is complete.
+// Retries happen before any text is streamed, so onDelta never sees a
+// response twice.
+func ReviewStream(ctx context.Context, r Reviewer, req ReviewRequest, onDelta func(string)) (ReviewResponse, error) {
+	if !CanStream(r) {
+		resp, err := r.Review(ctx, req)
+		if err == nil {
+			onDelta(resp.Content)
+		}
+		return resp, err
+	}
+	return r.Review(WithStream(ctx, onDelta), req)
+}
+
+// readSSE reads a server-sent event stream, calling fn with the data of
+// each event. It stops at the end of the stream, on a "[DONE]" event, or
+// when fn returns an error.
+func readSSE(r io.Reader, fn func(data string) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	var data []string
+	dispatch := func() error {
+		if len(data) == 0 {
+			return nil
+		}
+		event := strings.Join(data, "\n")
+		data = data[:0]
+		if event == "[DONE]" {
+			return io.EOF
+		}
+		return fn(event)
+	}
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" {
+			if err := dispatch(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "data:"); ok {
+			data = append(data, strings.TrimPrefix(rest, " "))
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("reading stream: %w", err)
+	}
+	if err := dispatch(); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadSSE(t *testing.T) {
+	in := "event: ping\ndata: {\"a\":1}\n\n: comment\ndata: line1\ndata: line2\n\ndata: [DONE]\n\ndata: after\n\n"
+	var got []string
+	if err := readSSE(strings.NewReader(in), func(data string) error {
+		got = append(got, data)
+		return nil
+	}); err != nil {
+		t.Fatalf("readSSE error: %v", err)
+	}
+	want := []string{`{"a":1}`, "line1\nline2"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("events = %q, want %q", got, want)
+	}
+}
+
+func TestReadSSE_CallbackError(t *testing.T) {
+	errStop := errors.New("stop")
+	err := readSSE(strings.NewReader("data: x\n\ndata: y\n\n"), func(string) error { return errStop })
+	if !errors.Is(err, errStop) {
+		t.Errorf("err = %v, want %v", err, errStop)
+	}
+}
+
+func TestAnthropic_ReviewStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["stream"] != true {
+			t.Errorf("stream = %v, want true", body["stream"])
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("event: message_start\n" +
+			`data: {"type":"message_start","message":{"model":"claude-test","usage":{"input_tokens":100}}}` + "\n\n" +
+			"event: content_block_delta\n" +
+			`data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"[{\"title\":"}}` + "\n\n" +
+			`data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"\"x\"}]"}}` + "\n\n" +
+			`data: {"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":12}}` + "\n\n" +
+			`data: {"type":"message_stop"}` + "\n\n"))
+	}))
+	defer server.Close()
+
+	a := &Anthropic{
+		apiKey: "test-key",
+		model:  "claude-test",
+		client: &http.Client{Transport: &rewriteTransport{base: server.Client().Transport, baseURL: server.URL}},
+	}
+	var deltas []string
+	resp, err := a.ReviewStream(context.Background(), ReviewRequest{UserPrompt: "test", MaxTokens: 10}, func(s string) {
+		deltas = append(deltas, s)
+	})
+	if err != nil {
+		t.Fatalf("ReviewStream error: %v", err)
+	}
+	if len(deltas) != 2 {
+		t.Errorf("deltas = %q, want 2", deltas)
+	}
+	if resp.Content != `[{"title":"x"}]` {
+		t.Errorf("Content = %q", resp.Content)
+	}
+	if resp.TokensUsed != 112 || resp.FinishReason != "end_turn" || resp.Model != "claude-test" {
+		t.Errorf("resp = %+v", resp)
+	}
+}
+
+func TestAnthropic_ReviewStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`data: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}` + "\n\n"))
+	}))
+	defer server.Close()
+
+	a := &Anthropic{
+		apiKey: "test-key",
+		model:  "claude-test",
+		client: &http.Client{Transport: &rewriteTransport{base: server.Client().Transport, baseURL: server.URL}},
+	}
+	_, err := a.ReviewStream(context.Background(), ReviewRequest{UserPrompt: "test"}, func(string) {})
+	if err == nil || !strings.Contains(err.Error(), "Overloaded") {
+		t.Errorf("err = %v, want the stream error", err)
+	}
+}
+
+func TestOpenAI_ReviewStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["stream"] != true {
+			t.Errorf("stream = %v, want true", body["stream"])
+		}
+		if opts, _ := body["stream_options"].(map[string]any); opts["include_usage"] != true {
+			t.Errorf("stream_options = %v, want include_usage", body["stream_options"])
+		}
+		w.Write([]byte(`data: {"model":"gpt-test","choices":[{"delta":{"role":"assistant","content":"[]"}}]}` + "\n\n" +
+			`data: {"choices":[{"delta":{},"finish_reason":"stop"}]}` + "\n\n" +
+			`data: {"choices":[],"usage":{"total_tokens":42}}` + "\n\n" +
+			"data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	o := &OpenAI{apiKey: "test-key", model: "gpt-test", baseURL: server.URL, client: server.Client()}
+	var got strings.Builder
+	resp, err := o.ReviewStream(context.Background(), ReviewRequest{UserPrompt: "test"}, func(s string) {
+		got.WriteString(s)
+	})
+	if err != nil {
+		t.Fatalf("ReviewStream error: %v", err)
+	}
+	if got.String() != "[]" || resp.Content != "[]" {
+		t.Errorf("deltas = %q, Content = %q, want []", got.String(), resp.Content)
+	}
+	if resp.TokensUsed != 42 || resp.FinishReason != "stop" || resp.Model != "gpt-test" {
+		t.Errorf("resp = %+v", resp)
+	}
+}
+
+func TestReviewStream_WrappedProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`data: {"choices":[{"delta":{"content":"[]"}}]}` + "\n\ndata: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	o := &OpenAI{apiKey: "test-key", model: "gpt-test", baseURL: server.URL, client: server.Client()}
+	r := withAuthBreaker(withAudit(o, "gpt-test"))
+	if !CanStream(r) {
+		t.Fatal("CanStream = false for a wrapped OpenAI provider")
+	}
+	var got string
+	if _, err := ReviewStream(context.Background(), r, ReviewRequest{UserPrompt: "test"}, func(s string) { got += s }); err != nil {
+		t.Fatalf("ReviewStream error: %v", err)
+	}
+	if got != "[]" {
+		t.Errorf("deltas = %q, want []", got)
+	}
+}
+
+func TestReviewStream_Fallback(t *testing.T) {
+	r := stubReviewer{}
+	if CanStream(r) {
+		t.Fatal("CanStream = true for a non-streaming provider")
+	}
+	var calls []string
+	resp, err := ReviewStream(context.Background(), r, ReviewRequest{}, func(s string) { calls = append(calls, s) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 || calls[0] != resp.Content {
+		t.Errorf("onDelta calls = %q, want the whole response once", calls)
+	}
+}
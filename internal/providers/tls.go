@@ -0,0 +1,77 @@
+package providers
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// insecureSkipVerifyEnv names the environment variable that disables TLS
+// certificate verification for self-hosted endpoints.
+const insecureSkipVerifyEnv = "PRISM_INSECURE_SKIP_VERIFY"
+
+// insecureSkipVerify reports whether PRISM_INSECURE_SKIP_VERIFY allows
+// skipping TLS certificate verification for host. A true value ("1",
+// "true", ...) covers loopback hosts only; any other value is a
+// comma-separated list of host names it covers, so a remote gateway has to
+// be named explicitly.
+func insecureSkipVerify(host string) bool {
+	v := strings.TrimSpace(os.Getenv(insecureSkipVerifyEnv))
+	if v == "" || host == "" {
+		return false
+	}
+	if b, err := strconv.ParseBool(v); err == nil {
+		return b && isLoopbackHost(host)
+	}
+	for _, h := range strings.Split(v, ",") {
+		if strings.EqualFold(strings.TrimSpace(h), host) {
+			return true
+		}
+	}
+	return false
+}
+
+// isLoopbackHost reports whether host is localhost or a loopback address.
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// endpointHost returns the host name of endpoint, which may omit the scheme
+// as in OLLAMA_HOST=gpu-box:11434.
+func endpointHost(endpoint string) string {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// selfHostedClient returns an HTTP client for a self-hosted or
+// OpenAI-compatible endpoint. When PRISM_INSECURE_SKIP_VERIFY covers the
+// endpoint's host it skips TLS certificate verification and warns on
+// stderr, naming the host. It must never be used for the public cloud APIs.
+func selfHostedClient(endpoint string, timeout time.Duration) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	host := endpointHost(endpoint)
+	if !insecureSkipVerify(host) {
+		return client
+	}
+	fmt.Fprintf(os.Stderr, "WARNING: TLS certificate verification is disabled for %s (%s)\n", host, insecureSkipVerifyEnv)
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} // #nosec G402 -- explicit opt-in for self-signed gateways
+	client.Transport = transport
+	return client
+}
@@ -0,0 +1,110 @@
+package providers
+
+import (
+	"net/http"
+	"testing"
+)
+
+func skipsVerify(c *http.Client) bool {
+	t, ok := c.Transport.(*http.Transport)
+	return ok && t.TLSClientConfig != nil && t.TLSClientConfig.InsecureSkipVerify
+}
+
+func TestInsecureSkipVerify_SelfHostedOnly(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	t.Setenv("OPENAI_API_KEY", "test-key")
+	t.Setenv("GEMINI_API_KEY", "test-key")
+
+	t.Setenv(insecureSkipVerifyEnv, "")
+	o, _ := NewOllama("llama3")
+	if skipsVerify(o.client) {
+		t.Error("ollama should verify TLS by default")
+	}
+
+	t.Setenv(insecureSkipVerifyEnv, "1")
+	o, _ = NewOllama("llama3")
+	if !skipsVerify(o.client) {
+		t.Error("ollama should skip TLS verification with PRISM_INSECURE_SKIP_VERIFY=1")
+	}
+	lm, _ := NewLMStudio("local")
+	if !skipsVerify(lm.client) {
+		t.Error("lmstudio should skip TLS verification with PRISM_INSECURE_SKIP_VERIFY=1")
+	}
+
+	// OpenAI only counts as self-hosted with a custom base URL.
+	t.Setenv("PRISM_OPENAI_BASE_URL", "")
+	oa, err := NewOpenAI("gpt-5.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipsVerify(oa.client) {
+		t.Error("the public OpenAI endpoint must always verify TLS")
+	}
+	t.Setenv("PRISM_OPENAI_BASE_URL", "https://gateway.internal/v1/chat/completions")
+	oa, _ = NewOpenAI("gpt-5.2")
+	if skipsVerify(oa.client) {
+		t.Error("a true value should not cover a non-loopback host")
+	}
+	t.Setenv(insecureSkipVerifyEnv, "other.internal, gateway.internal")
+	oa, _ = NewOpenAI("gpt-5.2")
+	if !skipsVerify(oa.client) {
+		t.Error("a custom OpenAI base URL should skip TLS verification when its host is listed")
+	}
+	t.Setenv("PRISM_OPENAI_BASE_URL", "https://api.openai.com/v1/chat/completions")
+	oa, _ = NewOpenAI("gpt-5.2")
+	if skipsVerify(oa.client) {
+		t.Error("a host missing from the list must verify TLS")
+	}
+
+	a, err := NewAnthropic("claude-sonnet-4-6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipsVerify(a.client) {
+		t.Error("anthropic must always verify TLS")
+	}
+	g, err := NewGemini("gemini-2.5-pro")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipsVerify(g.client) {
+		t.Error("gemini must always verify TLS")
+	}
+}
+
+func TestInsecureSkipVerify_Hosts(t *testing.T) {
+	tests := []struct {
+		env, host string
+		want      bool
+	}{
+		{"", "localhost", false},
+		{"1", "localhost", true},
+		{"true", "127.0.0.1", true},
+		{"1", "::1", true},
+		{"1", "gpu-box", false},
+		{"0", "localhost", false},
+		{"gpu-box", "gpu-box", true},
+		{"gpu-box", "GPU-Box", true},
+		{"gpu-box", "localhost", false},
+	}
+	for _, tt := range tests {
+		t.Setenv(insecureSkipVerifyEnv, tt.env)
+		if got := insecureSkipVerify(tt.host); got != tt.want {
+			t.Errorf("insecureSkipVerify(%q) with %q = %v, want %v", tt.host, tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestEndpointHost(t *testing.T) {
+	tests := map[string]string{
+		"http://localhost:11434/v1/chat/completions": "localhost",
+		"gpu-box:11434":            "gpu-box",
+		"https://[::1]:8443/v1":    "::1",
+		"https://gateway.internal": "gateway.internal",
+	}
+	for endpoint, want := range tests {
+		if got := endpointHost(endpoint); got != want {
+			t.Errorf("endpointHost(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Usage totals the tokens and estimated cost of a set of provider calls.
+type Usage struct {
+	Calls        int `json:"calls"`
+	InputTokens  int `json:"inputTokens"`
+	OutputTokens int `json:"outputTokens"`
+	TotalTokens  int `json:"totalTokens"`
+	// CostUSD is the estimated cost at the built-in list prices (see
+	// LookupPrice). Tokens a provider does not split into input and output
+	// are priced as input.
+	CostUSD float64 `json:"costUsd"`
+	// UnpricedCalls counts calls to models with no known price. Their
+	// tokens are included in the totals but not in CostUSD.
+	UnpricedCalls int `json:"unpricedCalls,omitempty"`
+}
+
+// UsageTracker accumulates the usage of provider calls made with a context
+// from WithUsageTracker, and optionally stops new calls once their cost
+// reaches a budget. It is safe for concurrent use.
+type UsageTracker struct {
+	mu      sync.Mutex
+	maxCost float64
+	usage   Usage
+	parent  *UsageTracker
+}
+
+// NewUsageTracker returns an empty tracker. With maxCost above zero, calls
+// fail with a budget error once the tracked cost reaches maxCost dollars.
+// Calls already in flight when the budget is reached still complete.
+func NewUsageTracker(maxCost float64) *UsageTracker {
+	return &UsageTracker{maxCost: maxCost}
+}
+
+// Usage returns the usage recorded so far.
+func (t *UsageTracker) Usage() Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.usage
+}
+
+type usageTrackerKey struct{}
+
+// WithUsageTracker returns a context whose provider calls are recorded in
+// t. If ctx already carries a tracker, calls are recorded in both, and
+// both budgets apply.
+func WithUsageTracker(ctx context.Context, t *UsageTracker) context.Context {
+	if t == nil {
+		return ctx
+	}
+	if p := usageTrackerFrom(ctx); p != nil && p != t && t.parent == nil {
+		t.parent = p
+	}
+	return context.WithValue(ctx, usageTrackerKey{}, t)
+}
+
+func usageTrackerFrom(ctx context.Context) *UsageTracker {
+	t, _ := ctx.Value(usageTrackerKey{}).(*UsageTracker)
+	return t
+}
+
+// budgetError reports that a call was refused because the tracked cost
+// reached the budget.
+type budgetError struct {
+	spent   float64
+	maxCost float64
+}
+
+func (e *budgetError) Error() string {
+	return fmt.Sprintf("cost budget reached: spent ~$%.4f of --max-cost $%.2f", e.spent, e.maxCost)
+}
+
+// IsBudgetError checks if an error, or any error it wraps, reports that a
+// call was refused by a usage tracker's budget.
+func IsBudgetError(err error) bool {
+	var be *budgetError
+	return errors.As(err, &be)
+}
+
+// checkBudget returns a budget error if t or any tracker it reports to has
+// reached its budget.
+func (t *UsageTracker) checkBudget() error {
+	for ; t != nil; t = t.parent {
+		t.mu.Lock()
+		spent, maxCost := t.usage.CostUSD, t.maxCost
+		t.mu.Unlock()
+		if maxCost > 0 && spent >= maxCost {
+			return &budgetError{spent: spent, maxCost: maxCost}
+		}
+	}
+	return nil
+}
+
+// add records one call in t and every tracker it reports to.
+func (t *UsageTracker) add(u Usage) {
+	for ; t != nil; t = t.parent {
+		t.mu.Lock()
+		t.usage.Calls += u.Calls
+		t.usage.InputTokens += u.InputTokens
+		t.usage.OutputTokens += u.OutputTokens
+		t.usage.TotalTokens += u.TotalTokens
+		t.usage.CostUSD += u.CostUSD
+		t.usage.UnpricedCalls += u.UnpricedCalls
+		t.mu.Unlock()
+	}
+}
+
+// usageReviewer records each successful call in the context's usage
+// tracker and refuses calls once a tracker's budget is reached.
+type usageReviewer struct {
+	Reviewer
+	model string
+}
+
+func withUsage(r Reviewer, model string) Reviewer {
+	return &usageReviewer{Reviewer: r, model: model}
+}
+
+func (r *usageReviewer) Review(ctx context.Context, req ReviewRequest) (ReviewResponse, error) {
+	t := usageTrackerFrom(ctx)
+	if err := t.checkBudget(); err != nil {
+		return ReviewResponse{}, err
+	}
+	resp, err := r.Reviewer.Review(ctx, req)
+	if err == nil && t != nil {
+		t.add(callUsage(r.Name(), r.model, resp))
+	}
+	return resp, err
+}
+
+// callUsage prices one response for provider's model.
+func callUsage(provider, model string, resp ReviewResponse) Usage {
+	u := Usage{
+		Calls:        1,
+		InputTokens:  resp.InputTokens,
+		OutputTokens: resp.OutputTokens,
+		TotalTokens:  resp.TokensUsed,
+	}
+	if u.TotalTokens == 0 {
+		u.TotalTokens = u.InputTokens + u.OutputTokens
+	}
+	price, ok := LookupPrice(provider, model)
+	if !ok {
+		u.UnpricedCalls = 1
+		return u
+	}
+	input := u.TotalTokens - u.OutputTokens
+	u.CostUSD = price.Cost(input, u.OutputTokens)
+	return u
+}
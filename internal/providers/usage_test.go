@@ -0,0 +1,96 @@
+package providers
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// tokenReviewer answers every request with a fixed token count.
+type tokenReviewer struct {
+	name          string
+	input, output int
+	calls         int
+}
+
+func (r *tokenReviewer) Review(context.Context, ReviewRequest) (ReviewResponse, error) {
+	r.calls++
+	return ReviewResponse{Content: "[]", TokensUsed: r.input + r.output, InputTokens: r.input, OutputTokens: r.output}, nil
+}
+
+func (r *tokenReviewer) Name() string { return r.name }
+
+func TestCallUsage(t *testing.T) {
+	// claude-haiku-4-5 lists at $1 input and $5 output per million tokens
+	u := callUsage("anthropic", "claude-haiku-4-5", ReviewResponse{TokensUsed: 1200, InputTokens: 1000, OutputTokens: 200})
+	if u.Calls != 1 || u.TotalTokens != 1200 || math.Abs(u.CostUSD-0.002) > 1e-9 {
+		t.Errorf("usage = %+v, want 1200 tokens costing $0.002", u)
+	}
+
+	// Without a split, all tokens are priced as input
+	u = callUsage("anthropic", "claude-haiku-4-5", ReviewResponse{TokensUsed: 1000})
+	if math.Abs(u.CostUSD-0.001) > 1e-9 {
+		t.Errorf("CostUSD = %v, want 0.001", u.CostUSD)
+	}
+
+	u = callUsage("openai", "some-unlisted-model", ReviewResponse{TokensUsed: 50})
+	if u.UnpricedCalls != 1 || u.CostUSD != 0 || u.TotalTokens != 50 {
+		t.Errorf("unpriced usage = %+v", u)
+	}
+
+	u = callUsage("ollama", "llama3", ReviewResponse{InputTokens: 10, OutputTokens: 5})
+	if u.UnpricedCalls != 0 || u.CostUSD != 0 || u.TotalTokens != 15 {
+		t.Errorf("local usage = %+v, want free with totals from the split", u)
+	}
+}
+
+func TestUsageTracker_RecordsInParents(t *testing.T) {
+	outer := NewUsageTracker(0)
+	inner := NewUsageTracker(0)
+	ctx := WithUsageTracker(WithUsageTracker(context.Background(), outer), inner)
+
+	r := withUsage(&tokenReviewer{name: "anthropic", input: 1000, output: 200}, "claude-haiku-4-5")
+	for i := 0; i < 2; i++ {
+		if _, err := r.Review(ctx, ReviewRequest{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A call outside the inner context only reaches the outer tracker
+	if _, err := r.Review(WithUsageTracker(context.Background(), outer), ReviewRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := inner.Usage(); got.Calls != 2 || got.InputTokens != 2000 || got.OutputTokens != 400 {
+		t.Errorf("inner usage = %+v", got)
+	}
+	if got := outer.Usage(); got.Calls != 3 || got.TotalTokens != 3600 || math.Abs(got.CostUSD-0.006) > 1e-9 {
+		t.Errorf("outer usage = %+v", got)
+	}
+}
+
+func TestUsageTracker_Budget(t *testing.T) {
+	// Each call costs $0.002, so the third is refused
+	tr := NewUsageTracker(0.004)
+	ctx := WithUsageTracker(context.Background(), tr)
+	inner := &tokenReviewer{name: "anthropic", input: 1000, output: 200}
+	r := withUsage(inner, "claude-haiku-4-5")
+
+	for i := 0; i < 2; i++ {
+		if _, err := r.Review(ctx, ReviewRequest{}); err != nil {
+			t.Fatalf("call %d: %v", i+1, err)
+		}
+	}
+	_, err := r.Review(ctx, ReviewRequest{})
+	if !IsBudgetError(err) {
+		t.Fatalf("err = %v, want a budget error", err)
+	}
+	if inner.calls != 2 {
+		t.Errorf("provider calls = %d, want the refused call not sent", inner.calls)
+	}
+
+	// A budget on an outer tracker also applies
+	ctx = WithUsageTracker(ctx, NewUsageTracker(0))
+	if _, err := r.Review(ctx, ReviewRequest{}); !IsBudgetError(err) {
+		t.Errorf("err = %v, want the outer budget to apply", err)
+	}
+}
@@ -0,0 +1,65 @@
+package review
+
+import "fmt"
+
+// Baseline values for Finding.Baseline.
+const (
+	BaselineNew       = "new"
+	BaselineEscalated = "escalated"
+)
+
+// ApplyBaseline keeps only the findings that need attention relative to a
+// baseline run: findings the baseline does not have, and findings whose
+// severity increased since. Findings are matched across runs by their
+// line-independent StableKey, so edits that shift code do not make an old
+// finding look new. Kept findings are labeled with Baseline and, when
+// escalated, PreviousSeverity.
+func ApplyBaseline(findings, baseline []Finding) []Finding {
+	// When the baseline reported an issue more than once, compare against
+	// its highest severity.
+	prev := make(map[string]Severity, len(baseline))
+	for _, b := range baseline {
+		key := baselineKey(b)
+		if s, ok := prev[key]; !ok || SeverityRank(b.Severity) > SeverityRank(s) {
+			prev[key] = b.Severity
+		}
+	}
+
+	kept := []Finding{}
+	for _, f := range findings {
+		s, ok := prev[baselineKey(f)]
+		switch {
+		case !ok:
+			f.Baseline = BaselineNew
+		case SeverityRank(f.Severity) > SeverityRank(s):
+			f.Baseline = BaselineEscalated
+			f.PreviousSeverity = s
+		default:
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+// baselineKey returns the finding's StableKey, computing it for findings
+// from reports written before stable keys existed.
+func baselineKey(f Finding) string {
+	if f.StableKey != "" {
+		return f.StableKey
+	}
+	return generateStableKey(f)
+}
+
+// BaselineLabel describes how a finding compares with the baseline, e.g.
+// "new" or "escalated from medium to high". It is empty for findings not
+// compared with a baseline.
+func BaselineLabel(f Finding) string {
+	switch f.Baseline {
+	case BaselineNew:
+		return "new"
+	case BaselineEscalated:
+		return fmt.Sprintf("escalated from %s to %s", f.PreviousSeverity, f.Severity)
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package review
+
+import "testing"
+
+func TestApplyBaseline(t *testing.T) {
+	mk := func(title string, line int, sev Severity) Finding {
+		f := Finding{
+			Severity:  sev,
+			Category:  CategoryBug,
+			Title:     title,
+			Locations: []Location{{Path: "main.go", Lines: LineRange{Start: line, End: line}}},
+		}
+		f.StableKey = generateStableKey(f)
+		return f
+	}
+	baseline := []Finding{
+		mk("Unchecked error", 10, SeverityMedium),
+		mk("Race on counter", 20, SeverityHigh),
+		mk("Unused variable", 30, SeverityLow),
+	}
+	// Stable keys from an old report may be missing
+	baseline[2].StableKey = ""
+
+	current := []Finding{
+		mk("Unchecked error", 14, SeverityHigh), // moved down and escalated
+		mk("Race on counter", 20, SeverityMedium),
+		mk("Unused variable", 31, SeverityLow),
+		mk("SQL injection", 40, SeverityHigh),
+	}
+
+	got := ApplyBaseline(current, baseline)
+	if len(got) != 2 {
+		t.Fatalf("got %d findings, want 2: %+v", len(got), got)
+	}
+	if got[0].Title != "Unchecked error" || got[0].Baseline != BaselineEscalated || got[0].PreviousSeverity != SeverityMedium {
+		t.Errorf("got[0] = %+v, want escalated from medium", got[0])
+	}
+	if l := BaselineLabel(got[0]); l != "escalated from medium to high" {
+		t.Errorf("label = %q", l)
+	}
+	if got[1].Title != "SQL injection" || got[1].Baseline != BaselineNew {
+		t.Errorf("got[1] = %+v, want new", got[1])
+	}
+	if l := BaselineLabel(got[1]); l != "new" {
+		t.Errorf("label = %q, want new", l)
+	}
+}
+
+func TestApplyBaseline_NothingNew(t *testing.T) {
+	f := Finding{Severity: SeverityHigh, Category: CategoryBug, Title: "Bad", Locations: []Location{{Path: "a.go"}}}
+	if got := ApplyBaseline([]Finding{f}, []Finding{f}); got == nil || len(got) != 0 {
+		t.Errorf("ApplyBaseline = %#v, want an empty slice", got)
+	}
+}
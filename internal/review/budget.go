@@ -0,0 +1,74 @@
+package review
+
+import (
+	"fmt"
+
+	"github.com/dshills/prism/internal/config"
+	"github.com/dshills/prism/internal/providers"
+)
+
+// costEstimate is the projected prompt cost of the requests sent to one
+// model.
+type costEstimate struct {
+	label    string
+	requests int
+	tokens   int
+	cost     float64
+}
+
+// estimateCost projects the prompt cost of sending reqs to provider/model.
+// It fails for models with no known price, since the budget could not be
+// enforced.
+func estimateCost(provider, model string, reqs []providers.ReviewRequest) (costEstimate, error) {
+	label := provider + ":" + model
+	price, ok := providers.LookupPrice(provider, model)
+	if !ok {
+		return costEstimate{}, fmt.Errorf("--max-cost: no price known for %s, so its cost cannot be estimated", label)
+	}
+	est := costEstimate{label: label, requests: len(reqs)}
+	for _, r := range reqs {
+		est.tokens += providers.EstimateTokens(r)
+	}
+	est.cost = price.Cost(est.tokens, 0)
+	return est, nil
+}
+
+// checkBudget returns an error if the combined estimates exceed maxCost
+// dollars. A maxCost of zero or less disables the check.
+func checkBudget(maxCost float64, estimates []costEstimate) error {
+	if maxCost <= 0 {
+		return nil
+	}
+	var total float64
+	var requests, tokens int
+	for _, e := range estimates {
+		total += e.cost
+		requests += e.requests
+		tokens += e.tokens
+	}
+	if total <= maxCost {
+		return nil
+	}
+	return fmt.Errorf("projected prompt cost $%.4f (%d request(s), ~%d tokens) exceeds --max-cost $%.2f; "+
+		"shrink the review with --max-diff-bytes, --paths, or --exclude", total, requests, tokens, maxCost)
+}
+
+// plannedRequests returns the review requests the pipeline would send for
+// diff, without sending them. It mirrors the chunking decision in
+// reviewPipeline; repair passes are not included.
+func plannedRequests(diff string, files []string, cfg config.Config, rules *Rules, opts reviewOpts) []providers.ReviewRequest {
+	builder := opts.builder
+	if builder == nil {
+		builder = defaultPromptBuilder
+	}
+	var reqs []providers.ReviewRequest
+	if opts.alwaysChunk || NeedsChunking(diff) {
+		for _, chunk := range SplitIntoChunksWithMaxFiles(diff, chunkBytes(cfg), opts.maxFilesPerChunk) {
+			sysPr, userPr := builder(chunk.Diff, chunk.Files, cfg, rules)
+			reqs = append(reqs, providers.ReviewRequest{SystemPrompt: sysPr, UserPrompt: userPr})
+		}
+		return reqs
+	}
+	sysPr, userPr := builder(diff, files, cfg, rules)
+	return append(reqs, providers.ReviewRequest{SystemPrompt: sysPr, UserPrompt: userPr})
+}
@@ -0,0 +1,80 @@
+package review
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dshills/prism/internal/config"
+	"github.com/dshills/prism/internal/gitctx"
+)
+
+func TestRun_MaxCostExceeded(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	cfg := config.Default()
+	cfg.Provider = "anthropic"
+	cfg.Model = "claude-opus-4-6"
+	cfg.Cache.Enabled = false
+	cfg.MaxCost = 0.0001
+
+	diff := gitctx.DiffResult{
+		Diff:  "diff --git a/x.go b/x.go\n--- a/x.go\n+++ b/x.go\n@@ -0,0 +1 @@\n+package x\n",
+		Files: []string{"x.go"},
+	}
+	// The budget is checked before the provider is created, so no API key
+	// is needed to see the error.
+	_, err := Run(context.Background(), diff, cfg)
+	if err == nil {
+		t.Fatal("expected budget error")
+	}
+	if !strings.Contains(err.Error(), "exceeds --max-cost") || !strings.Contains(err.Error(), "--max-diff-bytes") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRun_MaxCostUnknownModel(t *testing.T) {
+	cfg := config.Default()
+	cfg.Provider = "anthropic"
+	cfg.Model = "claude-next"
+	cfg.Cache.Enabled = false
+	cfg.MaxCost = 10
+
+	diff := gitctx.DiffResult{Diff: "diff --git a/x.go b/x.go\n+x\n", Files: []string{"x.go"}}
+	_, err := Run(context.Background(), diff, cfg)
+	if err == nil || !strings.Contains(err.Error(), "no price known") {
+		t.Errorf("expected unknown price error, got %v", err)
+	}
+}
+
+func TestCheckBudget(t *testing.T) {
+	reqs := plannedRequests(strings.Repeat("+line\n", 1000), []string{"a.go"}, config.Default(), nil, reviewOpts{})
+	if len(reqs) != 1 {
+		t.Fatalf("got %d planned requests, want 1", len(reqs))
+	}
+
+	local, err := estimateCost("ollama", "llama3", reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkBudget(0.01, []costEstimate{local}); err != nil {
+		t.Errorf("local models are free, got %v", err)
+	}
+
+	cloud, err := estimateCost("anthropic", "claude-sonnet-4-6", reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cloud.tokens == 0 || cloud.cost <= 0 {
+		t.Fatalf("estimate = %+v, want non-zero tokens and cost", cloud)
+	}
+	if err := checkBudget(cloud.cost*1.5, []costEstimate{cloud}); err != nil {
+		t.Errorf("one model under budget: %v", err)
+	}
+	// Two models together exceed the same budget.
+	if err := checkBudget(cloud.cost*1.5, []costEstimate{cloud, cloud}); err == nil {
+		t.Error("expected error when the combined cost exceeds the budget")
+	}
+	if err := checkBudget(0, []costEstimate{cloud}); err != nil {
+		t.Errorf("zero budget disables the check, got %v", err)
+	}
+}
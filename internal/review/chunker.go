@@ -13,7 +13,8 @@ import (
 )
 
 const (
-	// maxConcurrency limits parallel LLM calls.
+	// maxConcurrency limits parallel LLM calls unless the config sets
+	// Concurrency.
 	maxConcurrency = 4
 	// ChunkThreshold is the byte size above which we switch to chunked review.
 	ChunkThreshold = 100000 // 100KB
@@ -30,6 +31,14 @@ type Chunk struct {
 // Each chunk contains the diff sections for one or more files,
 // staying under maxBytes per chunk.
 func SplitIntoChunks(diff string, maxBytes int) []Chunk {
+	return SplitIntoChunksWithMaxFiles(diff, maxBytes, 0)
+}
+
+// SplitIntoChunksWithMaxFiles splits a diff like SplitIntoChunks, also
+// starting a new chunk once the current one holds maxFiles files, so many
+// small files do not share one chunk. A maxFiles of 0 or less means no
+// file limit.
+func SplitIntoChunksWithMaxFiles(diff string, maxBytes, maxFiles int) []Chunk {
 	sections := splitSections(diff)
 	if len(sections) == 0 {
 		return nil
@@ -47,8 +56,9 @@ func SplitIntoChunks(diff string, maxBytes int) []Chunk {
 	for _, sec := range sections {
 		path := pathFromSection(sec)
 
-		// If adding this section would exceed maxBytes, flush the current chunk
-		if currentDiff.Len() > 0 && currentDiff.Len()+len(sec) > maxBytes {
+		// If adding this section would exceed maxBytes or maxFiles, flush the current chunk
+		full := maxFiles > 0 && path != "" && len(currentFiles) >= maxFiles
+		if currentDiff.Len() > 0 && (currentDiff.Len()+len(sec) > maxBytes || full) {
 			chunks = append(chunks, Chunk{
 				Index: idx,
 				Diff:  currentDiff.String(),
@@ -77,6 +87,16 @@ func SplitIntoChunks(diff string, maxBytes int) []Chunk {
 	return chunks
 }
 
+// chunkBytes returns the byte budget for one chunk: cfg.MaxDiffBytes when
+// it is smaller than ChunkThreshold, otherwise ChunkThreshold, so a diff
+// that needed chunking is actually split.
+func chunkBytes(cfg config.Config) int {
+	if cfg.MaxDiffBytes > 0 && cfg.MaxDiffBytes < ChunkThreshold {
+		return cfg.MaxDiffBytes
+	}
+	return ChunkThreshold
+}
+
 // NeedsChunking returns true if the diff is large enough to benefit from chunked review.
 func NeedsChunking(diff string) bool {
 	return len(diff) > ChunkThreshold
@@ -88,11 +108,31 @@ type PromptBuilder func(chunkDiff string, files []string, cfg config.Config, rul
 // ChunkOptions controls how chunked review is performed.
 type ChunkOptions struct {
 	Builder PromptBuilder
+	// Retry coordinates retries across chunks so a rate-limited API makes
+	// all chunks back off together. nil = a fresh coordinator with a
+	// budget of cfg.RetryBudget.
+	Retry *providers.RetryCoordinator
+	// OnTiming, if set, receives each chunk's timing in chunk order once
+	// every chunk has finished.
+	OnTiming func(ChunkTiming)
+	// OnFinding, if set, receives findings as each chunk's response
+	// arrives; see Options.OnFinding. It is called concurrently from the
+	// chunk goroutines.
+	OnFinding func(Finding)
+}
+
+// ConcurrencyLimit returns the number of LLM calls a review may run in
+// parallel: cfg.Concurrency when set, otherwise maxConcurrency.
+func ConcurrencyLimit(cfg config.Config) int {
+	if cfg.Concurrency > 0 {
+		return cfg.Concurrency
+	}
+	return maxConcurrency
 }
 
 // defaultPromptBuilder uses the standard diff-review prompts.
 func defaultPromptBuilder(chunkDiff string, files []string, cfg config.Config, rules *Rules) (string, string) {
-	return SystemPrompt(), BuildUserPromptWithRules(chunkDiff, files, cfg.MaxFindings, cfg.FailOn, rules)
+	return SystemPromptWithCategories(cfg.ExtraCategories), BuildUserPromptWithRules(chunkDiff, files, cfg.MaxFindings, cfg.FailOn, rules)
 }
 
 // RunChunked reviews diff chunks in parallel and merges findings.
@@ -111,16 +151,23 @@ func RunChunkedWithOptions(ctx context.Context, chunks []Chunk, provider provide
 	if builder == nil {
 		builder = defaultPromptBuilder
 	}
+	retry := opts.Retry
+	if retry == nil {
+		retry = providers.NewRetryCoordinator(cfg.RetryBudget)
+	}
+	ctx = providers.WithRetryCoordinator(ctx, retry)
 
 	type result struct {
 		index    int
 		findings []Finding
+		llmMs    int64
+		retries  int
 		err      error
 	}
 
 	results := make([]result, len(chunks))
 	var wg sync.WaitGroup
-	sem := make(chan struct{}, maxConcurrency)
+	sem := make(chan struct{}, ConcurrencyLimit(cfg))
 	var totalLLMMs int64
 	var mu sync.Mutex
 
@@ -136,18 +183,20 @@ func RunChunkedWithOptions(ctx context.Context, chunks []Chunk, provider provide
 				SystemPrompt: sysPr,
 				UserPrompt:   userPr,
 				MaxTokens:    8192,
+				FindingsJSON: true,
 			}
 
 			llmStart := time.Now()
-			resp, err := provider.Review(ctx, req)
+			resp, err := reviewStreaming(ctx, provider, req, opts.OnFinding)
 			elapsed := time.Since(llmStart).Milliseconds()
+			retries := resp.Retries
 
 			mu.Lock()
 			totalLLMMs += elapsed
 			mu.Unlock()
 
 			if err != nil {
-				results[i] = result{index: i, err: fmt.Errorf("chunk %d: %w", i, err)}
+				results[i] = result{index: i, llmMs: elapsed, retries: retries, err: fmt.Errorf("chunk %d: %w", i, err)}
 				return
 			}
 
@@ -158,28 +207,45 @@ func RunChunkedWithOptions(ctx context.Context, chunks []Chunk, provider provide
 					"Your previous response was not valid JSON. The error was: %s\n\nPlease fix and respond with ONLY a valid JSON array of findings.\n\nPrevious response:\n%s",
 					err.Error(), resp.Content,
 				)
+				repairStart := time.Now()
 				resp2, err2 := provider.Review(ctx, providers.ReviewRequest{
 					SystemPrompt: sysPr,
 					UserPrompt:   repairPrompt,
 					MaxTokens:    8192,
+					FindingsJSON: true,
 				})
+				repairMs := time.Since(repairStart).Milliseconds()
+				elapsed += repairMs
+				retries += resp2.Retries
+				mu.Lock()
+				totalLLMMs += repairMs
+				mu.Unlock()
 				if err2 != nil {
-					results[i] = result{index: i, err: fmt.Errorf("chunk %d repair: %w", i, err2)}
+					results[i] = result{index: i, llmMs: elapsed, retries: retries, err: fmt.Errorf("chunk %d repair: %w", i, err2)}
 					return
 				}
 				findings, err = parseFindings(resp2.Content)
 				if err != nil {
-					results[i] = result{index: i, err: fmt.Errorf("chunk %d validation after repair: %w", i, err)}
+					if resp.Truncated() || resp2.Truncated() {
+						err = fmt.Errorf("output hit the token limit: %w", err)
+					}
+					results[i] = result{index: i, llmMs: elapsed, retries: retries, err: fmt.Errorf("chunk %d validation after repair: %w", i, err)}
 					return
 				}
 			}
 
-			results[i] = result{index: i, findings: findings}
+			results[i] = result{index: i, findings: findings, llmMs: elapsed, retries: retries}
 		}(i, chunk)
 	}
 
 	wg.Wait()
 
+	if opts.OnTiming != nil {
+		for i, r := range results {
+			opts.OnTiming(ChunkTiming{Index: chunks[i].Index, Files: chunks[i].Files, LLMMs: r.llmMs, Retries: r.retries})
+		}
+	}
+
 	// Merge findings in stable order (by chunk index)
 	var allFindings []Finding
 	for _, r := range results {
@@ -201,7 +267,7 @@ func RunChunkedWithOptions(ctx context.Context, chunks []Chunk, provider provide
 // DeduplicateFindings removes duplicate findings by ID.
 func DeduplicateFindings(findings []Finding) []Finding {
 	seen := make(map[string]bool)
-	var result []Finding
+	result := []Finding{}
 	for _, f := range findings {
 		if !seen[f.ID] {
 			seen[f.ID] = true
@@ -211,7 +277,53 @@ func DeduplicateFindings(findings []Finding) []Finding {
 	return result
 }
 
-// SortFindings sorts findings by severity (high first), then path, then line.
+// MergeIdenticalFindings collapses findings that share a title, category,
+// and suggestion into a single finding listing every location. The merged
+// finding keeps the first occurrence's position in the slice, the highest
+// severity, and the highest confidence of the group.
+func MergeIdenticalFindings(findings []Finding) []Finding {
+	type mergeKey struct {
+		title, category, suggestion string
+	}
+	index := make(map[mergeKey]int)
+	result := []Finding{}
+	for _, f := range findings {
+		k := mergeKey{normalizeTitle(f.Title), string(f.Category), strings.TrimSpace(f.Suggestion)}
+		i, ok := index[k]
+		if !ok {
+			index[k] = len(result)
+			f.Locations = append([]Location(nil), f.Locations...)
+			result = append(result, f)
+			continue
+		}
+		merged := &result[i]
+		if SeverityRank(f.Severity) > SeverityRank(merged.Severity) {
+			merged.Severity = f.Severity
+		}
+		if f.Confidence > merged.Confidence {
+			merged.Confidence = f.Confidence
+		}
+		for _, loc := range f.Locations {
+			if !hasLocation(merged.Locations, loc) {
+				merged.Locations = append(merged.Locations, loc)
+			}
+		}
+	}
+	return result
+}
+
+func hasLocation(locs []Location, loc Location) bool {
+	for _, l := range locs {
+		if l == loc {
+			return true
+		}
+	}
+	return false
+}
+
+// SortFindings sorts findings by severity (high first), then consensus
+// findings before the rest, then path, then line, with the finding ID as a
+// final tie-break so the order is deterministic.
 func SortFindings(findings []Finding) {
 	sort.Slice(findings, func(i, j int) bool {
 		ri := SeverityRank(findings[i].Severity)
@@ -219,6 +331,9 @@ func SortFindings(findings []Finding) {
 		if ri != rj {
 			return ri > rj
 		}
+		if findings[i].Consensus != findings[j].Consensus {
+			return findings[i].Consensus
+		}
 		pi := findingPath(findings[i])
 		pj := findingPath(findings[j])
 		if pi != pj {
@@ -226,10 +341,24 @@ func SortFindings(findings []Finding) {
 		}
 		li := findingStartLine(findings[i])
 		lj := findingStartLine(findings[j])
-		return li < lj
+		if li != lj {
+			return li < lj
+		}
+		return findings[i].ID < findings[j].ID
 	})
 }
 
+// LimitFindings sorts findings with SortFindings and keeps at most max of
+// them, so truncation always drops the least severe findings first. A max of
+// zero or less keeps everything.
+func LimitFindings(findings []Finding, max int) []Finding {
+	SortFindings(findings)
+	if max > 0 && len(findings) > max {
+		findings = findings[:max]
+	}
+	return findings
+}
+
 func findingPath(f Finding) string {
 	if len(f.Locations) > 0 {
 		return f.Locations[0].Path
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dshills/prism/internal/config"
 	"github.com/dshills/prism/internal/providers"
@@ -54,6 +55,48 @@ func TestSplitIntoChunks_MultipleFiles(t *testing.T) {
 	}
 }
 
+func TestSplitIntoChunksWithMaxFiles(t *testing.T) {
+	var sections []string
+	for i := 0; i < 7; i++ {
+		name := fmt.Sprintf("file%d.go", i)
+		sections = append(sections, fmt.Sprintf(
+			"diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n@@ -1,3 +1,4 @@\n+line\n",
+			name, name, name, name,
+		))
+	}
+	diff := strings.Join(sections, "")
+
+	// File limit only: 7 files at 3 per chunk
+	chunks := SplitIntoChunksWithMaxFiles(diff, 1000000, 3)
+	var sizes []int
+	for _, c := range chunks {
+		sizes = append(sizes, len(c.Files))
+	}
+	if fmt.Sprint(sizes) != "[3 3 1]" {
+		t.Errorf("files per chunk = %v, want [3 3 1]", sizes)
+	}
+
+	// Both limits: the byte limit (two sections) is hit before the file limit
+	sectionLen := len(sections[0])
+	chunks = SplitIntoChunksWithMaxFiles(diff, 2*sectionLen, 3)
+	if len(chunks) != 4 {
+		t.Errorf("got %d chunks, want 4 when bytes bind first", len(chunks))
+	}
+	for i, c := range chunks {
+		if c.Index != i {
+			t.Errorf("chunks[%d].Index = %d", i, c.Index)
+		}
+		if len(c.Files) > 2 {
+			t.Errorf("chunk %d has %d files, want at most 2", i, len(c.Files))
+		}
+	}
+
+	// No file limit matches SplitIntoChunks
+	if got, want := len(SplitIntoChunksWithMaxFiles(diff, 1000000, 0)), len(SplitIntoChunks(diff, 1000000)); got != want {
+		t.Errorf("maxFiles 0 gave %d chunks, want %d", got, want)
+	}
+}
+
 func TestSplitIntoChunks_LargeMaxBytes(t *testing.T) {
 	// With large maxBytes, everything fits in one chunk
 	diff := "diff --git a/a.go b/a.go\n+++ b/a.go\n+line1\ndiff --git a/b.go b/b.go\n+++ b/b.go\n+line2\n"
@@ -230,6 +273,36 @@ func TestDeduplicateFindings(t *testing.T) {
 	}
 }
 
+func TestLimitFindings_KeepsMostSevere(t *testing.T) {
+	// Compare mode and overridden severities can leave findings unsorted;
+	// truncation must still keep the high-severity ones.
+	findings := []Finding{
+		{ID: "l1", Severity: SeverityLow},
+		{ID: "m1", Severity: SeverityMedium},
+		{ID: "l2", Severity: SeverityLow},
+		{ID: "h1", Severity: SeverityHigh},
+		{ID: "h2", Severity: SeverityHigh},
+	}
+	result := LimitFindings(findings, 3)
+	if len(result) != 3 {
+		t.Fatalf("got %d findings, want 3", len(result))
+	}
+	want := []string{"h1", "h2", "m1"}
+	for i, id := range want {
+		if result[i].ID != id {
+			t.Errorf("result[%d].ID = %q, want %q", i, result[i].ID, id)
+		}
+	}
+}
+
+func TestLimitFindings_NoLimit(t *testing.T) {
+	findings := []Finding{{ID: "a", Severity: SeverityLow}, {ID: "b", Severity: SeverityHigh}}
+	result := LimitFindings(findings, 0)
+	if len(result) != 2 || result[0].ID != "b" {
+		t.Errorf("LimitFindings(0) = %v, want both findings sorted high first", result)
+	}
+}
+
 func TestFindingPath_NoLocations(t *testing.T) {
 	f := Finding{Title: "No locations"}
 	if findingPath(f) != "" {
@@ -328,3 +401,96 @@ func TestRunChunkedWithOptions_NilBuilder(t *testing.T) {
 		t.Errorf("got %d findings, want 0", len(findings))
 	}
 }
+
+func TestMergeIdenticalFindings(t *testing.T) {
+	mk := func(path string, line int, sev Severity, conf float64) Finding {
+		return Finding{
+			Severity:   sev,
+			Category:   CategoryBug,
+			Title:      "Missing context timeout",
+			Suggestion: "Use context.WithTimeout",
+			Confidence: conf,
+			Locations:  []Location{{Path: path, Lines: LineRange{Start: line, End: line}}},
+		}
+	}
+	findings := []Finding{
+		mk("a.go", 10, SeverityMedium, 0.7),
+		{Severity: SeverityLow, Category: CategoryStyle, Title: "Other", Locations: []Location{{Path: "a.go"}}},
+		mk("b.go", 20, SeverityHigh, 0.8),
+		mk("c.go", 30, SeverityLow, 0.9),
+	}
+
+	merged := MergeIdenticalFindings(findings)
+	if len(merged) != 2 {
+		t.Fatalf("got %d findings, want 2", len(merged))
+	}
+	m := merged[0]
+	if len(m.Locations) != 3 {
+		t.Fatalf("merged finding has %d locations, want 3", len(m.Locations))
+	}
+	for i, want := range []string{"a.go", "b.go", "c.go"} {
+		if m.Locations[i].Path != want {
+			t.Errorf("Locations[%d].Path = %q, want %q", i, m.Locations[i].Path, want)
+		}
+	}
+	if m.Severity != SeverityHigh {
+		t.Errorf("Severity = %q, want highest %q", m.Severity, SeverityHigh)
+	}
+	if m.Confidence != 0.9 {
+		t.Errorf("Confidence = %v, want 0.9", m.Confidence)
+	}
+	if len(findings[0].Locations) != 1 {
+		t.Error("input finding locations should not be modified")
+	}
+}
+
+func TestMergeIdenticalFindings_EmptyNotNil(t *testing.T) {
+	// A nil slice would marshal as "findings": null in the JSON report
+	if got := MergeIdenticalFindings(nil); got == nil {
+		t.Error("MergeIdenticalFindings(nil) = nil, want an empty slice")
+	}
+	if got := DeduplicateFindings(nil); got == nil {
+		t.Error("DeduplicateFindings(nil) = nil, want an empty slice")
+	}
+}
+
+// slowReviewer sleeps before answering requests whose prompt mentions slow.
+type slowReviewer struct{ delay time.Duration }
+
+func (s *slowReviewer) Review(_ context.Context, req providers.ReviewRequest) (providers.ReviewResponse, error) {
+	if strings.Contains(req.UserPrompt, "slow") {
+		time.Sleep(s.delay)
+	}
+	return providers.ReviewResponse{Content: "[]"}, nil
+}
+
+func (s *slowReviewer) Name() string { return "slow" }
+
+func TestRunChunkedWithOptions_Timings(t *testing.T) {
+	chunks := []Chunk{
+		{Index: 0, Diff: "diff slow", Files: []string{"a.go", "b.go"}},
+		{Index: 1, Diff: "diff fast", Files: []string{"c.go"}},
+	}
+	var timings []ChunkTiming
+	_, llmMs, err := RunChunkedWithOptions(context.Background(), chunks, &slowReviewer{delay: 20 * time.Millisecond}, config.Default(), nil, ChunkOptions{
+		OnTiming: func(ct ChunkTiming) { timings = append(timings, ct) },
+	})
+	if err != nil {
+		t.Fatalf("RunChunkedWithOptions error: %v", err)
+	}
+	if len(timings) != 2 {
+		t.Fatalf("got %d timings, want 2", len(timings))
+	}
+	if timings[0].Index != 0 || strings.Join(timings[0].Files, ",") != "a.go,b.go" {
+		t.Errorf("timings[0] = %+v", timings[0])
+	}
+	if timings[1].Index != 1 || strings.Join(timings[1].Files, ",") != "c.go" {
+		t.Errorf("timings[1] = %+v", timings[1])
+	}
+	if timings[0].LLMMs < 20 {
+		t.Errorf("slow chunk LLMMs = %d, want >= 20", timings[0].LLMMs)
+	}
+	if timings[0].LLMMs+timings[1].LLMMs != llmMs {
+		t.Errorf("chunk timings sum to %d, want total %d", timings[0].LLMMs+timings[1].LLMMs, llmMs)
+	}
+}
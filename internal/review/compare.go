@@ -18,6 +18,9 @@ type CompareResult struct {
 	Unique    map[string][]Finding // Unique findings per model (key: "provider:model")
 	All       []Finding // All merged findings for the report
 	LLMMs     int64
+	// Warnings are problems that did not stop the comparison (see
+	// Report.Warnings).
+	Warnings []string
 }
 
 // compareModelResult holds the output from a single model's review.
@@ -30,10 +33,14 @@ type compareModelResult struct {
 // CompareOptions controls how compare mode constructs prompts.
 type CompareOptions struct {
 	Builder PromptBuilder // nil = use default diff prompts
+	// Retry coordinates retries across models. Models on different
+	// providers rarely share rate limits, so nil leaves them independent.
+	Retry *providers.RetryCoordinator
 }
 
 // RunCompare runs reviews independently across multiple provider:model pairs
-// and merges findings.
+// and merges findings. At most cfg.Concurrency models (default 4) are
+// queried at once.
 func RunCompare(ctx context.Context, diff string, files []string, models []string, cfg config.Config, rules *Rules) (*CompareResult, error) {
 	return RunCompareWithOptions(ctx, diff, files, models, cfg, rules, CompareOptions{})
 }
@@ -44,9 +51,68 @@ func RunCompareWithOptions(ctx context.Context, diff string, files []string, mod
 	if builder == nil {
 		builder = defaultPromptBuilder
 	}
+	if err := ValidateSeverityFloors(cfg.SeverityFloors); err != nil {
+		return nil, err
+	}
+	var warnings []string
+	guide, warning, err := LoadGuide(cfg.GuideFile)
+	if err != nil {
+		return nil, err
+	}
+	if warning != "" {
+		warnings = append(warnings, warning)
+	}
+	tmpl, err := LoadPromptTemplate(cfg.PromptTemplateFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPromptTemplate(tmpl, builder, diff, files, cfg, rules); err != nil {
+		return nil, err
+	}
+	builder = withGuide(withPromptTemplate(builder, tmpl), guide)
+	ctx = providers.WithRetryCoordinator(ctx, opts.Retry)
+
+	// Refuse the whole comparison up front rather than sending the diff to
+	// the permitted models only.
+	if providers.Offline() {
+		for _, spec := range models {
+			providerName, _, err := parseModelSpec(spec)
+			if err != nil {
+				return nil, err
+			}
+			if err := providers.CheckOffline(providerName); err != nil {
+				return nil, fmt.Errorf("%s: %w", spec, err)
+			}
+		}
+	}
+
+	if cfg.MaxCost > 0 {
+		redactedDiff := diff
+		if cfg.Privacy.RedactSecrets {
+			redactedDiff = redact.Secrets(redactedDiff)
+		}
+		sysPr, userPr := builder(redactedDiff, files, cfg, rules)
+		reqs := []providers.ReviewRequest{{SystemPrompt: sysPr, UserPrompt: userPr}}
+		var estimates []costEstimate
+		for _, spec := range models {
+			providerName, modelName, err := parseModelSpec(spec)
+			if err != nil {
+				return nil, err
+			}
+			est, err := estimateCost(providerName, modelName, reqs)
+			if err != nil {
+				return nil, err
+			}
+			estimates = append(estimates, est)
+		}
+		if err := checkBudget(cfg.MaxCost, estimates); err != nil {
+			return nil, err
+		}
+	}
 
 	results := make([]compareModelResult, len(models))
 	var wg sync.WaitGroup
+	sem := make(chan struct{}, ConcurrencyLimit(cfg))
 	var totalLLMMs int64
 	var mu sync.Mutex
 
@@ -54,6 +120,8 @@ func RunCompareWithOptions(ctx context.Context, diff string, files []string, mod
 		wg.Add(1)
 		go func(i int, spec string) {
 			defer wg.Done()
+			sem <- struct{}{}        // acquire
+			defer func() { <-sem }() // release
 
 			providerName, modelName, err := parseModelSpec(spec)
 			if err != nil {
@@ -79,6 +147,7 @@ func RunCompareWithOptions(ctx context.Context, diff string, files []string, mod
 				SystemPrompt: sysPr,
 				UserPrompt:   userPr,
 				MaxTokens:    8192,
+				FindingsJSON: true,
 			})
 			elapsed := time.Since(llmStart).Milliseconds()
 
@@ -96,7 +165,11 @@ func RunCompareWithOptions(ctx context.Context, diff string, files []string, mod
 				results[i] = compareModelResult{label: spec, err: fmt.Errorf("%s: invalid response: %w", spec, err)}
 				return
 			}
-
+			findings = NormalizeCategories(findings, cfg.ExtraCategories)
+			// Apply rules overrides and floors per model, before the merge,
+			// as a single-model review would
+			findings = ApplySeverityOverridesWithFloors(findings, rules, cfg.SeverityFloors)
+			findings = ApplyInlineSuppressions(findings, redactedDiff)
 			results[i] = compareModelResult{label: spec, findings: findings}
 		}(i, modelSpec)
 	}
@@ -111,7 +184,9 @@ func RunCompareWithOptions(ctx context.Context, diff string, files []string, mod
 	}
 
 	// Merge findings
-	return mergeResults(results, totalLLMMs), nil
+	cr := mergeResults(results, totalLLMMs)
+	cr.Warnings = warnings
+	return cr, nil
 }
 
 func mergeResults(results []compareModelResult, totalLLMMs int64) *CompareResult {
@@ -125,7 +200,9 @@ func mergeResults(results []compareModelResult, totalLLMMs int64) *CompareResult
 	}
 
 	// Track which findings from each model match findings from other models
-	// A finding is "consensus" if it appears in >=2 models (by fuzzy match)
+	// A finding is "consensus" if it appears in >=2 models (by fuzzy match).
+	// Matched findings are raised to the strongest severity and confidence
+	// among them, so a model underrating an issue cannot downgrade it.
 	type matchKey struct {
 		modelIdx   int
 		findingIdx int
@@ -140,6 +217,7 @@ func mergeResults(results []compareModelResult, totalLLMMs int64) *CompareResult
 					if fuzzyMatch(f, g) {
 						matchCounts[key]++
 						matchCounts[matchKey{j, gj}]++
+						raiseToStrongest(&results[i].findings[fi], &results[j].findings[gj])
 						break
 					}
 				}
@@ -154,14 +232,23 @@ func mergeResults(results []compareModelResult, totalLLMMs int64) *CompareResult
 		startLine int
 		category  Category
 	}
-	consensusSeen := make(map[dedupKey]bool)
+	// A duplicate keeps the first-seen entry, raised to the strongest
+	// severity and confidence of the two.
+	type seenAt struct {
+		consensus, all int
+	}
+	consensusSeen := make(map[dedupKey]seenAt)
 	for i, r := range results {
 		for fi, f := range r.findings {
 			key := matchKey{i, fi}
 			if matchCounts[key] > 0 {
+				f.Consensus = true
 				dk := dedupKey{findingPath(f), findingStartLine(f), f.Category}
-				if !consensusSeen[dk] {
-					consensusSeen[dk] = true
+				if at, ok := consensusSeen[dk]; ok {
+					raiseToStrongest(&cr.Consensus[at.consensus], &f)
+					cr.All[at.all] = cr.Consensus[at.consensus]
+				} else {
+					consensusSeen[dk] = seenAt{len(cr.Consensus), len(cr.All)}
 					cr.Consensus = append(cr.Consensus, f)
 					cr.All = append(cr.All, f)
 				}
@@ -172,9 +259,25 @@ func mergeResults(results []compareModelResult, totalLLMMs int64) *CompareResult
 		}
 	}
 
+	// Rank consensus findings first within each severity, so truncation by
+	// MaxFindings keeps the most agreed-upon ones
+	SortFindings(cr.All)
+
 	return cr
 }
 
+// raiseToStrongest sets both findings' severity and confidence to the higher
+// of the two.
+func raiseToStrongest(a, b *Finding) {
+	if SeverityRank(b.Severity) > SeverityRank(a.Severity) {
+		a.Severity = b.Severity
+	} else {
+		b.Severity = a.Severity
+	}
+	conf := max(a.Confidence, b.Confidence)
+	a.Confidence, b.Confidence = conf, conf
+}
+
 // fuzzyMatch determines if two findings are similar enough to be considered the same.
 func fuzzyMatch(a, b Finding) bool {
 	// Must be same file
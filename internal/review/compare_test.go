@@ -1,7 +1,16 @@
 package review
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/dshills/prism/internal/config"
 )
 
 func TestParseModelSpec(t *testing.T) {
@@ -315,6 +324,66 @@ func TestTitleSimilar_EmptyWords(t *testing.T) {
 	}
 }
 
+func TestMergeResults_ConsensusKeepsHighestSeverity(t *testing.T) {
+	loc := []Location{{Path: "main.go", Lines: LineRange{Start: 10, End: 12}}}
+	low := Finding{ID: "a", Category: CategoryBug, Title: "Unchecked error", Severity: SeverityLow, Confidence: 0.9, Locations: loc}
+	high := Finding{ID: "b", Category: CategoryBug, Title: "Unchecked error return", Severity: SeverityHigh, Confidence: 0.6, Locations: loc}
+
+	results := []compareModelResult{
+		{label: "openai:gpt-4", findings: []Finding{low}},
+		{label: "anthropic:claude", findings: []Finding{high}},
+	}
+
+	cr := mergeResults(results, 0)
+
+	if len(cr.Consensus) != 1 {
+		t.Fatalf("Consensus = %d, want 1", len(cr.Consensus))
+	}
+	got := cr.Consensus[0]
+	if got.Severity != SeverityHigh {
+		t.Errorf("merged severity = %s, want high", got.Severity)
+	}
+	if got.Confidence != 0.9 {
+		t.Errorf("merged confidence = %v, want 0.9", got.Confidence)
+	}
+	if len(cr.All) != 1 || cr.All[0].Severity != SeverityHigh {
+		t.Errorf("All = %+v, want one high finding", cr.All)
+	}
+}
+
+func TestMergeResults_LimitKeepsConsensus(t *testing.T) {
+	at := func(path string) []Location {
+		return []Location{{Path: path, Lines: LineRange{Start: 5, End: 5}}}
+	}
+	// The unique findings sort before the consensus one by path alone
+	shared := Finding{ID: "s", Category: CategoryBug, Title: "Race on cache map", Severity: SeverityMedium, Locations: at("z.go")}
+	results := []compareModelResult{
+		{label: "model-a", findings: []Finding{
+			shared,
+			{ID: "a1", Category: CategoryStyle, Title: "Naming", Severity: SeverityMedium, Locations: at("a.go")},
+		}},
+		{label: "model-b", findings: []Finding{
+			shared,
+			{ID: "b1", Category: CategoryDocs, Title: "Missing doc comment", Severity: SeverityMedium, Locations: at("b.go")},
+		}},
+	}
+
+	cr := mergeResults(results, 0)
+	if len(cr.All) != 3 || !cr.All[0].Consensus || cr.All[0].ID != "s" {
+		t.Fatalf("All = %+v, want the consensus finding first", cr.All)
+	}
+	for _, f := range cr.All[1:] {
+		if f.Consensus {
+			t.Errorf("unique finding %s marked as consensus", f.ID)
+		}
+	}
+
+	kept := LimitFindings(cr.All, 1)
+	if len(kept) != 1 || kept[0].ID != "s" {
+		t.Errorf("LimitFindings(All, 1) = %+v, want the consensus finding", kept)
+	}
+}
+
 func TestMergeResults_AllUnique(t *testing.T) {
 	// Two models find completely different things
 	results := []compareModelResult{
@@ -361,3 +430,86 @@ func TestMergeResults_AllUnique(t *testing.T) {
 		t.Errorf("Unique[model-b] = %d, want 1", len(cr.Unique["model-b"]))
 	}
 }
+
+func TestRunCompare_OfflineRefusesCloudModels(t *testing.T) {
+	t.Setenv("PRISM_OFFLINE", "1")
+	models := []string{"ollama:llama3", "anthropic:claude-sonnet-4-6"}
+	_, err := RunCompare(context.Background(), "diff", nil, models, config.Default(), nil)
+	if err == nil || !strings.Contains(err.Error(), "anthropic:claude-sonnet-4-6") {
+		t.Fatalf("expected offline error naming the cloud model, got %v", err)
+	}
+}
+
+func TestRunCompare_BoundedConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+		w.Write([]byte(`{"choices":[{"message":{"content":"[]"}}]}`))
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	var models []string
+	for i := 0; i < 8; i++ {
+		models = append(models, fmt.Sprintf("ollama:model%d", i))
+	}
+	cfg := config.Default()
+	cfg.Concurrency = 3
+
+	cr, err := RunCompare(context.Background(), "diff", nil, models, cfg, nil)
+	if err != nil {
+		t.Fatalf("RunCompare error: %v", err)
+	}
+	if len(cr.All) != 0 {
+		t.Errorf("All = %d, want 0", len(cr.All))
+	}
+	if got := peak.Load(); got > 3 {
+		t.Errorf("peak concurrent requests = %d, want at most 3", got)
+	}
+	if got := peak.Load(); got < 2 {
+		t.Errorf("peak concurrent requests = %d, want models to run in parallel", got)
+	}
+}
+
+func TestRunCompare_SeverityFloors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"content":"[{\"severity\":\"low\",\"category\":\"security\",\"title\":\"Unvalidated input\",\"path\":\"a.go\",\"startLine\":3,\"endLine\":3}]"}}]}`))
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	models := []string{"ollama:model-a", "ollama:model-b"}
+	cfg := config.Default()
+	cfg.SeverityFloors = map[string]string{"security": "high"}
+
+	cr, err := RunCompare(context.Background(), "diff", nil, models, cfg, nil)
+	if err != nil {
+		t.Fatalf("RunCompare error: %v", err)
+	}
+	if len(cr.All) != 1 || cr.All[0].Severity != SeverityHigh || !cr.All[0].Consensus {
+		t.Fatalf("All = %+v, want one consensus finding raised to high", cr.All)
+	}
+
+	// A rules override for the category takes precedence over the floor
+	rules := &Rules{SeverityOverrides: map[string]string{"security": "medium"}}
+	cr, err = RunCompare(context.Background(), "diff", nil, models, cfg, rules)
+	if err != nil {
+		t.Fatalf("RunCompare error: %v", err)
+	}
+	if len(cr.All) != 1 || cr.All[0].Severity != SeverityMedium {
+		t.Errorf("All = %+v, want the rules override to win", cr.All)
+	}
+
+	cfg.SeverityFloors = map[string]string{"security": "urgent"}
+	if _, err := RunCompare(context.Background(), "diff", nil, models, cfg, nil); err == nil {
+		t.Error("expected an invalid floor to fail compare mode")
+	}
+}
@@ -0,0 +1,256 @@
+package review
+
+import (
+	"strconv"
+	"strings"
+)
+
+// diffLine is a single new-side line from a unified diff hunk.
+type diffLine struct {
+	Number int    // line number in the new version of the file
+	Text   string // line content without the leading +/space marker
+	Added  bool   // true for "+" lines, false for context lines
+}
+
+// parseDiffLines maps each file path in a unified diff to the new-side lines
+// (added and context) visible in its hunks, in diff order. Removed lines are
+// skipped since they have no line number in the new version.
+func parseDiffLines(diff string) map[string][]diffLine {
+	result := make(map[string][]diffLine)
+	for _, sec := range splitSections(diff) {
+		path := pathFromSection(sec)
+		if path == "" {
+			continue
+		}
+		lineNo := 0
+		inHunk := false
+		for _, line := range strings.Split(sec, "\n") {
+			if strings.HasPrefix(line, "@@") {
+				lineNo = hunkNewStart(line)
+				inHunk = true
+				continue
+			}
+			if !inHunk {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(line, "+"):
+				result[path] = append(result[path], diffLine{Number: lineNo, Text: line[1:], Added: true})
+				lineNo++
+			case strings.HasPrefix(line, " "):
+				result[path] = append(result[path], diffLine{Number: lineNo, Text: line[1:]})
+				lineNo++
+			}
+		}
+	}
+	return result
+}
+
+// hunkNewStart extracts the new-file start line from a hunk header such as
+// "@@ -10,4 +12,6 @@ func foo()". Returns 0 if it cannot be parsed.
+func hunkNewStart(header string) int {
+	idx := strings.Index(header, "+")
+	if idx < 0 {
+		return 0
+	}
+	rest := header[idx+1:]
+	end := strings.IndexAny(rest, ", @")
+	if end >= 0 {
+		rest = rest[:end]
+	}
+	n, err := strconv.Atoi(rest)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// ComputeDiffStats counts the files, added lines, and removed lines in a
+// unified diff. File headers ("+++", "---") are not counted as changes.
+func ComputeDiffStats(diff string) DiffStats {
+	var st DiffStats
+	for _, sec := range splitSections(diff) {
+		st.FilesChanged++
+		inHunk := false
+		for _, line := range strings.Split(sec, "\n") {
+			if strings.HasPrefix(line, "@@") {
+				inHunk = true
+				continue
+			}
+			if !inHunk {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(line, "+"):
+				st.Additions++
+			case strings.HasPrefix(line, "-"):
+				st.Deletions++
+			}
+		}
+	}
+	return st
+}
+
+// diffHunk is one "@@" hunk of a file section with its new-side line span.
+type diffHunk struct {
+	Start, End int    // new-side lines covered by the hunk
+	Text       string // hunk header and body
+}
+
+// parseHunks maps each file path in a unified diff to its hunks.
+func parseHunks(diff string) map[string][]diffHunk {
+	result := make(map[string][]diffHunk)
+	for _, sec := range splitSections(diff) {
+		path := pathFromSection(sec)
+		if path == "" {
+			continue
+		}
+		var cur *diffHunk
+		var body strings.Builder
+		lineNo := 0
+		flush := func() {
+			if cur != nil {
+				cur.Text = strings.TrimRight(body.String(), "\n")
+				result[path] = append(result[path], *cur)
+			}
+			body.Reset()
+		}
+		for _, line := range strings.Split(sec, "\n") {
+			if strings.HasPrefix(line, "@@") {
+				flush()
+				lineNo = hunkNewStart(line)
+				cur = &diffHunk{Start: lineNo, End: lineNo - 1}
+				body.WriteString(line + "\n")
+				continue
+			}
+			if cur == nil {
+				continue
+			}
+			body.WriteString(line + "\n")
+			if strings.HasPrefix(line, "+") || strings.HasPrefix(line, " ") {
+				cur.End = lineNo
+				lineNo++
+			}
+		}
+		flush()
+	}
+	return result
+}
+
+// AttachHunks sets Finding.Hunk to the diff hunk overlapping each finding's
+// primary location. File-level findings and findings outside any hunk are
+// left unchanged.
+func AttachHunks(findings []Finding, diff string) {
+	hunks := parseHunks(diff)
+	for i := range findings {
+		if len(findings[i].Locations) == 0 {
+			continue
+		}
+		loc := findings[i].Locations[0]
+		if loc.Lines.FileLevel() {
+			continue
+		}
+		end := loc.Lines.End
+		if end < loc.Lines.Start {
+			end = loc.Lines.Start
+		}
+		for _, h := range hunks[loc.Path] {
+			if loc.Lines.Start <= h.End && end >= h.Start {
+				findings[i].Hunk = h.Text
+				break
+			}
+		}
+	}
+}
+
+// FilterNewCode keeps only findings anchored to lines added by the diff,
+// dropping those that point solely at context lines or at code the diff did
+// not touch. A location matches when its line range covers an added line in
+// the same file; a file-level location matches when its file has any added
+// lines. Findings without locations are kept.
+func FilterNewCode(findings []Finding, diff string) []Finding {
+	added := make(map[string][]int)
+	for path, lines := range parseDiffLines(diff) {
+		for _, l := range lines {
+			if l.Added {
+				added[path] = append(added[path], l.Number)
+			}
+		}
+	}
+
+	kept := []Finding{}
+	for _, f := range findings {
+		if len(f.Locations) == 0 || touchesAdded(f.Locations, added) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+func touchesAdded(locs []Location, added map[string][]int) bool {
+	for _, loc := range locs {
+		lines := added[loc.Path]
+		if len(lines) == 0 {
+			continue
+		}
+		if loc.Lines.FileLevel() {
+			return true
+		}
+		end := loc.Lines.End
+		if end < loc.Lines.Start {
+			end = loc.Lines.Start
+		}
+		for _, n := range lines {
+			if n >= loc.Lines.Start && n <= end {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DropNoopSuggestions removes findings whose suggestion, after normalizing
+// whitespace and stripping a code fence, is identical to the code the diff
+// shows at the finding's primary location. Such findings ask for no change
+// and are not actionable. Findings without a suggestion, without line
+// numbers, or pointing at lines the diff does not show are kept.
+func DropNoopSuggestions(findings []Finding, diff string) []Finding {
+	lines := parseDiffLines(diff)
+	kept := []Finding{}
+	for _, f := range findings {
+		if !isNoopSuggestion(f, lines) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+func isNoopSuggestion(f Finding, lines map[string][]diffLine) bool {
+	suggestion := normalizeCode(stripCodeFence(f.Suggestion))
+	if suggestion == "" || len(f.Locations) == 0 {
+		return false
+	}
+	loc := f.Locations[0]
+	if loc.Lines.FileLevel() {
+		return false
+	}
+	end := loc.Lines.End
+	if end < loc.Lines.Start {
+		end = loc.Lines.Start
+	}
+	var code []string
+	for _, l := range lines[loc.Path] {
+		if l.Number >= loc.Lines.Start && l.Number <= end {
+			code = append(code, l.Text)
+		}
+	}
+	if len(code) != end-loc.Lines.Start+1 {
+		return false // part of the range is not visible in the diff
+	}
+	return normalizeCode(strings.Join(code, "\n")) == suggestion
+}
+
+// normalizeCode collapses all whitespace runs to single spaces.
+func normalizeCode(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
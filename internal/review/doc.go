@@ -15,4 +15,16 @@
 //
 // Rules packs (rules.go) allow callers to override finding severities, specify
 // focus areas, and declare required checks that must appear in every review.
+//
+// Findings can be silenced at the source with a "prism:ignore[category|id]"
+// comment on or directly above the flagged line (suppress.go).
+//
+// CODEOWNERS files are parsed in owners.go to list who owns each changed
+// file; this is metadata only and never sent to a provider.
+//
+// Programs embedding the engine can post-process findings without forking it
+// by passing a FindingTransformer to RunWithOptions (or CodebaseConfig). It
+// runs after rules severity overrides and inline suppressions and before the
+// MaxFindings cap and the summary. Options.OnFinding (stream.go) receives
+// provisional findings while a provider's response is still streaming in.
 package review
@@ -31,8 +31,46 @@ type rawFinding struct {
 
 // reviewOpts controls differences between Run() and RunCodebase() pipelines.
 type reviewOpts struct {
-	builder     PromptBuilder // nil = default diff prompts
-	alwaysChunk bool          // true = skip NeedsChunking() check
+	builder          PromptBuilder // nil = default diff prompts
+	alwaysChunk      bool          // true = skip NeedsChunking() check
+	maxFilesPerChunk int           // 0 = chunks limited by bytes only
+	transform        FindingTransformer
+	onFinding        func(Finding)
+}
+
+// FindingTransformer post-processes a review's findings, for example to
+// enrich, filter, or recategorize them. It may modify and return the slice
+// it is given.
+type FindingTransformer func([]Finding) []Finding
+
+// Options customizes Run for programs embedding the review engine.
+type Options struct {
+	// Transform, if set, runs once per review after findings are parsed,
+	// categories normalized, rules severity overrides and floors applied,
+	// and prism:ignore suppressions removed. It runs before findings are
+	// sorted and capped at MaxFindings and before the summary is computed,
+	// so the cap and the verdict only count the findings it returns.
+	Transform FindingTransformer
+	// OnFinding, if set, receives each finding as soon as the provider's
+	// response contains it, so callers can show progress during a long
+	// review. Anthropic and OpenAI stream their responses; other providers
+	// deliver a response's findings together once it completes. Findings
+	// are provisional: they have not had rules, suppressions, Transform, or
+	// the cap applied, and a response that needs a JSON repair pass may
+	// deliver findings the final report lacks. With chunked reviews
+	// OnFinding may be called from several goroutines at once. It is not
+	// called for cached reviews.
+	OnFinding func(Finding)
+}
+
+// RedactDiff returns diff as it is sent to the provider: with secrets
+// redacted unless redaction is disabled. Anything copied from the diff into
+// a report, such as hunks, should come from the redacted text.
+func RedactDiff(diff string, cfg config.Config) string {
+	if !cfg.Privacy.RedactSecrets {
+		return diff
+	}
+	return redact.Secrets(diff)
 }
 
 // Run executes a review using the given diff result and configuration.
@@ -40,32 +78,85 @@ func Run(ctx context.Context, diff gitctx.DiffResult, cfg config.Config) (*Repor
 	return reviewPipeline(ctx, diff, cfg, reviewOpts{})
 }
 
-// reviewPipeline is the shared review flow: redact → cache → rules → LLM → cache write → overrides → limit → report.
+// RunWithOptions executes a review like Run, customized by opts.
+func RunWithOptions(ctx context.Context, diff gitctx.DiffResult, cfg config.Config, opts Options) (*Report, error) {
+	return reviewPipeline(ctx, diff, cfg, reviewOpts{transform: opts.Transform, onFinding: opts.OnFinding})
+}
+
+// reviewPipeline is the shared review flow: redact → cache → rules → LLM → cache write → overrides → transform → limit → report.
 func reviewPipeline(ctx context.Context, diff gitctx.DiffResult, cfg config.Config, opts reviewOpts) (*Report, error) {
 	startTime := time.Now()
 
 	// Redact secrets from diff before sending to provider
-	redactedDiff := diff.Diff
-	if cfg.Privacy.RedactSecrets {
-		redactedDiff = redact.Secrets(redactedDiff)
-	}
+	redactedDiff := RedactDiff(diff.Diff, cfg)
 
 	if strings.TrimSpace(redactedDiff) == "" {
 		return emptyReport(diff, startTime), nil
 	}
 
+	// Skip trivial diffs, such as a version bump, without spending an LLM call
+	if cfg.MinDiffBytes > 0 && len(redactedDiff) < cfg.MinDiffBytes {
+		report := emptyReport(diff, startTime)
+		report.Skipped = fmt.Sprintf("diff is %d bytes, below --min-diff-bytes %d", len(redactedDiff), cfg.MinDiffBytes)
+		return report, nil
+	}
+
+	// Resolve "auto" up front so the cache key names the real provider and model
+	if cfg.Provider == "auto" {
+		provider, model, err := providers.ResolveAuto(cfg.Model)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Provider, cfg.Model = provider, model
+	}
+
 	// Initialize cache
-	reviewCache, err := cache.New(cfg.Cache.Enabled, cfg.Cache.Dir, cfg.Cache.TTLSeconds)
+	mode := cache.ReadWrite
+	if cfg.Cache.Refresh {
+		mode = cache.WriteOnly
+	}
+	reviewCache, err := cache.NewWithMode(cfg.Cache.Enabled, CacheDir(cfg.Cache), cfg.Cache.TTLSeconds, mode)
 	if err != nil {
 		// Cache failure is non-fatal, just disable it
 		reviewCache, _ = cache.New(false, "", 0)
 	}
 
-	cacheKey := cache.BuildCacheKey(cfg.Provider, cfg.Model, redactedDiff)
+	// Load rules
+	rules, warnings, err := LoadRules(cfg.RulesFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading rules: %w", err)
+	}
+	if err := ValidateSeverityFloors(cfg.SeverityFloors); err != nil {
+		return nil, err
+	}
+	guide, warning, err := LoadGuide(cfg.GuideFile)
+	if err != nil {
+		return nil, err
+	}
+	if warning != "" {
+		warnings = append(warnings, warning)
+	}
+	tmpl, err := LoadPromptTemplate(cfg.PromptTemplateFile)
+	if err != nil {
+		return nil, err
+	}
+	if opts.builder == nil {
+		opts.builder = defaultPromptBuilder
+	}
+	if err := checkPromptTemplate(tmpl, opts.builder, redactedDiff, diff.Files, cfg, rules); err != nil {
+		return nil, err
+	}
+	opts.builder = withGuide(withPromptTemplate(opts.builder, tmpl), guide)
+
+	// The key covers the prompts as well as the diff, so changing the
+	// guide, rules, template, or categories does not return stale findings
+	cacheKey := promptCacheKey(redactedDiff, diff.Files, cfg, rules, opts.builder)
 
 	// Check cache
 	var findings []Finding
 	var llmMs int64
+	var chunkTimings []ChunkTiming
+	var retries int
 	if cached, ok := reviewCache.Get(cacheKey); ok {
 		findings, err = parseFindings(cached)
 		if err != nil {
@@ -74,12 +165,22 @@ func reviewPipeline(ctx context.Context, diff gitctx.DiffResult, cfg config.Conf
 		}
 	}
 
-	// Load rules
-	rules, err := LoadRules(cfg.RulesFile)
-	if err != nil {
-		return nil, fmt.Errorf("loading rules: %w", err)
+	if findings == nil && cfg.MaxCost > 0 {
+		est, err := estimateCost(cfg.Provider, cfg.Model, plannedRequests(redactedDiff, diff.Files, cfg, rules, opts))
+		if err != nil {
+			return nil, err
+		}
+		if err := checkBudget(cfg.MaxCost, []costEstimate{est}); err != nil {
+			return nil, err
+		}
 	}
 
+	// Track the tokens and cost of this review's calls. --max-cost also
+	// stops new calls once actual spend reaches the budget, which repair
+	// passes and output tokens can push past the pre-flight estimate.
+	usage := providers.NewUsageTracker(cfg.MaxCost)
+	ctx = providers.WithUsageTracker(ctx, usage)
+
 	if findings == nil {
 		provider, err := providers.New(cfg.Provider, cfg.Model)
 		if err != nil {
@@ -88,9 +189,11 @@ func reviewPipeline(ctx context.Context, diff gitctx.DiffResult, cfg config.Conf
 
 		// Use chunked review for large diffs or when always requested (codebase mode)
 		if opts.alwaysChunk || NeedsChunking(redactedDiff) {
-			chunks := SplitIntoChunks(redactedDiff, cfg.MaxDiffBytes)
+			chunks := SplitIntoChunksWithMaxFiles(redactedDiff, chunkBytes(cfg), opts.maxFilesPerChunk)
 			findings, llmMs, err = RunChunkedWithOptions(ctx, chunks, provider, cfg, rules, ChunkOptions{
-				Builder: opts.builder,
+				Builder:   opts.builder,
+				OnTiming:  func(t ChunkTiming) { chunkTimings = append(chunkTimings, t) },
+				OnFinding: opts.onFinding,
 			})
 			if err != nil {
 				return nil, fmt.Errorf("chunked review: %w", err)
@@ -107,13 +210,15 @@ func reviewPipeline(ctx context.Context, diff gitctx.DiffResult, cfg config.Conf
 				SystemPrompt: sysPr,
 				UserPrompt:   userPr,
 				MaxTokens:    8192,
+				FindingsJSON: true,
 			}
 
-			resp, err := provider.Review(ctx, req)
+			resp, err := reviewStreaming(ctx, provider, req, opts.onFinding)
 			if err != nil {
 				return nil, fmt.Errorf("provider review: %w", err)
 			}
 			llmMs = time.Since(llmStart).Milliseconds()
+			retries = resp.Retries
 
 			findings, err = parseFindings(resp.Content)
 			if err != nil {
@@ -126,13 +231,18 @@ func reviewPipeline(ctx context.Context, diff gitctx.DiffResult, cfg config.Conf
 					SystemPrompt: sysPr,
 					UserPrompt:   repairPrompt,
 					MaxTokens:    8192,
+					FindingsJSON: true,
 				}
 				resp2, err2 := provider.Review(ctx, repairReq)
 				if err2 != nil {
 					return nil, fmt.Errorf("repair pass failed: %w (original error: %w)", err2, err)
 				}
+				retries += resp2.Retries
 				findings, err = parseFindings(resp2.Content)
 				if err != nil {
+					if resp.Truncated() || resp2.Truncated() {
+						return nil, fmt.Errorf("response validation failed after repair (output hit the token limit; try reducing --max-diff-bytes): %w", err)
+					}
 					return nil, fmt.Errorf("response validation failed after repair: %w", err)
 				}
 			}
@@ -144,21 +254,107 @@ func reviewPipeline(ctx context.Context, diff gitctx.DiffResult, cfg config.Conf
 		}
 	}
 
+	// Map categories the prompt did not allow onto the built-in set
+	findings = NormalizeCategories(findings, cfg.ExtraCategories)
+
 	// Apply rules severity overrides
-	findings = ApplySeverityOverrides(findings, rules)
+	findings = ApplySeverityOverridesWithFloors(findings, rules, cfg.SeverityFloors)
+
+	// Drop findings silenced by prism:ignore comments in the diff
+	findings = ApplyInlineSuppressions(findings, redactedDiff)
 
-	// Limit findings
-	if cfg.MaxFindings > 0 && len(findings) > cfg.MaxFindings {
-		findings = findings[:cfg.MaxFindings]
+	if opts.transform != nil {
+		findings = opts.transform(findings)
 	}
 
-	return BuildReport(diff, findings, llmMs, time.Since(startTime).Milliseconds()), nil
+	// Limit findings, keeping the most severe
+	findings = LimitFindings(findings, cfg.MaxFindings)
+
+	report := BuildReport(diff, findings, llmMs, time.Since(startTime).Milliseconds())
+	report.Timing.Chunks = chunkTimings
+	report.Warnings = warnings
+	report.Timing.Retries = retries
+	for _, t := range chunkTimings {
+		report.Timing.Retries += t.Retries
+	}
+	if u := usage.Usage(); u.Calls > 0 {
+		report.Timing.Usage = &u
+	}
+	return report, nil
+}
+
+// promptCacheKey returns the cache key for reviewing diff with builder: the
+// provider and model, the diff, and the prompts builder makes for the whole
+// diff. Chunked reviews build their prompts per chunk, but every setting
+// that shapes them also shapes the whole-diff prompts.
+func promptCacheKey(diff string, files []string, cfg config.Config, rules *Rules, builder PromptBuilder) string {
+	sysPr, userPr := builder(diff, files, cfg, rules)
+	return cache.BuildCacheKey(cfg.Provider, cfg.Model, sysPr+"\x00"+userPr, diff)
+}
+
+// CacheDir returns the cache directory to use for cfg. With PerRepo set it
+// is namespaced to the current repository; outside a git repository the
+// shared directory is used.
+func CacheDir(cfg config.CacheConfig) string {
+	if !cfg.PerRepo {
+		return cfg.Dir
+	}
+	meta, err := gitctx.GetRepoMeta()
+	if err != nil || meta.Root == "" {
+		return cfg.Dir
+	}
+	dir, err := cache.RepoDir(cfg.Dir, meta.Root)
+	if err != nil {
+		return cfg.Dir
+	}
+	return dir
 }
 
 func parseFindings(content string) ([]Finding, error) {
-	content = strings.TrimSpace(content)
+	content = stripCodeFence(content)
+
+	var raw []rawFinding
+	if err := json.Unmarshal([]byte(content), &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON array: %w", err)
+	}
+
+	findings := make([]Finding, 0, len(raw))
+	for _, r := range raw {
+		findings = append(findings, r.finding())
+	}
 
-	// Strip markdown code fences if present
+	return findings, nil
+}
+
+// finding converts r into a Finding with its ID and stable key set.
+func (r rawFinding) finding() Finding {
+	f := Finding{
+		Severity:   Severity(r.Severity),
+		Category:   Category(r.Category),
+		Title:      r.Title,
+		Message:    r.Message,
+		Suggestion: r.Suggestion,
+		Confidence: r.Confidence,
+		Tags:       r.Tags,
+		Locations: []Location{
+			{
+				Path: r.Path,
+				Lines: LineRange{
+					Start: r.StartLine,
+					End:   r.EndLine,
+				},
+			},
+		},
+	}
+	f.ID = generateFindingID(f)
+	f.StableKey = generateStableKey(f)
+	return f
+}
+
+// stripCodeFence trims whitespace and removes a surrounding markdown code
+// fence from a model response, if present.
+func stripCodeFence(content string) string {
+	content = strings.TrimSpace(content)
 	if strings.HasPrefix(content, "```") {
 		lines := strings.Split(content, "\n")
 		if len(lines) >= 2 {
@@ -176,37 +372,7 @@ func parseFindings(content string) ([]Finding, error) {
 			}
 		}
 	}
-
-	var raw []rawFinding
-	if err := json.Unmarshal([]byte(content), &raw); err != nil {
-		return nil, fmt.Errorf("invalid JSON array: %w", err)
-	}
-
-	findings := make([]Finding, 0, len(raw))
-	for _, r := range raw {
-		f := Finding{
-			Severity:   Severity(r.Severity),
-			Category:   Category(r.Category),
-			Title:      r.Title,
-			Message:    r.Message,
-			Suggestion: r.Suggestion,
-			Confidence: r.Confidence,
-			Tags:       r.Tags,
-			Locations: []Location{
-				{
-					Path: r.Path,
-					Lines: LineRange{
-						Start: r.StartLine,
-						End:   r.EndLine,
-					},
-				},
-			},
-		}
-		f.ID = generateFindingID(f)
-		findings = append(findings, f)
-	}
-
-	return findings, nil
+	return content
 }
 
 // findingsToRaw converts parsed Findings back to rawFinding format for cache storage.
@@ -232,12 +398,16 @@ func findingsToRaw(findings []Finding) []rawFinding {
 	return raw
 }
 
+// generateFindingID hashes the path, normalized title, and start line of a
+// finding, so findings that differ only in title whitespace, case, or
+// trailing punctuation share an ID and are deduplicated. The displayed title
+// is left unchanged.
 func generateFindingID(f Finding) string {
 	var path string
 	if len(f.Locations) > 0 {
 		path = f.Locations[0].Path
 	}
-	data := fmt.Sprintf("%s:%s:%d", path, f.Title, func() int {
+	data := fmt.Sprintf("%s:%s:%d", path, normalizeTitle(f.Title), func() int {
 		if len(f.Locations) > 0 {
 			return f.Locations[0].Lines.Start
 		}
@@ -247,6 +417,28 @@ func generateFindingID(f Finding) string {
 	return fmt.Sprintf("%x", h[:8])
 }
 
+// generateStableKey hashes the path, normalized title, and category of a
+// finding. Unlike the ID it ignores line numbers, so the same issue keeps
+// its key when surrounding edits shift it up or down the file. Use it to
+// match findings across versions; use the ID for exact dedup within a run.
+func generateStableKey(f Finding) string {
+	var path string
+	if len(f.Locations) > 0 {
+		path = f.Locations[0].Path
+	}
+	data := fmt.Sprintf("%s:%s:%s", path, normalizeTitle(f.Title), strings.ToLower(string(f.Category)))
+	h := sha256.Sum256([]byte(data))
+	return fmt.Sprintf("%x", h[:8])
+}
+
+// normalizeTitle lowercases a title, collapses whitespace, and trims
+// trailing punctuation so cosmetic rewording by the model doesn't change
+// the stable key.
+func normalizeTitle(title string) string {
+	title = strings.Join(strings.Fields(strings.ToLower(title)), " ")
+	return strings.TrimRight(title, ".!:;")
+}
+
 // GenerateRunID creates a unique run identifier.
 func GenerateRunID() string {
 	h := sha256.Sum256([]byte(fmt.Sprintf("%d", time.Now().UnixNano())))
@@ -257,15 +449,27 @@ func GenerateRunID() string {
 type CodebaseConfig struct {
 	config.Config
 	MaxFindingsPerFile int
+	// MaxFilesPerChunk caps the files sent in one request, in addition to
+	// the byte limit. 0 means no file limit.
+	MaxFilesPerChunk int
+	// Transform, if set, post-processes findings as Options.Transform does
+	// for Run.
+	Transform FindingTransformer
+	// OnFinding, if set, receives findings as they arrive as
+	// Options.OnFinding does for Run.
+	OnFinding func(Finding)
 }
 
 // RunCodebase executes a full-codebase review.
 func RunCodebase(ctx context.Context, diff gitctx.DiffResult, cfg CodebaseConfig) (*Report, error) {
 	maxPerFile := cfg.MaxFindingsPerFile
 	return reviewPipeline(ctx, diff, cfg.Config, reviewOpts{
-		alwaysChunk: true,
+		alwaysChunk:      true,
+		maxFilesPerChunk: cfg.MaxFilesPerChunk,
+		transform:        cfg.Transform,
+		onFinding:        cfg.OnFinding,
 		builder: func(chunkDiff string, files []string, c config.Config, r *Rules) (string, string) {
-			return CodebaseSystemPrompt(), BuildCodebaseUserPrompt(chunkDiff, files, c.MaxFindings, maxPerFile, c.FailOn, r)
+			return CodebaseSystemPromptWithCategories(c.ExtraCategories), BuildCodebaseUserPrompt(chunkDiff, files, c.MaxFindings, maxPerFile, c.FailOn, r)
 		},
 	})
 }
@@ -280,15 +484,17 @@ func BuildReport(diff gitctx.DiffResult, findings []Finding, llmMs, totalMs int6
 		Version: "1.0",
 		RunID:   GenerateRunID(),
 		Repo: RepoInfo{
-			Root:   diff.Repo.Root,
-			Head:   diff.Repo.Head,
-			Branch: diff.Repo.Branch,
+			Root:     diff.Repo.Root,
+			Head:     diff.Repo.Head,
+			Branch:   diff.Repo.Branch,
+			Detached: diff.Repo.Detached,
 		},
 		Inputs: InputInfo{
 			Mode:  diff.Mode,
 			Range: diff.Range,
 		},
 		Summary:  ComputeSummary(findings),
+		Stats:    ComputeDiffStats(diff.Diff),
 		Findings: findings,
 		Timing: Timing{
 			LLMMs:   llmMs,
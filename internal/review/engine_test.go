@@ -1,10 +1,20 @@
 package review
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/dshills/prism/internal/cache"
+	"github.com/dshills/prism/internal/config"
 	"github.com/dshills/prism/internal/gitctx"
+	"github.com/dshills/prism/internal/providers"
 )
 
 func TestParseFindings_ValidJSON(t *testing.T) {
@@ -128,6 +138,26 @@ func TestGenerateFindingID_Different(t *testing.T) {
 	}
 }
 
+func TestParseFindings_DedupTitleVariants(t *testing.T) {
+	content := `[
+		{"severity":"high","category":"bug","title":"Null pointer dereference","path":"main.go","startLine":10},
+		{"severity":"high","category":"bug","title":"Null pointer  dereference","path":"main.go","startLine":10},
+		{"severity":"high","category":"bug","title":"null pointer dereference.","path":"main.go","startLine":10},
+		{"severity":"high","category":"bug","title":" Null Pointer Dereference!","path":"main.go","startLine":10}
+	]`
+	findings, err := parseFindings(content)
+	if err != nil {
+		t.Fatalf("parseFindings error: %v", err)
+	}
+	deduped := DeduplicateFindings(findings)
+	if len(deduped) != 1 {
+		t.Fatalf("got %d findings after dedup, want 1: %+v", len(deduped), deduped)
+	}
+	if deduped[0].Title != "Null pointer dereference" {
+		t.Errorf("display title = %q, want the original first title", deduped[0].Title)
+	}
+}
+
 func TestGenerateFindingID_NoLocations(t *testing.T) {
 	f := Finding{Title: "No location finding"}
 	id := generateFindingID(f)
@@ -139,6 +169,58 @@ func TestGenerateFindingID_NoLocations(t *testing.T) {
 	}
 }
 
+func TestGenerateStableKey_SurvivesLineShift(t *testing.T) {
+	before := Finding{
+		Title:    "Unchecked error from Close",
+		Category: CategoryBug,
+		Locations: []Location{
+			{Path: "main.go", Lines: LineRange{Start: 10, End: 12}},
+		},
+	}
+	after := before
+	after.Title = "unchecked  error from close."
+	after.Locations = []Location{
+		{Path: "main.go", Lines: LineRange{Start: 42, End: 44}},
+	}
+
+	if generateFindingID(before) == generateFindingID(after) {
+		t.Error("IDs should differ when the line shifts")
+	}
+	if generateStableKey(before) != generateStableKey(after) {
+		t.Error("StableKey should survive a line shift and cosmetic title changes")
+	}
+}
+
+func TestGenerateStableKey_Different(t *testing.T) {
+	base := Finding{
+		Title:     "Unchecked error",
+		Category:  CategoryBug,
+		Locations: []Location{{Path: "main.go", Lines: LineRange{Start: 10}}},
+	}
+	otherPath := base
+	otherPath.Locations = []Location{{Path: "util.go", Lines: LineRange{Start: 10}}}
+	otherCategory := base
+	otherCategory.Category = CategoryStyle
+
+	key := generateStableKey(base)
+	if key == generateStableKey(otherPath) {
+		t.Error("StableKey should differ by path")
+	}
+	if key == generateStableKey(otherCategory) {
+		t.Error("StableKey should differ by category")
+	}
+}
+
+func TestParseFindings_SetsStableKey(t *testing.T) {
+	findings, err := parseFindings(`[{"severity":"high","category":"bug","title":"T","message":"M","path":"a.go","startLine":3,"endLine":3,"confidence":0.9}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].StableKey == "" {
+		t.Fatalf("expected StableKey to be set, got %+v", findings)
+	}
+}
+
 func TestParseFindings_EmptyCodeFence(t *testing.T) {
 	input := "```\n```"
 	findings, err := parseFindings(input)
@@ -286,3 +368,360 @@ func TestEmptyReport(t *testing.T) {
 		t.Errorf("Findings = %d, want 0", len(r.Findings))
 	}
 }
+
+func TestRun_RecordsRetries(t *testing.T) {
+	t.Setenv("PRISM_RETRY_MAX_DELAY", "1ms")
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"content": "[]"}}},
+		})
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	cfg := config.Default()
+	cfg.Provider = "ollama"
+	cfg.Model = "llama3"
+	cfg.Cache.Enabled = false
+
+	diff := gitctx.DiffResult{
+		Diff:  "diff --git a/x.go b/x.go\n--- a/x.go\n+++ b/x.go\n@@ -0,0 +1 @@\n+package x\n",
+		Files: []string{"x.go"},
+	}
+	report, err := Run(context.Background(), diff, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Timing.Retries != 2 {
+		t.Errorf("Timing.Retries = %d, want 2", report.Timing.Retries)
+	}
+}
+
+func TestRun_RefreshCache(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		content := `[{"severity":"low","category":"style","title":"Fresh","message":"m","path":"x.go","startLine":1,"endLine":1}]`
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"content": content}}},
+		})
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	cfg := config.Default()
+	cfg.Provider = "ollama"
+	cfg.Model = "llama3"
+	cfg.Privacy.RedactSecrets = false
+	cfg.Cache.Dir = t.TempDir()
+	diff := gitctx.DiffResult{
+		Diff:  "diff --git a/x.go b/x.go\n--- a/x.go\n+++ b/x.go\n@@ -0,0 +1 @@\n+package x\n",
+		Files: []string{"x.go"},
+	}
+
+	// Seed the cache with a stale result for this diff
+	c, err := cache.New(true, cfg.Cache.Dir, cfg.Cache.TTLSeconds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := promptCacheKey(diff.Diff, diff.Files, cfg, nil, defaultPromptBuilder)
+	if err := c.Put(key, `[{"severity":"high","category":"bug","title":"Stale","message":"m","path":"x.go","startLine":1,"endLine":1}]`); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Cache.Refresh = true
+	report, err := Run(context.Background(), diff, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("provider calls = %d, want 1 (cache read skipped)", calls)
+	}
+	if len(report.Findings) != 1 || report.Findings[0].Title != "Fresh" {
+		t.Errorf("expected the fresh finding, got %+v", report.Findings)
+	}
+
+	cached, ok := c.Get(key)
+	if !ok || !strings.Contains(cached, "Fresh") {
+		t.Errorf("cache should hold the fresh result, got %q", cached)
+	}
+}
+
+func TestRun_GuideChangeMissesCache(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"content": "[]"}}},
+		})
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	cfg := config.Default()
+	cfg.Provider = "ollama"
+	cfg.Model = "llama3"
+	cfg.Cache.Dir = t.TempDir()
+	cfg.GuideFile = filepath.Join(t.TempDir(), "STYLE.md")
+	diff := gitctx.DiffResult{
+		Diff:  "diff --git a/x.go b/x.go\n--- a/x.go\n+++ b/x.go\n@@ -0,0 +1 @@\n+package x\n",
+		Files: []string{"x.go"},
+	}
+
+	for i, guide := range []string{"Prefer early returns.", "Prefer early returns.", "Wrap every error."} {
+		os.WriteFile(cfg.GuideFile, []byte(guide), 0o644)
+		if _, err := Run(context.Background(), diff, cfg); err != nil {
+			t.Fatalf("run %d: %v", i, err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("provider calls = %d, want 2 (the unchanged guide hits the cache, the edited one misses)", calls)
+	}
+}
+
+func TestRun_PromptTemplateChangeMissesCache(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"content": "[]"}}},
+		})
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	cfg := config.Default()
+	cfg.Provider = "ollama"
+	cfg.Model = "llama3"
+	cfg.Cache.Dir = t.TempDir()
+	cfg.PromptTemplateFile = filepath.Join(t.TempDir(), "prompt.tmpl")
+	diff := gitctx.DiffResult{
+		Diff:  "diff --git a/x.go b/x.go\n--- a/x.go\n+++ b/x.go\n@@ -0,0 +1 @@\n+package x\n",
+		Files: []string{"x.go"},
+	}
+
+	for i, text := range []string{
+		`{{define "system"}}{{.DefaultSystem}} Be terse.{{end}}`,
+		`{{define "system"}}{{.DefaultSystem}} Be terse.{{end}}`,
+		`{{define "system"}}{{.DefaultSystem}} Explain each finding.{{end}}`,
+	} {
+		os.WriteFile(cfg.PromptTemplateFile, []byte(text), 0o644)
+		if _, err := Run(context.Background(), diff, cfg); err != nil {
+			t.Fatalf("run %d: %v", i, err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("provider calls = %d, want 2 (the unchanged template hits the cache, the edited one misses)", calls)
+	}
+
+	// A template that fails on the review's data stops the run before the provider call
+	os.WriteFile(cfg.PromptTemplateFile, []byte(`{{define "user"}}{{if .Files}}{{index .Files 5}}{{end}}{{end}}`), 0o644)
+	if _, err := Run(context.Background(), diff, cfg); err == nil || !strings.Contains(err.Error(), "prompt template") {
+		t.Errorf("err = %v, want a prompt template error", err)
+	}
+	if calls != 2 {
+		t.Errorf("provider calls = %d, a failing template should not reach the provider", calls)
+	}
+}
+
+func TestRun_MinDiffBytesSkipsReview(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"content": "[]"}}},
+		})
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	cfg := config.Default()
+	cfg.Provider = "ollama"
+	cfg.Model = "llama3"
+	cfg.Cache.Enabled = false
+	diff := gitctx.DiffResult{
+		Diff:  "diff --git a/VERSION b/VERSION\n--- a/VERSION\n+++ b/VERSION\n@@ -1 +1 @@\n-1.2.3\n+1.2.4\n",
+		Files: []string{"VERSION"},
+	}
+
+	cfg.MinDiffBytes = len(diff.Diff) + 1
+	report, err := Run(context.Background(), diff, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Errorf("provider calls = %d, want 0 below --min-diff-bytes", calls)
+	}
+	if len(report.Findings) != 0 {
+		t.Errorf("expected an empty report, got %+v", report.Findings)
+	}
+	if !strings.Contains(report.Skipped, "below --min-diff-bytes") {
+		t.Errorf("Skipped = %q, want the --min-diff-bytes reason", report.Skipped)
+	}
+
+	cfg.MinDiffBytes = len(diff.Diff)
+	report, err = Run(context.Background(), diff, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Skipped != "" {
+		t.Errorf("Skipped = %q for a reviewed diff", report.Skipped)
+	}
+	if calls != 1 {
+		t.Errorf("provider calls = %d, want 1 at the threshold", calls)
+	}
+}
+
+func TestRunWithOptions_Transform(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content := `[{"severity":"low","category":"style","title":"Naming","message":"m","path":"x.go","startLine":1,"endLine":1},` +
+			`{"severity":"high","category":"bug","title":"Nil deref","message":"m","path":"x.go","startLine":2,"endLine":2},` +
+			`{"severity":"medium","category":"style","title":"Long line","message":"m","path":"x.go","startLine":3,"endLine":3}]`
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"content": content}}},
+		})
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	cfg := config.Default()
+	cfg.Provider = "ollama"
+	cfg.Model = "llama3"
+	cfg.Cache.Enabled = false
+	cfg.MaxFindings = 1
+	diff := gitctx.DiffResult{
+		Diff:  "diff --git a/x.go b/x.go\n--- a/x.go\n+++ b/x.go\n@@ -0,0 +1,3 @@\n+package x\n+var a int\n+var b int\n",
+		Files: []string{"x.go"},
+	}
+
+	var seen int
+	dropStyle := func(findings []Finding) []Finding {
+		seen = len(findings)
+		var kept []Finding
+		for _, f := range findings {
+			if f.Category != CategoryStyle {
+				kept = append(kept, f)
+			}
+		}
+		return kept
+	}
+	report, err := RunWithOptions(context.Background(), diff, cfg, Options{Transform: dropStyle})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seen != 3 {
+		t.Errorf("transformer saw %d findings, want all 3 before the MaxFindings cap", seen)
+	}
+	if len(report.Findings) != 1 || report.Findings[0].Category != CategoryBug {
+		t.Errorf("expected only the bug finding, got %+v", report.Findings)
+	}
+	if report.Summary.Counts.Medium != 0 || report.Summary.Counts.Low != 0 {
+		t.Errorf("summary should count only kept findings, got %+v", report.Summary.Counts)
+	}
+}
+
+func TestRun_ExtraCategories(t *testing.T) {
+	var systemPrompt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		for _, m := range req.Messages {
+			if m.Role == "system" {
+				systemPrompt = m.Content
+			}
+		}
+		content := `[
+			{"severity":"medium","category":"A11y","title":"Missing alt text","message":"m","path":"x.html","startLine":1,"endLine":1},
+			{"severity":"low","category":"logic","title":"Odd branch","message":"m","path":"x.html","startLine":2,"endLine":2}
+		]`
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"content": content}}},
+		})
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	cfg := config.Default()
+	cfg.Provider = "ollama"
+	cfg.Model = "llama3"
+	cfg.Cache.Enabled = false
+	cfg.ExtraCategories = []string{"a11y", "i18n"}
+
+	diff := gitctx.DiffResult{
+		Diff:  "diff --git a/x.html b/x.html\n--- a/x.html\n+++ b/x.html\n@@ -0,0 +1,2 @@\n+<img src=a.png>\n+<p>hi</p>\n",
+		Files: []string{"x.html"},
+	}
+	report, err := Run(context.Background(), diff, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(systemPrompt, "testing, docs, a11y, i18n") || !strings.Contains(systemPrompt, "docs|a11y|i18n") {
+		t.Errorf("system prompt should list the extra categories, got:\n%s", systemPrompt)
+	}
+	got := map[string]Category{}
+	for _, f := range report.Findings {
+		got[f.Title] = f.Category
+	}
+	if got["Missing alt text"] != "a11y" {
+		t.Errorf("custom category = %q, want a11y", got["Missing alt text"])
+	}
+	if got["Odd branch"] != CategoryMaintainability {
+		t.Errorf("unknown category = %q, want maintainability", got["Odd branch"])
+	}
+	if SystemPromptWithCategories(nil) != SystemPrompt() {
+		t.Error("prompt without extras should be unchanged")
+	}
+}
+
+func TestPromptCacheKey_ExtraCategories(t *testing.T) {
+	cfg := config.Default()
+	diff := "diff --git a/x.go b/x.go\n+package x\n"
+	base := promptCacheKey(diff, []string{"x.go"}, cfg, nil, defaultPromptBuilder)
+	cfg.ExtraCategories = []string{"accessibility"}
+	if promptCacheKey(diff, []string{"x.go"}, cfg, nil, defaultPromptBuilder) == base {
+		t.Error("adding an extra category should change the cache key")
+	}
+}
+
+func TestRun_RecordsUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices":[{"message":{"content":"[]"}}],"usage":{"prompt_tokens":100,"completion_tokens":20,"total_tokens":120}}`))
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	cfg := config.Default()
+	cfg.Provider = "ollama"
+	cfg.Model = "llama3"
+	cfg.Cache.Enabled = false
+	diff := gitctx.DiffResult{
+		Diff:  "diff --git a/x.go b/x.go\n--- a/x.go\n+++ b/x.go\n@@ -0,0 +1 @@\n+package x\n",
+		Files: []string{"x.go"},
+	}
+
+	session := providers.NewUsageTracker(0)
+	ctx := providers.WithUsageTracker(context.Background(), session)
+	report, err := Run(ctx, diff, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := report.Timing.Usage
+	if u == nil || u.Calls != 1 || u.InputTokens != 100 || u.OutputTokens != 20 || u.TotalTokens != 120 || u.CostUSD != 0 {
+		t.Errorf("Timing.Usage = %+v, want one free 120-token call", u)
+	}
+	if session.Usage().Calls != 1 {
+		t.Errorf("caller's tracker should also see the call, got %+v", session.Usage())
+	}
+}
@@ -0,0 +1,169 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dshills/prism/internal/config"
+	"github.com/dshills/prism/internal/providers"
+	"github.com/dshills/prism/internal/redact"
+)
+
+// EscalateOptions selects which findings are sent for a second opinion.
+type EscalateOptions struct {
+	// MinSeverity escalates findings at or above this severity. Empty means high.
+	MinSeverity Severity
+	// BelowConfidence also escalates findings with confidence below this
+	// value, regardless of severity. Zero disables the confidence check.
+	BelowConfidence float64
+}
+
+// EscalateResult holds the outcome of an escalation pass.
+type EscalateResult struct {
+	Findings  []Finding // findings after confirmed votes are applied and denied ones dropped
+	Escalated int
+	Confirmed int
+	Denied    int
+	LLMMs     int64
+}
+
+// escalationVote is the JSON structure returned by the escalation model.
+type escalationVote struct {
+	ID         string  `json:"id"`
+	Verdict    string  `json:"verdict"`
+	Confidence float64 `json:"confidence"`
+}
+
+const escalationSystemPrompt = `You are a senior code reviewer asked for a second opinion on findings
+produced by another reviewer. For each finding, decide whether it is a real
+issue in the provided diff.
+
+Respond with ONLY a JSON array, one object per finding:
+[{"id": "<finding id>", "verdict": "confirm" | "deny", "confidence": 0.0-1.0}]
+
+Deny findings that are incorrect, speculative, or not supported by the code.
+Do not add new findings.`
+
+// Escalate re-submits high-severity or low-confidence findings to a second
+// model given as "provider:model". Confirmed findings take the escalation
+// model's confidence; denied findings are dropped. Other findings pass
+// through unchanged.
+func Escalate(ctx context.Context, findings []Finding, diff, spec string, cfg config.Config, opts EscalateOptions) (*EscalateResult, error) {
+	providerName, modelName, err := parseModelSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := providers.New(providerName, modelName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", spec, err)
+	}
+	if cfg.Privacy.RedactSecrets {
+		diff = redact.Secrets(diff)
+	}
+	return escalateWith(ctx, provider, findings, diff, opts)
+}
+
+func escalateWith(ctx context.Context, provider providers.Reviewer, findings []Finding, diff string, opts EscalateOptions) (*EscalateResult, error) {
+	result := &EscalateResult{Findings: findings}
+
+	var candidates []Finding
+	for _, f := range findings {
+		if needsEscalation(f, opts) {
+			candidates = append(candidates, f)
+		}
+	}
+	if len(candidates) == 0 {
+		return result, nil
+	}
+	result.Escalated = len(candidates)
+
+	llmStart := time.Now()
+	resp, err := provider.Review(ctx, providers.ReviewRequest{
+		SystemPrompt: escalationSystemPrompt,
+		UserPrompt:   buildEscalationPrompt(candidates, diff),
+		MaxTokens:    4096,
+	})
+	result.LLMMs = time.Since(llmStart).Milliseconds()
+	if err != nil {
+		return nil, fmt.Errorf("escalation review: %w", err)
+	}
+
+	var votes []escalationVote
+	if err := json.Unmarshal([]byte(stripCodeFence(resp.Content)), &votes); err != nil {
+		return nil, fmt.Errorf("escalation response: invalid JSON array: %w", err)
+	}
+	byID := make(map[string]escalationVote, len(votes))
+	for _, v := range votes {
+		byID[v.ID] = v
+	}
+
+	kept := []Finding{}
+	for _, f := range findings {
+		v, ok := byID[f.ID]
+		if !ok || !needsEscalation(f, opts) {
+			kept = append(kept, f)
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(v.Verdict)) {
+		case "deny":
+			result.Denied++
+			continue
+		case "confirm":
+			result.Confirmed++
+			if v.Confidence > 0 {
+				f.Confidence = v.Confidence
+			}
+		}
+		kept = append(kept, f)
+	}
+	result.Findings = kept
+	return result, nil
+}
+
+func needsEscalation(f Finding, opts EscalateOptions) bool {
+	minSev := opts.MinSeverity
+	if minSev == "" {
+		minSev = SeverityHigh
+	}
+	if SeverityRank(f.Severity) >= SeverityRank(minSev) {
+		return true
+	}
+	return opts.BelowConfidence > 0 && f.Confidence < opts.BelowConfidence
+}
+
+// buildEscalationPrompt lists the findings under review and the diff
+// sections for the files they reference.
+func buildEscalationPrompt(findings []Finding, diff string) string {
+	paths := make(map[string]bool)
+	for _, f := range findings {
+		for _, loc := range f.Locations {
+			paths[loc.Path] = true
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("Findings to verify:\n\n")
+	for _, f := range findings {
+		fmt.Fprintf(&b, "- id: %s\n  severity: %s\n  category: %s\n  title: %s\n  message: %s\n",
+			f.ID, f.Severity, f.Category, f.Title, f.Message)
+		if len(f.Locations) > 0 {
+			loc := f.Locations[0]
+			fmt.Fprintf(&b, "  location: %s:%d-%d\n", loc.Path, loc.Lines.Start, loc.Lines.End)
+		}
+	}
+
+	b.WriteString("\nRelevant diff:\n\n```diff\n")
+	for _, sec := range splitSections(diff) {
+		if paths[pathFromSection(sec)] {
+			b.WriteString(sec)
+			if !strings.HasSuffix(sec, "\n") {
+				b.WriteString("\n")
+			}
+		}
+	}
+	b.WriteString("```\n")
+	return b.String()
+}
@@ -0,0 +1,111 @@
+package review
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func escalationFindings() []Finding {
+	return []Finding{
+		{ID: "h1", Severity: SeverityHigh, Category: CategoryBug, Title: "Nil deref", Confidence: 0.6,
+			Locations: []Location{{Path: "a.go", Lines: LineRange{Start: 3, End: 3}}}},
+		{ID: "h2", Severity: SeverityHigh, Category: CategorySecurity, Title: "SQL injection", Confidence: 0.7,
+			Locations: []Location{{Path: "b.go", Lines: LineRange{Start: 8, End: 9}}}},
+		{ID: "l1", Severity: SeverityLow, Category: CategoryStyle, Title: "Naming", Confidence: 0.9,
+			Locations: []Location{{Path: "a.go", Lines: LineRange{Start: 1, End: 1}}}},
+	}
+}
+
+func TestEscalate_ConfirmAndDeny(t *testing.T) {
+	mock := &mockReviewer{responses: []string{
+		"```json\n" + `[{"id":"h1","verdict":"confirm","confidence":0.95},{"id":"h2","verdict":"deny","confidence":0.8}]` + "\n```",
+	}}
+
+	res, err := escalateWith(context.Background(), mock, escalationFindings(), "", EscalateOptions{})
+	if err != nil {
+		t.Fatalf("escalateWith error: %v", err)
+	}
+	if res.Escalated != 2 || res.Confirmed != 1 || res.Denied != 1 {
+		t.Errorf("escalated/confirmed/denied = %d/%d/%d, want 2/1/1", res.Escalated, res.Confirmed, res.Denied)
+	}
+	if len(res.Findings) != 2 {
+		t.Fatalf("got %d findings, want 2", len(res.Findings))
+	}
+	if res.Findings[0].ID != "h1" || res.Findings[0].Confidence != 0.95 {
+		t.Errorf("confirmed finding = %+v, want h1 with confidence 0.95", res.Findings[0])
+	}
+	if res.Findings[1].ID != "l1" {
+		t.Errorf("low-severity finding should pass through, got %s", res.Findings[1].ID)
+	}
+}
+
+func TestEscalate_LowConfidence(t *testing.T) {
+	findings := []Finding{
+		{ID: "m1", Severity: SeverityMedium, Confidence: 0.3},
+		{ID: "m2", Severity: SeverityMedium, Confidence: 0.9},
+	}
+	mock := &mockReviewer{responses: []string{`[{"id":"m1","verdict":"deny"}]`}}
+
+	res, err := escalateWith(context.Background(), mock, findings, "", EscalateOptions{BelowConfidence: 0.5})
+	if err != nil {
+		t.Fatalf("escalateWith error: %v", err)
+	}
+	if res.Escalated != 1 {
+		t.Errorf("Escalated = %d, want 1", res.Escalated)
+	}
+	if len(res.Findings) != 1 || res.Findings[0].ID != "m2" {
+		t.Errorf("expected only m2 to remain, got %+v", res.Findings)
+	}
+}
+
+func TestEscalate_AllDenied(t *testing.T) {
+	findings := []Finding{{ID: "h1", Severity: SeverityHigh, Confidence: 0.6}}
+	mock := &mockReviewer{responses: []string{`[{"id":"h1","verdict":"deny"}]`}}
+
+	res, err := escalateWith(context.Background(), mock, findings, "", EscalateOptions{})
+	if err != nil {
+		t.Fatalf("escalateWith error: %v", err)
+	}
+	if res.Findings == nil || len(res.Findings) != 0 {
+		t.Errorf("Findings = %#v, want an empty slice", res.Findings)
+	}
+}
+
+func TestEscalate_NothingToEscalate(t *testing.T) {
+	mock := &mockReviewer{}
+	findings := []Finding{{ID: "l1", Severity: SeverityLow, Confidence: 0.9}}
+
+	res, err := escalateWith(context.Background(), mock, findings, "", EscalateOptions{})
+	if err != nil {
+		t.Fatalf("escalateWith error: %v", err)
+	}
+	if mock.callCount != 0 {
+		t.Errorf("provider should not be called, got %d calls", mock.callCount)
+	}
+	if len(res.Findings) != 1 {
+		t.Errorf("findings should be unchanged, got %+v", res.Findings)
+	}
+}
+
+func TestEscalate_InvalidResponse(t *testing.T) {
+	mock := &mockReviewer{responses: []string{"I agree with everything"}}
+	if _, err := escalateWith(context.Background(), mock, escalationFindings(), "", EscalateOptions{}); err == nil {
+		t.Error("expected error for invalid escalation response")
+	}
+}
+
+func TestBuildEscalationPrompt_OnlyRelevantFiles(t *testing.T) {
+	diff := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n+x := 1\n" +
+		"diff --git a/c.go b/c.go\n--- a/c.go\n+++ b/c.go\n@@ -1 +1 @@\n+y := 2\n"
+	prompt := buildEscalationPrompt(escalationFindings()[:1], diff)
+	if !strings.Contains(prompt, "id: h1") {
+		t.Error("prompt should list the finding ID")
+	}
+	if !strings.Contains(prompt, "+++ b/a.go") {
+		t.Error("prompt should include the referenced file's diff")
+	}
+	if strings.Contains(prompt, "c.go") {
+		t.Error("prompt should not include unrelated files")
+	}
+}
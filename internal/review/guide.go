@@ -0,0 +1,79 @@
+package review
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dshills/prism/internal/config"
+)
+
+// maxGuideBytes caps the style guide text injected into the system prompt,
+// so a long guide plus a full chunk stays within the prompt size budget.
+const maxGuideBytes = ChunkThreshold / 5
+
+// LoadGuide reads the style guide at path, truncated to the guide budget.
+// When the guide is truncated it also returns a warning for the caller to
+// show. An empty path returns an empty guide.
+func LoadGuide(path string) (guide, warning string, err error) {
+	if path == "" {
+		return "", "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("reading style guide: %w", err)
+	}
+	guide, truncated := truncateGuide(string(data), maxGuideBytes)
+	if truncated {
+		warning = fmt.Sprintf("style guide %s truncated to %d of %d bytes", path, len(guide), len(data))
+	}
+	return guide, warning, nil
+}
+
+// truncateGuide cuts guide to at most maxBytes, at the last line break
+// within the budget when there is one, and never inside a UTF-8 sequence.
+func truncateGuide(guide string, maxBytes int) (string, bool) {
+	guide = strings.TrimSpace(guide)
+	if len(guide) <= maxBytes {
+		return guide, false
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(guide[cut]) {
+		cut--
+	}
+	guide = guide[:cut]
+	if i := strings.LastIndexByte(guide, '\n'); i > 0 {
+		guide = guide[:i]
+	}
+	return strings.TrimSpace(guide), true
+}
+
+// GuidePromptSection returns the system prompt section that presents guide
+// as authoritative standards, or "" for an empty guide.
+func GuidePromptSection(guide string) string {
+	if guide == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\nStyle guide:\n")
+	b.WriteString("The project's style guide below is authoritative. Flag code that deviates from it, ")
+	b.WriteString("citing the guideline it breaks, even when the deviation is purely stylistic.\n")
+	b.WriteString("--- BEGIN STYLE GUIDE ---\n")
+	b.WriteString(guide)
+	b.WriteString("\n--- END STYLE GUIDE ---")
+	return b.String()
+}
+
+// withGuide wraps builder so the system prompt ends with the guide section.
+// Without a guide builder is returned unchanged.
+func withGuide(builder PromptBuilder, guide string) PromptBuilder {
+	section := GuidePromptSection(guide)
+	if section == "" {
+		return builder
+	}
+	return func(chunkDiff string, files []string, cfg config.Config, rules *Rules) (string, string) {
+		sysPr, userPr := builder(chunkDiff, files, cfg, rules)
+		return sysPr + section, userPr
+	}
+}
@@ -0,0 +1,73 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/prism/internal/config"
+)
+
+func TestLoadGuide_InPrompt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "STYLE.md")
+	if err := os.WriteFile(path, []byte("# Style\n\nExported functions must have doc comments.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	guide, warning, err := LoadGuide(path)
+	if err != nil || warning != "" {
+		t.Fatalf("LoadGuide = %q, %v", warning, err)
+	}
+
+	builder := withGuide(defaultPromptBuilder, guide)
+	sysPr, userPr := builder("diff", []string{"a.go"}, config.Default(), nil)
+	if !strings.Contains(sysPr, "Exported functions must have doc comments.") {
+		t.Errorf("system prompt missing guide text:\n%s", sysPr)
+	}
+	if !strings.HasPrefix(sysPr, SystemPrompt()) {
+		t.Error("guide should be appended to the standard system prompt")
+	}
+	if strings.Contains(userPr, "STYLE GUIDE") {
+		t.Error("guide should not be in the user prompt")
+	}
+}
+
+func TestLoadGuide_Empty(t *testing.T) {
+	guide, _, err := LoadGuide("")
+	if err != nil || guide != "" {
+		t.Fatalf("LoadGuide(\"\") = %q, %v", guide, err)
+	}
+	sysPr, _ := withGuide(defaultPromptBuilder, guide)("diff", nil, config.Default(), nil)
+	if sysPr != SystemPrompt() {
+		t.Error("without a guide the system prompt must be unchanged")
+	}
+	if _, _, err := LoadGuide(filepath.Join(t.TempDir(), "missing.md")); err == nil {
+		t.Error("expected error for a missing guide file")
+	}
+}
+
+func TestLoadGuide_TruncatedWarning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "STYLE.md")
+	os.WriteFile(path, []byte(strings.Repeat("- keep lines short\n", maxGuideBytes/10)), 0o644)
+	guide, warning, err := LoadGuide(path)
+	if err != nil {
+		t.Fatalf("LoadGuide error: %v", err)
+	}
+	if len(guide) > maxGuideBytes || !strings.Contains(warning, "truncated") {
+		t.Errorf("guide is %d bytes, warning %q; want it truncated with a warning", len(guide), warning)
+	}
+}
+
+func TestTruncateGuide(t *testing.T) {
+	guide := strings.Repeat("- keep lines short\n", 10)
+	got, truncated := truncateGuide(guide, 50)
+	if !truncated {
+		t.Fatal("expected truncation")
+	}
+	if len(got) > 50 || strings.HasSuffix(got, "- keep") {
+		t.Errorf("truncated guide = %q, want whole lines within 50 bytes", got)
+	}
+	if _, truncated := truncateGuide("short", 50); truncated {
+		t.Error("short guide should not be truncated")
+	}
+}
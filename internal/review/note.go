@@ -0,0 +1,60 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dshills/prism/internal/config"
+	"github.com/dshills/prism/internal/providers"
+	"github.com/dshills/prism/internal/redact"
+)
+
+const reviewNoteSystemPrompt = `You are a senior code reviewer. A review of the provided diff found no
+issues. Write a brief note for the author explaining what you checked and
+why the change looks correct.
+
+Respond with ONE plain-text paragraph of at most five sentences. Mention the
+files or areas covered and the kinds of problems you looked for. Do not use
+markdown, lists, or code blocks, and do not report new issues.`
+
+// maxNoteDiffBytes caps the diff sent with a review note request; the note
+// only needs enough context to describe what was covered.
+const maxNoteDiffBytes = 100000
+
+// WriteReviewNote asks the configured model for a short "what was reviewed
+// and why it looks OK" paragraph for a review with no findings. It returns
+// the note and the time spent in the provider call.
+func WriteReviewNote(ctx context.Context, diff string, cfg config.Config) (string, int64, error) {
+	provider, err := providers.New(cfg.Provider, cfg.Model)
+	if err != nil {
+		return "", 0, fmt.Errorf("creating provider: %w", err)
+	}
+	if cfg.Privacy.RedactSecrets {
+		diff = redact.Secrets(diff)
+	}
+	return reviewNoteWith(ctx, provider, diff)
+}
+
+func reviewNoteWith(ctx context.Context, provider providers.Reviewer, diff string) (string, int64, error) {
+	if len(diff) > maxNoteDiffBytes {
+		diff = diff[:maxNoteDiffBytes] + "\n... (truncated)\n"
+	}
+
+	llmStart := time.Now()
+	resp, err := provider.Review(ctx, providers.ReviewRequest{
+		SystemPrompt: reviewNoteSystemPrompt,
+		UserPrompt:   "Diff reviewed with no findings:\n\n```diff\n" + diff + "\n```\n",
+		MaxTokens:    512,
+	})
+	llmMs := time.Since(llmStart).Milliseconds()
+	if err != nil {
+		return "", llmMs, fmt.Errorf("review note: %w", err)
+	}
+	note := strings.Join(strings.Fields(stripCodeFence(resp.Content)), " ")
+	if note == "" {
+		return "", llmMs, fmt.Errorf("review note: empty response")
+	}
+	return note, llmMs, nil
+}
@@ -0,0 +1,22 @@
+package review
+
+import (
+	"context"
+	"testing"
+)
+
+func TestReviewNote(t *testing.T) {
+	mock := &mockReviewer{responses: []string{"  Checked the new retry loop in a.go\nfor off-by-one and\tcancellation bugs.  "}}
+	note, _, err := reviewNoteWith(context.Background(), mock, "diff --git a/a.go b/a.go\n")
+	if err != nil {
+		t.Fatalf("reviewNoteWith error: %v", err)
+	}
+	want := "Checked the new retry loop in a.go for off-by-one and cancellation bugs."
+	if note != want {
+		t.Errorf("note = %q, want %q", note, want)
+	}
+
+	if _, _, err := reviewNoteWith(context.Background(), &mockReviewer{responses: []string{"   "}}, "diff"); err == nil {
+		t.Error("expected error for empty note")
+	}
+}
@@ -0,0 +1,128 @@
+package review
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dshills/prism/internal/gitctx"
+)
+
+// codeOwnersPaths lists where CODEOWNERS may live, in the order GitHub
+// searches them.
+var codeOwnersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// ownerRule is one CODEOWNERS line: a pattern and the owners it assigns.
+type ownerRule struct {
+	pattern string
+	owners  []string
+}
+
+// CodeOwners holds the rules parsed from a CODEOWNERS file.
+type CodeOwners struct {
+	rules []ownerRule
+}
+
+// LoadCodeOwners reads the CODEOWNERS file for the repository at root,
+// looking in .github/, the root, then docs/. Returns nil and no error when
+// the repository has no CODEOWNERS file.
+func LoadCodeOwners(root string) (*CodeOwners, error) {
+	for _, rel := range codeOwnersPaths {
+		f, err := os.Open(filepath.Join(root, rel))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("opening %s: %w", rel, err)
+		}
+		defer f.Close()
+		co, err := ParseCodeOwners(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", rel, err)
+		}
+		return co, nil
+	}
+	return nil, nil
+}
+
+// ParseCodeOwners parses CODEOWNERS content. Blank lines and # comments are
+// skipped; a pattern with no owners is kept so it can clear ownership set
+// by an earlier line.
+func ParseCodeOwners(r io.Reader) (*CodeOwners, error) {
+	co := &CodeOwners{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		co.rules = append(co.rules, ownerRule{pattern: fields[0], owners: fields[1:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return co, nil
+}
+
+// Owners returns the owners of path. As in GitHub, the last matching rule
+// wins. Returns nil when no rule matches or the matching rule has no owners.
+func (co *CodeOwners) Owners(path string) []string {
+	for i := len(co.rules) - 1; i >= 0; i-- {
+		if matchCodeOwners(co.rules[i].pattern, path) {
+			return co.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// MapOwners returns the owners of each file that has any, keyed by path.
+func (co *CodeOwners) MapOwners(files []string) map[string][]string {
+	result := make(map[string][]string)
+	for _, f := range files {
+		if owners := co.Owners(f); len(owners) > 0 {
+			result[f] = owners
+		}
+	}
+	return result
+}
+
+// matchCodeOwners reports whether a CODEOWNERS pattern matches path, using
+// gitignore rules: a pattern with a leading or inner "/" is anchored at the
+// repository root, otherwise it matches at any depth; "*" stays within a
+// path segment and "**" spans segments. A pattern naming a directory also
+// matches everything below it, except that a trailing "/*" matches direct
+// children only.
+func matchCodeOwners(pattern, path string) bool {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.Trim(pattern, "/")
+	if trimmed == "" {
+		return false
+	}
+	segs := strings.Split(trimmed, "/")
+	if !strings.HasPrefix(pattern, "/") && !strings.Contains(trimmed, "/") {
+		segs = append([]string{"**"}, segs...)
+	}
+	pathSegs := strings.Split(path, "/")
+
+	if !dirOnly && gitctx.MatchSegments(segs, pathSegs) {
+		return true
+	}
+	if strings.HasSuffix(pattern, "/*") {
+		return false
+	}
+	for n := len(pathSegs) - 1; n >= 1; n-- {
+		if gitctx.MatchSegments(segs, pathSegs[:n]) {
+			return true
+		}
+	}
+	return false
+}
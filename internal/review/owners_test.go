@@ -0,0 +1,90 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchCodeOwners(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*", "main.go", true},
+		{"*", "a/b/c.go", true},
+		{"*.js", "web/app.js", true},
+		{"*.js", "web/app.ts", false},
+		{"/build/logs/", "build/logs/out.txt", true},
+		{"/build/logs/", "src/build/logs/out.txt", false},
+		{"apps/", "apps/web/main.go", true},
+		{"apps/", "services/apps/main.go", true},
+		{"apps/", "apps", false},
+		{"docs/*", "docs/intro.md", true},
+		{"docs/*", "docs/guide/intro.md", false},
+		{"docs/*", "src/docs/intro.md", false},
+		{"/scripts", "scripts/deploy.sh", true},
+		{"/scripts", "tools/scripts/deploy.sh", false},
+		{"internal/cli", "internal/cli/review.go", true},
+		{"**/logs", "deep/tree/logs/x.log", true},
+		{"/docs/**/*.md", "docs/a/b/c.md", true},
+		{"/docs/**/*.md", "docs/c.md", true},
+		{"README.md", "sub/README.md", true},
+	}
+	for _, tt := range tests {
+		if got := matchCodeOwners(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchCodeOwners(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCodeOwners_LastMatchWins(t *testing.T) {
+	co, err := ParseCodeOwners(strings.NewReader(`# Default owners
+*            @org/everyone
+
+/internal/   @org/core   # core packages
+*.md         @docs-team
+/internal/generated/
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := co.MapOwners([]string{"main.go", "internal/cli/review.go", "internal/README.md", "internal/generated/x.go"})
+	want := map[string][]string{
+		"main.go":                {"@org/everyone"},
+		"internal/cli/review.go": {"@org/core"},
+		"internal/README.md":     {"@docs-team"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MapOwners = %v, want %v", got, want)
+	}
+}
+
+func TestLoadCodeOwners(t *testing.T) {
+	root := t.TempDir()
+	co, err := LoadCodeOwners(root)
+	if err != nil || co != nil {
+		t.Fatalf("LoadCodeOwners without a file = %v, %v; want nil, nil", co, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, ".github"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte("* @root\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"), []byte("* @github\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	co, err = LoadCodeOwners(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := co.Owners("x.go"); !reflect.DeepEqual(got, []string{"@github"}) {
+		t.Errorf("Owners = %v, want .github/CODEOWNERS to take precedence", got)
+	}
+}
@@ -0,0 +1,51 @@
+package review
+
+import "strings"
+
+// PatchHunk is one "@@" hunk of a unified diff, offered for selection when
+// reviewing interactively.
+type PatchHunk struct {
+	Path string // new-side file path
+	Text string // hunk header and body
+}
+
+// SelectHunks rebuilds diff with only the hunks keep accepts, in diff order.
+// A file's header lines are kept when at least one of its hunks is; files
+// with no accepted hunks, and sections without hunks such as binary files,
+// are dropped. Each kept hunk carries its original line numbers, so the
+// result is still a valid unified diff.
+func SelectHunks(diff string, keep func(PatchHunk) bool) string {
+	var out strings.Builder
+	for _, sec := range splitSections(diff) {
+		path := pathFromSection(sec)
+		var header, hunk strings.Builder
+		var kept []string
+		inHunk := false
+		flush := func() {
+			if inHunk && keep(PatchHunk{Path: path, Text: strings.TrimRight(hunk.String(), "\n")}) {
+				kept = append(kept, hunk.String())
+			}
+			hunk.Reset()
+		}
+		for _, line := range strings.Split(strings.TrimRight(sec, "\n"), "\n") {
+			if strings.HasPrefix(line, "@@") {
+				flush()
+				inHunk = true
+			}
+			if inHunk {
+				hunk.WriteString(line + "\n")
+			} else {
+				header.WriteString(line + "\n")
+			}
+		}
+		flush()
+		if len(kept) == 0 {
+			continue
+		}
+		out.WriteString(header.String())
+		for _, h := range kept {
+			out.WriteString(h)
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,61 @@
+package review
+
+import (
+	"strings"
+	"testing"
+)
+
+const patchDiff = `diff --git a/a.go b/a.go
+--- a/a.go
++++ b/a.go
+@@ -1,2 +1,3 @@
+ package a
++var one = 1
+@@ -10,2 +11,3 @@
+ func f() {
++	two()
+diff --git a/b.go b/b.go
+--- a/b.go
++++ b/b.go
+@@ -1 +1,2 @@
+ package b
++var three = 3
+`
+
+func TestSelectHunks(t *testing.T) {
+	var seen []string
+	got := SelectHunks(patchDiff, func(h PatchHunk) bool {
+		seen = append(seen, h.Path)
+		return strings.Contains(h.Text, "two()")
+	})
+
+	if strings.Join(seen, ",") != "a.go,a.go,b.go" {
+		t.Errorf("hunks offered = %v, want a.go, a.go, b.go", seen)
+	}
+	want := `diff --git a/a.go b/a.go
+--- a/a.go
++++ b/a.go
+@@ -10,2 +11,3 @@
+ func f() {
++	two()
+`
+	if got != want {
+		t.Errorf("SelectHunks =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSelectHunks_NoneKept(t *testing.T) {
+	got := SelectHunks(patchDiff, func(PatchHunk) bool { return false })
+	if got != "" {
+		t.Errorf("SelectHunks = %q, want empty diff", got)
+	}
+}
+
+func TestSelectHunks_SkipsSectionsWithoutHunks(t *testing.T) {
+	diff := "diff --git a/img.png b/img.png\nBinary files a/img.png and b/img.png differ\n"
+	calls := 0
+	got := SelectHunks(diff, func(PatchHunk) bool { calls++; return true })
+	if got != "" || calls != 0 {
+		t.Errorf("SelectHunks = %q after %d calls, want empty with no hunks offered", got, calls)
+	}
+}
@@ -0,0 +1,50 @@
+package review
+
+import (
+	"fmt"
+
+	"github.com/dshills/prism/internal/gitctx"
+)
+
+// RequireTestsID is the ID of the finding emitted by RequireTests.
+const RequireTestsID = "prism-require-tests"
+
+// RequireTests checks that a change touching production code also touches
+// at least one test file. Files matching any of testPatterns count as tests.
+// It returns a file-level finding listing the untested files when none of
+// the changed files is a test, or nil when the policy is satisfied or no
+// production files changed.
+func RequireTests(files, testPatterns []string) *Finding {
+	var untested []string
+	seen := make(map[string]bool, len(files))
+	for _, f := range files {
+		if gitctx.MatchesAny(f, testPatterns) {
+			return nil
+		}
+		if !seen[f] {
+			seen[f] = true
+			untested = append(untested, f)
+		}
+	}
+	if len(untested) == 0 {
+		return nil
+	}
+
+	locs := make([]Location, len(untested))
+	for i, f := range untested {
+		locs[i] = Location{Path: f}
+	}
+	f := Finding{
+		ID:         RequireTestsID,
+		Severity:   SeverityMedium,
+		Category:   CategoryTesting,
+		Title:      "Production code changed without test changes",
+		Message:    fmt.Sprintf("%d non-test file(s) changed but no test files did.", len(untested)),
+		Suggestion: "Add or update tests covering the changed code.",
+		Confidence: 1,
+		Locations:  locs,
+		Tags:       []string{"policy"},
+	}
+	f.StableKey = generateStableKey(f)
+	return &f
+}
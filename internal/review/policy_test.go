@@ -0,0 +1,42 @@
+package review
+
+import "testing"
+
+func TestRequireTests(t *testing.T) {
+	patterns := []string{"**/*_test.go", "**/test_*.py"}
+
+	tests := []struct {
+		name      string
+		files     []string
+		wantFired bool
+		wantLocs  int
+	}{
+		{"no files", nil, false, 0},
+		{"code with go test", []string{"pkg/a.go", "pkg/a_test.go"}, false, 0},
+		{"code with python test", []string{"app/views.py", "tests/test_views.py"}, false, 0},
+		{"tests only", []string{"pkg/a_test.go"}, false, 0},
+		{"code without tests", []string{"pkg/a.go", "cmd/main.go"}, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := RequireTests(tt.files, patterns)
+			if (f != nil) != tt.wantFired {
+				t.Fatalf("RequireTests() fired = %v, want %v", f != nil, tt.wantFired)
+			}
+			if f == nil {
+				return
+			}
+			if f.ID != RequireTestsID || f.Category != CategoryTesting || f.Severity != SeverityMedium {
+				t.Errorf("unexpected finding: %+v", f)
+			}
+			if len(f.Locations) != tt.wantLocs {
+				t.Errorf("got %d locations, want %d", len(f.Locations), tt.wantLocs)
+			}
+			for _, loc := range f.Locations {
+				if !loc.Lines.FileLevel() {
+					t.Errorf("location %s should be file-level", loc.Path)
+				}
+			}
+		})
+	}
+}
@@ -11,8 +11,8 @@ Rules:
 1. Only review the changes shown in the diff. Do not comment on unchanged code.
 2. Focus on bugs, security issues, performance problems, and correctness. Avoid bikeshedding on style unless it impacts readability significantly.
 3. Be concise and actionable. Every finding must include a concrete suggestion.
-4. Reference line numbers from the diff hunks.
-5. Rate severity as "low", "medium", or "high".
+4. Reference line numbers from the diff hunks. For file-level observations (architecture, module structure) that do not apply to specific lines, set "startLine" and "endLine" to 0.
+5. Rate severity as "info", "low", "medium", "high", or "critical". Reserve "critical" for issues that must be fixed before merging, such as an exploitable vulnerability or data loss. Use "info" for observations that need no change.
 6. Rate your confidence from 0.0 to 1.0.
 7. Categorize each finding as one of: bug, security, performance, correctness, style, maintainability, testing, docs.
 
@@ -20,7 +20,7 @@ You MUST respond with ONLY a JSON array of findings. No markdown, no explanation
 
 Each finding must have this exact structure:
 {
-  "severity": "low|medium|high",
+  "severity": "info|low|medium|high|critical",
   "category": "bug|security|performance|correctness|style|maintainability|testing|docs",
   "title": "Short descriptive title",
   "message": "What is wrong and why it matters",
@@ -75,13 +75,38 @@ func SystemPrompt() string {
 	return systemPrompt
 }
 
+// SystemPromptWithCategories returns the system prompt allowing the extra
+// categories alongside the built-in ones.
+func SystemPromptWithCategories(extra []string) string {
+	return withCategories(systemPrompt, extra)
+}
+
+// withCategories rewrites the category lists in a system prompt to include
+// extra categories. Without extras the prompt is returned unchanged so
+// cache keys and pinned request bodies stay stable.
+func withCategories(prompt string, extra []string) string {
+	cats := Categories(extra)
+	if len(cats) == len(builtinCategories) {
+		return prompt
+	}
+	names := make([]string, len(cats))
+	for i, c := range cats {
+		names[i] = string(c)
+	}
+	builtin := names[:len(builtinCategories)]
+	return strings.NewReplacer(
+		strings.Join(builtin, ", "), strings.Join(names, ", "),
+		strings.Join(builtin, "|"), strings.Join(names, "|"),
+	).Replace(prompt)
+}
+
 const codebaseSystemPromptText = `You are a strict, expert code reviewer. Your job is to review complete source files and produce structured findings in JSON format.
 
 Rules:
 1. Review the full source files provided. Look for bugs, security issues, performance problems, correctness issues, design flaws, and maintainability concerns.
 2. Be concise and actionable. Every finding must include a concrete suggestion.
-3. Reference line numbers from the source files.
-4. Rate severity as "low", "medium", or "high".
+3. Reference line numbers from the source files. For file-level observations (architecture, module structure) that do not apply to specific lines, set "startLine" and "endLine" to 0.
+4. Rate severity as "info", "low", "medium", "high", or "critical". Reserve "critical" for issues that must be fixed before merging, such as an exploitable vulnerability or data loss. Use "info" for observations that need no change.
 5. Rate your confidence from 0.0 to 1.0.
 6. Categorize each finding as one of: bug, security, performance, correctness, style, maintainability, testing, docs.
 
@@ -89,7 +114,7 @@ You MUST respond with ONLY a JSON array of findings. No markdown, no explanation
 
 Each finding must have this exact structure:
 {
-  "severity": "low|medium|high",
+  "severity": "info|low|medium|high|critical",
   "category": "bug|security|performance|correctness|style|maintainability|testing|docs",
   "title": "Short descriptive title",
   "message": "What is wrong and why it matters",
@@ -108,6 +133,12 @@ func CodebaseSystemPrompt() string {
 	return codebaseSystemPromptText
 }
 
+// CodebaseSystemPromptWithCategories returns the codebase system prompt
+// allowing the extra categories alongside the built-in ones.
+func CodebaseSystemPromptWithCategories(extra []string) string {
+	return withCategories(codebaseSystemPromptText, extra)
+}
+
 // BuildCodebaseUserPrompt constructs the user prompt for codebase review.
 func BuildCodebaseUserPrompt(diff string, files []string, maxFindings int, maxFindingsPerFile int, failOn string, rules *Rules) string {
 	var b strings.Builder
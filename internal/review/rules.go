@@ -3,7 +3,6 @@ package review
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 )
 
@@ -20,22 +19,124 @@ type RequiredCheck struct {
 	Text string `json:"text"`
 }
 
-// LoadRules loads a rules file from disk. Returns nil Rules and nil error if path is empty.
-func LoadRules(path string) (*Rules, error) {
+// LoadRules loads a rules pack. Returns nil Rules and nil error if path is empty.
+//
+// The path may be a local file, a built-in pack ("pack:go-security"), an
+// http(s) URL, or a git object ("git:<ref>:<path>"); see resolveRules.
+// A comma-separated list of sources is loaded in order and combined with
+// MergeRules, so later sources override earlier ones.
+// Loaded rules are checked with ValidateRules. The returned warnings
+// describe problems that did not stop the load, such as falling back to a
+// cached copy of a remote rules file, for the caller to show.
+func LoadRules(path string) (*Rules, []string, error) {
 	if path == "" {
-		return nil, nil
+		return nil, nil, nil
 	}
-	data, err := os.ReadFile(path)
+	var merged *Rules
+	var warnings []string
+	for _, source := range strings.Split(path, ",") {
+		source = strings.TrimSpace(source)
+		if source == "" {
+			continue
+		}
+		rules, warning, err := loadRulesSource(source)
+		if err != nil {
+			return nil, nil, err
+		}
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
+		merged = MergeRules(merged, rules)
+	}
+	return merged, warnings, nil
+}
+
+// loadRulesSource loads and validates a single rules source.
+func loadRulesSource(source string) (*Rules, string, error) {
+	if isRemoteRules(source) {
+		return fetchRemoteRules(source)
+	}
+	data, err := resolveRules(source)
 	if err != nil {
-		return nil, fmt.Errorf("reading rules file: %w", err)
+		return nil, "", err
 	}
+	rules, err := parseRules(source, data)
+	return rules, "", err
+}
+
+// parseRules parses and validates the rules read from source.
+func parseRules(source string, data []byte) (*Rules, error) {
 	var rules Rules
 	if err := json.Unmarshal(data, &rules); err != nil {
 		return nil, fmt.Errorf("parsing rules file: %w", err)
 	}
+	if err := ValidateRules(&rules); err != nil {
+		return nil, fmt.Errorf("invalid rules %s: %w", source, err)
+	}
 	return &rules, nil
 }
 
+// MergeRules layers overlay on top of base and returns the result without
+// modifying either. Focus areas are unioned in order, severity overrides
+// from overlay replace those for the same category, and required checks are
+// unioned by ID with overlay's text winning. Either argument may be nil.
+func MergeRules(base, overlay *Rules) *Rules {
+	if base == nil && overlay == nil {
+		return nil
+	}
+	merged := &Rules{}
+	seenFocus := make(map[string]bool)
+	requiredIdx := make(map[string]int)
+	for _, r := range []*Rules{base, overlay} {
+		if r == nil {
+			continue
+		}
+		for _, f := range r.Focus {
+			if !seenFocus[f] {
+				seenFocus[f] = true
+				merged.Focus = append(merged.Focus, f)
+			}
+		}
+		for cat, sev := range r.SeverityOverrides {
+			if merged.SeverityOverrides == nil {
+				merged.SeverityOverrides = make(map[string]string)
+			}
+			merged.SeverityOverrides[cat] = sev
+		}
+		for _, req := range r.Required {
+			if i, ok := requiredIdx[req.ID]; ok {
+				merged.Required[i] = req
+				continue
+			}
+			requiredIdx[req.ID] = len(merged.Required)
+			merged.Required = append(merged.Required, req)
+		}
+	}
+	return merged
+}
+
+// ValidateRules checks that a rules pack is well formed: severity overrides
+// name a known severity and required checks have both an ID and text.
+func ValidateRules(rules *Rules) error {
+	if rules == nil {
+		return nil
+	}
+	for cat, sev := range rules.SeverityOverrides {
+		if SeverityRank(Severity(sev)) == 0 {
+			return fmt.Errorf("severityOverrides[%s]: unknown severity %q (want info, low, medium, high, or critical)", cat, sev)
+		}
+	}
+	for i, req := range rules.Required {
+		if strings.TrimSpace(req.ID) == "" {
+			return fmt.Errorf("required[%d]: missing id", i)
+		}
+		if strings.TrimSpace(req.Text) == "" {
+			return fmt.Errorf("required[%d] (%s): missing text", i, req.ID)
+		}
+	}
+	return nil
+}
+
 // BuildRulesPromptSection returns additional prompt instructions derived from rules.
 func BuildRulesPromptSection(rules *Rules) string {
 	if rules == nil {
@@ -68,17 +169,45 @@ func BuildRulesPromptSection(rules *Rules) string {
 
 // ApplySeverityOverrides post-processes findings to enforce severity overrides from rules.
 func ApplySeverityOverrides(findings []Finding, rules *Rules) []Finding {
-	if rules == nil || len(rules.SeverityOverrides) == 0 {
+	return ApplySeverityOverridesWithFloors(findings, rules, nil)
+}
+
+// ApplySeverityOverridesWithFloors is like ApplySeverityOverrides but also
+// raises findings below the floor configured for their category (e.g.
+// "security": "medium"). Floors never lower a severity and do not apply to
+// categories that have an explicit rules override.
+func ApplySeverityOverridesWithFloors(findings []Finding, rules *Rules, floors map[string]string) []Finding {
+	var overrides map[string]string
+	if rules != nil {
+		overrides = rules.SeverityOverrides
+	}
+	if len(overrides) == 0 && len(floors) == 0 {
 		return findings
 	}
 
 	for i := range findings {
 		cat := string(findings[i].Category)
-		if override, ok := rules.SeverityOverrides[cat]; ok {
+		if override, ok := overrides[cat]; ok {
 			findings[i].Severity = Severity(override)
 			// Regenerate ID since severity change may affect dedup
 			findings[i].ID = generateFindingID(findings[i])
+			continue
+		}
+		if floor, ok := floors[cat]; ok && SeverityRank(findings[i].Severity) < SeverityRank(Severity(floor)) {
+			findings[i].Severity = Severity(floor)
+			findings[i].ID = generateFindingID(findings[i])
 		}
 	}
 	return findings
 }
+
+// ValidateSeverityFloors checks that every configured floor names a known
+// severity.
+func ValidateSeverityFloors(floors map[string]string) error {
+	for cat, sev := range floors {
+		if SeverityRank(Severity(sev)) == 0 {
+			return fmt.Errorf("severityFloors[%s]: unknown severity %q (want info, low, medium, high, or critical)", cat, sev)
+		}
+	}
+	return nil
+}
@@ -1,13 +1,19 @@
 package review
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/dshills/prism/internal/cache"
 )
 
 func TestLoadRules_Empty(t *testing.T) {
-	rules, err := LoadRules("")
+	rules, _, err := LoadRules("")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -33,7 +39,7 @@ func TestLoadRules_Valid(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	rules, err := LoadRules(path)
+	rules, _, err := LoadRules(path)
 	if err != nil {
 		t.Fatalf("LoadRules error: %v", err)
 	}
@@ -61,7 +67,7 @@ func TestLoadRules_Valid(t *testing.T) {
 }
 
 func TestLoadRules_NotFound(t *testing.T) {
-	_, err := LoadRules("/nonexistent/path/rules.json")
+	_, _, err := LoadRules("/nonexistent/path/rules.json")
 	if err == nil {
 		t.Error("expected error for nonexistent file")
 	}
@@ -73,7 +79,7 @@ func TestLoadRules_InvalidJSON(t *testing.T) {
 	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
 		t.Fatal(err)
 	}
-	_, err := LoadRules(path)
+	_, _, err := LoadRules(path)
 	if err == nil {
 		t.Error("expected error for invalid JSON")
 	}
@@ -167,6 +173,52 @@ func TestApplySeverityOverrides_EmptyOverrides(t *testing.T) {
 	}
 }
 
+func TestApplySeverityOverridesWithFloors(t *testing.T) {
+	floors := map[string]string{"security": "medium", "bug": "medium", "style": "medium"}
+	rules := &Rules{SeverityOverrides: map[string]string{"style": "low"}}
+	findings := []Finding{
+		{ID: "1", Severity: SeverityLow, Category: CategorySecurity, Title: "Weak hash"},
+		{ID: "2", Severity: SeverityHigh, Category: CategoryBug, Title: "Nil deref"},
+		{ID: "3", Severity: SeverityMedium, Category: CategoryStyle, Title: "Naming"},
+		{ID: "4", Severity: SeverityLow, Category: CategoryPerformance, Title: "Alloc in loop"},
+	}
+
+	result := ApplySeverityOverridesWithFloors(findings, rules, floors)
+
+	if result[0].Severity != SeverityMedium {
+		t.Errorf("low security finding should be raised to the medium floor, got %q", result[0].Severity)
+	}
+	if result[0].ID == "1" {
+		t.Error("ID should be regenerated when a floor raises severity")
+	}
+	if result[1].Severity != SeverityHigh {
+		t.Errorf("floors must not lower severity, got %q", result[1].Severity)
+	}
+	if result[2].Severity != SeverityLow {
+		t.Errorf("explicit override should win over the floor, got %q", result[2].Severity)
+	}
+	if result[3].Severity != SeverityLow {
+		t.Errorf("category without a floor should be unchanged, got %q", result[3].Severity)
+	}
+}
+
+func TestApplySeverityOverridesWithFloors_NoRules(t *testing.T) {
+	findings := []Finding{{Severity: SeverityLow, Category: CategorySecurity}}
+	result := ApplySeverityOverridesWithFloors(findings, nil, map[string]string{"security": "high"})
+	if result[0].Severity != SeverityHigh {
+		t.Errorf("floor should apply without rules, got %q", result[0].Severity)
+	}
+}
+
+func TestValidateSeverityFloors(t *testing.T) {
+	if err := ValidateSeverityFloors(map[string]string{"security": "medium"}); err != nil {
+		t.Errorf("valid floors: %v", err)
+	}
+	if err := ValidateSeverityFloors(map[string]string{"security": "urgent"}); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) > 0 && len(substr) > 0 && (s == substr || len(s) >= len(substr) && containsSubstring(s, substr))
 }
@@ -179,3 +231,202 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestLoadRules_BuiltinPack(t *testing.T) {
+	for _, name := range BuiltinRulesPacks() {
+		rules, _, err := LoadRules("pack:" + name)
+		if err != nil {
+			t.Errorf("LoadRules(pack:%s) error: %v", name, err)
+			continue
+		}
+		if len(rules.Required) == 0 {
+			t.Errorf("pack %s has no required checks", name)
+		}
+	}
+	if len(BuiltinRulesPacks()) == 0 {
+		t.Error("expected at least one built-in pack")
+	}
+}
+
+func TestLoadRules_UnknownPack(t *testing.T) {
+	_, _, err := LoadRules("pack:does-not-exist")
+	if err == nil {
+		t.Fatal("expected error for unknown pack")
+	}
+	if !strings.Contains(err.Error(), "go-security") {
+		t.Errorf("error should list available packs, got: %v", err)
+	}
+}
+
+func TestLoadRules_RemoteCached(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`{"focus": ["security"]}`))
+	}))
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		rules, _, err := LoadRules(server.URL + "/rules.json")
+		if err != nil {
+			t.Fatalf("LoadRules error: %v", err)
+		}
+		if len(rules.Focus) != 1 || rules.Focus[0] != "security" {
+			t.Errorf("Focus = %v, want [security]", rules.Focus)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("server hit %d times, want 1 (second load should use cache)", hits)
+	}
+}
+
+func TestLoadRules_RemoteInvalid(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"severityOverrides": {"style": "urgent"}}`))
+	}))
+	defer server.Close()
+
+	if _, _, err := LoadRules(server.URL); err == nil {
+		t.Error("expected validation error for unknown severity")
+	}
+}
+
+func TestLoadRules_RemoteInvalidNotCached(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	body := `{"severityOverrides": {"style": "urgent"}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	if _, _, err := LoadRules(server.URL); err == nil {
+		t.Fatal("expected validation error for unknown severity")
+	}
+	// Once the server is fixed, the invalid response must not be served
+	// from the cache.
+	body = `{"focus": ["security"]}`
+	rules, warnings, err := LoadRules(server.URL)
+	if err != nil {
+		t.Fatalf("LoadRules error: %v", err)
+	}
+	if len(rules.Focus) != 1 || len(warnings) != 0 {
+		t.Errorf("rules = %+v, warnings = %v; want the fixed rules", rules, warnings)
+	}
+}
+
+func TestLoadRules_RemoteStaleCopyWarns(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"focus": ["security"]}`))
+	}))
+	defer server.Close()
+
+	if _, _, err := LoadRules(server.URL); err != nil {
+		t.Fatalf("LoadRules error: %v", err)
+	}
+	// Age the cached copy past the TTL so the next load downloads again
+	dir, _ := cache.DefaultDir()
+	old := time.Now().Add(-2 * remoteRulesTTL)
+	os.Chtimes(filepath.Join(dir, "rules", cache.HashKey(server.URL)+".json"), old, old)
+
+	fail = true
+	rules, warnings, err := LoadRules(server.URL)
+	if err != nil {
+		t.Fatalf("LoadRules error: %v", err)
+	}
+	if len(rules.Focus) != 1 {
+		t.Errorf("Focus = %v, want the cached rules", rules.Focus)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "using cached copy") {
+		t.Errorf("warnings = %v, want a stale copy warning", warnings)
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   Rules
+		wantErr bool
+	}{
+		{"valid", Rules{SeverityOverrides: map[string]string{"style": "low"}, Required: []RequiredCheck{{ID: "a", Text: "b"}}}, false},
+		{"bad severity", Rules{SeverityOverrides: map[string]string{"style": "urgent"}}, true},
+		{"missing id", Rules{Required: []RequiredCheck{{Text: "b"}}}, true},
+		{"missing text", Rules{Required: []RequiredCheck{{ID: "a"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRules(&tt.rules)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadRules_MultipleMerged(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.json")
+	repo := filepath.Join(dir, "repo.json")
+	os.WriteFile(base, []byte(`{
+		"focus": ["security", "correctness"],
+		"severityOverrides": {"security": "high", "style": "low"},
+		"required": [{"id": "errs", "text": "Check errors"}, {"id": "logs", "text": "No secrets in logs"}]
+	}`), 0o644)
+	os.WriteFile(repo, []byte(`{
+		"focus": ["correctness", "performance"],
+		"severityOverrides": {"style": "medium"},
+		"required": [{"id": "errs", "text": "Wrap errors with context"}, {"id": "ctx", "text": "Pass context"}]
+	}`), 0o644)
+
+	rules, _, err := LoadRules(base + ", " + repo)
+	if err != nil {
+		t.Fatalf("LoadRules error: %v", err)
+	}
+
+	wantFocus := []string{"security", "correctness", "performance"}
+	if strings.Join(rules.Focus, ",") != strings.Join(wantFocus, ",") {
+		t.Errorf("Focus = %v, want %v", rules.Focus, wantFocus)
+	}
+	if rules.SeverityOverrides["security"] != "high" || rules.SeverityOverrides["style"] != "medium" {
+		t.Errorf("SeverityOverrides = %v, want security=high style=medium", rules.SeverityOverrides)
+	}
+	if len(rules.Required) != 3 {
+		t.Fatalf("Required = %v, want 3 checks", rules.Required)
+	}
+	if rules.Required[0].ID != "errs" || rules.Required[0].Text != "Wrap errors with context" {
+		t.Errorf("Required[0] = %+v, want overlay text for errs", rules.Required[0])
+	}
+	if rules.Required[2].ID != "ctx" {
+		t.Errorf("Required[2] = %+v, want ctx appended", rules.Required[2])
+	}
+}
+
+func TestLoadRules_MultipleOneMissing(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "base.json")
+	os.WriteFile(base, []byte(`{"focus": ["security"]}`), 0o644)
+	if _, _, err := LoadRules(base + ",/nonexistent/rules.json"); err == nil {
+		t.Error("expected error when any rules source is missing")
+	}
+}
+
+func TestMergeRules_DoesNotModifyInputs(t *testing.T) {
+	base := &Rules{SeverityOverrides: map[string]string{"style": "low"}}
+	overlay := &Rules{SeverityOverrides: map[string]string{"style": "high"}}
+	merged := MergeRules(base, overlay)
+	if merged.SeverityOverrides["style"] != "high" {
+		t.Errorf("merged style = %q, want high", merged.SeverityOverrides["style"])
+	}
+	if base.SeverityOverrides["style"] != "low" {
+		t.Error("MergeRules modified base")
+	}
+	if MergeRules(nil, nil) != nil {
+		t.Error("MergeRules(nil, nil) should be nil")
+	}
+}
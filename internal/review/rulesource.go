@@ -0,0 +1,158 @@
+package review
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dshills/prism/internal/cache"
+)
+
+//go:embed packs/*.json
+var builtinPacks embed.FS
+
+const (
+	// rulesPackPrefix selects a built-in rules pack, e.g. "pack:go-security".
+	rulesPackPrefix = "pack:"
+	// rulesGitPrefix selects a rules file from a git object, e.g.
+	// "git:origin/main:.prism/rules.json".
+	rulesGitPrefix = "git:"
+	// remoteRulesTTL is how long a fetched rules file is reused before
+	// it is downloaded again.
+	remoteRulesTTL = 24 * time.Hour
+)
+
+// BuiltinRulesPacks returns the names of the rules packs bundled with prism.
+func BuiltinRulesPacks() []string {
+	entries, err := builtinPacks.ReadDir("packs")
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveRules returns the raw JSON for a local rules source. Sources are
+// resolved in this order: built-in pack, git object, local file. Remote
+// sources are loaded by fetchRemoteRules instead.
+func resolveRules(source string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(source, rulesPackPrefix):
+		return loadBuiltinPack(strings.TrimPrefix(source, rulesPackPrefix))
+	case strings.HasPrefix(source, rulesGitPrefix):
+		return loadGitRules(strings.TrimPrefix(source, rulesGitPrefix))
+	default:
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("reading rules file: %w", err)
+		}
+		return data, nil
+	}
+}
+
+// isRemoteRules reports whether source is an http(s) URL.
+func isRemoteRules(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+func loadBuiltinPack(name string) ([]byte, error) {
+	if name == "" || strings.ContainsAny(name, `/\.`) {
+		return nil, fmt.Errorf("invalid rules pack name %q", name)
+	}
+	data, err := builtinPacks.ReadFile("packs/" + name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown rules pack %q (available: %s)", name, strings.Join(BuiltinRulesPacks(), ", "))
+	}
+	return data, nil
+}
+
+// fetchRemoteRules downloads a rules file and validates it, caching it
+// under the prism cache directory. Only rules that parse and validate are
+// cached. A cached copy younger than remoteRulesTTL is used without a
+// network call, and a stale copy is used, with a warning, if the download
+// fails or returns invalid rules.
+func fetchRemoteRules(url string) (*Rules, string, error) {
+	var cachePath string
+	if dir, err := cache.DefaultDir(); err == nil {
+		cachePath = filepath.Join(dir, "rules", cache.HashKey(url)+".json")
+		if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < remoteRulesTTL {
+			if rules, err := readCachedRules(url, cachePath); err == nil {
+				return rules, "", nil
+			}
+		}
+	}
+
+	data, err := downloadRules(url)
+	var rules *Rules
+	if err == nil {
+		rules, err = parseRules(url, data)
+	}
+	if err != nil {
+		if cachePath != "" {
+			if stale, rerr := readCachedRules(url, cachePath); rerr == nil {
+				return stale, fmt.Sprintf("%v; using cached copy", err), nil
+			}
+		}
+		return nil, "", err
+	}
+
+	if cachePath != "" {
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err == nil {
+			_ = os.WriteFile(cachePath, data, 0o644)
+		}
+	}
+	return rules, "", nil
+}
+
+// readCachedRules reads and validates the cached copy of the rules at url.
+func readCachedRules(url, path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseRules(url, data)
+}
+
+func downloadRules(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("fetching rules: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("reading rules response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching rules: %s returned status %d", url, resp.StatusCode)
+	}
+	return body, nil
+}
+
+// loadGitRules reads a rules file from a git object spec "<ref>:<path>".
+func loadGitRules(spec string) ([]byte, error) {
+	if !strings.Contains(spec, ":") {
+		return nil, fmt.Errorf("invalid git rules source %q: expected git:<ref>:<path>", spec)
+	}
+	out, err := exec.Command("git", "show", spec).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("git show %s: %s", spec, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git show %s: %w", spec, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,86 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/dshills/prism/internal/providers"
+)
+
+// reviewStreaming sends req to provider, passing each finding to onFinding
+// as soon as the response contains it. With a nil onFinding it is
+// provider.Review.
+func reviewStreaming(ctx context.Context, provider providers.Reviewer, req providers.ReviewRequest, onFinding func(Finding)) (providers.ReviewResponse, error) {
+	if onFinding == nil {
+		return provider.Review(ctx, req)
+	}
+	sc := &findingScanner{onFinding: onFinding}
+	return providers.ReviewStream(ctx, provider, req, sc.write)
+}
+
+// findingScanner extracts findings from a JSON array of findings that
+// arrives in pieces. It skips text before the array's opening bracket,
+// such as a code fence, and calls onFinding for each complete top-level
+// object. Objects that do not parse as findings are skipped; the full
+// response is still parsed and repaired as usual once it completes.
+type findingScanner struct {
+	onFinding func(Finding)
+
+	started  bool // the array's '[' has been seen
+	done     bool // the array's ']' has been seen
+	depth    int  // nesting depth inside the current object
+	inString bool
+	escaped  bool
+	obj      []byte
+}
+
+// write consumes the next piece of the response.
+func (s *findingScanner) write(text string) {
+	for i := 0; i < len(text) && !s.done; i++ {
+		c := text[i]
+		if !s.started {
+			s.started = c == '['
+			continue
+		}
+		if s.depth == 0 {
+			switch c {
+			case '{':
+				s.depth = 1
+				s.obj = append(s.obj[:0], c)
+			case ']':
+				s.done = true
+			}
+			continue
+		}
+
+		s.obj = append(s.obj, c)
+		switch {
+		case s.inString:
+			switch {
+			case s.escaped:
+				s.escaped = false
+			case c == '\\':
+				s.escaped = true
+			case c == '"':
+				s.inString = false
+			}
+		case c == '"':
+			s.inString = true
+		case c == '{' || c == '[':
+			s.depth++
+		case c == '}' || c == ']':
+			s.depth--
+			if s.depth == 0 {
+				s.emit()
+			}
+		}
+	}
+}
+
+func (s *findingScanner) emit() {
+	var r rawFinding
+	if err := json.Unmarshal(s.obj, &r); err != nil {
+		return
+	}
+	s.onFinding(r.finding())
+}
@@ -0,0 +1,84 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dshills/prism/internal/config"
+	"github.com/dshills/prism/internal/gitctx"
+)
+
+func TestFindingScanner(t *testing.T) {
+	var got []Finding
+	sc := &findingScanner{onFinding: func(f Finding) { got = append(got, f) }}
+
+	// A fenced response split at awkward points, with braces and escaped
+	// quotes inside strings
+	response := "```json\n[\n  {\"severity\":\"high\",\"category\":\"bug\",\"title\":\"Use of \\\"}\\\" in {tmpl}\"," +
+		"\"message\":\"m\",\"path\":\"a.go\",\"startLine\":3,\"endLine\":4,\"tags\":[\"x\"]},\n" +
+		"  {\"severity\":\"low\",\"title\":\"Second\",\"path\":\"b.go\"}\n]\n```\n{\"ignored\":true}"
+	for i := 0; i < len(response); i += 7 {
+		sc.write(response[i:min(i+7, len(response))])
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("got %d findings, want 2: %+v", len(got), got)
+	}
+	if got[0].Title != `Use of "}" in {tmpl}` || got[0].Severity != SeverityHigh || got[0].Locations[0].Lines.End != 4 {
+		t.Errorf("first finding = %+v", got[0])
+	}
+	if got[0].ID == "" {
+		t.Error("streamed findings should have an ID")
+	}
+	if got[1].Title != "Second" || got[1].Locations[0].Path != "b.go" {
+		t.Errorf("second finding = %+v", got[1])
+	}
+}
+
+func TestFindingScanner_SkipsInvalidObjects(t *testing.T) {
+	var got []Finding
+	sc := &findingScanner{onFinding: func(f Finding) { got = append(got, f) }}
+	sc.write(`[{"title": 5}, {"title":"ok"}]`)
+	if len(got) != 1 || got[0].Title != "ok" {
+		t.Errorf("got %+v, want only the valid finding", got)
+	}
+}
+
+func TestRunWithOptions_OnFinding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content := `[{"severity":"high","category":"bug","title":"Nil deref","message":"m","path":"x.go","startLine":2,"endLine":2},` +
+			`{"severity":"low","category":"style","title":"Naming","message":"m","path":"x.go","startLine":1,"endLine":1}]`
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"content": content}}},
+		})
+	}))
+	defer server.Close()
+	t.Setenv("OLLAMA_HOST", server.URL)
+
+	cfg := config.Default()
+	cfg.Provider = "ollama"
+	cfg.Model = "llama3"
+	cfg.Cache.Enabled = false
+	cfg.MaxFindings = 1
+	diff := gitctx.DiffResult{
+		Diff:  "diff --git a/x.go b/x.go\n--- a/x.go\n+++ b/x.go\n@@ -0,0 +1,2 @@\n+package x\n+var a int\n",
+		Files: []string{"x.go"},
+	}
+
+	var streamed []string
+	report, err := RunWithOptions(context.Background(), diff, cfg, Options{
+		OnFinding: func(f Finding) { streamed = append(streamed, f.Title) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(streamed) != 2 || streamed[0] != "Nil deref" || streamed[1] != "Naming" {
+		t.Errorf("streamed = %q, want both findings in response order", streamed)
+	}
+	if len(report.Findings) != 1 {
+		t.Errorf("report should still apply the MaxFindings cap, got %d findings", len(report.Findings))
+	}
+}
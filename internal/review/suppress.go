@@ -0,0 +1,97 @@
+package review
+
+import (
+	"strings"
+)
+
+// ignoreDirective is the marker developers place in a source comment to
+// suppress a finding, e.g. "// prism:ignore[security]" or
+// "# prism:ignore[3f2a9c01d4e5b6a7]", where the hex value is a finding's
+// ID or StableKey. Several targets may be given
+// separated by commas or pipes: "prism:ignore[style, docs]".
+const ignoreDirective = "prism:ignore["
+
+// inlineIgnore is a parsed suppression directive at a specific line.
+type inlineIgnore struct {
+	line    int
+	targets []string // lowercased categories and/or finding IDs
+}
+
+// parseInlineIgnores collects prism:ignore directives from the new-side
+// lines of a diff, keyed by file path.
+func parseInlineIgnores(diff string) map[string][]inlineIgnore {
+	result := make(map[string][]inlineIgnore)
+	for path, lines := range parseDiffLines(diff) {
+		for _, l := range lines {
+			targets := parseIgnoreTargets(l.Text)
+			if len(targets) > 0 {
+				result[path] = append(result[path], inlineIgnore{line: l.Number, targets: targets})
+			}
+		}
+	}
+	return result
+}
+
+// parseIgnoreTargets extracts the bracketed targets from a line containing
+// a prism:ignore directive. Returns nil if the line has no directive.
+func parseIgnoreTargets(text string) []string {
+	idx := strings.Index(text, ignoreDirective)
+	if idx < 0 {
+		return nil
+	}
+	rest := text[idx+len(ignoreDirective):]
+	end := strings.Index(rest, "]")
+	if end < 0 {
+		return nil
+	}
+	var targets []string
+	for _, t := range strings.FieldsFunc(rest[:end], func(r rune) bool { return r == ',' || r == '|' }) {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" {
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
+// ApplyInlineSuppressions drops findings silenced by a prism:ignore comment
+// in the diff. A directive applies to findings whose line range covers the
+// directive's own line or the line directly below it, and matches when one
+// of its targets equals the finding's category, ID, or StableKey. The ID is
+// hashed from the start line, so adding the directive above the flagged
+// line changes it; the StableKey survives that shift.
+func ApplyInlineSuppressions(findings []Finding, diff string) []Finding {
+	ignores := parseInlineIgnores(diff)
+	if len(ignores) == 0 {
+		return findings
+	}
+
+	kept := []Finding{}
+	for _, f := range findings {
+		if !isSuppressed(f, ignores[findingPath(f)]) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+func isSuppressed(f Finding, ignores []inlineIgnore) bool {
+	lines := findingLines(f)
+	end := lines.End
+	if end < lines.Start {
+		end = lines.Start
+	}
+	stableKey := baselineKey(f)
+	for _, ig := range ignores {
+		// The directive covers its own line and the one below it.
+		if ig.line > end || ig.line+1 < lines.Start {
+			continue
+		}
+		for _, t := range ig.targets {
+			if t == strings.ToLower(string(f.Category)) || t == f.ID || t == stableKey {
+				return true
+			}
+		}
+	}
+	return false
+}
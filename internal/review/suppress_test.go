@@ -0,0 +1,141 @@
+package review
+
+import "testing"
+
+const suppressDiff = `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,8 @@
+ package main
++
++// prism:ignore[security]
++func handler() { exec(userInput) }
++
++func other() { exec(userInput) } // prism:ignore[0123456789abcdef]
++
++func third() { exec(userInput) }
+`
+
+func TestApplyInlineSuppressions_Category(t *testing.T) {
+	findings := []Finding{
+		{ID: "aaa", Category: CategorySecurity, Locations: []Location{{Path: "main.go", Lines: LineRange{Start: 4, End: 4}}}},
+		{ID: "bbb", Category: CategoryBug, Locations: []Location{{Path: "main.go", Lines: LineRange{Start: 4, End: 4}}}},
+	}
+	got := ApplyInlineSuppressions(findings, suppressDiff)
+	if len(got) != 1 {
+		t.Fatalf("got %d findings, want 1", len(got))
+	}
+	if got[0].ID != "bbb" {
+		t.Errorf("kept finding %q, want bbb (category does not match directive)", got[0].ID)
+	}
+}
+
+func TestApplyInlineSuppressions_AllSuppressed(t *testing.T) {
+	findings := []Finding{
+		{ID: "aaa", Category: CategorySecurity, Locations: []Location{{Path: "main.go", Lines: LineRange{Start: 4, End: 4}}}},
+	}
+	if got := ApplyInlineSuppressions(findings, suppressDiff); got == nil || len(got) != 0 {
+		t.Errorf("got %#v, want an empty slice", got)
+	}
+}
+
+func TestApplyInlineSuppressions_FindingID(t *testing.T) {
+	findings := []Finding{
+		{ID: "0123456789abcdef", Category: CategorySecurity, Locations: []Location{{Path: "main.go", Lines: LineRange{Start: 6, End: 6}}}},
+		{ID: "fedcba9876543210", Category: CategorySecurity, Locations: []Location{{Path: "main.go", Lines: LineRange{Start: 8, End: 8}}}},
+	}
+	got := ApplyInlineSuppressions(findings, suppressDiff)
+	if len(got) != 1 {
+		t.Fatalf("got %d findings, want 1", len(got))
+	}
+	if got[0].ID != "fedcba9876543210" {
+		t.Errorf("kept finding %q, want the one without a directive", got[0].ID)
+	}
+}
+
+func TestApplyInlineSuppressions_StableKeyAfterShift(t *testing.T) {
+	// The finding was first reported on line 2. Inserting the directive
+	// above it moves the code to line 3, which changes the ID but not the
+	// StableKey.
+	flagged := Finding{Title: "Command injection", Category: CategorySecurity, Locations: []Location{{Path: "run.go", Lines: LineRange{Start: 2, End: 2}}}}
+	key := generateStableKey(flagged)
+	diff := `diff --git a/run.go b/run.go
+--- a/run.go
++++ b/run.go
+@@ -1,2 +1,3 @@
+ package run
++// prism:ignore[` + key + `]
+ func run() { exec(userInput) }
+`
+	shifted := flagged
+	shifted.Locations = []Location{{Path: "run.go", Lines: LineRange{Start: 3, End: 3}}}
+	shifted.ID = generateFindingID(shifted)
+	if shifted.ID == generateFindingID(flagged) {
+		t.Fatal("test assumes the shift changes the ID")
+	}
+	if got := ApplyInlineSuppressions([]Finding{shifted}, diff); len(got) != 0 {
+		t.Errorf("got %d findings, want the StableKey directive to suppress the shifted finding", len(got))
+	}
+}
+
+func TestApplyInlineSuppressions_OtherFile(t *testing.T) {
+	findings := []Finding{
+		{ID: "aaa", Category: CategorySecurity, Locations: []Location{{Path: "other.go", Lines: LineRange{Start: 4, End: 4}}}},
+	}
+	got := ApplyInlineSuppressions(findings, suppressDiff)
+	if len(got) != 1 {
+		t.Errorf("directive in main.go should not suppress findings in other.go")
+	}
+}
+
+func TestParseIgnoreTargets(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"// prism:ignore[security]", []string{"security"}},
+		{"# prism:ignore[ Style , docs ]", []string{"style", "docs"}},
+		{"-- prism:ignore[bug | abc123]", []string{"bug", "abc123"}},
+		{"// prism:ignore[unterminated", nil},
+		{"// nothing here", nil},
+	}
+	for _, tt := range tests {
+		got := parseIgnoreTargets(tt.line)
+		if len(got) != len(tt.want) {
+			t.Errorf("parseIgnoreTargets(%q) = %v, want %v", tt.line, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("parseIgnoreTargets(%q)[%d] = %q, want %q", tt.line, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseDiffLines(t *testing.T) {
+	lines := parseDiffLines(suppressDiff)["main.go"]
+	if len(lines) != 8 {
+		t.Fatalf("got %d lines, want 8", len(lines))
+	}
+	if lines[0].Number != 1 || lines[0].Added {
+		t.Errorf("lines[0] = %+v, want context line 1", lines[0])
+	}
+	if lines[3].Number != 4 || !lines[3].Added || lines[3].Text != "func handler() { exec(userInput) }" {
+		t.Errorf("lines[3] = %+v, want added line 4", lines[3])
+	}
+}
+
+func TestHunkNewStart(t *testing.T) {
+	tests := map[string]int{
+		"@@ -10,4 +12,6 @@ func foo()": 12,
+		"@@ -0,0 +1 @@":                1,
+		"@@ -1 +1 @@":                  1,
+		"@@ garbage @@":                0,
+	}
+	for header, want := range tests {
+		if got := hunkNewStart(header); got != want {
+			t.Errorf("hunkNewStart(%q) = %d, want %d", header, got, want)
+		}
+	}
+}
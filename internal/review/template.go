@@ -0,0 +1,144 @@
+package review
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/dshills/prism/internal/config"
+)
+
+// PromptData is the data prompt templates are executed with.
+type PromptData struct {
+	// Diff is the redacted diff under review, or the source files for
+	// codebase and directory reviews.
+	Diff      string
+	Files     []string
+	Languages []string
+	// Rules is the rules section of the built-in user prompt, or "" when
+	// no rules are loaded.
+	Rules       string
+	MaxFindings int
+	FailOn      string
+	Categories  []string
+	// DefaultSystem and DefaultUser are the built-in prompts, so a
+	// template can extend them rather than replace them.
+	DefaultSystem string
+	DefaultUser   string
+}
+
+// PromptTemplate replaces the built-in system and user prompts with Go
+// text/templates. Either may be nil, keeping the built-in prompt.
+type PromptTemplate struct {
+	system *template.Template
+	user   *template.Template
+}
+
+// LoadPromptTemplate reads the prompt template file at path. The file
+// defines a "system" template, a "user" template, or both, e.g.
+//
+//	{{define "system"}}{{.DefaultSystem}}
+//	Follow the conventions of our Go handbook.{{end}}
+//
+// Templates are executed with PromptData, and a reference to an unknown
+// field is reported here rather than during the review. An empty path
+// returns a nil template.
+func LoadPromptTemplate(path string) (*PromptTemplate, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading prompt template: %w", err)
+	}
+	t, err := template.New(filepath.Base(path)).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing prompt template: %w", err)
+	}
+	pt := &PromptTemplate{system: t.Lookup("system"), user: t.Lookup("user")}
+	if pt.system == nil && pt.user == nil {
+		return nil, fmt.Errorf("prompt template %s defines neither a \"system\" nor a \"user\" template", path)
+	}
+	for _, tmpl := range []*template.Template{pt.system, pt.user} {
+		if tmpl == nil {
+			continue
+		}
+		if err := tmpl.Execute(new(strings.Builder), PromptData{}); err != nil {
+			return nil, fmt.Errorf("prompt template %s: %w", path, err)
+		}
+	}
+	return pt, nil
+}
+
+// render executes tmpl with data, returning def when tmpl is nil.
+func (pt *PromptTemplate) render(tmpl *template.Template, data PromptData, def string) (string, error) {
+	if tmpl == nil {
+		return def, nil
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("prompt template %q: %w", tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
+
+// execute renders both prompts for one request. builder's prompts are
+// passed to the templates as DefaultSystem and DefaultUser.
+func (pt *PromptTemplate) execute(builder PromptBuilder, chunkDiff string, files []string, cfg config.Config, rules *Rules) (string, string, error) {
+	sysPr, userPr := builder(chunkDiff, files, cfg, rules)
+	cats := Categories(cfg.ExtraCategories)
+	names := make([]string, len(cats))
+	for i, c := range cats {
+		names[i] = string(c)
+	}
+	data := PromptData{
+		Diff:          chunkDiff,
+		Files:         files,
+		Languages:     detectLanguages(files),
+		Rules:         BuildRulesPromptSection(rules),
+		MaxFindings:   cfg.MaxFindings,
+		FailOn:        cfg.FailOn,
+		Categories:    names,
+		DefaultSystem: sysPr,
+		DefaultUser:   userPr,
+	}
+	system, err := pt.render(pt.system, data, sysPr)
+	if err != nil {
+		return "", "", err
+	}
+	user, err := pt.render(pt.user, data, userPr)
+	if err != nil {
+		return "", "", err
+	}
+	return system, user, nil
+}
+
+// checkPromptTemplate executes pt once for the whole diff, so a template
+// that fails on the review's data is reported before any provider call.
+func checkPromptTemplate(pt *PromptTemplate, builder PromptBuilder, diff string, files []string, cfg config.Config, rules *Rules) error {
+	if pt == nil {
+		return nil
+	}
+	_, _, err := pt.execute(builder, diff, files, cfg, rules)
+	return err
+}
+
+// withPromptTemplate wraps builder so its prompts are replaced by the ones
+// pt renders. The wrapped builder's prompts are passed to the templates as
+// DefaultSystem and DefaultUser. Without a template builder is returned
+// unchanged. A chunk the template fails on, which checkPromptTemplate did
+// not catch for the whole diff, keeps the built-in prompts.
+func withPromptTemplate(builder PromptBuilder, pt *PromptTemplate) PromptBuilder {
+	if pt == nil {
+		return builder
+	}
+	return func(chunkDiff string, files []string, cfg config.Config, rules *Rules) (string, string) {
+		sysPr, userPr, err := pt.execute(builder, chunkDiff, files, cfg, rules)
+		if err != nil {
+			return builder(chunkDiff, files, cfg, rules)
+		}
+		return sysPr, userPr
+	}
+}
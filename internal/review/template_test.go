@@ -0,0 +1,94 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dshills/prism/internal/config"
+)
+
+func writeTemplate(t *testing.T, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "prompt.tmpl")
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPromptTemplate_InPrompt(t *testing.T) {
+	path := writeTemplate(t, `{{define "system"}}{{.DefaultSystem}}
+Use our error wrapping conventions.{{end}}
+{{define "user"}}Files: {{range .Files}}{{.}} {{end}}; langs: {{range .Languages}}{{.}}{{end}}; max {{.MaxFindings}}
+{{.Rules}}
+{{.Diff}}{{end}}`)
+	tmpl, err := LoadPromptTemplate(path)
+	if err != nil {
+		t.Fatalf("LoadPromptTemplate error: %v", err)
+	}
+	rules := &Rules{Focus: []string{"error handling"}}
+	sysPr, userPr := withPromptTemplate(defaultPromptBuilder, tmpl)("+x := 1", []string{"a.go"}, config.Default(), rules)
+
+	if !strings.HasPrefix(sysPr, SystemPrompt()) || !strings.HasSuffix(sysPr, "Use our error wrapping conventions.") {
+		t.Errorf("system prompt should extend the built-in one:\n%s", sysPr)
+	}
+	for _, want := range []string{"Files: a.go ", "langs: Go", "max 50", "error handling", "+x := 1"} {
+		if !strings.Contains(userPr, want) {
+			t.Errorf("user prompt missing %q:\n%s", want, userPr)
+		}
+	}
+}
+
+func TestLoadPromptTemplate_UserOnly(t *testing.T) {
+	tmpl, err := LoadPromptTemplate(writeTemplate(t, `{{define "user"}}Review:
+{{.Diff}}{{end}}`))
+	if err != nil {
+		t.Fatalf("LoadPromptTemplate error: %v", err)
+	}
+	sysPr, userPr := withPromptTemplate(defaultPromptBuilder, tmpl)("diff", nil, config.Default(), nil)
+	if sysPr != SystemPrompt() {
+		t.Error("without a system template the system prompt must be unchanged")
+	}
+	if userPr != "Review:\ndiff" {
+		t.Errorf("user prompt = %q", userPr)
+	}
+}
+
+func TestLoadPromptTemplate_Errors(t *testing.T) {
+	if tmpl, err := LoadPromptTemplate(""); tmpl != nil || err != nil {
+		t.Fatalf("LoadPromptTemplate(\"\") = %v, %v", tmpl, err)
+	}
+	for name, text := range map[string]string{
+		"no templates":  "just text",
+		"unknown field": `{{define "system"}}{{.Style}}{{end}}`,
+		"syntax":        `{{define "user"}}{{.Diff}{{end}}`,
+	} {
+		if _, err := LoadPromptTemplate(writeTemplate(t, text)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := LoadPromptTemplate(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+		t.Error("expected error for a missing template file")
+	}
+}
+
+func TestCheckPromptTemplate(t *testing.T) {
+	// Loads fine with empty data, but fails once a file is listed
+	tmpl, err := LoadPromptTemplate(writeTemplate(t, `{{define "user"}}{{if .Files}}{{index .Files 5}}{{end}}{{end}}`))
+	if err != nil {
+		t.Fatalf("LoadPromptTemplate error: %v", err)
+	}
+	if err := checkPromptTemplate(tmpl, defaultPromptBuilder, "diff", []string{"a.go"}, config.Default(), nil); err == nil {
+		t.Error("expected the template error before the review")
+	}
+	if err := checkPromptTemplate(nil, defaultPromptBuilder, "diff", []string{"a.go"}, config.Default(), nil); err != nil {
+		t.Errorf("no template: %v", err)
+	}
+	// A chunk the template fails on keeps the built-in prompts
+	_, userPr := withPromptTemplate(defaultPromptBuilder, tmpl)("diff", []string{"a.go"}, config.Default(), nil)
+	if _, want := defaultPromptBuilder("diff", []string{"a.go"}, config.Default(), nil); userPr != want {
+		t.Errorf("user prompt = %q, want the built-in one", userPr)
+	}
+}
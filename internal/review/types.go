@@ -1,22 +1,41 @@
 package review
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dshills/prism/internal/providers"
+)
+
 // Severity represents the severity level of a finding.
 type Severity string
 
+// Critical is for issues that must be fixed before merging, such as an
+// exploitable vulnerability or data loss; info is for observations that
+// need no change.
 const (
-	SeverityLow    Severity = "low"
-	SeverityMedium Severity = "medium"
-	SeverityHigh   Severity = "high"
+	SeverityInfo     Severity = "info"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
 )
 
+// Severities lists the severity levels from most to least severe.
+var Severities = []Severity{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityInfo}
+
 // SeverityRank returns a numeric rank for sorting (higher = more severe).
 func SeverityRank(s Severity) int {
 	switch s {
+	case SeverityCritical:
+		return 5
 	case SeverityHigh:
-		return 3
+		return 4
 	case SeverityMedium:
-		return 2
+		return 3
 	case SeverityLow:
+		return 2
+	case SeverityInfo:
 		return 1
 	default:
 		return 0
@@ -45,6 +64,52 @@ const (
 	CategoryDocs            Category = "docs"
 )
 
+// builtinCategories lists the categories the review prompts always allow,
+// in prompt order.
+var builtinCategories = []Category{
+	CategoryBug, CategorySecurity, CategoryPerformance, CategoryCorrectness,
+	CategoryStyle, CategoryMaintainability, CategoryTesting, CategoryDocs,
+}
+
+// Categories returns the allowed finding categories: the built-in set
+// followed by any extra (custom) categories, lowercased and deduplicated.
+func Categories(extra []string) []Category {
+	cats := append([]Category(nil), builtinCategories...)
+	seen := make(map[Category]bool, len(cats)+len(extra))
+	for _, c := range cats {
+		seen[c] = true
+	}
+	for _, e := range extra {
+		c := Category(strings.ToLower(strings.TrimSpace(e)))
+		if c != "" && !seen[c] {
+			seen[c] = true
+			cats = append(cats, c)
+		}
+	}
+	return cats
+}
+
+// NormalizeCategories lowercases each finding's category and replaces any
+// category outside the allowed set (built-ins plus extra) with
+// maintainability. Stable keys are regenerated for changed findings.
+func NormalizeCategories(findings []Finding, extra []string) []Finding {
+	allowed := make(map[Category]bool)
+	for _, c := range Categories(extra) {
+		allowed[c] = true
+	}
+	for i := range findings {
+		c := Category(strings.ToLower(strings.TrimSpace(string(findings[i].Category))))
+		if !allowed[c] {
+			c = CategoryMaintainability
+		}
+		if c != findings[i].Category {
+			findings[i].Category = c
+			findings[i].StableKey = generateStableKey(findings[i])
+		}
+	}
+	return findings
+}
+
 // Location represents where a finding was detected.
 type Location struct {
 	Path    string    `json:"path"`
@@ -54,15 +119,22 @@ type Location struct {
 	Snippet string    `json:"snippet,omitempty"`
 }
 
-// LineRange represents a range of line numbers.
+// LineRange represents a range of line numbers. A zero Start marks a
+// file-level finding that applies to the whole file rather than specific lines.
 type LineRange struct {
 	Start int `json:"start"`
 	End   int `json:"end"`
 }
 
+// FileLevel reports whether the range refers to the whole file.
+func (r LineRange) FileLevel() bool {
+	return r.Start <= 0
+}
+
 // Finding represents a single code review finding.
 type Finding struct {
 	ID         string     `json:"id"`
+	StableKey  string     `json:"stableKey,omitempty"`
 	Severity   Severity   `json:"severity"`
 	Category   Category   `json:"category"`
 	Title      string     `json:"title"`
@@ -72,6 +144,17 @@ type Finding struct {
 	Locations  []Location `json:"locations"`
 	Tags       []string   `json:"tags,omitempty"`
 	References []string   `json:"references,omitempty"`
+	// Hunk is the diff hunk the primary location falls in. Only set when
+	// requested with --with-hunks.
+	Hunk string `json:"hunk,omitempty"`
+	// Baseline is BaselineNew or BaselineEscalated when the report was
+	// compared with a baseline report (see ApplyBaseline).
+	Baseline string `json:"baseline,omitempty"`
+	// PreviousSeverity is the baseline severity of an escalated finding.
+	PreviousSeverity Severity `json:"previousSeverity,omitempty"`
+	// Consensus is true when two or more compare-mode models reported the
+	// finding. Consensus findings sort ahead of others of the same severity.
+	Consensus bool `json:"consensus,omitempty"`
 }
 
 // RepoInfo contains repository metadata.
@@ -79,6 +162,9 @@ type RepoInfo struct {
 	Root   string `json:"root"`
 	Head   string `json:"head"`
 	Branch string `json:"branch"`
+	// Detached is true when the review ran on a detached HEAD, so Branch
+	// is empty.
+	Detached bool `json:"detached,omitempty"`
 }
 
 // InputInfo describes what was reviewed.
@@ -87,19 +173,117 @@ type InputInfo struct {
 	Range         string   `json:"range,omitempty"`
 	PathsIncluded []string `json:"pathsIncluded,omitempty"`
 	PathsExcluded []string `json:"pathsExcluded,omitempty"`
+	// Sources breaks a combined review down by the diff modes it merged.
+	Sources []SourceInfo `json:"sources,omitempty"`
 }
 
-// SeverityCounts holds counts by severity level.
+// SourceInfo describes one diff mode merged into a combined review.
+type SourceInfo struct {
+	Mode     string `json:"mode"`
+	Range    string `json:"range,omitempty"`
+	Files    int    `json:"files"`
+	Findings int    `json:"findings"`
+}
+
+// SeverityCounts holds counts by severity level. Critical and Info are
+// omitted from JSON when zero, so reports without them are unchanged.
 type SeverityCounts struct {
-	Low    int `json:"low"`
-	Medium int `json:"medium"`
-	High   int `json:"high"`
+	Info     int `json:"info,omitempty"`
+	Low      int `json:"low"`
+	Medium   int `json:"medium"`
+	High     int `json:"high"`
+	Critical int `json:"critical,omitempty"`
+}
+
+// Total returns the number of findings counted at every level.
+func (c SeverityCounts) Total() int {
+	return c.Critical + c.High + c.Medium + c.Low + c.Info
+}
+
+// Count returns the number of findings counted at severity s.
+func (c SeverityCounts) Count(s Severity) int {
+	switch s {
+	case SeverityCritical:
+		return c.Critical
+	case SeverityHigh:
+		return c.High
+	case SeverityMedium:
+		return c.Medium
+	case SeverityLow:
+		return c.Low
+	case SeverityInfo:
+		return c.Info
+	default:
+		return 0
+	}
+}
+
+// Shown returns the levels a summary lists, most severe first: high,
+// medium, and low always, and critical and info only when non-zero, so
+// summaries of reports without them are unchanged.
+func (c SeverityCounts) Shown() []Severity {
+	var levels []Severity
+	for _, s := range Severities {
+		if c.Count(s) == 0 && (s == SeverityCritical || s == SeverityInfo) {
+			continue
+		}
+		levels = append(levels, s)
+	}
+	return levels
+}
+
+// Breakdown renders the shown counts as "2 high, 5 medium, 1 low".
+func (c SeverityCounts) Breakdown() string {
+	var parts []string
+	for _, s := range c.Shown() {
+		parts = append(parts, fmt.Sprintf("%d %s", c.Count(s), s))
+	}
+	return strings.Join(parts, ", ")
 }
 
 // Summary provides an overview of findings.
 type Summary struct {
 	Counts          SeverityCounts `json:"counts"`
 	HighestSeverity Severity       `json:"highestSeverity"`
+	// CategoryCounts holds the number of findings per category.
+	CategoryCounts map[Category]int `json:"categoryCounts,omitempty"`
+	// Verdict is the one-line outcome: VerdictPass, VerdictReviewNeeded,
+	// or VerdictBlock (see ComputeVerdict).
+	Verdict string `json:"verdict,omitempty"`
+}
+
+// Verdict values for Summary.Verdict.
+const (
+	VerdictPass         = "pass"
+	VerdictReviewNeeded = "review-needed"
+	VerdictBlock        = "block"
+)
+
+// blockConfidence is the minimum confidence a finding needs to block.
+// Findings with no reported confidence (0) are treated as confident.
+const blockConfidence = 0.5
+
+// ComputeVerdict maps findings to a verdict against a severity threshold:
+//   - block: at least one finding meets the threshold with confidence of at
+//     least 0.5 (or no confidence reported)
+//   - review-needed: there are findings, but none that block
+//   - pass: there are no findings
+//
+// A threshold of "" or "none" is treated as "high", so a verdict is still
+// given when the run itself never fails.
+func ComputeVerdict(findings []Finding, threshold string) string {
+	if len(findings) == 0 {
+		return VerdictPass
+	}
+	if threshold == "" || threshold == "none" {
+		threshold = string(SeverityHigh)
+	}
+	for _, f := range findings {
+		if MeetsThreshold(f.Severity, threshold) && (f.Confidence == 0 || f.Confidence >= blockConfidence) {
+			return VerdictBlock
+		}
+	}
+	return VerdictReviewNeeded
 }
 
 // Timing contains performance metrics.
@@ -107,6 +291,33 @@ type Timing struct {
 	GitMs   int64 `json:"gitMs"`
 	LLMMs   int64 `json:"llmMs"`
 	TotalMs int64 `json:"totalMs"`
+	// Chunks breaks LLM time down per chunk for chunked reviews, in chunk
+	// order. It is empty when the diff was reviewed in a single call.
+	Chunks []ChunkTiming `json:"chunks,omitempty"`
+	// Retries counts the provider requests retried after a rate limit or
+	// server error, across all calls. A high count points at a flaky or
+	// overloaded provider.
+	Retries int `json:"retries,omitempty"`
+	// Usage totals the tokens and estimated cost of the provider calls made
+	// for the report. It is nil when no call was made, as for a cached
+	// review.
+	Usage *providers.Usage `json:"usage,omitempty"`
+}
+
+// ChunkTiming records the LLM time spent on one chunk, including any repair
+// pass.
+type ChunkTiming struct {
+	Index   int      `json:"index"`
+	Files   []string `json:"files"`
+	LLMMs   int64    `json:"llmMs"`
+	Retries int      `json:"retries,omitempty"`
+}
+
+// DiffStats summarizes the size of the reviewed diff.
+type DiffStats struct {
+	FilesChanged int `json:"filesChanged"`
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
 }
 
 // Report is the top-level output structure.
@@ -117,25 +328,67 @@ type Report struct {
 	Repo     RepoInfo  `json:"repo"`
 	Inputs   InputInfo `json:"inputs"`
 	Summary  Summary   `json:"summary"`
+	Stats    DiffStats `json:"stats"`
 	Findings []Finding `json:"findings"`
 	Timing   Timing    `json:"timing"`
+	// Metadata holds free-form run labels supplied by the caller (e.g.
+	// environment or ticket ID) for grouping stored reports.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Skipped explains why the diff was not sent to the provider, such as
+	// falling below --min-diff-bytes. It is empty for a normal review.
+	Skipped string `json:"skipped,omitempty"`
+	// Warnings are problems that did not stop the review, such as a
+	// truncated style guide, for the caller to show.
+	Warnings []string `json:"warnings,omitempty"`
+	// ReviewNote is the model's short account of what it checked, requested
+	// with --with-note when a review has no findings.
+	ReviewNote string `json:"reviewNote,omitempty"`
+	// Owners maps changed files to their CODEOWNERS entries, added with
+	// --with-owners. Files without owners are omitted.
+	Owners map[string][]string `json:"owners,omitempty"`
+	// Audit holds a hash of the prompt and response of every provider call
+	// made for the report, recorded with --audit.
+	Audit []providers.AuditEntry `json:"audit,omitempty"`
 }
 
-// ComputeSummary calculates the summary from findings.
+// SummaryLine renders a report as one stable, parseable line for chat
+// notifications, e.g. "prism: 2 high, 5 medium, 1 low in 8 files". The
+// high, medium, and low counts are always present (see
+// SeverityCounts.Breakdown).
+func SummaryLine(report *Report) string {
+	files := "files"
+	if report.Stats.FilesChanged == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("prism: %s in %d %s",
+		report.Summary.Counts.Breakdown(), report.Stats.FilesChanged, files)
+}
+
+// ComputeSummary calculates the summary from findings. The verdict uses the
+// default "high" threshold; use ComputeVerdict to apply a configured one.
 func ComputeSummary(findings []Finding) Summary {
 	var s Summary
 	for _, f := range findings {
 		switch f.Severity {
+		case SeverityInfo:
+			s.Counts.Info++
 		case SeverityLow:
 			s.Counts.Low++
 		case SeverityMedium:
 			s.Counts.Medium++
 		case SeverityHigh:
 			s.Counts.High++
+		case SeverityCritical:
+			s.Counts.Critical++
 		}
 		if SeverityRank(f.Severity) > SeverityRank(s.HighestSeverity) {
 			s.HighestSeverity = f.Severity
 		}
+		if s.CategoryCounts == nil {
+			s.CategoryCounts = make(map[Category]int)
+		}
+		s.CategoryCounts[f.Category]++
 	}
+	s.Verdict = ComputeVerdict(findings, "")
 	return s
 }
@@ -7,9 +7,11 @@ func TestSeverityRank(t *testing.T) {
 		severity Severity
 		want     int
 	}{
-		{SeverityLow, 1},
-		{SeverityMedium, 2},
-		{SeverityHigh, 3},
+		{SeverityInfo, 1},
+		{SeverityLow, 2},
+		{SeverityMedium, 3},
+		{SeverityHigh, 4},
+		{SeverityCritical, 5},
 		{Severity("unknown"), 0},
 	}
 	for _, tt := range tests {
@@ -37,6 +39,9 @@ func TestMeetsThreshold(t *testing.T) {
 		{SeverityLow, "high", false},
 		{SeverityLow, "medium", false},
 		{SeverityLow, "low", true},
+		{SeverityCritical, "high", true},
+		{SeverityInfo, "low", false},
+		{SeverityInfo, "info", true},
 	}
 	for _, tt := range tests {
 		got := MeetsThreshold(tt.severity, tt.threshold)
@@ -72,6 +77,36 @@ func TestComputeSummary(t *testing.T) {
 	}
 }
 
+func TestSummaryLine(t *testing.T) {
+	report := &Report{Stats: DiffStats{FilesChanged: 1}}
+	if got, want := SummaryLine(report), "prism: 0 high, 0 medium, 0 low in 1 file"; got != want {
+		t.Errorf("SummaryLine() = %q, want %q", got, want)
+	}
+}
+
+func TestComputeSummary_CriticalAndInfo(t *testing.T) {
+	s := ComputeSummary([]Finding{
+		{Severity: SeverityCritical},
+		{Severity: SeverityHigh},
+		{Severity: SeverityInfo},
+		{Severity: SeverityInfo},
+	})
+	if s.Counts.Critical != 1 || s.Counts.Info != 2 || s.Counts.Total() != 4 {
+		t.Errorf("Counts = %+v, want 1 critical, 2 info, 4 total", s.Counts)
+	}
+	if s.HighestSeverity != SeverityCritical {
+		t.Errorf("HighestSeverity = %q, want %q", s.HighestSeverity, SeverityCritical)
+	}
+	if s.Verdict != VerdictBlock {
+		t.Errorf("Verdict = %q, want critical to block at the default threshold", s.Verdict)
+	}
+
+	report := &Report{Summary: s, Stats: DiffStats{FilesChanged: 2}}
+	if got, want := SummaryLine(report), "prism: 1 critical, 1 high, 0 medium, 0 low, 2 info in 2 files"; got != want {
+		t.Errorf("SummaryLine() = %q, want %q", got, want)
+	}
+}
+
 func TestComputeSummary_Empty(t *testing.T) {
 	s := ComputeSummary(nil)
 	if s.Counts.High != 0 || s.Counts.Medium != 0 || s.Counts.Low != 0 {
@@ -81,3 +116,65 @@ func TestComputeSummary_Empty(t *testing.T) {
 		t.Errorf("HighestSeverity = %q, want empty", s.HighestSeverity)
 	}
 }
+
+func TestComputeSummary_CategoryCounts(t *testing.T) {
+	findings := []Finding{
+		{Severity: SeverityHigh, Category: CategorySecurity},
+		{Severity: SeverityMedium, Category: CategorySecurity},
+		{Severity: SeverityLow, Category: CategoryStyle},
+		{Severity: SeverityLow, Category: CategoryBug},
+	}
+
+	s := ComputeSummary(findings)
+
+	if s.CategoryCounts[CategorySecurity] != 2 {
+		t.Errorf("security count = %d, want 2", s.CategoryCounts[CategorySecurity])
+	}
+	sum := 0
+	for _, n := range s.CategoryCounts {
+		sum += n
+	}
+	total := s.Counts.High + s.Counts.Medium + s.Counts.Low
+	if sum != total {
+		t.Errorf("category counts sum to %d, want %d", sum, total)
+	}
+	if ComputeSummary(nil).CategoryCounts != nil {
+		t.Error("CategoryCounts should be nil with no findings")
+	}
+}
+
+func TestComputeVerdict(t *testing.T) {
+	tests := []struct {
+		name      string
+		findings  []Finding
+		threshold string
+		want      string
+	}{
+		{"no findings", nil, "high", VerdictPass},
+		{"high blocks by default", []Finding{{Severity: SeverityHigh, Confidence: 0.9}}, "none", VerdictBlock},
+		{"medium below default", []Finding{{Severity: SeverityMedium, Confidence: 0.9}}, "", VerdictReviewNeeded},
+		{"medium meets threshold", []Finding{{Severity: SeverityMedium, Confidence: 0.9}}, "medium", VerdictBlock},
+		{"low confidence does not block", []Finding{{Severity: SeverityHigh, Confidence: 0.3}}, "high", VerdictReviewNeeded},
+		{"unreported confidence blocks", []Finding{{Severity: SeverityHigh}}, "high", VerdictBlock},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeVerdict(tt.findings, tt.threshold); got != tt.want {
+				t.Errorf("ComputeVerdict() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := ComputeSummary([]Finding{{Severity: SeverityHigh}}).Verdict; got != VerdictBlock {
+		t.Errorf("ComputeSummary verdict = %q, want %q", got, VerdictBlock)
+	}
+}
+
+func TestLineRange_FileLevel(t *testing.T) {
+	if !(LineRange{}).FileLevel() {
+		t.Error("zero range should be file-level")
+	}
+	if (LineRange{Start: 1, End: 1}).FileLevel() {
+		t.Error("range starting at line 1 should not be file-level")
+	}
+}